@@ -0,0 +1,8 @@
+//go:build !sqlite_fts5
+
+package main
+
+// Pencarian dokumen memakai indeks SQLite FTS5 yang hanya ikut terkompilasi dengan tag sqlite_fts5.
+// File ini sengaja menggagalkan kompilasi tanpa tag tersebut, agar biner yang tidak bisa membuka
+// database tidak pernah terbangun. Bangun dengan `make build` atau `go build -tags sqlite_fts5 ./cmd`.
+var _ = SIMDOKPOL_WAJIB_DIBANGUN_DENGAN_TAG_sqlite_fts5
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"simdokpol/internal/config"
+	"simdokpol/internal/dto"
+	"simdokpol/internal/services"
+	"strings"
+)
+
+// runImportCommand menjalankan impor surat lama dari baris perintah, misalnya:
+//
+//	simdokpol import -file arsip2023.xlsx -nrp 12345678 -commit
+//
+// Tanpa -commit file hanya diperiksa. Kode keluar 0 berarti berhasil, 2 berarti masih ada
+// baris yang tidak valid, dan 1 untuk kesalahan lainnya.
+func runImportCommand(args []string) int {
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	filePath := fs.String("file", "", "path file .xlsx atau .csv yang akan diimpor")
+	nrp := fs.String("nrp", "", "NRP pengguna yang tercatat sebagai operator impor")
+	commit := fs.Bool("commit", false, "simpan data; tanpa opsi ini file hanya diperiksa")
+	if err := fs.Parse(args); err != nil {
+		return 1
+	}
+	if *filePath == "" || *nrp == "" {
+		fmt.Fprintln(os.Stderr, "Penggunaan: simdokpol import -file <file.xlsx|file.csv> -nrp <NRP> [-commit]")
+		return 1
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Gagal memuat konfigurasi: %v\n", err)
+		return 1
+	}
+	db, pool, err := setupDatabase(cfg.DBDSN)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Gagal terhubung ke database: %v\n", err)
+		return 1
+	}
+	repos, svcs, _ := setupDependencies(db, pool, cfg)
+
+	actor, err := repos.UserRepo.FindByNRP(*nrp)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Pengguna dengan NRP %s tidak ditemukan\n", *nrp)
+		return 1
+	}
+	data, err := os.ReadFile(*filePath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Gagal membaca file: %v\n", err)
+		return 1
+	}
+
+	report, err := svcs.ImportService.Import(filepath.Base(*filePath), data, actor.ID, *commit)
+	if report != nil {
+		printImportReport(os.Stdout, report)
+	}
+	switch {
+	case errors.Is(err, services.ErrImportHasErrors):
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	case err != nil:
+		fmt.Fprintf(os.Stderr, "Impor gagal: %v\n", err)
+		return 1
+	case len(report.Errors) > 0:
+		return 2
+	}
+	return 0
+}
+
+// printImportReport menuliskan laporan impor dalam bentuk teks untuk terminal.
+func printImportReport(w io.Writer, report *dto.ImportReport) {
+	fmt.Fprintf(w, "File          : %s\n", report.FileName)
+	fmt.Fprintf(w, "Jumlah baris  : %d\n", report.TotalRows)
+	fmt.Fprintf(w, "Baris valid   : %d\n", report.ValidRows)
+	if report.DryRun {
+		fmt.Fprintln(w, "Mode          : pemeriksaan (tidak ada data yang disimpan)")
+	} else {
+		fmt.Fprintf(w, "Tersimpan     : %d\n", report.Imported)
+	}
+
+	fmt.Fprintln(w, "\nPemetaan kolom:")
+	for _, column := range report.Columns {
+		fmt.Fprintf(w, "  %-30s -> %s\n", column.Header, column.Field)
+	}
+	if len(report.IgnoredColumns) > 0 {
+		fmt.Fprintf(w, "Kolom diabaikan: %s\n", strings.Join(report.IgnoredColumns, ", "))
+	}
+
+	if len(report.Errors) > 0 {
+		fmt.Fprintf(w, "\n%d baris tidak valid:\n", len(report.Errors))
+		for _, rowErr := range report.Errors {
+			label := rowErr.NomorSurat
+			if label == "" {
+				label = "-"
+			}
+			fmt.Fprintf(w, "  Baris %d (%s): %s\n", rowErr.Row, label, strings.Join(rowErr.Messages, "; "))
+		}
+	}
+}
@@ -12,17 +12,22 @@
 package main
 
 import (
+	"context"
+	"encoding/base64"
 	"errors"
 	"fmt"
 	"html/template"
 	"io/ioutil"
 	"log"
 	"net/http"
+	"os"
 	"os/exec"
 	"runtime"
 	"simdokpol/internal/config"
 	"simdokpol/internal/controllers"
+	"simdokpol/internal/database"
 	"simdokpol/internal/middleware"
+	"simdokpol/internal/models"
 	"simdokpol/internal/repositories"
 	"simdokpol/internal/services"
 	"strconv"
@@ -35,9 +40,6 @@ import (
 	"github.com/gen2brain/beeep"
 	"github.com/getlantern/systray"
 	"github.com/gin-gonic/gin"
-	"github.com/golang-migrate/migrate/v4"
-	"github.com/golang-migrate/migrate/v4/database/sqlite"
-	_ "github.com/golang-migrate/migrate/v4/source/file"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 	gormsqlite "gorm.io/driver/sqlite"
@@ -50,8 +52,12 @@ const (
 	url  = "http://localhost:8080"
 )
 
-// main sekarang menjadi entrypoint untuk aplikasi system tray.
+// main sekarang menjadi entrypoint untuk aplikasi system tray. Subperintah "import" menjalankan
+// impor surat lama tanpa membuka tray maupun server web.
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "import" {
+		os.Exit(runImportCommand(os.Args[2:]))
+	}
 	systray.Run(onReady, onExit)
 }
 
@@ -110,12 +116,16 @@ func startWebServer() {
 		log.Fatalf("FATAL: Gagal memuat konfigurasi: %v", err)
 	}
 
-	db, err := setupDatabase(cfg.DBDSN)
+	db, pool, err := setupDatabase(cfg.DBDSN)
 	if err != nil {
 		log.Fatalf("FATAL: Gagal terhubung ke database: %v", err)
 	}
 
-	repos, svcs, ctrls := setupDependencies(db, cfg)
+	repos, svcs, ctrls := setupDependencies(db, pool, cfg)
+	go svcs.ArchiveService.Start(context.Background(), services.DefaultArchiveInterval)
+	go svcs.RecycleBinService.Start(context.Background(), services.DefaultRecycleBinPurgeInterval)
+	go svcs.BackupService.Start(context.Background(), services.DefaultBackupScheduleTick)
+	go svcs.BackupBrowserService.Start(context.Background(), services.DefaultBackupSessionSweep)
 	router := setupRouter(repos.UserRepo, svcs, ctrls)
 
 	log.Printf("INFO: Server web dimulai di %s", url)
@@ -174,44 +184,55 @@ func openBrowser(url string) {
 // @name Authorization
 // @description Masukkan token JWT Anda dengan format 'Bearer {token}'.
 
-func setupDatabase(dsn string) (*gorm.DB, error) {
-	db, err := gorm.Open(gormsqlite.Open(dsn), &gorm.Config{
+func setupDatabase(dsn string) (*gorm.DB, *database.Pool, error) {
+	// Koneksi dibungkus database.Pool agar dapat ditukar saat database dipulihkan dari backup.
+	pool, err := database.NewPool(dsn)
+	if err != nil {
+		return nil, nil, err
+	}
+	db, err := gorm.Open(gormsqlite.New(gormsqlite.Config{Conn: pool}), &gorm.Config{
 		Logger: logger.Default.LogMode(logger.Info),
 	})
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	sqlDB, err := db.DB()
-	if err != nil {
-		return nil, err
+	// Indeks pencarian dokumen memakai FTS5, yang hanya ikut terkompilasi bila biner dibangun dengan -tags sqlite_fts5.
+	var hasFTS5 bool
+	if err := db.Raw("SELECT sqlite_compileoption_used('ENABLE_FTS5')").Scan(&hasFTS5).Error; err != nil {
+		return nil, nil, err
 	}
-
-	driver, err := sqlite.WithInstance(sqlDB, &sqlite.Config{})
-	if err != nil {
-		return nil, err
+	if !hasFTS5 {
+		return nil, nil, errors.New("SQLite tidak mendukung FTS5; bangun ulang aplikasi dengan 'go build -tags sqlite_fts5'")
 	}
 
-	m, err := migrate.NewWithDatabaseInstance("file://migrations", "sqlite", driver)
+	sqlDB, err := db.DB()
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	if err := m.Up(); err != nil && err != migrate.ErrNoChange {
-		return nil, err
+	if err := database.Migrate(sqlDB, database.MigrationsSource); err != nil {
+		return nil, nil, err
 	}
 
 	log.Println("INFO: Migrasi database berhasil dijalankan.")
-	return db, nil
+	return db, pool, nil
 }
 
-func setupDependencies(db *gorm.DB, cfg *config.Config) (Repositories, Services, Controllers) {
+func setupDependencies(db *gorm.DB, pool *database.Pool, cfg *config.Config) (Repositories, Services, Controllers) {
 	// Repositories
 	userRepo := repositories.NewUserRepository(db)
 	residentRepo := repositories.NewResidentRepository(db)
 	docRepo := repositories.NewLostDocumentRepository(db)
 	configRepo := repositories.NewConfigRepository(db)
 	auditRepo := repositories.NewAuditLogRepository(db)
+	seqRepo := repositories.NewDocumentSequenceRepository(db)
+	revisionRepo := repositories.NewDocumentRevisionRepository(db)
+	docTypeRepo := repositories.NewDocumentTypeRepository(db)
+	itemTypeRepo := repositories.NewItemTypeRepository(db)
+	roleRepo := repositories.NewRoleRepository(db)
+	reportRepo := repositories.NewReportRepository(db)
+	recycleBinRepo := repositories.NewRecycleBinRepository(db)
 
 	// Services
 	services.JWTSecretKey = []byte(cfg.JWTSecretKey)
@@ -219,31 +240,68 @@ func setupDependencies(db *gorm.DB, cfg *config.Config) (Repositories, Services,
 	auditService := services.NewAuditLogService(auditRepo)
 	authService := services.NewAuthService(userRepo)
 	dashboardService := services.NewDashboardService(docRepo, userRepo, configService)
-	docService := services.NewLostDocumentService(db, docRepo, residentRepo, userRepo, auditService, configService)
-	userService := services.NewUserService(userRepo, auditService, cfg)
-	backupService := services.NewBackupService(cfg, configService, auditService)
+	numberingService := services.NewDocumentNumberingService(seqRepo, docTypeRepo, configService)
+	revisionService := services.NewDocumentRevisionService(revisionRepo, userRepo)
+	docTypeService := services.NewDocumentTypeService(docTypeRepo, configService, auditService)
+	itemTypeService := services.NewItemTypeService(itemTypeRepo, auditService)
+	docService := services.NewLostDocumentService(db, docRepo, residentRepo, userRepo, auditService, configService, numberingService, revisionService, docTypeService, itemTypeService)
+	userService := services.NewUserService(userRepo, roleRepo, auditService, cfg)
+	roleService := services.NewRoleService(roleRepo, auditService)
+	backupService := services.NewBackupService(db, pool, cfg, configService, auditService)
+	backupBrowserService := services.NewBackupBrowserService(db, cfg, configService, residentRepo, seqRepo, revisionService, auditService)
+	pdfService := services.NewPDFService(configService)
+	verificationService := services.NewVerificationService(docRepo, configService)
+	residentService := services.NewResidentService(db, residentRepo, auditService)
+	archiveService := services.NewArchiveService(docRepo, configService, auditService)
+	recycleBinService := services.NewRecycleBinService(recycleBinRepo, configService, auditService)
+	reportService := services.NewReportService(reportRepo, userRepo, configService)
+	importService := services.NewDocumentImportService(db, docRepo, residentRepo, userRepo, seqRepo, docTypeService, itemTypeService, revisionService, auditRepo, configService)
 
 	// Controllers
 	authController := controllers.NewAuthController(authService)
 	dashboardController := controllers.NewDashboardController(dashboardService)
-	docController := controllers.NewLostDocumentController(docService)
+	docController := controllers.NewLostDocumentController(docService, docTypeService, pdfService, verificationService, configService)
 	userController := controllers.NewUserController(userService)
-	configController := controllers.NewConfigController(configService, userService)
-	auditController := controllers.NewAuditLogController(auditService)
+	configController := controllers.NewConfigController(configService, userService, numberingService)
+	auditController := controllers.NewAuditLogController(auditService, configService)
 	backupController := controllers.NewBackupController(backupService)
-	settingsController := controllers.NewSettingsController(configService, auditService)
+	backupBrowserController := controllers.NewBackupBrowserController(backupBrowserService, configService)
+	settingsController := controllers.NewSettingsController(configService, auditService, numberingService)
+	verificationController := controllers.NewVerificationController(verificationService)
+	numberingController := controllers.NewNumberingController(numberingService)
+	residentController := controllers.NewResidentController(residentService)
+	revisionController := controllers.NewDocumentRevisionController(docService, revisionService)
+	archiveController := controllers.NewArchiveController(archiveService)
+	recycleBinController := controllers.NewRecycleBinController(recycleBinService)
+	docTypeController := controllers.NewDocumentTypeController(docTypeService)
+	itemTypeController := controllers.NewItemTypeController(itemTypeService)
+	roleController := controllers.NewRoleController(roleService)
+	reportController := controllers.NewReportController(reportService, pdfService, configService)
+	importController := controllers.NewImportController(importService)
 
 	return Repositories{UserRepo: userRepo},
-		Services{ConfigService: configService, DocService: docService},
+		Services{ConfigService: configService, DocService: docService, VerificationService: verificationService, ArchiveService: archiveService, RecycleBinService: recycleBinService, BackupService: backupService, BackupBrowserService: backupBrowserService, DocTypeService: docTypeService, ImportService: importService},
 		Controllers{
-			AuthController:      authController,
-			DashboardController: dashboardController,
-			DocController:       docController,
-			UserController:      userController,
-			ConfigController:    configController,
-			AuditController:     auditController,
-			BackupController:    backupController,
-			SettingsController:  settingsController,
+			AuthController:          authController,
+			DashboardController:     dashboardController,
+			DocController:           docController,
+			UserController:          userController,
+			ConfigController:        configController,
+			AuditController:         auditController,
+			BackupController:        backupController,
+			SettingsController:      settingsController,
+			VerificationController:  verificationController,
+			NumberingController:     numberingController,
+			ResidentController:      residentController,
+			RevisionController:      revisionController,
+			ArchiveController:       archiveController,
+			DocTypeController:       docTypeController,
+			ItemTypeController:      itemTypeController,
+			RoleController:          roleController,
+			ReportController:        reportController,
+			ImportController:        importController,
+			RecycleBinController:    recycleBinController,
+			BackupBrowserController: backupBrowserController,
 		}
 }
 
@@ -267,6 +325,7 @@ func setupRouter(userRepo repositories.UserRepository, svcs Services, ctrls Cont
 		app.GET("/login", func(c *gin.Context) { c.HTML(http.StatusOK, "login.html", gin.H{"Title": "Login"}) })
 		app.POST("/api/login", ctrls.AuthController.Login)
 		app.POST("/api/logout", ctrls.AuthController.Logout)
+		app.GET("/verify/:token", ctrls.VerificationController.ShowVerifyPage)
 
 		protected := app.Group("")
 		protected.Use(middleware.AuthMiddleware(userRepo))
@@ -287,11 +346,18 @@ func setupPageRoutes(router *gin.RouterGroup, svcs Services) {
 	router.GET("/", func(c *gin.Context) { c.HTML(http.StatusOK, "dashboard.html", gin.H{"Title": "Dasbor", "CurrentUser": getUser(c)}) })
 	router.GET("/documents", func(c *gin.Context) { c.HTML(http.StatusOK, "document_list.html", gin.H{"Title": "Daftar Dokumen Aktif", "CurrentUser": getUser(c), "PageType": "active"}) })
 	router.GET("/documents/archived", func(c *gin.Context) { c.HTML(http.StatusOK, "document_list.html", gin.H{"Title": "Arsip Dokumen", "CurrentUser": getUser(c), "PageType": "archived"}) })
-	router.GET("/documents/new", func(c *gin.Context) { c.HTML(http.StatusOK, "document_form.html", gin.H{"Title": "Buat Surat Baru", "CurrentUser": getUser(c), "IsEdit": false, "DocID": 0}) })
-	router.GET("/documents/:id/edit", func(c *gin.Context) { id := c.Param("id"); c.HTML(http.StatusOK, "document_form.html", gin.H{"Title": "Edit Surat", "CurrentUser": getUser(c), "IsEdit": true, "DocID": id}) })
+	router.GET("/documents/drafts", func(c *gin.Context) { c.HTML(http.StatusOK, "document_list.html", gin.H{"Title": "Draf Dokumen", "CurrentUser": getUser(c), "PageType": "draft"}) })
+	router.GET("/documents/revoked", func(c *gin.Context) { c.HTML(http.StatusOK, "document_list.html", gin.H{"Title": "Dokumen Dicabut", "CurrentUser": getUser(c), "PageType": "revoked"}) })
+	router.GET("/approvals", func(c *gin.Context) { c.HTML(http.StatusOK, "document_list.html", gin.H{"Title": "Persetujuan Dokumen", "CurrentUser": getUser(c), "PageType": "approvals"}) })
+	router.GET("/documents/import", middleware.RequirePermission(models.PermDocumentImport), func(c *gin.Context) { c.HTML(http.StatusOK, "document_import.html", gin.H{"Title": "Impor Surat Lama", "CurrentUser": getUser(c)}) })
+	router.GET("/documents/new", middleware.RequirePermission(models.PermDocumentCreate), func(c *gin.Context) { c.HTML(http.StatusOK, "document_form.html", gin.H{"Title": "Buat Surat Baru", "CurrentUser": getUser(c), "IsEdit": false, "DocID": 0}) })
+	router.GET("/documents/:id/edit", middleware.RequirePermission(models.PermDocumentCreate), func(c *gin.Context) { id := c.Param("id"); c.HTML(http.StatusOK, "document_form.html", gin.H{"Title": "Edit Surat", "CurrentUser": getUser(c), "IsEdit": true, "DocID": id}) })
+	router.GET("/documents/:id/revisions", func(c *gin.Context) { id := c.Param("id"); c.HTML(http.StatusOK, "document_revisions.html", gin.H{"Title": "Riwayat Revisi Surat", "CurrentUser": getUser(c), "DocID": id}) })
 	router.GET("/search", func(c *gin.Context) { query := c.Query("q"); c.HTML(http.StatusOK, "search_results.html", gin.H{"Title": "Hasil Pencarian", "CurrentUser": getUser(c), "Query": query}) })
 	router.GET("/profile", func(c *gin.Context) { c.HTML(http.StatusOK, "profile.html", gin.H{"Title": "Profil Pengguna", "CurrentUser": getUser(c)}) })
 	router.GET("/panduan", func(c *gin.Context) { c.HTML(http.StatusOK, "panduan.html", gin.H{"Title": "Panduan Pengguna", "CurrentUser": getUser(c)}) })
+	router.GET("/reports/statistics", middleware.RequirePermission(models.PermReportView), func(c *gin.Context) { c.HTML(http.StatusOK, "statistics_report.html", gin.H{"Title": "Laporan Statistik", "CurrentUser": getUser(c)}) })
+	router.GET("/reports/handover", middleware.RequirePermission(models.PermReportHandover), func(c *gin.Context) { c.HTML(http.StatusOK, "handover_report.html", gin.H{"Title": "Laporan Serah Terima Jaga", "CurrentUser": getUser(c)}) })
 	router.GET("/tentang", func(c *gin.Context) { c.HTML(http.StatusOK, "tentang.html", gin.H{"Title": "Tentang Aplikasi", "CurrentUser": getUser(c)}) })
 	
 	router.GET("/documents/:id/print", func(c *gin.Context) {
@@ -311,23 +377,46 @@ func setupPageRoutes(router *gin.RouterGroup, svcs Services) {
 			c.HTML(status, "error.html", gin.H{"Title": "Error", "CurrentUser": getUser(c), "ErrorMessage": message})
 			return
 		}
+		if !services.IsPrintable(doc) {
+			c.HTML(http.StatusConflict, "error.html", gin.H{"Title": "Error", "CurrentUser": getUser(c), "ErrorMessage": "Dokumen belum diterbitkan atau sudah dicabut sehingga tidak dapat dicetak."})
+			return
+		}
 		appConfig, err := svcs.ConfigService.GetConfig()
 		if err != nil {
 			c.HTML(http.StatusInternalServerError, "error.html", gin.H{"Title": "Error", "CurrentUser": getUser(c), "ErrorMessage": "Gagal memuat konfigurasi aplikasi."})
 			return
 		}
-		c.HTML(http.StatusOK, "print_preview.html", gin.H{"Document": doc, "Now": time.Now(), "CurrentUser": getUser(c), "Config": appConfig})
+		docType, err := svcs.DocTypeService.FindByCode(doc.JenisDokumen)
+		if err != nil {
+			c.HTML(http.StatusInternalServerError, "error.html", gin.H{"Title": "Error", "CurrentUser": getUser(c), "ErrorMessage": "Gagal memuat jenis dokumen."})
+			return
+		}
+		var verificationQR template.URL
+		// QR code hanya dicetak bila URL publik verifikasi sudah diatur di Pengaturan.
+		if verificationURL, err := svcs.VerificationService.VerificationURL(doc); err == nil {
+			if png, err := svcs.VerificationService.QRCodePNG(verificationURL); err == nil {
+				verificationQR = template.URL("data:image/png;base64," + base64.StdEncoding.EncodeToString(png))
+			}
+		}
+		c.HTML(http.StatusOK, docType.PrintTemplate, gin.H{"Document": doc, "DocumentType": docType, "Now": time.Now(), "CurrentUser": getUser(c), "Config": appConfig, "VerificationQR": verificationQR})
 	})
 	
-	adminRoutes := router.Group("")
-	adminRoutes.Use(middleware.AdminAuthMiddleware())
+	userPages := router.Group("")
+	userPages.Use(middleware.RequirePermission(models.PermUserManage))
 	{
-		adminRoutes.GET("/users", func(c *gin.Context) { c.HTML(http.StatusOK, "user_list.html", gin.H{"Title": "Manajemen Pengguna", "CurrentUser": getUser(c)}) })
-		adminRoutes.GET("/users/new", func(c *gin.Context) { c.HTML(http.StatusOK, "user_form.html", gin.H{"Title": "Tambah Pengguna", "CurrentUser": getUser(c), "IsEdit": false, "UserID": 0}) })
-		adminRoutes.GET("/users/:id/edit", func(c *gin.Context) { id, _ := strconv.Atoi(c.Param("id")); c.HTML(http.StatusOK, "user_form.html", gin.H{"Title": "Edit Pengguna", "CurrentUser": getUser(c), "IsEdit": true, "UserID": id}) })
-		adminRoutes.GET("/audit-logs", func(c *gin.Context) { c.HTML(http.StatusOK, "audit_log_list.html", gin.H{"Title": "Log Audit Sistem", "CurrentUser": getUser(c)}) })
-		adminRoutes.GET("/settings", func(c *gin.Context) { c.HTML(http.StatusOK, "settings.html", gin.H{"Title": "Pengaturan Sistem", "CurrentUser": getUser(c)}) })
+		userPages.GET("/users", func(c *gin.Context) { c.HTML(http.StatusOK, "user_list.html", gin.H{"Title": "Manajemen Pengguna", "CurrentUser": getUser(c)}) })
+		userPages.GET("/users/new", func(c *gin.Context) { c.HTML(http.StatusOK, "user_form.html", gin.H{"Title": "Tambah Pengguna", "CurrentUser": getUser(c), "IsEdit": false, "UserID": 0}) })
+		userPages.GET("/users/:id/edit", func(c *gin.Context) { id, _ := strconv.Atoi(c.Param("id")); c.HTML(http.StatusOK, "user_form.html", gin.H{"Title": "Edit Pengguna", "CurrentUser": getUser(c), "IsEdit": true, "UserID": id}) })
+		userPages.GET("/roles", func(c *gin.Context) { c.HTML(http.StatusOK, "roles.html", gin.H{"Title": "Peran & Hak Akses", "CurrentUser": getUser(c)}) })
 	}
+	router.GET("/audit-logs", middleware.RequirePermission(models.PermAuditView), func(c *gin.Context) { c.HTML(http.StatusOK, "audit_log_list.html", gin.H{"Title": "Log Audit Sistem", "CurrentUser": getUser(c)}) })
+	router.GET("/settings", middleware.RequirePermission(models.PermSettingsEdit), func(c *gin.Context) { c.HTML(http.StatusOK, "settings.html", gin.H{"Title": "Pengaturan Sistem", "CurrentUser": getUser(c)}) })
+	router.GET("/residents/duplicates", middleware.RequirePermission(models.PermResidentMerge), func(c *gin.Context) { c.HTML(http.StatusOK, "resident_merge.html", gin.H{"Title": "Gabungkan Data Penduduk", "CurrentUser": getUser(c)}) })
+	router.GET("/document-types", middleware.RequirePermission(models.PermDocumentTypeManage), func(c *gin.Context) { c.HTML(http.StatusOK, "document_types.html", gin.H{"Title": "Jenis Dokumen", "CurrentUser": getUser(c)}) })
+	router.GET("/documents/duplicates", middleware.RequirePermission(models.PermDocumentCrossCheck), func(c *gin.Context) { c.HTML(http.StatusOK, "document_duplicates.html", gin.H{"Title": "Laporan Berulang", "CurrentUser": getUser(c)}) })
+	router.GET("/item-types", middleware.RequirePermission(models.PermItemTypeManage), func(c *gin.Context) { c.HTML(http.StatusOK, "item_types.html", gin.H{"Title": "Katalog Barang", "CurrentUser": getUser(c)}) })
+	router.GET("/recycle-bin", middleware.RequirePermission(models.PermRecycleBinManage), func(c *gin.Context) { c.HTML(http.StatusOK, "recycle_bin.html", gin.H{"Title": "Tong Sampah", "CurrentUser": getUser(c)}) })
+	router.GET("/backups/browse", middleware.RequirePermission(models.PermBackupRestore), func(c *gin.Context) { c.HTML(http.StatusOK, "backup_browser.html", gin.H{"Title": "Jelajahi Backup", "CurrentUser": getUser(c)}) })
 }
 
 func setupAPIRoutes(router *gin.RouterGroup, ctrls Controllers) {
@@ -340,28 +429,86 @@ func setupAPIRoutes(router *gin.RouterGroup, ctrls Controllers) {
 		api.PUT("/profile", ctrls.UserController.UpdateProfile)
 		api.PUT("/profile/password", ctrls.UserController.ChangePassword)
 		api.GET("/search", ctrls.DocController.SearchGlobal)
-		api.POST("/documents", ctrls.DocController.Create)
+		// Daftar petugas juga dipakai filter operator di halaman daftar dokumen, termasuk oleh peran hanya-baca.
+		api.GET("/users/operators", ctrls.UserController.FindOperators)
 		api.GET("/documents", ctrls.DocController.FindAll)
+		api.GET("/documents/export", ctrls.DocController.Export)
 		api.GET("/documents/:id", ctrls.DocController.FindByID)
-		api.PUT("/documents/:id", ctrls.DocController.Update)
-		api.DELETE("/documents/:id", ctrls.DocController.Delete)
-		
-		adminAPI := api.Group("")
-		adminAPI.Use(middleware.AdminAuthMiddleware())
+		api.GET("/documents/:id/pdf", ctrls.DocController.DownloadPDF)
+		api.POST("/documents/:id/approve", ctrls.DocController.Approve)
+		api.POST("/documents/:id/reject", ctrls.DocController.Reject)
+		api.POST("/documents/:id/revoke", ctrls.DocController.Revoke)
+		api.GET("/approvals", ctrls.DocController.ApprovalQueue)
+		api.GET("/documents/:id/revisions", ctrls.RevisionController.ListRevisions)
+		api.GET("/documents/:id/revisions/diff", ctrls.RevisionController.DiffRevisions)
+		api.GET("/documents/:id/revisions/:revision", ctrls.RevisionController.GetRevision)
+		api.GET("/numbering/preview", ctrls.NumberingController.PreviewNextNumber)
+		// Data penduduk memuat data pribadi (NIK, alamat), sehingga hanya dibuka bagi pengisi formulir surat.
+		api.GET("/residents", middleware.RequirePermission(models.PermDocumentCreate), ctrls.ResidentController.Search)
+		api.GET("/residents/:id", middleware.RequirePermission(models.PermDocumentCreate), ctrls.ResidentController.FindByID)
+		api.GET("/document-types", ctrls.DocTypeController.FindAll)
+		api.GET("/document-types/:kode", ctrls.DocTypeController.FindByCode)
+		api.GET("/item-types", ctrls.ItemTypeController.FindAll)
+		api.GET("/item-types/:kode", ctrls.ItemTypeController.FindByCode)
+		api.GET("/reports/handover", middleware.RequirePermission(models.PermReportHandover), ctrls.ReportController.Handover)
+		api.GET("/reports/handover/pdf", middleware.RequirePermission(models.PermReportHandover), ctrls.ReportController.HandoverPDF)
+
+		// Kepemilikan dokumen tetap diperiksa di service; izin ini menutup akses tulis untuk peran hanya-baca.
+		docWriteAPI := api.Group("")
+		docWriteAPI.Use(middleware.RequirePermission(models.PermDocumentCreate))
 		{
-			adminAPI.GET("/users/operators", ctrls.UserController.FindOperators)
-			adminAPI.POST("/users", ctrls.UserController.Create)
-			adminAPI.GET("/users", ctrls.UserController.FindAll)
-			adminAPI.GET("/users/:id", ctrls.UserController.FindByID)
-			adminAPI.PUT("/users/:id", ctrls.UserController.Update)
-			adminAPI.DELETE("/users/:id", ctrls.UserController.Delete)
-			adminAPI.POST("/users/:id/activate", ctrls.UserController.Activate)
-			adminAPI.GET("/audit-logs", ctrls.AuditController.FindAll)
-			adminAPI.POST("/backups", ctrls.BackupController.CreateBackup)
-			adminAPI.POST("/restore", ctrls.BackupController.RestoreBackup)
-			adminAPI.GET("/settings", ctrls.SettingsController.GetSettings)
-			adminAPI.PUT("/settings", ctrls.SettingsController.UpdateSettings)
+			docWriteAPI.POST("/documents", ctrls.DocController.Create)
+			docWriteAPI.PUT("/documents/:id", ctrls.DocController.Update)
+			docWriteAPI.DELETE("/documents/:id", ctrls.DocController.Delete)
+			docWriteAPI.POST("/documents/:id/submit", ctrls.DocController.Submit)
 		}
+
+		userAPI := api.Group("")
+		userAPI.Use(middleware.RequirePermission(models.PermUserManage))
+		{
+			userAPI.POST("/users", ctrls.UserController.Create)
+			userAPI.GET("/users", ctrls.UserController.FindAll)
+			userAPI.GET("/users/:id", ctrls.UserController.FindByID)
+			userAPI.PUT("/users/:id", ctrls.UserController.Update)
+			userAPI.DELETE("/users/:id", ctrls.UserController.Delete)
+			userAPI.POST("/users/:id/activate", ctrls.UserController.Activate)
+			userAPI.GET("/roles", ctrls.RoleController.FindAll)
+			userAPI.GET("/permissions", ctrls.RoleController.Permissions)
+			userAPI.POST("/roles", ctrls.RoleController.Create)
+			userAPI.PUT("/roles/:kode", ctrls.RoleController.Update)
+			userAPI.DELETE("/roles/:kode", ctrls.RoleController.Delete)
+		}
+
+		api.GET("/audit-logs", middleware.RequirePermission(models.PermAuditView), ctrls.AuditController.FindAll)
+		api.GET("/numbering/gaps", middleware.RequirePermission(models.PermAuditView), ctrls.NumberingController.GetGapReport)
+		api.GET("/backups", middleware.RequirePermission(models.PermBackupRun), ctrls.BackupController.ListBackups)
+		api.POST("/backups", middleware.RequirePermission(models.PermBackupRun), ctrls.BackupController.CreateBackup)
+		api.POST("/restore", middleware.RequirePermission(models.PermBackupRestore), ctrls.BackupController.RestoreBackup)
+		api.POST("/backup-browser/sessions", middleware.RequirePermission(models.PermBackupRestore), ctrls.BackupBrowserController.OpenBackup)
+		api.DELETE("/backup-browser/sessions/:id", middleware.RequirePermission(models.PermBackupRestore), ctrls.BackupBrowserController.CloseBackup)
+		api.GET("/backup-browser/sessions/:id/documents", middleware.RequirePermission(models.PermBackupRestore), ctrls.BackupBrowserController.FindDocuments)
+		api.GET("/backup-browser/sessions/:id/documents/:docId", middleware.RequirePermission(models.PermBackupRestore), ctrls.BackupBrowserController.FindDocument)
+		api.GET("/backup-browser/sessions/:id/diff", middleware.RequirePermission(models.PermBackupRestore), ctrls.BackupBrowserController.Compare)
+		api.POST("/backup-browser/sessions/:id/recover", middleware.RequirePermission(models.PermBackupRestore), ctrls.BackupBrowserController.RecoverDocuments)
+		api.GET("/settings", middleware.RequirePermission(models.PermSettingsEdit), ctrls.SettingsController.GetSettings)
+		api.PUT("/settings", middleware.RequirePermission(models.PermSettingsEdit), ctrls.SettingsController.UpdateSettings)
+		api.GET("/residents/duplicates", middleware.RequirePermission(models.PermResidentMerge), ctrls.ResidentController.FindDuplicates)
+		api.POST("/residents/merge", middleware.RequirePermission(models.PermResidentMerge), ctrls.ResidentController.Merge)
+		api.POST("/documents/:id/revisions/:revision/restore", middleware.RequirePermission(models.PermDocumentRestore), ctrls.RevisionController.RestoreRevision)
+		api.GET("/archiver/status", middleware.RequirePermission(models.PermArchiveRun), ctrls.ArchiveController.GetStatus)
+		api.POST("/archiver/run", middleware.RequirePermission(models.PermArchiveRun), ctrls.ArchiveController.RunNow)
+		api.POST("/document-types", middleware.RequirePermission(models.PermDocumentTypeManage), ctrls.DocTypeController.Create)
+		api.PUT("/document-types/:kode", middleware.RequirePermission(models.PermDocumentTypeManage), ctrls.DocTypeController.Update)
+		api.POST("/item-types", middleware.RequirePermission(models.PermItemTypeManage), ctrls.ItemTypeController.Create)
+		api.PUT("/item-types/:kode", middleware.RequirePermission(models.PermItemTypeManage), ctrls.ItemTypeController.Update)
+		api.GET("/reports/statistics", middleware.RequirePermission(models.PermReportView), ctrls.ReportController.Statistics)
+		api.GET("/reports/statistics/export", middleware.RequirePermission(models.PermReportView), ctrls.ReportController.ExportStatistics)
+		api.POST("/documents/import", middleware.RequirePermission(models.PermDocumentImport), ctrls.ImportController.Import)
+		api.GET("/documents/identifier-duplicates", middleware.RequirePermission(models.PermDocumentCrossCheck), ctrls.DocController.IdentifierDuplicates)
+		api.GET("/documents/:id/identifier-matches", middleware.RequirePermission(models.PermDocumentCrossCheck), ctrls.DocController.IdentifierMatches)
+		api.GET("/recycle-bin/documents", middleware.RequirePermission(models.PermRecycleBinManage), ctrls.RecycleBinController.FindDeletedDocuments)
+		api.POST("/recycle-bin/documents/:id/restore", middleware.RequirePermission(models.PermRecycleBinManage), ctrls.RecycleBinController.RestoreDocument)
+		api.DELETE("/recycle-bin/documents/:id", middleware.RequirePermission(models.PermRecycleBinManage), ctrls.RecycleBinController.PurgeDocument)
 	}
 }
 
@@ -369,16 +516,35 @@ type Repositories struct {
 	UserRepo repositories.UserRepository
 }
 type Services struct {
-	ConfigService services.ConfigService
-	DocService    services.LostDocumentService
+	ConfigService        services.ConfigService
+	DocService           services.LostDocumentService
+	VerificationService  services.VerificationService
+	ArchiveService       services.ArchiveService
+	DocTypeService       services.DocumentTypeService
+	ImportService        services.DocumentImportService
+	RecycleBinService    services.RecycleBinService
+	BackupService        services.BackupService
+	BackupBrowserService services.BackupBrowserService
 }
 type Controllers struct {
-	AuthController      *controllers.AuthController
-	DashboardController *controllers.DashboardController
-	DocController       *controllers.LostDocumentController
-	UserController      *controllers.UserController
-	ConfigController    *controllers.ConfigController
-	AuditController     *controllers.AuditLogController
-	BackupController    *controllers.BackupController
-	SettingsController  *controllers.SettingsController
+	AuthController          *controllers.AuthController
+	DashboardController     *controllers.DashboardController
+	DocController           *controllers.LostDocumentController
+	UserController          *controllers.UserController
+	ConfigController        *controllers.ConfigController
+	AuditController         *controllers.AuditLogController
+	BackupController        *controllers.BackupController
+	SettingsController      *controllers.SettingsController
+	VerificationController  *controllers.VerificationController
+	NumberingController     *controllers.NumberingController
+	ResidentController      *controllers.ResidentController
+	RevisionController      *controllers.DocumentRevisionController
+	ArchiveController       *controllers.ArchiveController
+	DocTypeController       *controllers.DocumentTypeController
+	ItemTypeController      *controllers.ItemTypeController
+	RoleController          *controllers.RoleController
+	ReportController        *controllers.ReportController
+	ImportController        *controllers.ImportController
+	RecycleBinController    *controllers.RecycleBinController
+	BackupBrowserController *controllers.BackupBrowserController
 }
\ No newline at end of file
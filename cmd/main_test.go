@@ -0,0 +1,77 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"simdokpol/internal/controllers"
+	"simdokpol/internal/database"
+	"simdokpol/internal/mocks"
+	"simdokpol/internal/models"
+	"simdokpol/internal/repositories"
+	"simdokpol/internal/services"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	gormsqlite "gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// Laporan serah terima disusun oleh anggota jaga sendiri. Peran diambil dari database yang dimigrasi
+// penuh, sehingga test ini juga memastikan migrasi memberikan izin report.handover ke OPERATOR.
+func TestHandoverRoutesPermission(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db, err := gorm.Open(gormsqlite.Open(filepath.Join(t.TempDir(), "simdokpol.db")), &gorm.Config{Logger: logger.Discard})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	t.Cleanup(func() { sqlDB.Close() })
+	require.NoError(t, database.Migrate(sqlDB, "file://../migrations"))
+
+	require.NoError(t, db.Create(&models.Role{Kode: "TAMU", Nama: "Tamu", Permissions: []string{models.PermDocumentCreate}}).Error)
+	users := []*models.User{
+		{ID: 10, NamaLengkap: "BRIPDA OPERATOR", NRP: "10", KataSandi: "x", Peran: models.RoleOperator, Regu: "I"},
+		{ID: 11, NamaLengkap: "BRIPDA TAMU", NRP: "11", KataSandi: "x", Peran: "TAMU", Regu: "I"},
+	}
+	require.NoError(t, db.Create(users).Error)
+	userRepo := repositories.NewUserRepository(db)
+
+	configService := new(mocks.ConfigService)
+	configService.On("GetLocation").Return(time.UTC, nil).Maybe()
+	reportService := services.NewReportService(repositories.NewReportRepository(db), userRepo, configService)
+
+	testCases := []struct {
+		name           string
+		userID         uint
+		path           string
+		expectedStatus int
+	}{
+		{name: "Operator membuat laporan regunya sendiri", userID: 10, path: "/api/reports/handover", expectedStatus: http.StatusOK},
+		{name: "Operator ditolak untuk regu lain", userID: 10, path: "/api/reports/handover?regu=II", expectedStatus: http.StatusForbidden},
+		{name: "Peran tanpa izin serah terima ditolak", userID: 11, path: "/api/reports/handover", expectedStatus: http.StatusForbidden},
+		{name: "PDF peran tanpa izin serah terima ditolak", userID: 11, path: "/api/reports/handover/pdf", expectedStatus: http.StatusForbidden},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			currentUser, err := userRepo.FindByID(tc.userID)
+			require.NoError(t, err)
+
+			router := gin.New()
+			authenticated := router.Group("/", func(c *gin.Context) {
+				c.Set("currentUser", currentUser)
+				c.Set("userID", currentUser.ID)
+			})
+			setupAPIRoutes(authenticated, Controllers{ReportController: controllers.NewReportController(reportService, nil, configService)})
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tc.path, nil))
+
+			assert.Equal(t, tc.expectedStatus, w.Code, w.Body.String())
+		})
+	}
+}
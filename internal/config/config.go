@@ -1,6 +1,7 @@
 package config
 
 import (
+	"encoding/base64"
 	"log"
 	"os"
 	"time"
@@ -9,11 +10,22 @@ import (
 	"golang.org/x/crypto/bcrypt"
 )
 
+// AppVersion adalah versi aplikasi; dicatat di header file backup terenkripsi.
+const AppVersion = "1.0.0"
+
+// BackupKeySize adalah panjang kunci enkripsi backup (AES-256) dalam byte.
+const BackupKeySize = 32
+
 // Config menampung semua variabel konfigurasi aplikasi.
 type Config struct {
 	JWTSecretKey string
 	DBDSN        string
 	BcryptCost   int // Biaya bcrypt yang sudah dihitung
+
+	// BackupEncryptionKey dibaca dari BACKUP_ENCRYPTION_KEY (base64, 32 byte). Bila diisi, backup
+	// yang dibuat tanpa kata sandi, termasuk backup terjadwal, dienkripsi dengan kunci ini.
+	// Kunci disimpan di luar database agar tidak ikut tersalin ke dalam backup.
+	BackupEncryptionKey []byte
 }
 
 // determineBcryptCost menjalankan benchmark kecil untuk menemukan biaya bcrypt yang optimal.
@@ -59,6 +71,13 @@ func Load() (*Config, error) {
 	if cfg.DBDSN == "" {
 		log.Fatal("FATAL: DB_DSN tidak di-set di environment atau file .env")
 	}
+	if encoded := os.Getenv("BACKUP_ENCRYPTION_KEY"); encoded != "" {
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil || len(key) != BackupKeySize {
+			log.Fatal("FATAL: BACKUP_ENCRYPTION_KEY harus berisi 32 byte acak dalam format base64, misalnya hasil 'openssl rand -base64 32'")
+		}
+		cfg.BackupEncryptionKey = key
+	}
 
 	return cfg, nil
 }
\ No newline at end of file
@@ -0,0 +1,47 @@
+package controllers
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"simdokpol/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ArchiveController struct {
+	archiveService services.ArchiveService
+}
+
+func NewArchiveController(archiveService services.ArchiveService) *ArchiveController {
+	return &ArchiveController{archiveService: archiveService}
+}
+
+// @Summary Status Pengarsip Otomatis
+// @Description Menampilkan waktu dan hasil eksekusi terakhir pengarsip dokumen otomatis. Memerlukan izin archive.run.
+// @Tags Archive
+// @Produce json
+// @Success 200 {object} dto.ArchiveRunStatus
+// @Security BearerAuth
+// @Router /archiver/status [get]
+func (c *ArchiveController) GetStatus(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, c.archiveService.Status())
+}
+
+// @Summary Jalankan Pengarsipan Sekarang
+// @Description Mengarsipkan segera semua dokumen yang telah melewati durasi arsip tanpa menunggu jadwal. Memerlukan izin archive.run.
+// @Tags Archive
+// @Produce json
+// @Success 200 {object} map[string]interface{} "Pesan sukses dan status pengarsip"
+// @Failure 500 {object} map[string]string "Error: Gagal menjalankan pengarsipan"
+// @Security BearerAuth
+// @Router /archiver/run [post]
+func (c *ArchiveController) RunNow(ctx *gin.Context) {
+	status, err := c.archiveService.RunNow(ctx.GetUint("userID"))
+	if err != nil {
+		log.Printf("ERROR: Gagal menjalankan pengarsipan manual: %v", err)
+		APIError(ctx, http.StatusInternalServerError, "Gagal menjalankan pengarsipan.")
+		return
+	}
+	APIResponse(ctx, http.StatusOK, fmt.Sprintf("%d dokumen berhasil diarsipkan.", status.LastArchivedCount), status)
+}
@@ -4,32 +4,52 @@ import (
 	"log"
 	"net/http"
 	"simdokpol/internal/services"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
 type AuditLogController struct {
-	service services.AuditLogService
+	service       services.AuditLogService
+	configService services.ConfigService
 }
 
-func NewAuditLogController(service services.AuditLogService) *AuditLogController {
-	return &AuditLogController{service: service}
+func NewAuditLogController(service services.AuditLogService, configService services.ConfigService) *AuditLogController {
+	return &AuditLogController{service: service, configService: configService}
 }
 
-// @Summary Mendapatkan Semua Log Audit
-// @Description Mengambil seluruh riwayat aktivitas yang tercatat di sistem. Hanya bisa diakses oleh Super Admin.
+// @Summary Mendapatkan Daftar Log Audit
+// @Description Mengambil satu halaman riwayat aktivitas yang tercatat di sistem, terbaru lebih dulu, dengan pengurutan dan filter di server. Memerlukan izin audit.view.
 // @Tags Audit Log
 // @Produce json
-// @Success 200 {array} models.AuditLog
+// @Param page query int false "Nomor halaman, mulai dari 1" default(1)
+// @Param size query int false "Jumlah baris per halaman (maksimal 500)" default(25)
+// @Param sort query string false "Kolom pengurutan" enums(timestamp, user, aksi)
+// @Param dir query string false "Arah pengurutan" enums(asc, desc)
+// @Param q query string false "Kata kunci pada aksi, detail, atau nama pengguna"
+// @Param from query string false "Tanggal awal (YYYY-MM-DD)"
+// @Param to query string false "Tanggal akhir, inklusif (YYYY-MM-DD)"
+// @Param operator_id query int false "ID pengguna yang melakukan aksi"
+// @Success 200 {object} dto.PageResponse{data=[]models.AuditLog}
+// @Failure 400 {object} map[string]string "Error: Parameter tidak valid"
 // @Failure 500 {object} map[string]string "Error: Gagal mengambil data log audit"
 // @Security BearerAuth
 // @Router /audit-logs [get]
 func (c *AuditLogController) FindAll(ctx *gin.Context) {
-	logs, err := c.service.FindAll()
+	loc, err := c.configService.GetLocation()
+	if err != nil {
+		loc = time.UTC
+	}
+	list, err := ParseListQuery(ctx, loc)
+	if err != nil {
+		APIError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	logs, result, err := c.service.FindPage(list)
 	if err != nil {
 		log.Printf("ERROR: Gagal mengambil data log audit: %v", err)
 		APIError(ctx, http.StatusInternalServerError, "Gagal mengambil data log audit")
 		return
 	}
-	ctx.JSON(http.StatusOK, logs)
-}
\ No newline at end of file
+	APIPage(ctx, list, result, logs)
+}
@@ -0,0 +1,228 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"simdokpol/internal/dto"
+	"simdokpol/internal/services"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type BackupBrowserController struct {
+	browserService services.BackupBrowserService
+	configService  services.ConfigService
+}
+
+func NewBackupBrowserController(browserService services.BackupBrowserService, configService services.ConfigService) *BackupBrowserController {
+	return &BackupBrowserController{browserService: browserService, configService: configService}
+}
+
+// RecoverDocumentsRequest adalah DTO untuk memulihkan dokumen terpilih dari backup yang sedang dibuka.
+type RecoverDocumentsRequest struct {
+	IDs []uint `json:"ids" binding:"required,min=1" example:"12,15"`
+}
+
+// @Summary Buka File Backup
+// @Description Membuka file backup hanya-baca untuk dijelajahi. Pilih file dari folder backup lewat file_name atau unggah file lewat backup-file. File .simdokpol-backup didekripsi memakai kata sandi yang diisi atau BACKUP_ENCRYPTION_KEY. File backup tidak diubah; salinannya dibuka di folder sementara dan ditutup otomatis setelah 30 menit tidak dipakai. Memerlukan izin backup.restore.
+// @Tags Backup & Restore
+// @Accept multipart/form-data
+// @Produce json
+// @Param file_name formData string false "Nama file di folder backup (dari GET /backups)"
+// @Param backup-file formData file false "File backup .db atau .simdokpol-backup yang diunggah"
+// @Param password formData string false "Kata sandi bila file backup dienkripsi dengan kata sandi"
+// @Success 201 {object} map[string]interface{} "Pesan sukses dan dto.BackupSession"
+// @Failure 400 {object} map[string]string "Error: File bukan backup SIMDOKPOL yang utuh atau kata sandi salah"
+// @Failure 404 {object} map[string]string "Error: File tidak ada di folder backup"
+// @Failure 500 {object} map[string]string "Error: Gagal membuka file backup"
+// @Security BearerAuth
+// @Router /backup-browser/sessions [post]
+func (c *BackupBrowserController) OpenBackup(ctx *gin.Context) {
+	actorID := ctx.GetUint("userID")
+	password := ctx.PostForm("password")
+
+	var (
+		fileName string
+		err      error
+		session  *dto.BackupSession
+	)
+	if fileName = ctx.PostForm("file_name"); fileName != "" {
+		session, err = c.browserService.OpenStoredBackup(fileName, password, actorID)
+	} else {
+		file, formErr := ctx.FormFile("backup-file")
+		if formErr != nil {
+			APIError(ctx, http.StatusBadRequest, "Pilih file dari folder backup atau unggah file backup.")
+			return
+		}
+		src, openErr := file.Open()
+		if openErr != nil {
+			log.Printf("ERROR: Gagal membuka file backup yang diunggah: %v", openErr)
+			APIError(ctx, http.StatusInternalServerError, "Gagal memproses file yang diunggah.")
+			return
+		}
+		defer src.Close()
+		fileName = file.Filename
+		session, err = c.browserService.OpenUploadedBackup(fileName, src, password, actorID)
+	}
+
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrNotFound):
+			APIError(ctx, http.StatusNotFound, "File backup tidak ada di folder backup.")
+		case errors.Is(err, services.ErrBackupPasswordRequired):
+			APIError(ctx, http.StatusBadRequest, "File backup ini dienkripsi dengan kata sandi. Masukkan kata sandinya lalu coba lagi.")
+		case errors.Is(err, services.ErrBackupVerification) || errors.Is(err, services.ErrIncompatibleBackup) || errors.Is(err, services.ErrBackupDecryption):
+			APIError(ctx, http.StatusBadRequest, "File tidak dapat dibuka: "+err.Error())
+		default:
+			log.Printf("ERROR: Gagal membuka file backup %s oleh user id %d: %v", fileName, actorID, err)
+			APIError(ctx, http.StatusInternalServerError, "Gagal membuka file backup.")
+		}
+		return
+	}
+	APIResponse(ctx, http.StatusCreated, "File backup berhasil dibuka.", session)
+}
+
+// @Summary Tutup File Backup
+// @Description Menutup file backup yang sedang dijelajahi dan menghapus salinan sementaranya. Memerlukan izin backup.restore.
+// @Tags Backup & Restore
+// @Produce json
+// @Param id path string true "ID Sesi"
+// @Success 200 {object} map[string]string "Pesan Sukses"
+// @Failure 404 {object} map[string]string "Error: Sesi tidak ditemukan"
+// @Security BearerAuth
+// @Router /backup-browser/sessions/{id} [delete]
+func (c *BackupBrowserController) CloseBackup(ctx *gin.Context) {
+	if err := c.browserService.CloseBackup(ctx.Param("id")); err != nil {
+		c.handleError(ctx, err, "menutup file backup")
+		return
+	}
+	APIResponse(ctx, http.StatusOK, "File backup ditutup.", nil)
+}
+
+// @Summary Daftar Dokumen di Backup
+// @Description Mengambil satu halaman dokumen (semua status) di dalam file backup yang sedang dibuka, dengan pengurutan dan filter yang sama seperti daftar dokumen. Memerlukan izin backup.restore.
+// @Tags Backup & Restore
+// @Produce json
+// @Param id path string true "ID Sesi"
+// @Param page query int false "Nomor halaman, mulai dari 1" default(1)
+// @Param size query int false "Jumlah baris per halaman (maksimal 500)" default(25)
+// @Param sort query string false "Kolom pengurutan" enums(nomor_surat, jenis_dokumen, nama_pemohon, tanggal_laporan, status, operator)
+// @Param dir query string false "Arah pengurutan" enums(asc, desc)
+// @Param q query string false "Kata Kunci Pencarian (No. Surat / Nama)"
+// @Param from query string false "Tanggal laporan awal (YYYY-MM-DD)"
+// @Param to query string false "Tanggal laporan akhir, inklusif (YYYY-MM-DD)"
+// @Success 200 {object} dto.PageResponse{data=[]models.LostDocument}
+// @Failure 400 {object} map[string]string "Error: Parameter tidak valid"
+// @Failure 404 {object} map[string]string "Error: Sesi tidak ditemukan"
+// @Security BearerAuth
+// @Router /backup-browser/sessions/{id}/documents [get]
+func (c *BackupBrowserController) FindDocuments(ctx *gin.Context) {
+	loc, err := c.configService.GetLocation()
+	if err != nil {
+		loc = time.UTC
+	}
+	list, err := ParseListQuery(ctx, loc)
+	if err != nil {
+		APIError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	documents, result, err := c.browserService.FindDocuments(ctx.Param("id"), list)
+	if err != nil {
+		c.handleError(ctx, err, "membaca dokumen di backup")
+		return
+	}
+	APIPage(ctx, list, result, documents)
+}
+
+// @Summary Detail Dokumen di Backup
+// @Description Mengambil satu dokumen beserta pemohon dan barangnya dari file backup yang sedang dibuka. Memerlukan izin backup.restore.
+// @Tags Backup & Restore
+// @Produce json
+// @Param id path string true "ID Sesi"
+// @Param docId path int true "ID Dokumen di dalam backup"
+// @Success 200 {object} models.LostDocument
+// @Failure 400 {object} map[string]string "Error: ID tidak valid"
+// @Failure 404 {object} map[string]string "Error: Sesi atau dokumen tidak ditemukan"
+// @Security BearerAuth
+// @Router /backup-browser/sessions/{id}/documents/{docId} [get]
+func (c *BackupBrowserController) FindDocument(ctx *gin.Context) {
+	docID, err := strconv.ParseUint(ctx.Param("docId"), 10, 32)
+	if err != nil {
+		APIError(ctx, http.StatusBadRequest, "ID dokumen tidak valid")
+		return
+	}
+	doc, err := c.browserService.FindDocument(ctx.Param("id"), uint(docID))
+	if err != nil {
+		c.handleError(ctx, err, "membaca dokumen di backup")
+		return
+	}
+	ctx.JSON(http.StatusOK, doc)
+}
+
+// @Summary Bandingkan Backup dengan Database Saat Ini
+// @Description Menampilkan dokumen yang hilang (sudah dimusnahkan), masih di tong sampah, berubah, atau baru dibuat dibandingkan dengan file backup yang sedang dibuka. Memerlukan izin backup.restore.
+// @Tags Backup & Restore
+// @Produce json
+// @Param id path string true "ID Sesi"
+// @Success 200 {object} dto.BackupDiff
+// @Failure 404 {object} map[string]string "Error: Sesi tidak ditemukan"
+// @Failure 500 {object} map[string]string "Error: Gagal membandingkan backup"
+// @Security BearerAuth
+// @Router /backup-browser/sessions/{id}/diff [get]
+func (c *BackupBrowserController) Compare(ctx *gin.Context) {
+	diff, err := c.browserService.Compare(ctx.Param("id"))
+	if err != nil {
+		c.handleError(ctx, err, "membandingkan backup")
+		return
+	}
+	ctx.JSON(http.StatusOK, diff)
+}
+
+// @Summary Pulihkan Dokumen dari Backup
+// @Description Membuat kembali dokumen terpilih dari file backup yang sedang dibuka beserta pemohon, barang, dan riwayat revisinya. Dokumen yang masih ada di database saat ini (termasuk di tong sampah) dilewati. Bila Nomor Surat aslinya sudah dipakai dokumen lain, dokumen dipulihkan sebagai draf tanpa nomor. Memerlukan izin backup.restore.
+// @Tags Backup & Restore
+// @Accept json
+// @Produce json
+// @Param id path string true "ID Sesi"
+// @Param request body RecoverDocumentsRequest true "ID dokumen di dalam backup"
+// @Success 200 {object} map[string]interface{} "Pesan sukses dan dto.BackupRecoveryResult"
+// @Failure 400 {object} map[string]string "Error: Input tidak valid"
+// @Failure 404 {object} map[string]string "Error: Sesi tidak ditemukan"
+// @Failure 500 {object} map[string]string "Error: Gagal memulihkan dokumen"
+// @Security BearerAuth
+// @Router /backup-browser/sessions/{id}/recover [post]
+func (c *BackupBrowserController) RecoverDocuments(ctx *gin.Context) {
+	var req RecoverDocumentsRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		APIError(ctx, http.StatusBadRequest, "Pilih minimal satu dokumen untuk dipulihkan.")
+		return
+	}
+
+	result, err := c.browserService.RecoverDocuments(ctx.Param("id"), req.IDs, ctx.GetUint("userID"))
+	if err != nil {
+		c.handleError(ctx, err, "memulihkan dokumen dari backup")
+		return
+	}
+	message := fmt.Sprintf("%d dokumen berhasil dipulihkan.", len(result.Recovered))
+	if len(result.Skipped) > 0 {
+		message += fmt.Sprintf(" %d dokumen dilewati.", len(result.Skipped))
+	}
+	APIResponse(ctx, http.StatusOK, message, result)
+}
+
+func (c *BackupBrowserController) handleError(ctx *gin.Context, err error, aksi string) {
+	switch {
+	case errors.Is(err, services.ErrBackupSessionNotFound):
+		APIError(ctx, http.StatusNotFound, "File backup sudah ditutup atau kedaluwarsa. Buka kembali file backup-nya.")
+	case errors.Is(err, services.ErrNotFound):
+		APIError(ctx, http.StatusNotFound, "Dokumen tidak ada di dalam backup.")
+	default:
+		log.Printf("ERROR: Gagal %s: %v", aksi, err)
+		APIError(ctx, http.StatusInternalServerError, fmt.Sprintf("Gagal %s.", aksi))
+	}
+}
@@ -1,11 +1,12 @@
 package controllers
 
 import (
+	"errors"
+	"io"
 	"log"
 	"net/http"
 	"path/filepath"
 	"simdokpol/internal/services"
-	"strings"
 
 	"github.com/gin-gonic/gin"
 )
@@ -18,35 +19,80 @@ func NewBackupController(service services.BackupService) *BackupController {
 	return &BackupController{service: service}
 }
 
+// BackupRequest adalah isi opsional permintaan backup manual.
+type BackupRequest struct {
+	// Password mengenkripsi backup dengan kata sandi. Bila kosong, backup dienkripsi dengan
+	// BACKUP_ENCRYPTION_KEY jika diatur, atau disimpan sebagai file .db biasa.
+	Password string `json:"password"`
+}
+
 // @Summary Membuat Backup Database
-// @Description Membuat salinan database saat ini dan mengirimkannya sebagai file unduhan. Hanya bisa diakses oleh Super Admin.
+// @Description Membuat salinan database saat ini secara online (VACUUM INTO), memeriksa integritasnya, lalu mengirimkannya sebagai file unduhan. Bila kata sandi diisi atau BACKUP_ENCRYPTION_KEY diatur, file dienkripsi AES-256-GCM dalam format .simdokpol-backup. Checksum SHA-256 file dikirim di header X-Backup-SHA256 dan dicatat di manifest di samping file backup. Memerlukan izin backup.run.
 // @Tags Backup & Restore
+// @Accept json
 // @Produce application/octet-stream
-// @Success 200 {file} file "File backup database (.db)"
-// @Failure 500 {object} map[string]string "Error: Gagal memproses backup"
+// @Param request body BackupRequest false "Kata sandi enkripsi (opsional)"
+// @Success 200 {file} file "File backup database (.db atau .simdokpol-backup)"
+// @Header 200 {string} X-Backup-SHA256 "Checksum SHA-256 file backup"
+// @Failure 400 {object} map[string]string "Error: Kata sandi terlalu pendek"
+// @Failure 500 {object} map[string]string "Error: Gagal memproses backup atau verifikasi backup gagal"
 // @Security BearerAuth
 // @Router /backups [post]
 func (c *BackupController) CreateBackup(ctx *gin.Context) {
+	var req BackupRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		APIError(ctx, http.StatusBadRequest, "Format data tidak valid")
+		return
+	}
+
 	actorID := ctx.GetUint("userID")
-	backupPath, err := c.service.CreateBackup(actorID)
+	backupPath, manifest, err := c.service.CreateBackup(actorID, req.Password)
 	if err != nil {
+		if errors.Is(err, services.ErrWeakBackupPassword) {
+			APIError(ctx, http.StatusBadRequest, "Kata sandi backup minimal 8 karakter.")
+			return
+		}
 		log.Printf("ERROR: Gagal membuat backup oleh user id %d: %v", actorID, err)
+		if errors.Is(err, services.ErrBackupVerification) {
+			APIError(ctx, http.StatusInternalServerError, "Backup dibatalkan karena salinan database gagal diverifikasi: "+err.Error())
+			return
+		}
 		APIError(ctx, http.StatusInternalServerError, "Gagal memproses backup.")
 		return
 	}
 
 	// Mengirim file sebagai attachment untuk diunduh oleh browser.
+	ctx.Header("X-Backup-SHA256", manifest.SHA256)
 	ctx.FileAttachment(backupPath, filepath.Base(backupPath))
 }
 
+// @Summary Daftar File Backup
+// @Description Menampilkan file backup di folder backup beserta ukuran, waktu pembuatan, dan checksum SHA-256 dari manifest-nya, serta status backup terjadwal. Memerlukan izin backup.run.
+// @Tags Backup & Restore
+// @Produce json
+// @Success 200 {object} dto.BackupList
+// @Failure 500 {object} map[string]string "Error: Gagal membaca folder backup"
+// @Security BearerAuth
+// @Router /backups [get]
+func (c *BackupController) ListBackups(ctx *gin.Context) {
+	list, err := c.service.ListBackups()
+	if err != nil {
+		log.Printf("ERROR: Gagal memuat daftar backup: %v", err)
+		APIError(ctx, http.StatusInternalServerError, "Gagal membaca folder backup.")
+		return
+	}
+	ctx.JSON(http.StatusOK, list)
+}
+
 // @Summary Melakukan Restore Database
-// @Description Memulihkan database dari file .db yang diunggah. Semua data saat ini akan ditimpa. Hanya bisa diakses oleh Super Admin.
+// @Description Memulihkan database dari file backup yang diunggah. File .simdokpol-backup didekripsi otomatis memakai kata sandi yang diisi atau BACKUP_ENCRYPTION_KEY sesuai header-nya. File harus database SIMDOKPOL yang utuh dengan versi skema yang tidak lebih baru dari aplikasi; migrasi yang tertinggal dijalankan otomatis. Database lama disimpan sebagai file .before-restore-* dan dipasang kembali bila restore gagal. Semua data saat ini akan ditimpa. Memerlukan izin backup.restore.
 // @Tags Backup & Restore
 // @Accept multipart/form-data
 // @Produce json
-// @Param restore-file formData file true "File backup .db yang akan di-restore"
+// @Param restore-file formData file true "File backup .db atau .simdokpol-backup yang akan di-restore"
+// @Param password formData string false "Kata sandi bila file backup dienkripsi dengan kata sandi"
 // @Success 200 {object} map[string]string "Pesan Sukses"
-// @Failure 400 {object} map[string]string "Error: Input tidak valid"
+// @Failure 400 {object} map[string]string "Error: File bukan backup SIMDOKPOL yang utuh atau versi skemanya tidak didukung"
 // @Failure 500 {object} map[string]string "Error: Gagal memulihkan database"
 // @Security BearerAuth
 // @Router /restore [post]
@@ -57,11 +103,6 @@ func (c *BackupController) RestoreBackup(ctx *gin.Context) {
 		return
 	}
 
-	if !strings.HasSuffix(file.Filename, ".db") {
-		APIError(ctx, http.StatusBadRequest, "Format file tidak valid. Harap unggah file .db")
-		return
-	}
-
 	src, err := file.Open()
 	if err != nil {
 		log.Printf("ERROR: Gagal membuka file restore yang diunggah: %v", err)
@@ -71,9 +112,17 @@ func (c *BackupController) RestoreBackup(ctx *gin.Context) {
 	defer src.Close()
 
 	actorID := ctx.GetUint("userID")
-	if err := c.service.RestoreBackup(src, actorID); err != nil {
+	if err := c.service.RestoreBackup(src, ctx.PostForm("password"), actorID); err != nil {
 		log.Printf("ERROR: Gagal melakukan restore oleh user id %d: %v", actorID, err)
-		APIError(ctx, http.StatusInternalServerError, "Gagal memulihkan database.")
+		if errors.Is(err, services.ErrBackupPasswordRequired) {
+			APIError(ctx, http.StatusBadRequest, "File backup ini dienkripsi dengan kata sandi. Masukkan kata sandinya lalu coba lagi.")
+			return
+		}
+		if errors.Is(err, services.ErrBackupVerification) || errors.Is(err, services.ErrIncompatibleBackup) || errors.Is(err, services.ErrBackupDecryption) {
+			APIError(ctx, http.StatusBadRequest, "File tidak dapat dipulihkan, database saat ini tidak diubah: "+err.Error())
+			return
+		}
+		APIError(ctx, http.StatusInternalServerError, "Gagal memulihkan database: "+err.Error())
 		return
 	}
 
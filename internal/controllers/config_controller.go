@@ -5,19 +5,22 @@ import (
 	"net/http"
 	"simdokpol/internal/models"
 	"simdokpol/internal/services"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 )
 
 type ConfigController struct {
-	configService services.ConfigService
-	userService   services.UserService
+	configService    services.ConfigService
+	userService      services.UserService
+	numberingService services.DocumentNumberingService
 }
 
-func NewConfigController(configService services.ConfigService, userService services.UserService) *ConfigController {
+func NewConfigController(configService services.ConfigService, userService services.UserService, numberingService services.DocumentNumberingService) *ConfigController {
 	return &ConfigController{
-		configService: configService,
-		userService:   userService,
+		configService:    configService,
+		userService:      userService,
+		numberingService: numberingService,
 	}
 }
 
@@ -27,6 +30,7 @@ type SaveSetupRequest struct {
 	KopBaris3           string `json:"kop_baris_3" binding:"required"`
 	NamaKantor          string `json:"nama_kantor" binding:"required"`
 	TempatSurat         string `json:"tempat_surat" binding:"required"`
+	KodeKantor          string `json:"kode_kantor"`
 	FormatNomorSurat    string `json:"format_nomor_surat" binding:"required"`
 	NomorSuratTerakhir  string `json:"nomor_surat_terakhir" binding:"required"`
 	ZonaWaktu           string `json:"zona_waktu" binding:"required"`
@@ -50,12 +54,23 @@ func (c *ConfigController) SaveSetup(ctx *gin.Context) {
 		return
 	}
 
+	if n, err := strconv.Atoi(req.NomorSuratTerakhir); err != nil || n < 0 {
+		APIError(ctx, http.StatusBadRequest, "Nomor surat terakhir harus berupa angka.")
+		return
+	}
+
+	if err := c.numberingService.ValidateFormat(req.FormatNomorSurat, req.KodeKantor); err != nil {
+		APIError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
 	configData := map[string]string{
 		"kop_baris_1":           req.KopBaris1,
 		"kop_baris_2":           req.KopBaris2,
 		"kop_baris_3":           req.KopBaris3,
 		"nama_kantor":           req.NamaKantor,
 		"tempat_surat":          req.TempatSurat,
+		"kode_kantor":           req.KodeKantor,
 		"format_nomor_surat":    req.FormatNomorSurat,
 		"nomor_surat_terakhir":  req.NomorSuratTerakhir,
 		"zona_waktu":            req.ZonaWaktu,
@@ -69,6 +84,12 @@ func (c *ConfigController) SaveSetup(ctx *gin.Context) {
 		return
 	}
 
+	if err := c.numberingService.SyncLastNumber(req.NomorSuratTerakhir); err != nil {
+		log.Printf("ERROR: Gagal menerapkan nomor surat terakhir saat setup: %v", err)
+		APIError(ctx, http.StatusInternalServerError, "Gagal menerapkan nomor surat terakhir.")
+		return
+	}
+
 	superAdmin := &models.User{
 		NamaLengkap: req.AdminNamaLengkap,
 		NRP:         req.AdminNRP,
@@ -0,0 +1,185 @@
+package controllers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"simdokpol/internal/services"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type DocumentRevisionController struct {
+	docService      services.LostDocumentService
+	revisionService services.DocumentRevisionService
+}
+
+func NewDocumentRevisionController(docService services.LostDocumentService, revisionService services.DocumentRevisionService) *DocumentRevisionController {
+	return &DocumentRevisionController{
+		docService:      docService,
+		revisionService: revisionService,
+	}
+}
+
+// authorizeDocument memastikan dokumen ada dan boleh dilihat oleh pengguna yang login.
+// Mengembalikan ID dokumen, atau false jika respons error sudah dikirim.
+func (c *DocumentRevisionController) authorizeDocument(ctx *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		APIError(ctx, http.StatusBadRequest, "ID dokumen tidak valid")
+		return 0, false
+	}
+	if _, err := c.docService.FindByID(uint(id), ctx.GetUint("userID")); err != nil {
+		if errors.Is(err, services.ErrAccessDenied) {
+			APIError(ctx, http.StatusForbidden, "Akses ditolak: Anda tidak memiliki izin untuk melihat dokumen ini.")
+			return 0, false
+		}
+		APIError(ctx, http.StatusNotFound, "Dokumen tidak ditemukan")
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// @Summary Riwayat Revisi Dokumen
+// @Description Mengambil daftar revisi sebuah surat keterangan hilang, dari yang terbaru. Setiap penyimpanan dokumen menghasilkan satu revisi.
+// @Tags Documents
+// @Produce json
+// @Param id path int true "ID Dokumen"
+// @Success 200 {array} models.DocumentRevision
+// @Failure 403 {object} map[string]string "Error: Akses ditolak"
+// @Failure 404 {object} map[string]string "Error: Dokumen tidak ditemukan"
+// @Security BearerAuth
+// @Router /documents/{id}/revisions [get]
+func (c *DocumentRevisionController) ListRevisions(ctx *gin.Context) {
+	docID, ok := c.authorizeDocument(ctx)
+	if !ok {
+		return
+	}
+
+	revisions, err := c.revisionService.ListRevisions(docID)
+	if err != nil {
+		log.Printf("ERROR: Gagal mengambil riwayat revisi dokumen id %d: %v", docID, err)
+		APIError(ctx, http.StatusInternalServerError, "Gagal mengambil riwayat revisi dokumen.")
+		return
+	}
+	ctx.JSON(http.StatusOK, revisions)
+}
+
+// @Summary Detail Revisi Dokumen
+// @Description Mengambil isi lengkap dokumen pada satu revisi.
+// @Tags Documents
+// @Produce json
+// @Param id path int true "ID Dokumen"
+// @Param revision path int true "Nomor Revisi"
+// @Success 200 {object} dto.RevisionDetail
+// @Failure 400 {object} map[string]string "Error: Nomor revisi tidak valid"
+// @Failure 404 {object} map[string]string "Error: Revisi tidak ditemukan"
+// @Security BearerAuth
+// @Router /documents/{id}/revisions/{revision} [get]
+func (c *DocumentRevisionController) GetRevision(ctx *gin.Context) {
+	docID, ok := c.authorizeDocument(ctx)
+	if !ok {
+		return
+	}
+	revisionNumber, err := strconv.Atoi(ctx.Param("revision"))
+	if err != nil || revisionNumber < 1 {
+		APIError(ctx, http.StatusBadRequest, "Nomor revisi tidak valid")
+		return
+	}
+
+	revision, err := c.revisionService.GetRevision(docID, revisionNumber)
+	if err != nil {
+		if errors.Is(err, services.ErrNotFound) {
+			APIError(ctx, http.StatusNotFound, "Revisi tidak ditemukan")
+			return
+		}
+		log.Printf("ERROR: Gagal mengambil revisi %d dokumen id %d: %v", revisionNumber, docID, err)
+		APIError(ctx, http.StatusInternalServerError, "Gagal mengambil revisi dokumen.")
+		return
+	}
+	ctx.JSON(http.StatusOK, revision)
+}
+
+// @Summary Perbandingan Dua Revisi Dokumen
+// @Description Menampilkan field yang berbeda di antara dua revisi. Tanpa parameter, revisi terakhir dibandingkan dengan revisi sebelumnya.
+// @Tags Documents
+// @Produce json
+// @Param id path int true "ID Dokumen"
+// @Param from query int false "Nomor revisi awal (default: revisi sebelum 'to')"
+// @Param to query int false "Nomor revisi akhir (default: revisi terakhir)"
+// @Success 200 {object} dto.RevisionDiff
+// @Failure 400 {object} map[string]string "Error: Nomor revisi tidak valid"
+// @Failure 404 {object} map[string]string "Error: Revisi tidak ditemukan"
+// @Security BearerAuth
+// @Router /documents/{id}/revisions/diff [get]
+func (c *DocumentRevisionController) DiffRevisions(ctx *gin.Context) {
+	docID, ok := c.authorizeDocument(ctx)
+	if !ok {
+		return
+	}
+	from, errFrom := strconv.Atoi(ctx.DefaultQuery("from", "0"))
+	to, errTo := strconv.Atoi(ctx.DefaultQuery("to", "0"))
+	if errFrom != nil || errTo != nil || from < 0 || to < 0 {
+		APIError(ctx, http.StatusBadRequest, "Nomor revisi tidak valid")
+		return
+	}
+
+	diff, err := c.revisionService.Diff(docID, from, to)
+	if err != nil {
+		if errors.Is(err, services.ErrNotFound) {
+			APIError(ctx, http.StatusNotFound, "Revisi tidak ditemukan")
+			return
+		}
+		log.Printf("ERROR: Gagal membandingkan revisi dokumen id %d: %v", docID, err)
+		APIError(ctx, http.StatusInternalServerError, "Gagal membandingkan revisi dokumen.")
+		return
+	}
+	ctx.JSON(http.StatusOK, diff)
+}
+
+// @Summary Memulihkan Revisi Dokumen
+// @Description Mengembalikan isi dokumen ke revisi tertentu. Pemulihan dicatat sebagai revisi baru sehingga riwayat tidak hilang. Hanya untuk surat berstatus DRAF. Memerlukan izin document.restore_revision.
+// @Tags Documents
+// @Produce json
+// @Param id path int true "ID Dokumen"
+// @Param revision path int true "Nomor Revisi"
+// @Success 200 {object} models.LostDocument
+// @Failure 400 {object} map[string]string "Error: Nomor revisi tidak valid"
+// @Failure 403 {object} map[string]string "Error: Akses ditolak"
+// @Failure 404 {object} map[string]string "Error: Revisi tidak ditemukan"
+// @Failure 409 {object} map[string]string "Error: Surat bukan draf"
+// @Failure 500 {object} map[string]string "Error: Gagal memulihkan revisi"
+// @Security BearerAuth
+// @Router /documents/{id}/revisions/{revision}/restore [post]
+func (c *DocumentRevisionController) RestoreRevision(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		APIError(ctx, http.StatusBadRequest, "ID dokumen tidak valid")
+		return
+	}
+	revisionNumber, err := strconv.Atoi(ctx.Param("revision"))
+	if err != nil || revisionNumber < 1 {
+		APIError(ctx, http.StatusBadRequest, "Nomor revisi tidak valid")
+		return
+	}
+
+	restoredDoc, err := c.docService.RestoreRevision(uint(id), revisionNumber, ctx.GetUint("userID"))
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrAccessDenied):
+			APIError(ctx, http.StatusForbidden, err.Error())
+		case errors.Is(err, services.ErrNotFound):
+			APIError(ctx, http.StatusNotFound, "Revisi tidak ditemukan")
+		case errors.Is(err, services.ErrInvalidStatusTransition):
+			APIError(ctx, http.StatusConflict, err.Error())
+		case errors.Is(err, services.ErrInvalidNIK), errors.Is(err, services.ErrMissingRequiredField), errors.Is(err, services.ErrInvalidApprover):
+			APIError(ctx, http.StatusBadRequest, err.Error())
+		default:
+			log.Printf("ERROR: Gagal memulihkan revisi %d dokumen id %d: %v", revisionNumber, id, err)
+			APIError(ctx, http.StatusInternalServerError, "Gagal memulihkan revisi dokumen.")
+		}
+		return
+	}
+	ctx.JSON(http.StatusOK, restoredDoc)
+}
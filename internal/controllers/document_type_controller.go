@@ -0,0 +1,148 @@
+package controllers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"simdokpol/internal/models"
+	"simdokpol/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DocumentTypeRequest adalah DTO untuk membuat atau memperbarui jenis dokumen.
+type DocumentTypeRequest struct {
+	Kode          string                 `json:"kode" example:"STPL"` // Hanya dipakai saat membuat; tidak dapat diubah
+	Nama          string                 `json:"nama" binding:"required" example:"Surat Tanda Penerimaan Laporan"`
+	FormatNomor   string                 `json:"format_nomor" example:"STPL/{SEQ}/{ROMAN_MONTH}/{YEAR}/{OFFICE_CODE}"`
+	PrintTemplate string                 `json:"print_template" binding:"required" example:"print_generic.html"`
+	Fields        []models.DocumentField `json:"fields"`
+	Aktif         bool                   `json:"aktif"`
+}
+
+type DocumentTypeController struct {
+	docTypeService services.DocumentTypeService
+}
+
+func NewDocumentTypeController(docTypeService services.DocumentTypeService) *DocumentTypeController {
+	return &DocumentTypeController{docTypeService: docTypeService}
+}
+
+// @Summary Mendapatkan Semua Jenis Dokumen
+// @Description Mengambil registri jenis dokumen beserta format nomor, template cetak, dan isiannya.
+// @Tags Document Types
+// @Produce json
+// @Param include_inactive query bool false "Sertakan jenis dokumen nonaktif"
+// @Success 200 {array} models.DocumentType
+// @Failure 500 {object} map[string]string "Error: Gagal mengambil jenis dokumen"
+// @Security BearerAuth
+// @Router /document-types [get]
+func (c *DocumentTypeController) FindAll(ctx *gin.Context) {
+	docTypes, err := c.docTypeService.FindAll(ctx.Query("include_inactive") != "true")
+	if err != nil {
+		log.Printf("ERROR: Gagal mengambil jenis dokumen: %v", err)
+		APIError(ctx, http.StatusInternalServerError, "Gagal mengambil jenis dokumen.")
+		return
+	}
+	ctx.JSON(http.StatusOK, docTypes)
+}
+
+// @Summary Mendapatkan Jenis Dokumen Berdasarkan Kode
+// @Description Mengambil satu definisi jenis dokumen.
+// @Tags Document Types
+// @Produce json
+// @Param kode path string true "Kode Jenis Dokumen"
+// @Success 200 {object} models.DocumentType
+// @Failure 404 {object} map[string]string "Error: Jenis dokumen tidak ditemukan"
+// @Security BearerAuth
+// @Router /document-types/{kode} [get]
+func (c *DocumentTypeController) FindByCode(ctx *gin.Context) {
+	docType, err := c.docTypeService.FindByCode(ctx.Param("kode"))
+	if err != nil {
+		if errors.Is(err, services.ErrNotFound) {
+			APIError(ctx, http.StatusNotFound, "Jenis dokumen tidak ditemukan")
+			return
+		}
+		log.Printf("ERROR: Gagal mengambil jenis dokumen %s: %v", ctx.Param("kode"), err)
+		APIError(ctx, http.StatusInternalServerError, "Gagal mengambil jenis dokumen.")
+		return
+	}
+	ctx.JSON(http.StatusOK, docType)
+}
+
+// @Summary Menambahkan Jenis Dokumen
+// @Description Mendaftarkan jenis dokumen baru beserta format nomor, template cetak, dan isian wajibnya. Memerlukan izin document_type.manage.
+// @Tags Document Types
+// @Accept json
+// @Produce json
+// @Param documentType body DocumentTypeRequest true "Definisi Jenis Dokumen"
+// @Success 201 {object} models.DocumentType
+// @Failure 400 {object} map[string]string "Error: Definisi jenis dokumen tidak valid"
+// @Failure 500 {object} map[string]string "Error: Gagal menyimpan jenis dokumen"
+// @Security BearerAuth
+// @Router /document-types [post]
+func (c *DocumentTypeController) Create(ctx *gin.Context) {
+	var req DocumentTypeRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		APIError(ctx, http.StatusBadRequest, "Input tidak valid: "+err.Error())
+		return
+	}
+
+	created, err := c.docTypeService.Create(req.toModel(), ctx.GetUint("userID"))
+	if err != nil {
+		c.handleSaveError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, created)
+}
+
+// @Summary Memperbarui Jenis Dokumen
+// @Description Memperbarui definisi jenis dokumen. Kode jenis dokumen tidak dapat diubah. Memerlukan izin document_type.manage.
+// @Tags Document Types
+// @Accept json
+// @Produce json
+// @Param kode path string true "Kode Jenis Dokumen"
+// @Param documentType body DocumentTypeRequest true "Definisi Jenis Dokumen"
+// @Success 200 {object} models.DocumentType
+// @Failure 400 {object} map[string]string "Error: Definisi jenis dokumen tidak valid"
+// @Failure 404 {object} map[string]string "Error: Jenis dokumen tidak ditemukan"
+// @Failure 500 {object} map[string]string "Error: Gagal menyimpan jenis dokumen"
+// @Security BearerAuth
+// @Router /document-types/{kode} [put]
+func (c *DocumentTypeController) Update(ctx *gin.Context) {
+	var req DocumentTypeRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		APIError(ctx, http.StatusBadRequest, "Input tidak valid: "+err.Error())
+		return
+	}
+
+	updated, err := c.docTypeService.Update(ctx.Param("kode"), req.toModel(), ctx.GetUint("userID"))
+	if err != nil {
+		c.handleSaveError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, updated)
+}
+
+func (c *DocumentTypeController) handleSaveError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrNotFound):
+		APIError(ctx, http.StatusNotFound, "Jenis dokumen tidak ditemukan")
+	case errors.Is(err, services.ErrInvalidDocumentType), errors.Is(err, services.ErrInvalidNumberFormat):
+		APIError(ctx, http.StatusBadRequest, err.Error())
+	default:
+		log.Printf("ERROR: Gagal menyimpan jenis dokumen: %v", err)
+		APIError(ctx, http.StatusInternalServerError, "Gagal menyimpan jenis dokumen.")
+	}
+}
+
+func (req DocumentTypeRequest) toModel() *models.DocumentType {
+	return &models.DocumentType{
+		Kode:          req.Kode,
+		Nama:          req.Nama,
+		FormatNomor:   req.FormatNomor,
+		PrintTemplate: req.PrintTemplate,
+		Fields:        req.Fields,
+		Aktif:         req.Aktif,
+	}
+}
@@ -8,7 +8,17 @@
  */
 package controllers
 
-import "github.com/gin-gonic/gin"
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"simdokpol/internal/dto"
+	"simdokpol/internal/repositories"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
 
 // APIResponse mengirimkan respons JSON standar untuk operasi yang sukses.
 //
@@ -33,4 +43,71 @@ func APIResponse(ctx *gin.Context, statusCode int, message string, data interfac
 // - errorMessage (string): Pesan error yang aman untuk ditampilkan ke klien.
 func APIError(ctx *gin.Context, statusCode int, errorMessage string) {
 	ctx.JSON(statusCode, gin.H{"error": errorMessage})
-}
\ No newline at end of file
+}
+
+// APIPage mengirimkan satu halaman daftar beserta jumlah barisnya dalam format dto.PageResponse.
+func APIPage(ctx *gin.Context, list repositories.ListQuery, result repositories.PageResult, data interface{}) {
+	page := list.Page
+	if page < 1 {
+		page = 1
+	}
+	ctx.JSON(http.StatusOK, dto.PageResponse{Data: data, Page: page, Size: list.Limit(), Total: result.Total, Filtered: result.Filtered})
+}
+
+// ParseListQuery membaca parameter daftar bersama: page, size, sort, dir (asc/desc), q, from, to
+// (YYYY-MM-DD di zona waktu loc, to inklusif), operator_id, dan item_type.
+func ParseListQuery(ctx *gin.Context, loc *time.Location) (repositories.ListQuery, error) {
+	list := repositories.ListQuery{
+		Sort:     ctx.Query("sort"),
+		Desc:     ctx.Query("dir") == "desc",
+		Search:   ctx.Query("q"),
+		ItemType: ctx.Query("item_type"),
+	}
+	for param, target := range map[string]*int{"page": &list.Page, "size": &list.Size} {
+		if value := ctx.Query(param); value != "" {
+			number, err := strconv.Atoi(value)
+			if err != nil || number < 0 {
+				return list, fmt.Errorf("Parameter %s tidak valid.", param)
+			}
+			*target = number
+		}
+	}
+	if value := ctx.Query("operator_id"); value != "" {
+		id, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			return list, errors.New("Parameter operator_id tidak valid.")
+		}
+		list.OperatorID = uint(id)
+	}
+	from, to, err := ParseDateRange(ctx, loc)
+	if err != nil {
+		return list, err
+	}
+	list.From, list.To = from, to
+	return list, nil
+}
+
+// ParseDateRange membaca parameter from dan to (YYYY-MM-DD) di zona waktu loc. Tanggal akhir inklusif,
+// sehingga to yang dikembalikan adalah awal hari berikutnya.
+func ParseDateRange(ctx *gin.Context, loc *time.Location) (*time.Time, *time.Time, error) {
+	var from, to *time.Time
+	if fromParam := ctx.Query("from"); fromParam != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", fromParam, loc)
+		if err != nil {
+			return nil, nil, errors.New("Format tanggal awal tidak valid.")
+		}
+		from = &parsed
+	}
+	if toParam := ctx.Query("to"); toParam != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", toParam, loc)
+		if err != nil {
+			return nil, nil, errors.New("Format tanggal akhir tidak valid.")
+		}
+		parsed = parsed.AddDate(0, 0, 1)
+		to = &parsed
+	}
+	if from != nil && to != nil && !from.Before(*to) {
+		return nil, nil, errors.New("Tanggal awal tidak boleh setelah tanggal akhir.")
+	}
+	return from, to, nil
+}
@@ -0,0 +1,85 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"simdokpol/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxImportFileSize membatasi ukuran file impor yang diunggah.
+const maxImportFileSize = 20 << 20
+
+type ImportController struct {
+	importService services.DocumentImportService
+}
+
+func NewImportController(importService services.DocumentImportService) *ImportController {
+	return &ImportController{importService: importService}
+}
+
+// @Summary Impor Surat Lama
+// @Description Mengimpor surat yang sudah terbit sebelum memakai SIMDOKPOL dari file .xlsx atau .csv. Tanpa commit=true, file hanya diperiksa (dry-run) dan laporan kesalahan per baris dikembalikan. Dengan commit=true, semua baris disimpan dalam satu transaksi dengan Nomor Surat dan Tanggal Laporan asli, atau tidak ada sama sekali jika masih ada baris yang salah. Memerlukan izin document.import.
+// @Tags Documents
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "File .xlsx atau .csv dengan baris judul kolom"
+// @Param commit formData bool false "true untuk menyimpan; kosong atau false untuk dry-run"
+// @Success 200 {object} dto.ImportReport
+// @Failure 400 {object} map[string]string "Error: File tidak valid"
+// @Failure 403 {object} map[string]string "Error: Akses ditolak"
+// @Failure 422 {object} map[string]interface{} "Error: Masih ada baris yang tidak valid, beserta laporannya"
+// @Failure 500 {object} map[string]string "Error: Terjadi kesalahan pada server"
+// @Security BearerAuth
+// @Router /documents/import [post]
+func (c *ImportController) Import(ctx *gin.Context) {
+	fileHeader, err := ctx.FormFile("file")
+	if err != nil {
+		APIError(ctx, http.StatusBadRequest, "Tidak ada file yang diunggah.")
+		return
+	}
+	if fileHeader.Size > maxImportFileSize {
+		APIError(ctx, http.StatusBadRequest, fmt.Sprintf("Ukuran file maksimal %d MB.", maxImportFileSize>>20))
+		return
+	}
+	src, err := fileHeader.Open()
+	if err != nil {
+		log.Printf("ERROR: Gagal membuka file impor yang diunggah: %v", err)
+		APIError(ctx, http.StatusInternalServerError, "Gagal memproses file yang diunggah.")
+		return
+	}
+	defer src.Close()
+	data, err := io.ReadAll(io.LimitReader(src, maxImportFileSize))
+	if err != nil {
+		log.Printf("ERROR: Gagal membaca file impor yang diunggah: %v", err)
+		APIError(ctx, http.StatusInternalServerError, "Gagal memproses file yang diunggah.")
+		return
+	}
+
+	commit := ctx.PostForm("commit") == "true"
+	report, err := c.importService.Import(fileHeader.Filename, data, ctx.GetUint("userID"), commit)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrImportHasErrors):
+			ctx.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error(), "data": report})
+		case errors.Is(err, services.ErrInvalidImportFile):
+			APIError(ctx, http.StatusBadRequest, err.Error())
+		case errors.Is(err, services.ErrAccessDenied):
+			APIError(ctx, http.StatusForbidden, err.Error())
+		default:
+			log.Printf("ERROR: Gagal mengimpor file %s: %v", fileHeader.Filename, err)
+			APIError(ctx, http.StatusInternalServerError, "Gagal mengimpor dokumen.")
+		}
+		return
+	}
+
+	message := fmt.Sprintf("Pemeriksaan selesai: %d dari %d baris valid.", report.ValidRows, report.TotalRows)
+	if commit {
+		message = fmt.Sprintf("%d dokumen berhasil diimpor.", report.Imported)
+	}
+	APIResponse(ctx, http.StatusOK, message, report)
+}
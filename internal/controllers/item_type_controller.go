@@ -0,0 +1,146 @@
+package controllers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"simdokpol/internal/models"
+	"simdokpol/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ItemTypeRequest adalah DTO untuk membuat atau memperbarui jenis barang pada katalog.
+type ItemTypeRequest struct {
+	Kode    string                 `json:"kode" example:"STNK"` // Hanya dipakai saat membuat; tidak dapat diubah
+	Nama    string                 `json:"nama" binding:"required" example:"STNK"`
+	Alias   []string               `json:"alias" example:"SURAT TANDA NOMOR KENDARAAN"`
+	Atribut []models.ItemAttribute `json:"atribut"`
+	Aktif   bool                   `json:"aktif"`
+}
+
+type ItemTypeController struct {
+	itemTypeService services.ItemTypeService
+}
+
+func NewItemTypeController(itemTypeService services.ItemTypeService) *ItemTypeController {
+	return &ItemTypeController{itemTypeService: itemTypeService}
+}
+
+// @Summary Mendapatkan Katalog Jenis Barang
+// @Description Mengambil katalog jenis barang hilang beserta alias dan atribut isiannya.
+// @Tags Item Types
+// @Produce json
+// @Param include_inactive query bool false "Sertakan jenis barang nonaktif"
+// @Success 200 {array} models.ItemType
+// @Failure 500 {object} map[string]string "Error: Gagal mengambil katalog barang"
+// @Security BearerAuth
+// @Router /item-types [get]
+func (c *ItemTypeController) FindAll(ctx *gin.Context) {
+	itemTypes, err := c.itemTypeService.FindAll(ctx.Query("include_inactive") != "true")
+	if err != nil {
+		log.Printf("ERROR: Gagal mengambil katalog barang: %v", err)
+		APIError(ctx, http.StatusInternalServerError, "Gagal mengambil katalog barang.")
+		return
+	}
+	ctx.JSON(http.StatusOK, itemTypes)
+}
+
+// @Summary Mendapatkan Jenis Barang Berdasarkan Kode
+// @Description Mengambil satu definisi jenis barang.
+// @Tags Item Types
+// @Produce json
+// @Param kode path string true "Kode Jenis Barang"
+// @Success 200 {object} models.ItemType
+// @Failure 404 {object} map[string]string "Error: Jenis barang tidak ditemukan"
+// @Security BearerAuth
+// @Router /item-types/{kode} [get]
+func (c *ItemTypeController) FindByCode(ctx *gin.Context) {
+	itemType, err := c.itemTypeService.FindByCode(ctx.Param("kode"))
+	if err != nil {
+		if errors.Is(err, services.ErrNotFound) {
+			APIError(ctx, http.StatusNotFound, "Jenis barang tidak ditemukan")
+			return
+		}
+		log.Printf("ERROR: Gagal mengambil jenis barang %s: %v", ctx.Param("kode"), err)
+		APIError(ctx, http.StatusInternalServerError, "Gagal mengambil katalog barang.")
+		return
+	}
+	ctx.JSON(http.StatusOK, itemType)
+}
+
+// @Summary Menambahkan Jenis Barang
+// @Description Menambahkan jenis barang ke katalog beserta alias dan atribut isiannya. Memerlukan izin item_type.manage.
+// @Tags Item Types
+// @Accept json
+// @Produce json
+// @Param itemType body ItemTypeRequest true "Definisi Jenis Barang"
+// @Success 201 {object} models.ItemType
+// @Failure 400 {object} map[string]string "Error: Definisi jenis barang tidak valid"
+// @Failure 500 {object} map[string]string "Error: Gagal menyimpan jenis barang"
+// @Security BearerAuth
+// @Router /item-types [post]
+func (c *ItemTypeController) Create(ctx *gin.Context) {
+	var req ItemTypeRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		APIError(ctx, http.StatusBadRequest, "Input tidak valid: "+err.Error())
+		return
+	}
+
+	created, err := c.itemTypeService.Create(req.toModel(), ctx.GetUint("userID"))
+	if err != nil {
+		c.handleSaveError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, created)
+}
+
+// @Summary Memperbarui Jenis Barang
+// @Description Memperbarui definisi jenis barang. Kode jenis barang tidak dapat diubah. Memerlukan izin item_type.manage.
+// @Tags Item Types
+// @Accept json
+// @Produce json
+// @Param kode path string true "Kode Jenis Barang"
+// @Param itemType body ItemTypeRequest true "Definisi Jenis Barang"
+// @Success 200 {object} models.ItemType
+// @Failure 400 {object} map[string]string "Error: Definisi jenis barang tidak valid"
+// @Failure 404 {object} map[string]string "Error: Jenis barang tidak ditemukan"
+// @Failure 500 {object} map[string]string "Error: Gagal menyimpan jenis barang"
+// @Security BearerAuth
+// @Router /item-types/{kode} [put]
+func (c *ItemTypeController) Update(ctx *gin.Context) {
+	var req ItemTypeRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		APIError(ctx, http.StatusBadRequest, "Input tidak valid: "+err.Error())
+		return
+	}
+
+	updated, err := c.itemTypeService.Update(ctx.Param("kode"), req.toModel(), ctx.GetUint("userID"))
+	if err != nil {
+		c.handleSaveError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, updated)
+}
+
+func (c *ItemTypeController) handleSaveError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrNotFound):
+		APIError(ctx, http.StatusNotFound, "Jenis barang tidak ditemukan")
+	case errors.Is(err, services.ErrInvalidItemType):
+		APIError(ctx, http.StatusBadRequest, err.Error())
+	default:
+		log.Printf("ERROR: Gagal menyimpan jenis barang: %v", err)
+		APIError(ctx, http.StatusInternalServerError, "Gagal menyimpan jenis barang.")
+	}
+}
+
+func (req ItemTypeRequest) toModel() *models.ItemType {
+	return &models.ItemType{
+		Kode:    req.Kode,
+		Nama:    req.Nama,
+		Alias:   req.Alias,
+		Atribut: req.Atribut,
+		Aktif:   req.Aktif,
+	}
+}
@@ -2,18 +2,25 @@ package controllers
 
 import (
 	"errors"
+	"fmt"
 	"log"
 	"net/http"
 	"simdokpol/internal/models"
+	"simdokpol/internal/repositories"
 	"simdokpol/internal/services"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 // DocumentRequest adalah DTO untuk membuat atau memperbarui dokumen.
+// Isian yang wajib di luar data pemohon ditentukan oleh jenis dokumennya.
 type DocumentRequest struct {
+	JenisDokumen       string `json:"jenis_dokumen" example:"LOST_DOCUMENT"` // Hanya dipakai saat membuat; kosong berarti LOST_DOCUMENT
+	NIK                string `json:"nik" example:"3171011501900001"` // Opsional; dikosongkan jika pemohon tidak membawa identitas
 	NamaLengkap        string `json:"nama_lengkap" binding:"required" example:"BUDI SANTOSO"`
 	TempatLahir        string `json:"tempat_lahir" binding:"required" example:"JAKARTA"`
 	TanggalLahir       string `json:"tanggal_lahir" binding:"required" example:"1990-01-15"`
@@ -21,25 +28,44 @@ type DocumentRequest struct {
 	Agama              string `json:"agama" binding:"required" example:"Islam"`
 	Pekerjaan          string `json:"pekerjaan" binding:"required" example:"Karyawan Swasta"`
 	Alamat             string `json:"alamat" binding:"required" example:"JL. MERDEKA NO. 10, JAKARTA"`
-	LokasiHilang       string `json:"lokasi_hilang" binding:"required" example:"Sekitar Pasar Senen"`
+	LokasiHilang       string `json:"lokasi_hilang" example:"Sekitar Pasar Senen"`
 	PetugasPelaporID   uint   `json:"petugas_pelapor_id" binding:"required" example:"2"`
 	PejabatPersetujuID uint   `json:"pejabat_persetuju_id" binding:"required" example:"1"`
 	Items              []struct {
-		NamaBarang string `json:"nama_barang" binding:"required" example:"KTP"`
-		Deskripsi  string `json:"deskripsi" example:"NIK: 3171234567890001"`
-	} `json:"items" binding:"required,min=1"`
+		JenisBarang string            `json:"jenis_barang" example:"KTP"` // Kode katalog barang; kosongkan untuk barang isian bebas
+		NamaBarang  string            `json:"nama_barang" example:"KTP"`  // Wajib untuk barang isian bebas; diisi dari katalog bila jenis dipilih
+		Deskripsi   string            `json:"deskripsi" example:"NIK: 3171234567890001"`
+		Atribut     map[string]string `json:"atribut"`
+	} `json:"items"`
+	DataTambahan map[string]string `json:"data_tambahan"`
+	Ajukan       bool              `json:"ajukan" example:"false"` // true berarti draf langsung diajukan ke pejabat persetuju setelah disimpan
+}
+
+// StatusReasonRequest adalah DTO untuk aksi yang wajib disertai alasan (tolak dan cabut).
+type StatusReasonRequest struct {
+	Alasan string `json:"alasan" binding:"required" example:"Identitas pemohon tidak sesuai"`
 }
 
 type LostDocumentController struct {
-	docService services.LostDocumentService
+	docService          services.LostDocumentService
+	docTypeService      services.DocumentTypeService
+	pdfService          services.PDFService
+	verificationService services.VerificationService
+	configService       services.ConfigService
 }
 
-func NewLostDocumentController(docService services.LostDocumentService) *LostDocumentController {
-	return &LostDocumentController{docService: docService}
+func NewLostDocumentController(docService services.LostDocumentService, docTypeService services.DocumentTypeService, pdfService services.PDFService, verificationService services.VerificationService, configService services.ConfigService) *LostDocumentController {
+	return &LostDocumentController{
+		docService:          docService,
+		docTypeService:      docTypeService,
+		pdfService:          pdfService,
+		verificationService: verificationService,
+		configService:       configService,
+	}
 }
 
 // @Summary Mendapatkan Dokumen Berdasarkan ID
-// @Description Mengambil detail satu surat keterangan hilang berdasarkan ID-nya. Hanya bisa diakses oleh Super Admin atau operator yang membuat dokumen tersebut.
+// @Description Mengambil detail satu surat keterangan hilang berdasarkan ID-nya. Hanya bisa diakses oleh operator yang membuat dokumen tersebut, anggota regu yang sama, pejabat persetujunya, atau pengguna berizin document.view_all.
 // @Tags Documents
 // @Produce json
 // @Param id path int true "ID Dokumen"
@@ -71,18 +97,138 @@ func (c *LostDocumentController) FindByID(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, document)
 }
 
+// @Summary Laporan Lain dengan Nomor Identitas yang Sama
+// @Description Mengambil laporan lain yang memuat nomor identitas barang (NIK, nomor SIM, nomor polisi, IMEI, dst.) yang sama dengan barang pada dokumen ini, dikelompokkan per nomor. Memerlukan izin document.crosscheck dan akses ke dokumen tersebut.
+// @Tags Documents
+// @Produce json
+// @Param id path int true "ID Dokumen"
+// @Success 200 {array} repositories.IdentifierMatchGroup
+// @Failure 400 {object} map[string]string "Error: ID tidak valid"
+// @Failure 403 {object} map[string]string "Error: Akses ditolak"
+// @Failure 404 {object} map[string]string "Error: Dokumen tidak ditemukan"
+// @Security BearerAuth
+// @Router /documents/{id}/identifier-matches [get]
+func (c *LostDocumentController) IdentifierMatches(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		APIError(ctx, http.StatusBadRequest, "ID dokumen tidak valid")
+		return
+	}
+
+	groups, err := c.docService.FindIdentifierMatches(uint(id), ctx.GetUint("userID"))
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrAccessDenied):
+			APIError(ctx, http.StatusForbidden, "Akses ditolak: Anda tidak memiliki izin untuk melihat dokumen ini.")
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			APIError(ctx, http.StatusNotFound, "Dokumen tidak ditemukan")
+		default:
+			log.Printf("ERROR: Gagal mencocokkan nomor identitas dokumen id %d: %v", id, err)
+			APIError(ctx, http.StatusInternalServerError, "Gagal mencocokkan nomor identitas barang.")
+		}
+		return
+	}
+	ctx.JSON(http.StatusOK, groups)
+}
+
+// @Summary Daftar Laporan Berulang
+// @Description Mengelompokkan nomor identitas barang (NIK, nomor SIM, nomor polisi, IMEI, dst.) yang tercatat pada lebih dari satu laporan, sebagai petunjuk laporan berulang atau klaim palsu. Atribut yang dicocokkan diatur di Katalog Barang. Memerlukan izin document.crosscheck.
+// @Tags Documents
+// @Produce json
+// @Success 200 {array} repositories.IdentifierMatchGroup
+// @Failure 500 {object} map[string]string "Error: Gagal memuat laporan berulang"
+// @Security BearerAuth
+// @Router /documents/identifier-duplicates [get]
+func (c *LostDocumentController) IdentifierDuplicates(ctx *gin.Context) {
+	groups, err := c.docService.FindIdentifierDuplicates()
+	if err != nil {
+		log.Printf("ERROR: Gagal memuat laporan berulang: %v", err)
+		APIError(ctx, http.StatusInternalServerError, "Gagal memuat laporan berulang.")
+		return
+	}
+	ctx.JSON(http.StatusOK, groups)
+}
+
+// @Summary Mengunduh Dokumen sebagai PDF
+// @Description Merender dokumen sesuai template cetak jenisnya menjadi file PDF di sisi server sehingga tampilannya identik di setiap komputer. Hanya bisa diakses oleh operator yang membuat dokumen tersebut, anggota regu yang sama, pejabat persetujunya, atau pengguna berizin document.view_all.
+// @Tags Documents
+// @Produce application/pdf
+// @Param id path int true "ID Dokumen"
+// @Success 200 {file} file "File PDF dokumen"
+// @Failure 400 {object} map[string]string "Error: ID tidak valid"
+// @Failure 403 {object} map[string]string "Error: Akses ditolak"
+// @Failure 404 {object} map[string]string "Error: Dokumen tidak ditemukan"
+// @Failure 409 {object} map[string]string "Error: Dokumen belum diterbitkan atau sudah dicabut"
+// @Failure 500 {object} map[string]string "Error: Gagal membuat PDF"
+// @Security BearerAuth
+// @Router /documents/{id}/pdf [get]
+func (c *LostDocumentController) DownloadPDF(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		APIError(ctx, http.StatusBadRequest, "ID dokumen tidak valid")
+		return
+	}
+
+	loggedInUserID := ctx.GetUint("userID")
+
+	document, err := c.docService.FindByID(uint(id), loggedInUserID)
+	if err != nil {
+		if errors.Is(err, services.ErrAccessDenied) {
+			APIError(ctx, http.StatusForbidden, "Akses ditolak: Anda tidak memiliki izin untuk melihat dokumen ini.")
+			return
+		}
+		APIError(ctx, http.StatusNotFound, "Dokumen tidak ditemukan")
+		return
+	}
+
+	if !services.IsPrintable(document) {
+		APIError(ctx, http.StatusConflict, "Dokumen belum diterbitkan atau sudah dicabut sehingga tidak dapat dicetak.")
+		return
+	}
+
+	// Tanpa URL publik verifikasi di Pengaturan, surat tetap dicetak tanpa QR code.
+	verificationURL, err := c.verificationService.VerificationURL(document)
+	if errors.Is(err, services.ErrVerificationBaseURLNotSet) {
+		log.Printf("PERINGATAN: PDF dokumen id %d dibuat tanpa QR code verifikasi: %v", id, err)
+		verificationURL, err = "", nil
+	}
+	if err != nil {
+		log.Printf("ERROR: Gagal membuat URL verifikasi untuk dokumen id %d: %v", id, err)
+		APIError(ctx, http.StatusInternalServerError, "Gagal membuat PDF dokumen.")
+		return
+	}
+
+	docType, err := c.docTypeService.FindByCode(document.JenisDokumen)
+	if err != nil {
+		log.Printf("ERROR: Gagal memuat jenis dokumen %s untuk dokumen id %d: %v", document.JenisDokumen, id, err)
+		APIError(ctx, http.StatusInternalServerError, "Gagal membuat PDF dokumen.")
+		return
+	}
+
+	pdfBytes, err := c.pdfService.GenerateDocumentPDF(document, docType, verificationURL)
+	if err != nil {
+		log.Printf("ERROR: Gagal membuat PDF untuk dokumen id %d: %v", id, err)
+		APIError(ctx, http.StatusInternalServerError, "Gagal membuat PDF dokumen.")
+		return
+	}
+
+	fileName := fmt.Sprintf("%s-%d.pdf", strings.ReplaceAll(strings.ToLower(docType.Nama), " ", "-"), document.ID)
+	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", fileName))
+	ctx.Data(http.StatusOK, "application/pdf", pdfBytes)
+}
+
 // @Summary Pencarian Dokumen Global
-// @Description Mencari dokumen (aktif dan arsip) berdasarkan Nomor Surat atau Nama Pemohon.
+// @Description Mencari dokumen (semua status) yang terlihat oleh pengguna berdasarkan Nomor Surat, data pemohon, lokasi hilang, atau nama dan keterangan barang. Hasil diurutkan dari yang paling relevan dan memuat cuplikan teks yang cocok.
 // @Tags Documents
 // @Produce json
 // @Param q query string true "Kata Kunci Pencarian"
-// @Success 200 {array} models.LostDocument
+// @Success 200 {array} repositories.DocumentSearchHit
 // @Failure 500 {object} map[string]string "Error: Terjadi kesalahan pada server"
 // @Security BearerAuth
 // @Router /search [get]
 func (c *LostDocumentController) SearchGlobal(ctx *gin.Context) {
 	query := ctx.Query("q")
-	documents, err := c.docService.SearchGlobal(query)
+	documents, err := c.docService.SearchGlobal(query, ctx.GetUint("userID"))
 	if err != nil {
 		log.Printf("ERROR: Gagal melakukan pencarian global: %v", err)
 		APIError(ctx, http.StatusInternalServerError, "Gagal melakukan pencarian dokumen.")
@@ -91,37 +237,155 @@ func (c *LostDocumentController) SearchGlobal(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, documents)
 }
 
-// @Summary Mendapatkan Semua Dokumen
-// @Description Mengambil daftar semua surat keterangan hilang, bisa difilter berdasarkan status (aktif/arsip) dan query pencarian.
+// @Summary Mendapatkan Daftar Dokumen
+// @Description Mengambil satu halaman daftar dokumen yang terlihat oleh pengguna (milik sendiri, milik regu yang sama, atau semua bagi pemegang izin document.view_all), dengan pengurutan dan filter di server.
 // @Tags Documents
 // @Produce json
+// @Param status query string false "Filter status dokumen" enums(active, archived, draft, revoked) default(active)
+// @Param page query int false "Nomor halaman, mulai dari 1" default(1)
+// @Param size query int false "Jumlah baris per halaman (maksimal 500)" default(25)
+// @Param sort query string false "Kolom pengurutan" enums(nomor_surat, jenis_dokumen, nama_pemohon, tanggal_laporan, status, operator)
+// @Param dir query string false "Arah pengurutan" enums(asc, desc)
 // @Param q query string false "Kata Kunci Pencarian (No. Surat / Nama)"
-// @Param status query string false "Filter status dokumen" enums(active, archived) default(active)
-// @Success 200 {array} models.LostDocument
+// @Param from query string false "Tanggal laporan awal (YYYY-MM-DD)"
+// @Param to query string false "Tanggal laporan akhir, inklusif (YYYY-MM-DD)"
+// @Param operator_id query int false "ID operator pembuat dokumen"
+// @Param item_type query string false "Jenis barang hilang, misalnya KTP"
+// @Success 200 {object} dto.PageResponse{data=[]models.LostDocument}
+// @Failure 400 {object} map[string]string "Error: Parameter tidak valid"
 // @Failure 500 {object} map[string]string "Error: Terjadi kesalahan pada server"
 // @Security BearerAuth
 // @Router /documents [get]
 func (c *LostDocumentController) FindAll(ctx *gin.Context) {
-	query := ctx.Query("q")
-	status := ctx.DefaultQuery("status", "active")
+	loc, err := c.configService.GetLocation()
+	if err != nil {
+		loc = time.UTC
+	}
+	list, err := ParseListQuery(ctx, loc)
+	if err != nil {
+		APIError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
 
-	documents, err := c.docService.FindAll(query, status)
+	documents, result, err := c.docService.FindPage(ctx.DefaultQuery("status", "active"), list, ctx.GetUint("userID"))
 	if err != nil {
 		log.Printf("ERROR: Gagal mengambil data dokumen: %v", err)
 		APIError(ctx, http.StatusInternalServerError, "Gagal mengambil data dokumen.")
 		return
 	}
-	ctx.JSON(http.StatusOK, documents)
+	APIPage(ctx, list, result, documents)
+}
+
+// @Summary Ekspor Daftar Dokumen
+// @Description Mengunduh daftar dokumen yang terlihat oleh pengguna sebagai file Excel (.xlsx) atau CSV dengan filter yang sama seperti halaman daftar dan pencarian. File dialirkan per batch sehingga daftar besar tidak dimuat sekaligus.
+// @Tags Documents
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet,text/csv
+// @Param format query string false "Format file" enums(xlsx, csv) default(xlsx)
+// @Param q query string false "Kata Kunci Pencarian (No. Surat / Nama)"
+// @Param status query string false "Filter status dokumen; all untuk semua status seperti halaman pencarian" enums(active, archived, draft, revoked, all) default(active)
+// @Param from query string false "Tanggal laporan awal (YYYY-MM-DD)"
+// @Param to query string false "Tanggal laporan akhir, inklusif (YYYY-MM-DD)"
+// @Param operator_id query int false "ID operator pembuat dokumen"
+// @Param item_type query string false "Jenis barang hilang, misalnya KTP"
+// @Param columns query string false "Kolom dipisah koma: number, date, resident, items, officers (kosong berarti semua)"
+// @Success 200 {file} file "File daftar dokumen"
+// @Failure 400 {object} map[string]string "Error: Format, tanggal, atau kolom tidak valid"
+// @Failure 500 {object} map[string]string "Error: Terjadi kesalahan pada server"
+// @Security BearerAuth
+// @Router /documents/export [get]
+func (c *LostDocumentController) Export(ctx *gin.Context) {
+	format := ctx.DefaultQuery("format", "xlsx")
+	if format != "xlsx" && format != "csv" {
+		APIError(ctx, http.StatusBadRequest, "Format file harus xlsx atau csv.")
+		return
+	}
+	loc, err := c.configService.GetLocation()
+	if err != nil {
+		loc = time.UTC
+	}
+
+	// Tanggal akhir inklusif: dokumen sepanjang hari tersebut ikut diekspor.
+	list, err := ParseListQuery(ctx, loc)
+	if err != nil {
+		APIError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	filter := repositories.DocumentFilter{
+		Query:      list.Search,
+		Status:     ctx.DefaultQuery("status", "active"),
+		From:       list.From,
+		To:         list.To,
+		OperatorID: list.OperatorID,
+		ItemType:   list.ItemType,
+	}
+	var columns []string
+	if columnsParam := ctx.Query("columns"); columnsParam != "" {
+		columns = strings.Split(columnsParam, ",")
+	}
+
+	out := &attachmentWriter{
+		ctx:         ctx,
+		fileName:    fmt.Sprintf("daftar-dokumen-%s.%s", time.Now().In(loc).Format("20060102-1504"), format),
+		contentType: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+	}
+	var writer services.SpreadsheetRowWriter
+	if format == "csv" {
+		out.contentType = "text/csv; charset=utf-8"
+		writer = services.NewCSVRowWriter(out)
+	} else {
+		writer = services.NewXLSXRowWriter(out, "Daftar Dokumen")
+	}
+
+	_, err = c.docService.ExportDocuments(writer, filter, columns, ctx.GetUint("userID"))
+	if err == nil {
+		err = writer.Close()
+	}
+	if err != nil {
+		if !out.started {
+			if errors.Is(err, services.ErrInvalidExportColumn) {
+				APIError(ctx, http.StatusBadRequest, err.Error())
+				return
+			}
+			log.Printf("ERROR: Gagal mengekspor daftar dokumen: %v", err)
+			APIError(ctx, http.StatusInternalServerError, "Gagal mengekspor daftar dokumen.")
+			return
+		}
+		// Sebagian file sudah terkirim sehingga status respons tidak bisa diubah lagi.
+		log.Printf("ERROR: Ekspor daftar dokumen terputus: %v", err)
+		ctx.Abort()
+	}
+}
+
+// attachmentWriter menulis ke response sebagai lampiran unduhan. Header baru dikirim saat
+// byte pertama ditulis, sehingga error sebelum itu masih bisa dijawab dengan JSON biasa.
+type attachmentWriter struct {
+	ctx         *gin.Context
+	fileName    string
+	contentType string
+	started     bool
+}
+
+func (w *attachmentWriter) Write(p []byte) (int, error) {
+	if !w.started {
+		w.started = true
+		w.ctx.Header("Content-Type", w.contentType)
+		w.ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", w.fileName))
+		w.ctx.Status(http.StatusOK)
+	}
+	return w.ctx.Writer.Write(p)
 }
 
 // @Summary Menghapus Dokumen
-// @Description Menghapus (soft delete) sebuah surat keterangan hilang. Hanya bisa diakses oleh Super Admin atau operator yang membuatnya.
+// @Description Memindahkan sebuah surat keterangan hilang ke tong sampah (soft delete) dengan alasan wajib. Hanya bisa diakses oleh operator yang membuatnya atau pengguna berizin document.edit_all.
 // @Tags Documents
+// @Accept json
 // @Produce json
 // @Param id path int true "ID Dokumen"
+// @Param body body StatusReasonRequest true "Alasan Penghapusan"
 // @Success 200 {object} map[string]string "Pesan Sukses"
-// @Failure 400 {object} map[string]string "Error: ID tidak valid"
+// @Failure 400 {object} map[string]string "Error: ID tidak valid atau alasan kosong"
 // @Failure 403 {object} map[string]string "Error: Akses ditolak"
+// @Failure 404 {object} map[string]string "Error: Dokumen tidak ditemukan"
 // @Failure 500 {object} map[string]string "Error: Gagal menghapus dokumen"
 // @Security BearerAuth
 // @Router /documents/{id} [delete]
@@ -132,31 +396,43 @@ func (c *LostDocumentController) Delete(ctx *gin.Context) {
 		return
 	}
 
+	var req StatusReasonRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		APIError(ctx, http.StatusBadRequest, "Alasan penghapusan wajib diisi.")
+		return
+	}
+
 	loggedInUserID := ctx.GetUint("userID")
 
-	if err := c.docService.DeleteLostDocument(uint(id), loggedInUserID); err != nil {
-		if errors.Is(err, services.ErrAccessDenied) {
+	if err := c.docService.DeleteLostDocument(uint(id), loggedInUserID, req.Alasan); err != nil {
+		switch {
+		case errors.Is(err, services.ErrAccessDenied):
 			APIError(ctx, http.StatusForbidden, err.Error())
-			return
+		case errors.Is(err, services.ErrReasonRequired):
+			APIError(ctx, http.StatusBadRequest, "Alasan penghapusan wajib diisi.")
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			APIError(ctx, http.StatusNotFound, "Dokumen tidak ditemukan")
+		default:
+			log.Printf("ERROR: Gagal menghapus dokumen id %d: %v", id, err)
+			APIError(ctx, http.StatusInternalServerError, "Gagal menghapus dokumen.")
 		}
-		log.Printf("ERROR: Gagal menghapus dokumen id %d: %v", id, err)
-		APIError(ctx, http.StatusInternalServerError, "Gagal menghapus dokumen.")
 		return
 	}
 
-	APIResponse(ctx, http.StatusOK, "Dokumen berhasil dihapus", nil)
+	APIResponse(ctx, http.StatusOK, "Dokumen dipindahkan ke tong sampah", nil)
 }
 
 // @Summary Memperbarui Dokumen
-// @Description Memperbarui data sebuah surat keterangan hilang. Hanya bisa diakses oleh Super Admin atau operator yang membuatnya.
+// @Description Memperbarui data sebuah surat keterangan hilang yang masih berstatus draf. Hanya bisa diakses oleh operator yang membuatnya, anggota regu yang sama, atau pengguna berizin document.edit_all. Pejabat persetuju harus berizin document.approve dan bukan pembuat dokumen.
 // @Tags Documents
 // @Accept json
 // @Produce json
 // @Param id path int true "ID Dokumen"
 // @Param document body DocumentRequest true "Data Dokumen yang Diperbarui"
 // @Success 200 {object} models.LostDocument
-// @Failure 400 {object} map[string]string "Error: Input tidak valid"
+// @Failure 400 {object} map[string]string "Error: Input atau NIK tidak valid"
 // @Failure 403 {object} map[string]string "Error: Akses ditolak"
+// @Failure 409 {object} map[string]string "Error: Dokumen bukan draf"
 // @Failure 500 {object} map[string]string "Error: Gagal memperbarui dokumen"
 // @Security BearerAuth
 // @Router /documents/{id} [put]
@@ -182,6 +458,7 @@ func (c *LostDocumentController) Update(ctx *gin.Context) {
 	loggedInUserID := ctx.GetUint("userID")
 
 	residentData := models.Resident{
+		NIK:          optionalNIK(req.NIK),
 		NamaLengkap:  req.NamaLengkap,
 		TempatLahir:  req.TempatLahir,
 		TanggalLahir: tglLahir,
@@ -193,31 +470,47 @@ func (c *LostDocumentController) Update(ctx *gin.Context) {
 
 	var lostItems []models.LostItem
 	for _, item := range req.Items {
-		lostItems = append(lostItems, models.LostItem{NamaBarang: item.NamaBarang, Deskripsi: item.Deskripsi})
+		lostItems = append(lostItems, models.LostItem{JenisBarang: item.JenisBarang, NamaBarang: item.NamaBarang, Deskripsi: item.Deskripsi, Atribut: item.Atribut})
 	}
 
-	updatedDoc, err := c.docService.UpdateLostDocument(uint(id), residentData, lostItems, req.LokasiHilang, req.PetugasPelaporID, req.PejabatPersetujuID, loggedInUserID)
+	updatedDoc, err := c.docService.UpdateLostDocument(uint(id), residentData, lostItems, req.DataTambahan, req.LokasiHilang, req.PetugasPelaporID, req.PejabatPersetujuID, loggedInUserID)
 	if err != nil {
 		if errors.Is(err, services.ErrAccessDenied) {
 			APIError(ctx, http.StatusForbidden, err.Error())
 			return
 		}
+		if isDocumentInputError(err) {
+			APIError(ctx, http.StatusBadRequest, err.Error())
+			return
+		}
+		if errors.Is(err, services.ErrInvalidStatusTransition) {
+			APIError(ctx, http.StatusConflict, "Hanya dokumen berstatus draf yang dapat diubah.")
+			return
+		}
 		log.Printf("ERROR: Gagal memperbarui dokumen id %d: %v", id, err)
 		APIError(ctx, http.StatusInternalServerError, "Gagal memperbarui dokumen.")
 		return
 	}
 
+	if req.Ajukan && updatedDoc.Status == models.StatusDraf {
+		updatedDoc, err = c.docService.SubmitForApproval(updatedDoc.ID, loggedInUserID)
+		if err != nil {
+			c.handleWorkflowError(ctx, err, updatedDoc, "mengajukan")
+			return
+		}
+	}
+
 	ctx.JSON(http.StatusOK, updatedDoc)
 }
 
 // @Summary Membuat Dokumen Baru
-// @Description Membuat surat keterangan hilang baru.
+// @Description Membuat dokumen baru dari jenis dokumen yang terdaftar (default: surat keterangan hilang). Pejabat persetuju harus berizin document.approve dan bukan pembuat dokumen.
 // @Tags Documents
 // @Accept json
 // @Produce json
 // @Param document body DocumentRequest true "Data Dokumen Baru"
 // @Success 201 {object} models.LostDocument
-// @Failure 400 {object} map[string]string "Error: Input tidak valid"
+// @Failure 400 {object} map[string]string "Error: Input atau NIK tidak valid"
 // @Failure 500 {object} map[string]string "Error: Gagal membuat dokumen"
 // @Security BearerAuth
 // @Router /documents [post]
@@ -237,6 +530,7 @@ func (c *LostDocumentController) Create(ctx *gin.Context) {
 	operatorID := ctx.GetUint("userID")
 
 	residentData := models.Resident{
+		NIK:          optionalNIK(req.NIK),
 		NamaLengkap:  req.NamaLengkap,
 		TempatLahir:  req.TempatLahir,
 		TanggalLahir: tglLahir,
@@ -248,15 +542,216 @@ func (c *LostDocumentController) Create(ctx *gin.Context) {
 
 	var lostItems []models.LostItem
 	for _, item := range req.Items {
-		lostItems = append(lostItems, models.LostItem{NamaBarang: item.NamaBarang, Deskripsi: item.Deskripsi})
+		lostItems = append(lostItems, models.LostItem{JenisBarang: item.JenisBarang, NamaBarang: item.NamaBarang, Deskripsi: item.Deskripsi, Atribut: item.Atribut})
 	}
 
-	createdDoc, err := c.docService.CreateLostDocument(residentData, lostItems, operatorID, req.LokasiHilang, req.PetugasPelaporID, req.PejabatPersetujuID)
+	jenisDokumen := req.JenisDokumen
+	if jenisDokumen == "" {
+		jenisDokumen = models.DocumentTypeLostDocument
+	}
+
+	createdDoc, err := c.docService.CreateLostDocument(jenisDokumen, residentData, lostItems, req.DataTambahan, operatorID, req.LokasiHilang, req.PetugasPelaporID, req.PejabatPersetujuID)
 	if err != nil {
+		if isDocumentInputError(err) {
+			APIError(ctx, http.StatusBadRequest, err.Error())
+			return
+		}
 		log.Printf("ERROR: Gagal membuat dokumen: %v", err)
 		APIError(ctx, http.StatusInternalServerError, "Gagal membuat dokumen.")
 		return
 	}
 
+	if req.Ajukan {
+		submittedDoc, err := c.docService.SubmitForApproval(createdDoc.ID, operatorID)
+		if err != nil {
+			// Draf sudah tersimpan; kegagalan pengajuan dilaporkan tanpa membatalkan draf.
+			c.handleWorkflowError(ctx, err, createdDoc, "mengajukan")
+			return
+		}
+		createdDoc = submittedDoc
+	}
+
 	ctx.JSON(http.StatusCreated, createdDoc)
-}
\ No newline at end of file
+}
+
+// @Summary Daftar Dokumen Menunggu Persetujuan
+// @Description Mengambil dokumen berstatus MENUNGGU_PERSETUJUAN yang menunjuk pengguna yang login sebagai pejabat persetuju.
+// @Tags Approvals
+// @Produce json
+// @Success 200 {array} models.LostDocument
+// @Failure 500 {object} map[string]string "Error: Gagal mengambil antrean persetujuan"
+// @Security BearerAuth
+// @Router /approvals [get]
+func (c *LostDocumentController) ApprovalQueue(ctx *gin.Context) {
+	documents, err := c.docService.FindApprovalQueue(ctx.GetUint("userID"))
+	if err != nil {
+		log.Printf("ERROR: Gagal mengambil antrean persetujuan: %v", err)
+		APIError(ctx, http.StatusInternalServerError, "Gagal mengambil antrean persetujuan.")
+		return
+	}
+	ctx.JSON(http.StatusOK, documents)
+}
+
+// @Summary Mengajukan Draf untuk Disetujui
+// @Description Mengubah status draf menjadi MENUNGGU_PERSETUJUAN. Hanya bisa dilakukan oleh operator pembuat draf, anggota regu yang sama, atau pengguna berizin document.edit_all.
+// @Tags Approvals
+// @Produce json
+// @Param id path int true "ID Dokumen"
+// @Success 200 {object} models.LostDocument
+// @Failure 400 {object} map[string]string "Error: Pejabat persetuju belum dipilih"
+// @Failure 403 {object} map[string]string "Error: Akses ditolak"
+// @Failure 404 {object} map[string]string "Error: Dokumen tidak ditemukan"
+// @Failure 409 {object} map[string]string "Error: Status dokumen tidak sesuai"
+// @Security BearerAuth
+// @Router /documents/{id}/submit [post]
+func (c *LostDocumentController) Submit(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		APIError(ctx, http.StatusBadRequest, "ID dokumen tidak valid")
+		return
+	}
+
+	doc, err := c.docService.SubmitForApproval(uint(id), ctx.GetUint("userID"))
+	if err != nil {
+		c.handleWorkflowError(ctx, err, nil, "mengajukan")
+		return
+	}
+	APIResponse(ctx, http.StatusOK, "Dokumen berhasil diajukan untuk persetujuan", doc)
+}
+
+// @Summary Menyetujui Dokumen
+// @Description Menyetujui dokumen yang menunggu persetujuan. Nomor Surat baru diterbitkan pada tahap ini. Hanya bisa dilakukan oleh pejabat persetuju yang ditunjuk pada dokumen, dan tidak oleh pembuat dokumen.
+// @Tags Approvals
+// @Produce json
+// @Param id path int true "ID Dokumen"
+// @Success 200 {object} models.LostDocument
+// @Failure 403 {object} map[string]string "Error: Akses ditolak"
+// @Failure 404 {object} map[string]string "Error: Dokumen tidak ditemukan"
+// @Failure 409 {object} map[string]string "Error: Status dokumen tidak sesuai"
+// @Security BearerAuth
+// @Router /documents/{id}/approve [post]
+func (c *LostDocumentController) Approve(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		APIError(ctx, http.StatusBadRequest, "ID dokumen tidak valid")
+		return
+	}
+
+	doc, err := c.docService.ApproveDocument(uint(id), ctx.GetUint("userID"))
+	if err != nil {
+		c.handleWorkflowError(ctx, err, nil, "menyetujui")
+		return
+	}
+	APIResponse(ctx, http.StatusOK, "Dokumen disetujui dengan Nomor Surat "+doc.NomorSurat, doc)
+}
+
+// @Summary Menolak Dokumen
+// @Description Mengembalikan dokumen yang menunggu persetujuan menjadi draf beserta alasan penolakan. Hanya bisa dilakukan oleh pejabat persetuju yang ditunjuk pada dokumen, dan tidak oleh pembuat dokumen.
+// @Tags Approvals
+// @Accept json
+// @Produce json
+// @Param id path int true "ID Dokumen"
+// @Param body body StatusReasonRequest true "Alasan Penolakan"
+// @Success 200 {object} models.LostDocument
+// @Failure 400 {object} map[string]string "Error: Alasan wajib diisi"
+// @Failure 403 {object} map[string]string "Error: Akses ditolak"
+// @Failure 409 {object} map[string]string "Error: Status dokumen tidak sesuai"
+// @Security BearerAuth
+// @Router /documents/{id}/reject [post]
+func (c *LostDocumentController) Reject(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		APIError(ctx, http.StatusBadRequest, "ID dokumen tidak valid")
+		return
+	}
+
+	var req StatusReasonRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		APIError(ctx, http.StatusBadRequest, "Alasan penolakan wajib diisi.")
+		return
+	}
+
+	doc, err := c.docService.RejectDocument(uint(id), ctx.GetUint("userID"), req.Alasan)
+	if err != nil {
+		c.handleWorkflowError(ctx, err, nil, "menolak")
+		return
+	}
+	APIResponse(ctx, http.StatusOK, "Dokumen dikembalikan ke operator sebagai draf", doc)
+}
+
+// @Summary Mencabut Dokumen
+// @Description Mencabut dokumen yang sudah terbit. Nomor Surat tetap tercatat, tetapi verifikasi QR akan menyatakan dokumen tidak berlaku. Hanya bisa dilakukan oleh pejabat persetuju dokumen atau pengguna berizin document.revoke.
+// @Tags Approvals
+// @Accept json
+// @Produce json
+// @Param id path int true "ID Dokumen"
+// @Param body body StatusReasonRequest true "Alasan Pencabutan"
+// @Success 200 {object} models.LostDocument
+// @Failure 400 {object} map[string]string "Error: Alasan wajib diisi"
+// @Failure 403 {object} map[string]string "Error: Akses ditolak"
+// @Failure 409 {object} map[string]string "Error: Status dokumen tidak sesuai"
+// @Security BearerAuth
+// @Router /documents/{id}/revoke [post]
+func (c *LostDocumentController) Revoke(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		APIError(ctx, http.StatusBadRequest, "ID dokumen tidak valid")
+		return
+	}
+
+	var req StatusReasonRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		APIError(ctx, http.StatusBadRequest, "Alasan pencabutan wajib diisi.")
+		return
+	}
+
+	doc, err := c.docService.RevokeDocument(uint(id), ctx.GetUint("userID"), req.Alasan)
+	if err != nil {
+		c.handleWorkflowError(ctx, err, nil, "mencabut")
+		return
+	}
+	APIResponse(ctx, http.StatusOK, "Dokumen berhasil dicabut", doc)
+}
+
+// handleWorkflowError memetakan kesalahan aksi alur persetujuan ke status HTTP.
+// savedDoc diisi jika dokumen sudah tersimpan sebelum aksi gagal, agar klien tetap menerimanya.
+func (c *LostDocumentController) handleWorkflowError(ctx *gin.Context, err error, savedDoc *models.LostDocument, aksi string) {
+	code := http.StatusInternalServerError
+	message := fmt.Sprintf("Gagal %s dokumen.", aksi)
+	switch {
+	case errors.Is(err, services.ErrAccessDenied):
+		code, message = http.StatusForbidden, err.Error()
+	case errors.Is(err, services.ErrInvalidStatusTransition):
+		code, message = http.StatusConflict, err.Error()
+	case errors.Is(err, services.ErrReasonRequired), errors.Is(err, services.ErrMissingRequiredField), errors.Is(err, services.ErrInvalidApprover):
+		code, message = http.StatusBadRequest, err.Error()
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		code, message = http.StatusNotFound, "Dokumen tidak ditemukan"
+	default:
+		log.Printf("ERROR: Gagal %s dokumen: %v", aksi, err)
+	}
+
+	if savedDoc != nil {
+		ctx.JSON(code, gin.H{"error": "Draf tersimpan, tetapi gagal diajukan: " + message, "data": savedDoc})
+		return
+	}
+	APIError(ctx, code, message)
+}
+
+// isDocumentInputError menandai kesalahan input dokumen yang dikembalikan sebagai 400.
+func isDocumentInputError(err error) bool {
+	return errors.Is(err, services.ErrInvalidNIK) ||
+		errors.Is(err, services.ErrInvalidDocumentType) ||
+		errors.Is(err, services.ErrMissingRequiredField) ||
+		errors.Is(err, services.ErrInvalidLostItem) ||
+		errors.Is(err, services.ErrInvalidApprover)
+}
+
+// optionalNIK mengubah NIK kosong dari formulir menjadi nil agar tidak tersimpan sebagai string kosong.
+func optionalNIK(nik string) *string {
+	nik = strings.TrimSpace(nik)
+	if nik == "" {
+		return nil
+	}
+	return &nik
+}
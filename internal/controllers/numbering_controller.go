@@ -0,0 +1,92 @@
+package controllers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"simdokpol/internal/models"
+	"simdokpol/internal/services"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type NumberingController struct {
+	numberingService services.DocumentNumberingService
+}
+
+func NewNumberingController(numberingService services.DocumentNumberingService) *NumberingController {
+	return &NumberingController{numberingService: numberingService}
+}
+
+// @Summary Laporan Celah Penomoran Surat
+// @Description Menampilkan nomor urut yang terlewat, milik dokumen terhapus, atau terpakai ganda dalam satu tahun. Memerlukan izin audit.view.
+// @Tags Numbering
+// @Produce json
+// @Param jenis query string false "Kode jenis dokumen (default: LOST_DOCUMENT)"
+// @Param year query int false "Tahun penomoran (default: tahun berjalan)"
+// @Success 200 {object} dto.NumberingGapReport
+// @Failure 400 {object} map[string]string "Error: Tahun tidak valid"
+// @Failure 500 {object} map[string]string "Error: Gagal membuat laporan penomoran"
+// @Security BearerAuth
+// @Router /numbering/gaps [get]
+func (c *NumberingController) GetGapReport(ctx *gin.Context) {
+	year := time.Now().Year()
+	if yearParam := ctx.Query("year"); yearParam != "" {
+		parsed, err := strconv.Atoi(yearParam)
+		if err != nil || parsed < 1900 {
+			APIError(ctx, http.StatusBadRequest, "Tahun tidak valid")
+			return
+		}
+		year = parsed
+	}
+
+	report, err := c.numberingService.GetGapReport(ctx.DefaultQuery("jenis", models.DocumentTypeLostDocument), year)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidDocumentType) {
+			APIError(ctx, http.StatusBadRequest, err.Error())
+			return
+		}
+		log.Printf("ERROR: Gagal membuat laporan celah penomoran tahun %d: %v", year, err)
+		APIError(ctx, http.StatusInternalServerError, "Gagal membuat laporan penomoran.")
+		return
+	}
+	ctx.JSON(http.StatusOK, report)
+}
+
+// @Summary Pratinjau Nomor Surat Berikutnya
+// @Description Menyusun Nomor Surat yang akan terbit berikutnya tanpa memakai nomor urutnya. Parameter format dapat diisi untuk mencoba format baru sebelum disimpan.
+// @Tags Numbering
+// @Produce json
+// @Param jenis query string false "Kode jenis dokumen (default: LOST_DOCUMENT)"
+// @Param format query string false "Format Nomor Surat (default: format tersimpan jenis dokumen)"
+// @Param kode_kantor query string false "Kode Kantor untuk placeholder {OFFICE_CODE} (default: kode tersimpan)"
+// @Param regu query string false "Regu untuk placeholder {REGU} (default: regu pengguna yang login)"
+// @Success 200 {object} dto.IssuedNumber
+// @Failure 400 {object} map[string]string "Error: Format nomor surat tidak valid"
+// @Failure 500 {object} map[string]string "Error: Gagal menyusun pratinjau nomor surat"
+// @Security BearerAuth
+// @Router /numbering/preview [get]
+func (c *NumberingController) PreviewNextNumber(ctx *gin.Context) {
+	regu, hasRegu := ctx.GetQuery("regu")
+	if !hasRegu {
+		if user, ok := ctx.Get("currentUser"); ok {
+			if currentUser, ok := user.(*models.User); ok {
+				regu = currentUser.Regu
+			}
+		}
+	}
+
+	preview, err := c.numberingService.PreviewNextNumber(ctx.DefaultQuery("jenis", models.DocumentTypeLostDocument), ctx.Query("format"), ctx.Query("kode_kantor"), regu)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidNumberFormat) || errors.Is(err, services.ErrInvalidDocumentType) {
+			APIError(ctx, http.StatusBadRequest, err.Error())
+			return
+		}
+		log.Printf("ERROR: Gagal menyusun pratinjau nomor surat: %v", err)
+		APIError(ctx, http.StatusInternalServerError, "Gagal menyusun pratinjau nomor surat.")
+		return
+	}
+	ctx.JSON(http.StatusOK, preview)
+}
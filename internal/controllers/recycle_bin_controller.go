@@ -0,0 +1,104 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"simdokpol/internal/services"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type RecycleBinController struct {
+	binService services.RecycleBinService
+}
+
+func NewRecycleBinController(binService services.RecycleBinService) *RecycleBinController {
+	return &RecycleBinController{binService: binService}
+}
+
+// @Summary Daftar Tong Sampah Dokumen
+// @Description Menampilkan dokumen yang sudah dihapus beserta alasan, penghapus, dan jadwal pemusnahan otomatisnya. Memerlukan izin recycle_bin.manage.
+// @Tags Recycle Bin
+// @Produce json
+// @Success 200 {array} dto.DeletedDocument
+// @Failure 500 {object} map[string]string "Error: Gagal memuat tong sampah"
+// @Security BearerAuth
+// @Router /recycle-bin/documents [get]
+func (c *RecycleBinController) FindDeletedDocuments(ctx *gin.Context) {
+	docs, err := c.binService.FindDeletedDocuments()
+	if err != nil {
+		log.Printf("ERROR: Gagal memuat tong sampah dokumen: %v", err)
+		APIError(ctx, http.StatusInternalServerError, "Gagal memuat tong sampah.")
+		return
+	}
+	ctx.JSON(http.StatusOK, docs)
+}
+
+// @Summary Pulihkan Dokumen Terhapus
+// @Description Mengembalikan dokumen dari tong sampah. Nomor Surat asli ikut dipulihkan bila belum dipakai dokumen lain; jika sudah, dokumen dipulihkan sebagai draf tanpa nomor. Memerlukan izin recycle_bin.manage.
+// @Tags Recycle Bin
+// @Produce json
+// @Param id path int true "ID Dokumen"
+// @Success 200 {object} dto.RestoredDocument
+// @Failure 400 {object} map[string]string "Error: ID tidak valid"
+// @Failure 404 {object} map[string]string "Error: Dokumen tidak ada di tong sampah"
+// @Security BearerAuth
+// @Router /recycle-bin/documents/{id}/restore [post]
+func (c *RecycleBinController) RestoreDocument(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		APIError(ctx, http.StatusBadRequest, "ID dokumen tidak valid")
+		return
+	}
+
+	restored, err := c.binService.RestoreDocument(uint(id), ctx.GetUint("userID"))
+	if err != nil {
+		c.handleError(ctx, err, "memulihkan")
+		return
+	}
+
+	message := "Dokumen berhasil dipulihkan."
+	if restored.NomorBentrok {
+		message = fmt.Sprintf("Nomor Surat %s sudah dipakai dokumen lain, sehingga dokumen dipulihkan sebagai draf dan perlu diajukan ulang untuk mendapat nomor baru.", restored.NomorAsli)
+	} else if restored.NomorSurat != "" {
+		message = fmt.Sprintf("Dokumen berhasil dipulihkan dengan Nomor Surat %s.", restored.NomorSurat)
+	}
+	APIResponse(ctx, http.StatusOK, message, restored)
+}
+
+// @Summary Musnahkan Dokumen
+// @Description Menghapus permanen dokumen dari tong sampah beserta barang dan riwayat revisinya. Tidak dapat dibatalkan. Memerlukan izin recycle_bin.manage.
+// @Tags Recycle Bin
+// @Produce json
+// @Param id path int true "ID Dokumen"
+// @Success 200 {object} map[string]string "Pesan Sukses"
+// @Failure 400 {object} map[string]string "Error: ID tidak valid"
+// @Failure 404 {object} map[string]string "Error: Dokumen tidak ada di tong sampah"
+// @Security BearerAuth
+// @Router /recycle-bin/documents/{id} [delete]
+func (c *RecycleBinController) PurgeDocument(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		APIError(ctx, http.StatusBadRequest, "ID dokumen tidak valid")
+		return
+	}
+
+	if err := c.binService.PurgeDocument(uint(id), ctx.GetUint("userID")); err != nil {
+		c.handleError(ctx, err, "memusnahkan")
+		return
+	}
+	APIResponse(ctx, http.StatusOK, "Dokumen berhasil dimusnahkan secara permanen.", nil)
+}
+
+func (c *RecycleBinController) handleError(ctx *gin.Context, err error, aksi string) {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		APIError(ctx, http.StatusNotFound, "Dokumen tidak ada di tong sampah.")
+		return
+	}
+	log.Printf("ERROR: Gagal %s dokumen dari tong sampah: %v", aksi, err)
+	APIError(ctx, http.StatusInternalServerError, fmt.Sprintf("Gagal %s dokumen.", aksi))
+}
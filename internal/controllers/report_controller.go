@@ -0,0 +1,250 @@
+package controllers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"simdokpol/internal/dto"
+	"simdokpol/internal/models"
+	"simdokpol/internal/services"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// reportTimeLayout adalah format parameter waktu laporan, sama dengan nilai input datetime-local.
+const reportTimeLayout = "2006-01-02T15:04"
+
+// defaultHandoverPeriod adalah rentang laporan serah terima jika parameter from tidak diisi.
+const defaultHandoverPeriod = 24 * time.Hour
+
+type ReportController struct {
+	reportService services.ReportService
+	pdfService    services.PDFService
+	configService services.ConfigService
+}
+
+func NewReportController(reportService services.ReportService, pdfService services.PDFService, configService services.ConfigService) *ReportController {
+	return &ReportController{reportService: reportService, pdfService: pdfService, configService: configService}
+}
+
+// @Summary Laporan Serah Terima Jaga
+// @Description Menyusun laporan serah terima satu regu: dokumen yang terbit, diubah, dan dihapus dalam rentang waktu, draf dan pengajuan yang belum selesai, serta anomali. Memerlukan izin report.handover; laporan regu lain juga memerlukan izin document.view_all.
+// @Tags Reports
+// @Produce json
+// @Param regu query string false "Regu (default: regu pengguna yang login)"
+// @Param from query string false "Awal periode, format 2006-01-02T15:04 (default: 24 jam sebelum to)"
+// @Param to query string false "Akhir periode, format 2006-01-02T15:04 (default: sekarang)"
+// @Success 200 {object} dto.HandoverReport
+// @Failure 400 {object} map[string]string "Error: Rentang waktu laporan tidak valid"
+// @Failure 403 {object} map[string]string "Error: Akses ditolak"
+// @Failure 500 {object} map[string]string "Error: Gagal menyusun laporan serah terima"
+// @Security BearerAuth
+// @Router /reports/handover [get]
+func (c *ReportController) Handover(ctx *gin.Context) {
+	report, ok := c.buildHandoverReport(ctx)
+	if !ok {
+		return
+	}
+	ctx.JSON(http.StatusOK, report)
+}
+
+// @Summary Unduh PDF Laporan Serah Terima Jaga
+// @Description Merender laporan serah terima jaga menjadi file PDF siap cetak. Parameter dan izin sama dengan /reports/handover.
+// @Tags Reports
+// @Produce application/pdf
+// @Param regu query string false "Regu (default: regu pengguna yang login)"
+// @Param from query string false "Awal periode, format 2006-01-02T15:04 (default: 24 jam sebelum to)"
+// @Param to query string false "Akhir periode, format 2006-01-02T15:04 (default: sekarang)"
+// @Success 200 {file} file "File PDF laporan"
+// @Failure 400 {object} map[string]string "Error: Rentang waktu laporan tidak valid"
+// @Failure 403 {object} map[string]string "Error: Akses ditolak"
+// @Failure 500 {object} map[string]string "Error: Gagal membuat PDF laporan"
+// @Security BearerAuth
+// @Router /reports/handover/pdf [get]
+func (c *ReportController) HandoverPDF(ctx *gin.Context) {
+	report, ok := c.buildHandoverReport(ctx)
+	if !ok {
+		return
+	}
+	pdfBytes, err := c.pdfService.GenerateHandoverReportPDF(report)
+	if err != nil {
+		log.Printf("ERROR: Gagal membuat PDF laporan serah terima regu %s: %v", report.Regu, err)
+		APIError(ctx, http.StatusInternalServerError, "Gagal membuat PDF laporan.")
+		return
+	}
+	fileName := fmt.Sprintf("serah-terima-regu-%s-%s.pdf", strings.ToLower(report.Regu), report.To.Format("20060102-1504"))
+	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", fileName))
+	ctx.Data(http.StatusOK, "application/pdf", pdfBytes)
+}
+
+// buildHandoverReport membaca parameter laporan serah terima dan memanggil service.
+// Nilai false berarti respons error sudah dikirim.
+func (c *ReportController) buildHandoverReport(ctx *gin.Context) (*dto.HandoverReport, bool) {
+	loc, err := c.configService.GetLocation()
+	if err != nil {
+		loc = time.UTC
+	}
+
+	to := time.Now().In(loc)
+	if toParam := ctx.Query("to"); toParam != "" {
+		if to, err = time.ParseInLocation(reportTimeLayout, toParam, loc); err != nil {
+			APIError(ctx, http.StatusBadRequest, "Format waktu akhir periode tidak valid.")
+			return nil, false
+		}
+	}
+	from := to.Add(-defaultHandoverPeriod)
+	if fromParam := ctx.Query("from"); fromParam != "" {
+		if from, err = time.ParseInLocation(reportTimeLayout, fromParam, loc); err != nil {
+			APIError(ctx, http.StatusBadRequest, "Format waktu awal periode tidak valid.")
+			return nil, false
+		}
+	}
+
+	regu := ctx.Query("regu")
+	if regu == "" {
+		if user, ok := ctx.Get("currentUser"); ok {
+			if currentUser, ok := user.(*models.User); ok {
+				regu = currentUser.Regu
+			}
+		}
+	}
+
+	report, err := c.reportService.GenerateHandoverReport(regu, from, to, ctx.GetUint("userID"))
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrAccessDenied):
+			APIError(ctx, http.StatusForbidden, err.Error())
+		case errors.Is(err, services.ErrInvalidReportPeriod):
+			APIError(ctx, http.StatusBadRequest, fmt.Sprintf("Rentang waktu laporan tidak valid (maksimal %d hari).", int(services.MaxHandoverPeriod.Hours()/24)))
+		case errors.Is(err, services.ErrMissingRequiredField):
+			APIError(ctx, http.StatusBadRequest, err.Error())
+		default:
+			log.Printf("ERROR: Gagal menyusun laporan serah terima regu %s: %v", regu, err)
+			APIError(ctx, http.StatusInternalServerError, "Gagal menyusun laporan serah terima.")
+		}
+		return nil, false
+	}
+	return report, true
+}
+
+// @Summary Laporan Statistik Periodik
+// @Description Menyusun rekap dokumen terbit untuk satu bulan, triwulan, atau tahun: per jenis barang, operator, petugas pelapor, kata kunci lokasi, dan hari, beserta perbandingan dengan periode yang sama tahun sebelumnya. Memerlukan izin report.view.
+// @Tags Reports
+// @Produce json
+// @Param period query string false "Jenis periode" enums(monthly, quarterly, yearly) default(monthly)
+// @Param year query int false "Tahun (default: tahun berjalan)"
+// @Param month query int false "Bulan 1-12 untuk periode monthly (default: bulan berjalan)"
+// @Param quarter query int false "Triwulan 1-4 untuk periode quarterly (default: triwulan berjalan)"
+// @Param keywords query string false "Kata kunci lokasi dipisah koma (default: kata yang paling sering muncul)"
+// @Success 200 {object} dto.StatisticsReport
+// @Failure 400 {object} map[string]string "Error: Periode laporan tidak valid"
+// @Failure 500 {object} map[string]string "Error: Gagal menyusun laporan statistik"
+// @Security BearerAuth
+// @Router /reports/statistics [get]
+func (c *ReportController) Statistics(ctx *gin.Context) {
+	report, ok := c.buildStatisticsReport(ctx)
+	if !ok {
+		return
+	}
+	ctx.JSON(http.StatusOK, report)
+}
+
+// @Summary Ekspor Laporan Statistik Periodik
+// @Description Mengunduh laporan statistik periodik sebagai file Excel (XLSX, satu lembar per rekap) atau CSV. Parameter sama dengan /reports/statistics. Memerlukan izin report.view.
+// @Tags Reports
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Produce text/csv
+// @Param format query string false "Format file" enums(xlsx, csv) default(xlsx)
+// @Param period query string false "Jenis periode" enums(monthly, quarterly, yearly) default(monthly)
+// @Param year query int false "Tahun (default: tahun berjalan)"
+// @Param month query int false "Bulan 1-12 untuk periode monthly (default: bulan berjalan)"
+// @Param quarter query int false "Triwulan 1-4 untuk periode quarterly (default: triwulan berjalan)"
+// @Param keywords query string false "Kata kunci lokasi dipisah koma (default: kata yang paling sering muncul)"
+// @Success 200 {file} file "File laporan"
+// @Failure 400 {object} map[string]string "Error: Format atau periode laporan tidak valid"
+// @Failure 500 {object} map[string]string "Error: Gagal membuat file laporan"
+// @Security BearerAuth
+// @Router /reports/statistics/export [get]
+func (c *ReportController) ExportStatistics(ctx *gin.Context) {
+	format := ctx.DefaultQuery("format", "xlsx")
+	if format != "xlsx" && format != "csv" {
+		APIError(ctx, http.StatusBadRequest, "Format file harus xlsx atau csv.")
+		return
+	}
+	report, ok := c.buildStatisticsReport(ctx)
+	if !ok {
+		return
+	}
+
+	var buf bytes.Buffer
+	sheets := services.StatisticsSheets(report)
+	contentType := "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	var err error
+	if format == "csv" {
+		contentType = "text/csv; charset=utf-8"
+		err = services.WriteCSV(&buf, sheets)
+	} else {
+		err = services.WriteXLSX(&buf, sheets)
+	}
+	if err != nil {
+		log.Printf("ERROR: Gagal membuat file %s laporan statistik %s: %v", format, report.Label, err)
+		APIError(ctx, http.StatusInternalServerError, "Gagal membuat file laporan.")
+		return
+	}
+
+	fileName := fmt.Sprintf("statistik-%s.%s", strings.ReplaceAll(strings.ToLower(report.Label), " ", "-"), format)
+	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", fileName))
+	ctx.Data(http.StatusOK, contentType, buf.Bytes())
+}
+
+// buildStatisticsReport membaca parameter laporan statistik dan memanggil service.
+// Nilai false berarti respons error sudah dikirim.
+func (c *ReportController) buildStatisticsReport(ctx *gin.Context) (*dto.StatisticsReport, bool) {
+	loc, err := c.configService.GetLocation()
+	if err != nil {
+		loc = time.UTC
+	}
+	now := time.Now().In(loc)
+
+	period := ctx.DefaultQuery("period", dto.PeriodMonthly)
+	year, err := strconv.Atoi(ctx.DefaultQuery("year", strconv.Itoa(now.Year())))
+	if err != nil {
+		APIError(ctx, http.StatusBadRequest, "Tahun tidak valid")
+		return nil, false
+	}
+	number := 0
+	switch period {
+	case dto.PeriodMonthly:
+		number, err = strconv.Atoi(ctx.DefaultQuery("month", strconv.Itoa(int(now.Month()))))
+	case dto.PeriodQuarterly:
+		number, err = strconv.Atoi(ctx.DefaultQuery("quarter", strconv.Itoa((int(now.Month())-1)/3+1)))
+	}
+	if err != nil {
+		APIError(ctx, http.StatusBadRequest, "Periode laporan tidak valid.")
+		return nil, false
+	}
+
+	var keywords []string
+	for _, keyword := range strings.Split(ctx.Query("keywords"), ",") {
+		if keyword = strings.TrimSpace(keyword); keyword != "" {
+			keywords = append(keywords, keyword)
+		}
+	}
+
+	report, err := c.reportService.GenerateStatisticsReport(period, year, number, keywords)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidReportPeriod) {
+			APIError(ctx, http.StatusBadRequest, "Periode laporan tidak valid.")
+			return nil, false
+		}
+		log.Printf("ERROR: Gagal menyusun laporan statistik %s %d/%d: %v", period, number, year, err)
+		APIError(ctx, http.StatusInternalServerError, "Gagal menyusun laporan statistik.")
+		return nil, false
+	}
+	return report, true
+}
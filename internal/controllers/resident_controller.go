@@ -0,0 +1,136 @@
+package controllers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"simdokpol/internal/services"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ResidentController struct {
+	residentService services.ResidentService
+}
+
+func NewResidentController(residentService services.ResidentService) *ResidentController {
+	return &ResidentController{residentService: residentService}
+}
+
+// MergeResidentsRequest adalah DTO untuk menggabungkan data penduduk duplikat.
+type MergeResidentsRequest struct {
+	PrimaryID    uint   `json:"primary_id" binding:"required" example:"1"`
+	DuplicateIDs []uint `json:"duplicate_ids" binding:"required,min=1" example:"2,3"`
+}
+
+// @Summary Mencari Data Penduduk
+// @Description Mencari penduduk berdasarkan awalan NIK atau potongan nama. Digunakan untuk autocomplete pada formulir surat. Memerlukan izin document.create.
+// @Tags Residents
+// @Produce json
+// @Param q query string false "Awalan NIK atau potongan nama"
+// @Param limit query int false "Jumlah hasil maksimal (default 10, maks 50)"
+// @Success 200 {array} models.Resident
+// @Failure 403 {object} map[string]string "Error: Akses ditolak"
+// @Failure 500 {object} map[string]string "Error: Gagal mencari data penduduk"
+// @Security BearerAuth
+// @Router /residents [get]
+func (c *ResidentController) Search(ctx *gin.Context) {
+	limit, _ := strconv.Atoi(ctx.Query("limit"))
+
+	residents, err := c.residentService.Search(ctx.Query("q"), limit)
+	if err != nil {
+		log.Printf("ERROR: Gagal mencari data penduduk: %v", err)
+		APIError(ctx, http.StatusInternalServerError, "Gagal mencari data penduduk.")
+		return
+	}
+	ctx.JSON(http.StatusOK, residents)
+}
+
+// @Summary Mendapatkan Data Penduduk Berdasarkan ID
+// @Description Mengambil detail satu data penduduk untuk mengisi formulir surat. Memerlukan izin document.create.
+// @Tags Residents
+// @Produce json
+// @Param id path int true "ID Penduduk"
+// @Success 200 {object} models.Resident
+// @Failure 400 {object} map[string]string "Error: ID tidak valid"
+// @Failure 403 {object} map[string]string "Error: Akses ditolak"
+// @Failure 404 {object} map[string]string "Error: Penduduk tidak ditemukan"
+// @Security BearerAuth
+// @Router /residents/{id} [get]
+func (c *ResidentController) FindByID(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		APIError(ctx, http.StatusBadRequest, "ID penduduk tidak valid")
+		return
+	}
+
+	resident, err := c.residentService.FindByID(uint(id))
+	if err != nil {
+		if errors.Is(err, services.ErrNotFound) {
+			APIError(ctx, http.StatusNotFound, "Penduduk tidak ditemukan")
+			return
+		}
+		log.Printf("ERROR: Gagal mengambil data penduduk id %d: %v", id, err)
+		APIError(ctx, http.StatusInternalServerError, "Gagal mengambil data penduduk.")
+		return
+	}
+	ctx.JSON(http.StatusOK, resident)
+}
+
+// @Summary Daftar Kandidat Penduduk Duplikat
+// @Description Mengelompokkan data penduduk yang memiliki nama dan tanggal lahir sama sebagai kandidat penggabungan. Memerlukan izin resident.merge.
+// @Tags Residents
+// @Produce json
+// @Success 200 {array} repositories.DuplicateResidentGroup
+// @Failure 500 {object} map[string]string "Error: Gagal memuat data duplikat"
+// @Security BearerAuth
+// @Router /residents/duplicates [get]
+func (c *ResidentController) FindDuplicates(ctx *gin.Context) {
+	groups, err := c.residentService.FindDuplicateGroups()
+	if err != nil {
+		log.Printf("ERROR: Gagal memuat kandidat penduduk duplikat: %v", err)
+		APIError(ctx, http.StatusInternalServerError, "Gagal memuat data penduduk duplikat.")
+		return
+	}
+	ctx.JSON(http.StatusOK, groups)
+}
+
+// @Summary Menggabungkan Data Penduduk Duplikat
+// @Description Memindahkan semua dokumen milik data duplikat ke data utama, lalu menghapus data duplikat. Memerlukan izin resident.merge.
+// @Tags Residents
+// @Accept json
+// @Produce json
+// @Param merge body MergeResidentsRequest true "Data utama dan daftar duplikat"
+// @Success 200 {object} map[string]interface{} "Pesan sukses dan data penduduk utama"
+// @Failure 400 {object} map[string]string "Error: Input tidak valid"
+// @Failure 404 {object} map[string]string "Error: Penduduk tidak ditemukan"
+// @Failure 409 {object} map[string]string "Error: NIK berbeda"
+// @Failure 500 {object} map[string]string "Error: Gagal menggabungkan data penduduk"
+// @Security BearerAuth
+// @Router /residents/merge [post]
+func (c *ResidentController) Merge(ctx *gin.Context) {
+	var req MergeResidentsRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		APIError(ctx, http.StatusBadRequest, "Input tidak valid: "+err.Error())
+		return
+	}
+
+	primary, err := c.residentService.Merge(req.PrimaryID, req.DuplicateIDs, ctx.GetUint("userID"))
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrNotFound):
+			APIError(ctx, http.StatusNotFound, "Sebagian data penduduk tidak ditemukan")
+		case errors.Is(err, services.ErrResidentMergeConflict):
+			APIError(ctx, http.StatusConflict, err.Error())
+		case errors.Is(err, services.ErrInvalidResidentMerge):
+			APIError(ctx, http.StatusBadRequest, err.Error())
+		default:
+			log.Printf("ERROR: Gagal menggabungkan data penduduk ke id %d: %v", req.PrimaryID, err)
+			APIError(ctx, http.StatusInternalServerError, "Gagal menggabungkan data penduduk.")
+		}
+		return
+	}
+
+	APIResponse(ctx, http.StatusOK, "Data penduduk berhasil digabungkan", primary)
+}
@@ -0,0 +1,152 @@
+package controllers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"simdokpol/internal/models"
+	"simdokpol/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RoleRequest adalah DTO untuk membuat atau memperbarui peran.
+type RoleRequest struct {
+	Kode        string   `json:"kode" example:"KANIT_SPKT"` // Hanya dipakai saat membuat; tidak dapat diubah
+	Nama        string   `json:"nama" binding:"required" example:"Kanit SPKT"`
+	Deskripsi   string   `json:"deskripsi" example:"Melihat seluruh surat dan menyetujui surat"`
+	Permissions []string `json:"permissions" example:"document.view_all,document.approve"`
+}
+
+type RoleController struct {
+	roleService services.RoleService
+}
+
+func NewRoleController(roleService services.RoleService) *RoleController {
+	return &RoleController{roleService: roleService}
+}
+
+// @Summary Mendapatkan Semua Peran
+// @Description Mengambil daftar peran beserta izin yang dibundelnya. Memerlukan izin user.manage.
+// @Tags Roles
+// @Produce json
+// @Success 200 {array} models.Role
+// @Failure 500 {object} map[string]string "Error: Gagal mengambil data peran"
+// @Security BearerAuth
+// @Router /roles [get]
+func (c *RoleController) FindAll(ctx *gin.Context) {
+	roles, err := c.roleService.FindAll()
+	if err != nil {
+		log.Printf("ERROR: Gagal mengambil data peran: %v", err)
+		APIError(ctx, http.StatusInternalServerError, "Gagal mengambil data peran.")
+		return
+	}
+	ctx.JSON(http.StatusOK, roles)
+}
+
+// @Summary Mendapatkan Katalog Izin
+// @Description Mengambil semua izin yang dapat dibundel ke dalam peran. Memerlukan izin user.manage.
+// @Tags Roles
+// @Produce json
+// @Success 200 {array} services.PermissionInfo
+// @Security BearerAuth
+// @Router /permissions [get]
+func (c *RoleController) Permissions(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, services.AvailablePermissions)
+}
+
+// @Summary Menambahkan Peran
+// @Description Membuat peran baru dari sejumlah izin. Memerlukan izin user.manage.
+// @Tags Roles
+// @Accept json
+// @Produce json
+// @Param role body RoleRequest true "Definisi Peran"
+// @Success 201 {object} models.Role
+// @Failure 400 {object} map[string]string "Error: Definisi peran tidak valid"
+// @Failure 500 {object} map[string]string "Error: Gagal menyimpan peran"
+// @Security BearerAuth
+// @Router /roles [post]
+func (c *RoleController) Create(ctx *gin.Context) {
+	var req RoleRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		APIError(ctx, http.StatusBadRequest, "Input tidak valid: "+err.Error())
+		return
+	}
+
+	created, err := c.roleService.Create(req.toModel(), ctx.GetUint("userID"))
+	if err != nil {
+		c.handleSaveError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, created)
+}
+
+// @Summary Memperbarui Peran
+// @Description Memperbarui nama, deskripsi, dan izin sebuah peran. Kode peran dan peran Super Admin tidak dapat diubah. Memerlukan izin user.manage.
+// @Tags Roles
+// @Accept json
+// @Produce json
+// @Param kode path string true "Kode Peran"
+// @Param role body RoleRequest true "Definisi Peran"
+// @Success 200 {object} models.Role
+// @Failure 400 {object} map[string]string "Error: Definisi peran tidak valid"
+// @Failure 404 {object} map[string]string "Error: Peran tidak ditemukan"
+// @Failure 500 {object} map[string]string "Error: Gagal menyimpan peran"
+// @Security BearerAuth
+// @Router /roles/{kode} [put]
+func (c *RoleController) Update(ctx *gin.Context) {
+	var req RoleRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		APIError(ctx, http.StatusBadRequest, "Input tidak valid: "+err.Error())
+		return
+	}
+
+	updated, err := c.roleService.Update(ctx.Param("kode"), req.toModel(), ctx.GetUint("userID"))
+	if err != nil {
+		c.handleSaveError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, updated)
+}
+
+// @Summary Menghapus Peran
+// @Description Menghapus peran buatan pengguna yang tidak lagi dipakai. Peran bawaan tidak dapat dihapus. Memerlukan izin user.manage.
+// @Tags Roles
+// @Produce json
+// @Param kode path string true "Kode Peran"
+// @Success 200 {object} map[string]string "Pesan Sukses"
+// @Failure 400 {object} map[string]string "Error: Peran bawaan tidak dapat dihapus"
+// @Failure 404 {object} map[string]string "Error: Peran tidak ditemukan"
+// @Failure 409 {object} map[string]string "Error: Peran masih dipakai oleh pengguna"
+// @Security BearerAuth
+// @Router /roles/{kode} [delete]
+func (c *RoleController) Delete(ctx *gin.Context) {
+	if err := c.roleService.Delete(ctx.Param("kode"), ctx.GetUint("userID")); err != nil {
+		c.handleSaveError(ctx, err)
+		return
+	}
+	APIResponse(ctx, http.StatusOK, "Peran berhasil dihapus", nil)
+}
+
+func (c *RoleController) handleSaveError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrNotFound):
+		APIError(ctx, http.StatusNotFound, "Peran tidak ditemukan")
+	case errors.Is(err, services.ErrInvalidRole):
+		APIError(ctx, http.StatusBadRequest, err.Error())
+	case errors.Is(err, services.ErrRoleInUse):
+		APIError(ctx, http.StatusConflict, err.Error())
+	default:
+		log.Printf("ERROR: Gagal menyimpan peran: %v", err)
+		APIError(ctx, http.StatusInternalServerError, "Gagal menyimpan peran.")
+	}
+}
+
+func (req RoleRequest) toModel() *models.Role {
+	return &models.Role{
+		Kode:        req.Kode,
+		Nama:        req.Nama,
+		Deskripsi:   req.Deskripsi,
+		Permissions: req.Permissions,
+	}
+}
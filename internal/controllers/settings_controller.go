@@ -1,29 +1,33 @@
 package controllers
 
 import (
+	"errors"
 	"log"
 	"net/http"
 	"simdokpol/internal/models"
 	"simdokpol/internal/services"
+	"strconv"
 	"strings"
 
 	"github.com/gin-gonic/gin"
 )
 
 type SettingsController struct {
-	configService services.ConfigService
-	auditService  services.AuditLogService
+	configService    services.ConfigService
+	auditService     services.AuditLogService
+	numberingService services.DocumentNumberingService
 }
 
-func NewSettingsController(configService services.ConfigService, auditService services.AuditLogService) *SettingsController {
+func NewSettingsController(configService services.ConfigService, auditService services.AuditLogService, numberingService services.DocumentNumberingService) *SettingsController {
 	return &SettingsController{
-		configService: configService,
-		auditService:  auditService,
+		configService:    configService,
+		auditService:     auditService,
+		numberingService: numberingService,
 	}
 }
 
 // @Summary Mendapatkan Semua Pengaturan Sistem
-// @Description Mengambil semua data konfigurasi sistem yang sedang aktif. Hanya bisa diakses oleh Super Admin.
+// @Description Mengambil semua data konfigurasi sistem yang sedang aktif. Memerlukan izin settings.edit.
 // @Tags Settings
 // @Produce json
 // @Success 200 {object} dto.AppConfig
@@ -41,7 +45,7 @@ func (c *SettingsController) GetSettings(ctx *gin.Context) {
 }
 
 // @Summary Memperbarui Pengaturan Sistem
-// @Description Menyimpan satu atau lebih data konfigurasi sistem. Hanya bisa diakses oleh Super Admin.
+// @Description Menyimpan satu atau lebih data konfigurasi sistem. Memerlukan izin settings.edit.
 // @Tags Settings
 // @Accept json
 // @Produce json
@@ -66,14 +70,84 @@ func (c *SettingsController) UpdateSettings(ctx *gin.Context) {
 		}
 	}
 
+	if lastNumber, exists := settings["nomor_surat_terakhir"]; exists {
+		if n, err := strconv.Atoi(lastNumber); err != nil || n < 0 {
+			APIError(ctx, http.StatusBadRequest, "Nomor surat terakhir harus berupa angka.")
+			return
+		}
+	}
+
+	if retention, exists := settings["recycle_bin_retention_days"]; exists {
+		if n, err := strconv.Atoi(retention); err != nil || n < 1 {
+			APIError(ctx, http.StatusBadRequest, "Masa simpan tong sampah harus berupa angka minimal 1 hari.")
+			return
+		}
+	}
+
+	if schedule, exists := settings["backup_schedule"]; exists && strings.TrimSpace(schedule) != "" {
+		if _, err := services.ParseBackupSchedule(schedule); err != nil {
+			APIError(ctx, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	for _, key := range []string{"backup_keep_daily", "backup_keep_weekly", "backup_keep_monthly"} {
+		if keep, exists := settings[key]; exists {
+			if n, err := strconv.Atoi(keep); err != nil || n < 0 {
+				APIError(ctx, http.StatusBadRequest, "Jumlah backup yang disimpan harus berupa angka minimal 0.")
+				return
+			}
+		}
+	}
+
+	if err := c.validateNumberFormat(settings); err != nil {
+		if errors.Is(err, services.ErrInvalidNumberFormat) {
+			APIError(ctx, http.StatusBadRequest, err.Error())
+			return
+		}
+		log.Printf("ERROR: Gagal memvalidasi format nomor surat: %v", err)
+		APIError(ctx, http.StatusInternalServerError, "Gagal memvalidasi format nomor surat.")
+		return
+	}
+
 	if err := c.configService.SaveConfig(settings); err != nil {
 		log.Printf("ERROR: Gagal menyimpan pengaturan: %v", err)
 		APIError(ctx, http.StatusInternalServerError, "Gagal menyimpan pengaturan.")
 		return
 	}
 
+	if lastNumber, exists := settings["nomor_surat_terakhir"]; exists {
+		if err := c.numberingService.SyncLastNumber(lastNumber); err != nil {
+			log.Printf("ERROR: Gagal menyinkronkan nomor surat terakhir: %v", err)
+			APIError(ctx, http.StatusInternalServerError, "Pengaturan tersimpan, tetapi nomor surat terakhir gagal diterapkan.")
+			return
+		}
+	}
+
 	actorID := ctx.GetUint("userID")
 	c.auditService.LogActivity(actorID, models.AuditSettingsUpdated, "Pengaturan sistem telah diperbarui.")
 
 	APIResponse(ctx, http.StatusOK, "Pengaturan berhasil disimpan", nil)
-}
\ No newline at end of file
+}
+
+// validateNumberFormat memeriksa format Nomor Surat dan Kode Kantor hasil gabungan
+// pengaturan yang dikirim dengan pengaturan yang sudah tersimpan.
+func (c *SettingsController) validateNumberFormat(settings map[string]string) error {
+	format, formatChanged := settings["format_nomor_surat"]
+	officeCode, officeCodeChanged := settings["kode_kantor"]
+	if !formatChanged && !officeCodeChanged {
+		return nil
+	}
+
+	current, err := c.configService.GetConfig()
+	if err != nil {
+		return err
+	}
+	if !formatChanged {
+		format = current.FormatNomorSurat
+	}
+	if !officeCodeChanged {
+		officeCode = current.KodeKantor
+	}
+	return c.numberingService.ValidateFormat(format, officeCode)
+}
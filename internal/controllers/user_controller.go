@@ -7,6 +7,7 @@ import (
 	"simdokpol/internal/models"
 	"simdokpol/internal/services"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -24,7 +25,7 @@ type CreateUserRequest struct {
 	NRP         string `json:"nrp" binding:"required" example:"98765"`
 	KataSandi   string `json:"kata_sandi" binding:"required,min=8" example:"password123"`
 	Pangkat     string `json:"pangkat" binding:"required" example:"BRIPDA"`
-	Peran       string `json:"peran" binding:"required" enums:"OPERATOR,SUPER_ADMIN"`
+	Peran       string `json:"peran" binding:"required" example:"OPERATOR"` // Kode peran, lihat GET /roles
 	Jabatan     string `json:"jabatan" binding:"required" example:"ANGGOTA JAGA REGU"`
 	Regu        string `json:"regu" example:"I"`
 }
@@ -126,13 +127,14 @@ func (c *UserController) ChangePassword(ctx *gin.Context) {
 }
 
 // @Summary Membuat Pengguna Baru
-// @Description Membuat akun pengguna baru (Operator atau Super Admin). Hanya bisa diakses oleh Super Admin.
+// @Description Membuat akun pengguna baru dengan peran yang terdaftar. Memerlukan izin user.manage; hanya Super Admin yang dapat membuat akun Super Admin.
 // @Tags Users
 // @Accept json
 // @Produce json
 // @Param user body CreateUserRequest true "Data Pengguna Baru"
 // @Success 201 {object} models.User
-// @Failure 400 {object} map[string]string "Error: Input tidak valid"
+// @Failure 400 {object} map[string]string "Error: Input atau peran tidak valid"
+// @Failure 403 {object} map[string]string "Error: Hanya Super Admin yang dapat membuat akun Super Admin"
 // @Failure 500 {object} map[string]string "Error: Terjadi kesalahan pada server"
 // @Security BearerAuth
 // @Router /users [post]
@@ -155,6 +157,9 @@ func (c *UserController) Create(ctx *gin.Context) {
 	}
 
 	if err := c.userService.Create(&user, actorID); err != nil {
+		if c.handleRoleError(ctx, err) {
+			return
+		}
 		log.Printf("ERROR: Gagal membuat pengguna: %v", err)
 		APIError(ctx, http.StatusInternalServerError, "Gagal membuat pengguna.")
 		return
@@ -190,6 +195,9 @@ func (c *UserController) Update(ctx *gin.Context) {
 	}
 
 	if err := c.userService.Update(&user, req.KataSandi, actorID); err != nil {
+		if c.handleRoleError(ctx, err) {
+			return
+		}
 		log.Printf("ERROR: Gagal memperbarui pengguna id %d: %v", id, err)
 		APIError(ctx, http.StatusInternalServerError, "Gagal memperbarui pengguna.")
 		return
@@ -206,6 +214,9 @@ func (c *UserController) Delete(ctx *gin.Context) {
 	actorID := ctx.GetUint("userID")
 
 	if err := c.userService.Deactivate(uint(id), actorID); err != nil {
+		if c.handleRoleError(ctx, err) {
+			return
+		}
 		log.Printf("ERROR: Gagal menonaktifkan pengguna id %d: %v", id, err)
 		APIError(ctx, http.StatusInternalServerError, "Gagal menonaktifkan pengguna.")
 		return
@@ -229,24 +240,35 @@ func (c *UserController) Activate(ctx *gin.Context) {
 	APIResponse(ctx, http.StatusOK, "Pengguna berhasil diaktifkan", nil)
 }
 
-// @Summary Mendapatkan Semua Pengguna
-// @Description Mengambil daftar semua pengguna (aktif atau non-aktif). Hanya bisa diakses oleh Super Admin.
+// @Summary Mendapatkan Daftar Pengguna
+// @Description Mengambil satu halaman daftar pengguna (aktif atau non-aktif) dengan pengurutan dan pencarian di server. Memerlukan izin user.manage.
 // @Tags Users
 // @Produce json
 // @Param status query string false "Filter status pengguna" enums(active, inactive) default(active)
-// @Success 200 {array} models.User
+// @Param page query int false "Nomor halaman, mulai dari 1" default(1)
+// @Param size query int false "Jumlah baris per halaman (maksimal 500)" default(25)
+// @Param sort query string false "Kolom pengurutan" enums(nama_lengkap, nrp, pangkat, jabatan, regu, peran)
+// @Param dir query string false "Arah pengurutan" enums(asc, desc)
+// @Param q query string false "Kata kunci pada nama, NRP, pangkat, atau jabatan"
+// @Success 200 {object} dto.PageResponse{data=[]models.User}
+// @Failure 400 {object} map[string]string "Error: Parameter tidak valid"
 // @Failure 500 {object} map[string]string "Error: Gagal mengambil data pengguna"
 // @Security BearerAuth
 // @Router /users [get]
 func (c *UserController) FindAll(ctx *gin.Context) {
-	statusFilter := ctx.DefaultQuery("status", "active")
-	users, err := c.userService.FindAll(statusFilter)
+	// Daftar pengguna tidak memakai filter tanggal, sehingga zona waktu tidak berpengaruh.
+	list, err := ParseListQuery(ctx, time.Local)
+	if err != nil {
+		APIError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	users, result, err := c.userService.FindPage(ctx.DefaultQuery("status", "active"), list)
 	if err != nil {
 		log.Printf("ERROR: Gagal mengambil data semua pengguna: %v", err)
 		APIError(ctx, http.StatusInternalServerError, "Gagal mengambil data pengguna.")
 		return
 	}
-	ctx.JSON(http.StatusOK, users)
+	APIPage(ctx, list, result, users)
 }
 
 func (c *UserController) FindByID(ctx *gin.Context) {
@@ -271,4 +293,18 @@ func (c *UserController) FindOperators(ctx *gin.Context) {
 		return
 	}
 	ctx.JSON(http.StatusOK, operators)
-}
\ No newline at end of file
+}
+
+// handleRoleError mengirim respons untuk kesalahan peran pengguna dan mengembalikan true
+// jika err sudah ditangani.
+func (c *UserController) handleRoleError(ctx *gin.Context, err error) bool {
+	switch {
+	case errors.Is(err, services.ErrInvalidRole):
+		APIError(ctx, http.StatusBadRequest, err.Error())
+	case errors.Is(err, services.ErrAccessDenied):
+		APIError(ctx, http.StatusForbidden, err.Error())
+	default:
+		return false
+	}
+	return true
+}
@@ -52,9 +52,9 @@ func setupTestRouter(mockUserService *mocks.UserService) (*gin.Engine, *mocks.Us
 	// Rute publik/non-admin
 	router.PUT("/api/profile", userController.UpdateProfile)
 
-	// Grup rute yang dilindungi oleh AdminAuthMiddleware
+	// Grup rute yang dilindungi oleh RequirePermission(user.manage)
 	adminRoutes := router.Group("/api")
-	adminRoutes.Use(middleware.AdminAuthMiddleware())
+	adminRoutes.Use(middleware.RequirePermission(models.PermUserManage))
 	{
 		adminRoutes.POST("/users", userController.Create)
 	}
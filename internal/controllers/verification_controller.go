@@ -0,0 +1,34 @@
+package controllers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"simdokpol/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type VerificationController struct {
+	service services.VerificationService
+}
+
+func NewVerificationController(service services.VerificationService) *VerificationController {
+	return &VerificationController{service: service}
+}
+
+// ShowVerifyPage menampilkan halaman verifikasi publik untuk token dari QR code surat.
+// Halaman ini tidak memerlukan login dan hanya menampilkan fakta minimal dokumen.
+func (c *VerificationController) ShowVerifyPage(ctx *gin.Context) {
+	result, err := c.service.Verify(ctx.Param("token"))
+	if err != nil {
+		status := http.StatusNotFound
+		if !errors.Is(err, services.ErrInvalidVerificationToken) {
+			log.Printf("ERROR: Gagal memverifikasi dokumen: %v", err)
+			status = http.StatusInternalServerError
+		}
+		ctx.HTML(status, "verify.html", gin.H{"Title": "Verifikasi Dokumen", "Valid": false})
+		return
+	}
+	ctx.HTML(http.StatusOK, "verify.html", gin.H{"Title": "Verifikasi Dokumen", "Valid": true, "Result": result})
+}
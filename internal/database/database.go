@@ -0,0 +1,169 @@
+/**
+ * FILE HEADER: internal/database/database.go
+ *
+ * PURPOSE:
+ * Menyediakan koneksi database SQLite yang dapat ditutup dan dibuka ulang saat aplikasi berjalan
+ * (misalnya ketika database dipulihkan dari backup), beserta helper migrasi skema.
+ */
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database/sqlite"
+	"github.com/golang-migrate/migrate/v4/source"
+	_ "github.com/golang-migrate/migrate/v4/source/file"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// MigrationsSource adalah lokasi file migrasi skema yang dibaca golang-migrate.
+const MigrationsSource = "file://migrations"
+
+// DriverName adalah driver database/sql yang dipakai aplikasi.
+const DriverName = "sqlite3"
+
+// swapTimeout membatasi lama Swap menunggu query yang sedang berjalan selesai.
+const swapTimeout = 30 * time.Second
+
+// Pool membungkus *sql.DB agar dapat dipakai GORM sebagai ConnPool sekaligus ditukar dengan
+// koneksi baru tanpa membuat ulang *gorm.DB maupun repository yang sudah memegangnya.
+type Pool struct {
+	mu  sync.RWMutex
+	db  *sql.DB
+	dsn string
+}
+
+// NewPool membuka koneksi ke database SQLite pada dsn.
+func NewPool(dsn string) (*Pool, error) {
+	db, err := sql.Open(DriverName, dsn)
+	if err != nil {
+		return nil, err
+	}
+	return &Pool{db: db, dsn: dsn}, nil
+}
+
+func (p *Pool) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.db.PrepareContext(ctx, query)
+}
+
+func (p *Pool) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.db.ExecContext(ctx, query, args...)
+}
+
+func (p *Pool) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.db.QueryContext(ctx, query, args...)
+}
+
+func (p *Pool) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.db.QueryRowContext(ctx, query, args...)
+}
+
+func (p *Pool) BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.db.BeginTx(ctx, opts)
+}
+
+func (p *Pool) Ping() error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.db.Ping()
+}
+
+// GetDBConn mengembalikan *sql.DB yang sedang aktif; dipakai oleh (*gorm.DB).DB().
+// Nilainya tidak boleh disimpan melewati Swap.
+func (p *Pool) GetDBConn() (*sql.DB, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.db, nil
+}
+
+// Swap menutup koneksi, menjalankan replace selagi tidak ada koneksi yang terbuka ke file
+// database, lalu membuka koneksi kembali. Query baru tertahan selama Swap berjalan. Koneksi
+// selalu dibuka kembali meskipun replace gagal, sehingga aplikasi tetap dapat dipakai.
+func (p *Pool) Swap(replace func() error) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	// Rows dan transaksi yang sudah dimulai tetap memegang koneksinya sampai selesai.
+	deadline := time.Now().Add(swapTimeout)
+	for p.db.Stats().InUse > 0 {
+		if time.Now().After(deadline) {
+			return errors.New("database masih dipakai, coba lagi beberapa saat lagi")
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	if err := p.db.Close(); err != nil {
+		return fmt.Errorf("gagal menutup koneksi database: %w", err)
+	}
+
+	replaceErr := replace()
+
+	db, err := sql.Open(DriverName, p.dsn)
+	if err != nil {
+		return fmt.Errorf("gagal membuka ulang koneksi database: %w", err)
+	}
+	p.db = db
+	if replaceErr != nil {
+		return replaceErr
+	}
+	if err := db.Ping(); err != nil {
+		return fmt.Errorf("gagal membuka ulang koneksi database: %w", err)
+	}
+	return nil
+}
+
+// Migrate menjalankan migrasi yang belum diterapkan dari sourceURL pada db.
+func Migrate(db *sql.DB, sourceURL string) error {
+	driver, err := sqlite.WithInstance(db, &sqlite.Config{})
+	if err != nil {
+		return err
+	}
+	m, err := migrate.NewWithDatabaseInstance(sourceURL, "sqlite", driver)
+	if err != nil {
+		return err
+	}
+	if err := m.Up(); err != nil && err != migrate.ErrNoChange {
+		return err
+	}
+	return nil
+}
+
+// LatestVersion mengembalikan versi migrasi tertinggi yang tersedia di sourceURL.
+func LatestVersion(sourceURL string) (uint, error) {
+	src, err := source.Open(sourceURL)
+	if err != nil {
+		return 0, err
+	}
+	defer src.Close()
+
+	version, err := src.First()
+	if err != nil {
+		return 0, err
+	}
+	for {
+		next, err := src.Next(version)
+		if errors.Is(err, os.ErrNotExist) {
+			return version, nil
+		}
+		if err != nil {
+			return 0, err
+		}
+		version = next
+	}
+}
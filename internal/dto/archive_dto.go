@@ -0,0 +1,14 @@
+package dto
+
+import "time"
+
+// ArchiveRunStatus menggambarkan keadaan pengarsip otomatis dan hasil eksekusi terakhirnya.
+type ArchiveRunStatus struct {
+	Running           bool       `json:"running"`
+	IntervalMinutes   int        `json:"interval_minutes"`
+	LastRunAt         *time.Time `json:"last_run_at"`
+	LastCutoff        *time.Time `json:"last_cutoff"`
+	LastArchivedCount int64      `json:"last_archived_count"`
+	LastError         string     `json:"last_error"`
+	NextRunAt         *time.Time `json:"next_run_at"`
+}
@@ -0,0 +1,60 @@
+package dto
+
+import "time"
+
+// BackupSession adalah file backup yang sedang dibuka hanya-baca di penjelajah backup.
+type BackupSession struct {
+	ID              string     `json:"id"`
+	FileName        string     `json:"file_name"`
+	Encrypted       bool       `json:"encrypted"`
+	SchemaVersion   uint       `json:"schema_version"`    // Versi skema asli file, sebelum dimigrasikan untuk dibaca
+	BackupCreatedAt *time.Time `json:"backup_created_at"` // Dari manifest; kosong untuk file unggahan tanpa manifest
+	Documents       int64      `json:"documents"`
+	OpenedAt        time.Time  `json:"opened_at"`
+	ExpiresAt       time.Time  `json:"expires_at"` // Diperpanjang setiap kali sesi dipakai
+}
+
+// BackupDiffEntry adalah satu dokumen yang berbeda antara file backup dan database saat ini.
+// Untuk dokumen BARU, ID dan isian lainnya diambil dari database saat ini.
+type BackupDiffEntry struct {
+	ID             uint       `json:"id"`
+	Perubahan      string     `json:"perubahan"` // HILANG, DIHAPUS, BERUBAH, atau BARU
+	NomorSurat     string     `json:"nomor_surat"`
+	JenisDokumen   string     `json:"jenis_dokumen"`
+	NamaPemohon    string     `json:"nama_pemohon"`
+	TanggalLaporan time.Time  `json:"tanggal_laporan"`
+	Status         string     `json:"status"`          // Status di backup; kosong untuk dokumen BARU
+	StatusSaatIni  string     `json:"status_saat_ini"` // Kosong bila dokumen tidak ada lagi di database saat ini
+	DiubahPada     *time.Time `json:"diubah_pada"`     // Waktu perubahan terakhir di backup
+	DiubahSaatIni  *time.Time `json:"diubah_saat_ini"` // Waktu perubahan terakhir di database saat ini
+}
+
+// BackupDiff merangkum perbedaan dokumen antara file backup dan database saat ini.
+type BackupDiff struct {
+	Hilang  int               `json:"hilang"`  // Ada di backup, sudah dimusnahkan dari database saat ini
+	Dihapus int               `json:"dihapus"` // Ada di backup, sekarang berada di tong sampah
+	Berubah int               `json:"berubah"` // Diubah setelah backup dibuat
+	Baru    int               `json:"baru"`    // Dibuat setelah backup dibuat
+	Sama    int               `json:"sama"`
+	Entries []BackupDiffEntry `json:"entries"`
+}
+
+// RecoveredDocument adalah dokumen yang dibuat kembali dari file backup. BackupID adalah ID dokumen
+// di dalam backup; ID bisa berbeda bila ID aslinya sudah dipakai dokumen lain.
+type RecoveredDocument struct {
+	BackupID uint `json:"backup_id"`
+	RestoredDocument
+}
+
+// SkippedRecovery adalah dokumen terpilih yang tidak dipulihkan beserta alasannya.
+type SkippedRecovery struct {
+	BackupID   uint   `json:"backup_id"`
+	NomorSurat string `json:"nomor_surat"`
+	Alasan     string `json:"alasan"`
+}
+
+// BackupRecoveryResult adalah hasil pemulihan dokumen terpilih dari file backup.
+type BackupRecoveryResult struct {
+	Recovered []RecoveredDocument `json:"recovered"`
+	Skipped   []SkippedRecovery   `json:"skipped"`
+}
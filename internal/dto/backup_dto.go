@@ -0,0 +1,72 @@
+package dto
+
+import "time"
+
+// BackupManifest disimpan sebagai JSON di samping file backup (<nama file>.manifest.json)
+// agar keutuhan file dapat diperiksa ulang sebelum dipulihkan.
+type BackupManifest struct {
+	File           string    `json:"file"`
+	Size           int64     `json:"size"`
+	SHA256         string    `json:"sha256"`
+	SchemaVersion  uint      `json:"schema_version"` // Versi migrasi terakhir di dalam file backup
+	IntegrityCheck string    `json:"integrity_check"`
+	CreatedAt      time.Time `json:"created_at"`
+	Encrypted      bool      `json:"encrypted"` // File berformat .simdokpol-backup; SHA256 dihitung atas file terenkripsi
+}
+
+// BackupHeader ditulis di awal file .simdokpol-backup dalam bentuk JSON dan ikut diautentikasi
+// oleh AES-GCM, sehingga perubahan sekecil apa pun pada header membuat dekripsi gagal.
+type BackupHeader struct {
+	Format        int              `json:"format"`
+	AppVersion    string           `json:"app_version"`
+	SchemaVersion uint             `json:"schema_version"`
+	CreatedAt     time.Time        `json:"created_at"`
+	Size          int64            `json:"size"`   // Ukuran database sebelum dienkripsi
+	SHA256        string           `json:"sha256"` // Checksum database sebelum dienkripsi
+	Encryption    BackupEncryption `json:"encryption"`
+}
+
+// BackupEncryption menjelaskan cara kunci diturunkan dan isi file dienkripsi.
+type BackupEncryption struct {
+	Cipher      string `json:"cipher"` // AES-256-GCM
+	KDF         string `json:"kdf"`    // argon2id (kata sandi) atau hkdf-sha256 (kunci server)
+	Salt        []byte `json:"salt"`
+	Time        uint32 `json:"time,omitempty"`
+	MemoryKiB   uint32 `json:"memory_kib,omitempty"`
+	Threads     uint8  `json:"threads,omitempty"`
+	NoncePrefix []byte `json:"nonce_prefix"`
+	ChunkSize   int    `json:"chunk_size"`
+}
+
+// BackupFile adalah satu file backup di folder backup, beserta checksum dari manifest-nya.
+type BackupFile struct {
+	Name      string    `json:"name"`
+	Size      int64     `json:"size"`
+	CreatedAt time.Time `json:"created_at"`
+	SHA256    string    `json:"sha256"`    // Kosong bila file tidak memiliki manifest
+	Scheduled bool      `json:"scheduled"` // Dibuat oleh backup terjadwal dan ikut dirotasi
+	Encrypted bool      `json:"encrypted"`
+}
+
+// BackupScheduleStatus menggambarkan jadwal backup otomatis dan hasil eksekusi terakhirnya.
+type BackupScheduleStatus struct {
+	Schedule    string     `json:"schedule"`
+	Enabled     bool       `json:"enabled"`
+	Running     bool       `json:"running"`
+	KeepDaily   int        `json:"keep_daily"`
+	KeepWeekly  int        `json:"keep_weekly"`
+	KeepMonthly int        `json:"keep_monthly"`
+	LastRunAt   *time.Time `json:"last_run_at"`
+	LastFile    string     `json:"last_file"`
+	LastPruned  int        `json:"last_pruned"`
+	LastError   string     `json:"last_error"`
+	NextRunAt   *time.Time `json:"next_run_at"`
+}
+
+// BackupList adalah isi folder backup beserta status backup terjadwal.
+type BackupList struct {
+	Directory     string               `json:"directory"`
+	KeyConfigured bool                 `json:"key_configured"` // BACKUP_ENCRYPTION_KEY diatur; backup tanpa kata sandi ikut dienkripsi
+	Schedule      BackupScheduleStatus `json:"schedule"`
+	Files         []BackupFile         `json:"files"`
+}
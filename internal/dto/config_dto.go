@@ -9,9 +9,20 @@ type AppConfig struct {
 	KopBaris3           string `json:"kop_baris_3"`
 	NamaKantor          string `json:"nama_kantor"`
 	TempatSurat         string `json:"tempat_surat"`
+	KodeKantor          string `json:"kode_kantor"`
 	FormatNomorSurat    string `json:"format_nomor_surat"`
 	NomorSuratTerakhir  string `json:"nomor_surat_terakhir"`
 	ZonaWaktu           string `json:"zona_waktu"`
 	BackupPath          string `json:"backup_path"`
 	ArchiveDurationDays int    `json:"archive_duration_days"`
+	VerificationBaseURL string `json:"verification_base_url"`
+
+	// RecycleBinRetentionDays adalah masa simpan dokumen di tong sampah sebelum dimusnahkan otomatis.
+	RecycleBinRetentionDays int `json:"recycle_bin_retention_days"`
+
+	// BackupSchedule adalah jadwal backup otomatis berformat cron; kosong berarti nonaktif.
+	BackupSchedule    string `json:"backup_schedule"`
+	BackupKeepDaily   int    `json:"backup_keep_daily"`
+	BackupKeepWeekly  int    `json:"backup_keep_weekly"`
+	BackupKeepMonthly int    `json:"backup_keep_monthly"`
 }
\ No newline at end of file
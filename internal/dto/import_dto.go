@@ -0,0 +1,28 @@
+package dto
+
+// ImportReport adalah hasil pemeriksaan atau penyimpanan impor surat lama dari file Excel/CSV.
+// Pada dry-run tidak ada data yang disimpan; Imported selalu 0.
+type ImportReport struct {
+	FileName       string           `json:"file_name"`
+	DryRun         bool             `json:"dry_run"`
+	TotalRows      int              `json:"total_rows"`
+	ValidRows      int              `json:"valid_rows"`
+	Imported       int              `json:"imported"`
+	Columns        []ImportColumn   `json:"columns"`
+	IgnoredColumns []string         `json:"ignored_columns"`
+	Errors         []ImportRowError `json:"errors"`
+}
+
+// ImportColumn memetakan judul kolom di file ke isian dokumen.
+type ImportColumn struct {
+	Header string `json:"header"`
+	Field  string `json:"field"`
+}
+
+// ImportRowError adalah daftar kesalahan pada satu baris file. Row mengikuti nomor baris di
+// Excel (judul kolom di baris 1).
+type ImportRowError struct {
+	Row        int      `json:"row"`
+	NomorSurat string   `json:"nomor_surat"`
+	Messages   []string `json:"messages"`
+}
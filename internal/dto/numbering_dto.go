@@ -0,0 +1,25 @@
+package dto
+
+// IssuedNumber adalah hasil penomoran untuk satu dokumen baru.
+type IssuedNumber struct {
+	NomorSurat string `json:"nomor_surat"`
+	NomorUrut  int    `json:"nomor_urut"`
+	Tahun      int    `json:"tahun"`
+}
+
+// DeletedNumber adalah nomor urut yang dimiliki oleh dokumen yang sudah dihapus.
+type DeletedNumber struct {
+	NomorUrut  int    `json:"nomor_urut"`
+	NomorSurat string `json:"nomor_surat"`
+}
+
+// NumberingGapReport merangkum kesinambungan nomor surat dalam satu tahun.
+type NumberingGapReport struct {
+	Year             int             `json:"year"`
+	FirstNumber      int             `json:"first_number"`
+	LastNumber       int             `json:"last_number"`
+	IssuedCount      int             `json:"issued_count"`
+	MissingNumbers   []int           `json:"missing_numbers"`
+	DeletedNumbers   []DeletedNumber `json:"deleted_numbers"`
+	DuplicateNumbers []int           `json:"duplicate_numbers"`
+}
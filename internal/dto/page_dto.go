@@ -0,0 +1,11 @@
+package dto
+
+// PageResponse adalah respons endpoint daftar yang dipaginasi di server. Total adalah jumlah baris
+// tanpa filter pencarian dan Filtered jumlah setelah filter, sesuai kebutuhan DataTables server-side.
+type PageResponse struct {
+	Data     interface{} `json:"data"`
+	Page     int         `json:"page"`
+	Size     int         `json:"size"`
+	Total    int64       `json:"total"`
+	Filtered int64       `json:"filtered"`
+}
@@ -0,0 +1,29 @@
+package dto
+
+import "time"
+
+// DeletedDocument adalah satu baris tong sampah dokumen.
+type DeletedDocument struct {
+	ID             uint      `json:"id"`
+	NomorSurat     string    `json:"nomor_surat"` // Nomor asli sebelum dihapus; kosong untuk draf
+	NomorTersedia  bool      `json:"nomor_tersedia"`
+	JenisDokumen   string    `json:"jenis_dokumen"`
+	Status         string    `json:"status"`
+	NamaPemohon    string    `json:"nama_pemohon"`
+	TanggalLaporan time.Time `json:"tanggal_laporan"`
+	DihapusPada    time.Time `json:"dihapus_pada"`
+	DihapusOleh    string    `json:"dihapus_oleh"`
+	Alasan         string    `json:"alasan_penghapusan"`
+	// DimusnahkanPada adalah perkiraan waktu pemusnahan otomatis berdasarkan masa simpan di Pengaturan.
+	DimusnahkanPada time.Time `json:"dimusnahkan_pada"`
+}
+
+// RestoredDocument adalah hasil pemulihan dokumen dari tong sampah. NomorBentrok bernilai true jika
+// nomor asli sudah dipakai dokumen lain, sehingga dokumen dipulihkan sebagai draf tanpa nomor.
+type RestoredDocument struct {
+	ID           uint   `json:"id"`
+	NomorSurat   string `json:"nomor_surat"`
+	NomorAsli    string `json:"nomor_asli"`
+	Status       string `json:"status"`
+	NomorBentrok bool   `json:"nomor_bentrok"`
+}
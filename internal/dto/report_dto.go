@@ -0,0 +1,102 @@
+package dto
+
+import "time"
+
+// Jenis anomali pada laporan serah terima jaga.
+const (
+	AnomalyNumberedDeleted   = "NOMOR_DIHAPUS"
+	AnomalyRevoked           = "DICABUT"
+	AnomalyEditedAfterIssued = "DIUBAH_SETELAH_TERBIT"
+	AnomalyStaleApproval     = "PERSETUJUAN_TERTUNDA"
+)
+
+// HandoverReport adalah laporan serah terima jaga satu regu dalam rentang waktu From sampai To.
+type HandoverReport struct {
+	Regu        string             `json:"regu"`
+	From        time.Time          `json:"from"`
+	To          time.Time          `json:"to"`
+	GeneratedAt time.Time          `json:"generated_at"`
+	GeneratedBy string             `json:"generated_by"`
+	Summary     HandoverSummary    `json:"summary"`
+	Issued      []HandoverDocument `json:"issued"`
+	Edited      []HandoverActivity `json:"edited"`
+	Deleted     []HandoverActivity `json:"deleted"`
+	Pending     []HandoverDocument `json:"pending"` // Draf dan dokumen menunggu persetujuan saat laporan dibuat
+	Anomalies   []HandoverAnomaly  `json:"anomalies"`
+}
+
+// HandoverSummary berisi jumlah entri tiap bagian laporan serah terima.
+type HandoverSummary struct {
+	Issued    int `json:"issued"`
+	Edited    int `json:"edited"`
+	Deleted   int `json:"deleted"`
+	Pending   int `json:"pending"`
+	Anomalies int `json:"anomalies"`
+}
+
+// HandoverDocument adalah ringkasan satu dokumen pada laporan serah terima.
+type HandoverDocument struct {
+	ID           uint      `json:"id"`
+	NomorSurat   string    `json:"nomor_surat"`
+	JenisDokumen string    `json:"jenis_dokumen"`
+	NamaPemohon  string    `json:"nama_pemohon"`
+	Status       string    `json:"status"`
+	Operator     string    `json:"operator"`
+	Waktu        time.Time `json:"waktu"` // Waktu terbit untuk dokumen terbit, waktu perubahan terakhir untuk dokumen tertunda
+}
+
+// HandoverActivity adalah satu entri log audit yang dicantumkan pada laporan serah terima.
+type HandoverActivity struct {
+	Waktu    time.Time `json:"waktu"`
+	Pengguna string    `json:"pengguna"`
+	Aksi     string    `json:"aksi"`
+	Detail   string    `json:"detail"`
+}
+
+// HandoverAnomaly adalah kejadian yang perlu diperhatikan regu berikutnya.
+type HandoverAnomaly struct {
+	Jenis      string    `json:"jenis"`
+	Keterangan string    `json:"keterangan"`
+	DocumentID uint      `json:"document_id"`
+	Waktu      time.Time `json:"waktu"`
+}
+
+// Jenis periode laporan statistik.
+const (
+	PeriodMonthly   = "monthly"
+	PeriodQuarterly = "quarterly"
+	PeriodYearly    = "yearly"
+)
+
+// StatisticsReport adalah rekap resmi dokumen terbit dalam satu periode (bulan, triwulan, atau tahun)
+// beserta perbandingan dengan periode yang sama tahun sebelumnya. Start inklusif, End eksklusif.
+type StatisticsReport struct {
+	Period        string           `json:"period"`
+	Label         string           `json:"label"`
+	Start         time.Time        `json:"start"`
+	End           time.Time        `json:"end"`
+	GeneratedAt   time.Time        `json:"generated_at"`
+	Total         int              `json:"total"`
+	PreviousTotal int              `json:"previous_total"`
+	ChangePercent *float64         `json:"change_percent"` // nil jika tahun sebelumnya nol
+	ByMonth       []StatComparison `json:"by_month"`
+	ByItem        []StatComparison `json:"by_item"`
+	ByOperator    []StatCount      `json:"by_operator"`
+	ByPetugas     []StatCount      `json:"by_petugas"`
+	ByLocation    []StatCount      `json:"by_location"`
+	ByWeekday     []StatCount      `json:"by_weekday"`
+}
+
+// StatCount adalah jumlah dokumen untuk satu kelompok.
+type StatCount struct {
+	Label string `json:"label"`
+	Count int    `json:"count"`
+}
+
+// StatComparison membandingkan jumlah satu kelompok dengan periode yang sama tahun sebelumnya.
+type StatComparison struct {
+	Label         string   `json:"label"`
+	Current       int      `json:"current"`
+	Previous      int      `json:"previous"`
+	ChangePercent *float64 `json:"change_percent"`
+}
@@ -0,0 +1,68 @@
+package dto
+
+import "time"
+
+// DocumentSnapshot adalah isi lengkap sebuah dokumen pada satu revisi.
+// Disimpan sebagai JSON di kolom document_revisions.snapshot.
+type DocumentSnapshot struct {
+	NomorSurat         string           `json:"nomor_surat"`
+	Status             string           `json:"status"`
+	LokasiHilang       string           `json:"lokasi_hilang"`
+	Resident           ResidentSnapshot `json:"resident"`
+	PetugasPelaporID   uint             `json:"petugas_pelapor_id"`
+	PetugasPelapor     string           `json:"petugas_pelapor"`
+	PejabatPersetujuID *uint            `json:"pejabat_persetuju_id"`
+	PejabatPersetuju   string           `json:"pejabat_persetuju"`
+	LostItems          []ItemSnapshot   `json:"lost_items"`
+	// DataTambahan berisi isian khusus jenis dokumen; kosong pada revisi Surat Keterangan Hilang.
+	DataTambahan map[string]string `json:"data_tambahan,omitempty"`
+}
+
+// ResidentSnapshot adalah data pemohon pada satu revisi. Tanggal lahir disimpan
+// sebagai YYYY-MM-DD agar tidak bergeser oleh zona waktu.
+type ResidentSnapshot struct {
+	ID           uint    `json:"id"`
+	NIK          *string `json:"nik"`
+	NamaLengkap  string  `json:"nama_lengkap"`
+	TempatLahir  string  `json:"tempat_lahir"`
+	TanggalLahir string  `json:"tanggal_lahir"`
+	JenisKelamin string  `json:"jenis_kelamin"`
+	Agama        string  `json:"agama"`
+	Pekerjaan    string  `json:"pekerjaan"`
+	Alamat       string  `json:"alamat"`
+}
+
+// ItemSnapshot adalah satu barang hilang pada satu revisi. JenisBarang dan Atribut kosong pada
+// revisi yang dibuat sebelum katalog jenis barang ada.
+type ItemSnapshot struct {
+	NamaBarang  string            `json:"nama_barang"`
+	Deskripsi   string            `json:"deskripsi"`
+	JenisBarang string            `json:"jenis_barang,omitempty"`
+	Atribut     map[string]string `json:"atribut,omitempty"`
+}
+
+// RevisionDetail adalah satu revisi beserta snapshot dokumennya.
+type RevisionDetail struct {
+	RevisionNumber int              `json:"revision_number"`
+	Aksi           string           `json:"aksi"`
+	ChangedByID    uint             `json:"changed_by_id"`
+	ChangedBy      string           `json:"changed_by"`
+	CreatedAt      time.Time        `json:"created_at"`
+	Snapshot       DocumentSnapshot `json:"snapshot"`
+}
+
+// FieldChange adalah perubahan nilai satu field di antara dua revisi.
+type FieldChange struct {
+	Field    string `json:"field"`
+	Label    string `json:"label"`
+	OldValue string `json:"old_value"`
+	NewValue string `json:"new_value"`
+}
+
+// RevisionDiff adalah daftar field yang berbeda di antara dua revisi dokumen.
+type RevisionDiff struct {
+	DocumentID   uint          `json:"document_id"`
+	FromRevision int           `json:"from_revision"`
+	ToRevision   int           `json:"to_revision"`
+	Changes      []FieldChange `json:"changes"`
+}
@@ -3,11 +3,15 @@ package middleware
 import (
 	"net/http"
 	"simdokpol/internal/models"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 )
 
-func AdminAuthMiddleware() gin.HandlerFunc {
+// RequirePermission hanya meneruskan request jika peran pengguna yang login memuat
+// semua izin yang diminta. Request API ditolak dengan JSON 403, request halaman
+// dialihkan ke dasbor.
+func RequirePermission(permissions ...string) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		userInterface, exists := c.Get("currentUser")
 		if !exists {
@@ -23,9 +27,11 @@ func AdminAuthMiddleware() gin.HandlerFunc {
 			return
 		}
 
-		// Gunakan konstanta untuk peran
-		if currentUser.Peran != models.RoleSuperAdmin {
-			if c.Request.Header.Get("Accept") == "application/json" {
+		for _, permission := range permissions {
+			if currentUser.HasPermission(permission) {
+				continue
+			}
+			if strings.HasPrefix(c.Request.URL.Path, "/api") {
 				c.JSON(http.StatusForbidden, gin.H{"error": "Akses ditolak. Anda tidak memiliki hak akses yang cukup."})
 			} else {
 				c.Redirect(http.StatusFound, "/")
@@ -36,4 +42,4 @@ func AdminAuthMiddleware() gin.HandlerFunc {
 
 		c.Next()
 	}
-}
\ No newline at end of file
+}
@@ -2,6 +2,7 @@ package mocks
 
 import (
 	"simdokpol/internal/models" // <-- BARIS INI YANG DITAMBAHKAN
+	"simdokpol/internal/repositories"
 	"github.com/stretchr/testify/mock"
 )
 
@@ -13,10 +14,10 @@ func (_m *AuditLogService) LogActivity(userID uint, action string, details strin
 	_m.Called(userID, action, details)
 }
 
-func (_m *AuditLogService) FindAll() ([]models.AuditLog, error) {
-	ret := _m.Called()
+func (_m *AuditLogService) FindPage(list repositories.ListQuery) ([]models.AuditLog, repositories.PageResult, error) {
+	ret := _m.Called(list)
 	if ret.Get(0) == nil {
-		return nil, ret.Error(1)
+		return nil, ret.Get(1).(repositories.PageResult), ret.Error(2)
 	}
-	return ret.Get(0).([]models.AuditLog), ret.Error(1)
+	return ret.Get(0).([]models.AuditLog), ret.Get(1).(repositories.PageResult), ret.Error(2)
 }
\ No newline at end of file
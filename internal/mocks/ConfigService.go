@@ -28,6 +28,10 @@ func (_m *ConfigService) SaveConfig(configData map[string]string) error {
 	return _m.Called(configData).Error(0)
 }
 
+func (_m *ConfigService) InvalidateCache() {
+	_m.Called()
+}
+
 func (_m *ConfigService) GetLocation() (*time.Location, error) {
 	ret := _m.Called()
 	if ret.Get(0) == nil {
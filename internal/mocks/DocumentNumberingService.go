@@ -0,0 +1,45 @@
+package mocks
+
+import (
+	"simdokpol/internal/dto"
+	"simdokpol/internal/models"
+
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+type DocumentNumberingService struct {
+	mock.Mock
+}
+
+func (_m *DocumentNumberingService) NextDocumentNumber(tx *gorm.DB, docType *models.DocumentType, regu string) (*dto.IssuedNumber, error) {
+	ret := _m.Called(tx, docType, regu)
+	if ret.Get(0) == nil {
+		return nil, ret.Error(1)
+	}
+	return ret.Get(0).(*dto.IssuedNumber), ret.Error(1)
+}
+
+func (_m *DocumentNumberingService) PreviewNextNumber(documentType string, format string, officeCode string, regu string) (*dto.IssuedNumber, error) {
+	ret := _m.Called(documentType, format, officeCode, regu)
+	if ret.Get(0) == nil {
+		return nil, ret.Error(1)
+	}
+	return ret.Get(0).(*dto.IssuedNumber), ret.Error(1)
+}
+
+func (_m *DocumentNumberingService) ValidateFormat(format string, officeCode string) error {
+	return _m.Called(format, officeCode).Error(0)
+}
+
+func (_m *DocumentNumberingService) SyncLastNumber(lastNumber string) error {
+	return _m.Called(lastNumber).Error(0)
+}
+
+func (_m *DocumentNumberingService) GetGapReport(documentType string, year int) (*dto.NumberingGapReport, error) {
+	ret := _m.Called(documentType, year)
+	if ret.Get(0) == nil {
+		return nil, ret.Error(1)
+	}
+	return ret.Get(0).(*dto.NumberingGapReport), ret.Error(1)
+}
@@ -0,0 +1,38 @@
+package mocks
+
+import (
+	"simdokpol/internal/dto"
+	"simdokpol/internal/models"
+
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+type DocumentRevisionService struct {
+	mock.Mock
+}
+
+func (_m *DocumentRevisionService) RecordRevision(tx *gorm.DB, doc *models.LostDocument, aksi string, actorID uint) error {
+	return _m.Called(tx, doc, aksi, actorID).Error(0)
+}
+
+func (_m *DocumentRevisionService) ListRevisions(docID uint) ([]models.DocumentRevision, error) {
+	ret := _m.Called(docID)
+	return ret.Get(0).([]models.DocumentRevision), ret.Error(1)
+}
+
+func (_m *DocumentRevisionService) GetRevision(docID uint, revisionNumber int) (*dto.RevisionDetail, error) {
+	ret := _m.Called(docID, revisionNumber)
+	if ret.Get(0) == nil {
+		return nil, ret.Error(1)
+	}
+	return ret.Get(0).(*dto.RevisionDetail), ret.Error(1)
+}
+
+func (_m *DocumentRevisionService) Diff(docID uint, fromRevision int, toRevision int) (*dto.RevisionDiff, error) {
+	ret := _m.Called(docID, fromRevision, toRevision)
+	if ret.Get(0) == nil {
+		return nil, ret.Error(1)
+	}
+	return ret.Get(0).(*dto.RevisionDiff), ret.Error(1)
+}
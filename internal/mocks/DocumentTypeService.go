@@ -0,0 +1,40 @@
+package mocks
+
+import (
+	"simdokpol/internal/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type DocumentTypeService struct {
+	mock.Mock
+}
+
+func (_m *DocumentTypeService) FindAll(activeOnly bool) ([]models.DocumentType, error) {
+	ret := _m.Called(activeOnly)
+	return ret.Get(0).([]models.DocumentType), ret.Error(1)
+}
+
+func (_m *DocumentTypeService) FindByCode(kode string) (*models.DocumentType, error) {
+	ret := _m.Called(kode)
+	if ret.Get(0) == nil {
+		return nil, ret.Error(1)
+	}
+	return ret.Get(0).(*models.DocumentType), ret.Error(1)
+}
+
+func (_m *DocumentTypeService) Create(docType *models.DocumentType, actorID uint) (*models.DocumentType, error) {
+	ret := _m.Called(docType, actorID)
+	if ret.Get(0) == nil {
+		return nil, ret.Error(1)
+	}
+	return ret.Get(0).(*models.DocumentType), ret.Error(1)
+}
+
+func (_m *DocumentTypeService) Update(kode string, docType *models.DocumentType, actorID uint) (*models.DocumentType, error) {
+	ret := _m.Called(kode, docType, actorID)
+	if ret.Get(0) == nil {
+		return nil, ret.Error(1)
+	}
+	return ret.Get(0).(*models.DocumentType), ret.Error(1)
+}
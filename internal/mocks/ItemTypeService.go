@@ -0,0 +1,40 @@
+package mocks
+
+import (
+	"simdokpol/internal/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type ItemTypeService struct {
+	mock.Mock
+}
+
+func (_m *ItemTypeService) FindAll(activeOnly bool) ([]models.ItemType, error) {
+	ret := _m.Called(activeOnly)
+	return ret.Get(0).([]models.ItemType), ret.Error(1)
+}
+
+func (_m *ItemTypeService) FindByCode(kode string) (*models.ItemType, error) {
+	ret := _m.Called(kode)
+	if ret.Get(0) == nil {
+		return nil, ret.Error(1)
+	}
+	return ret.Get(0).(*models.ItemType), ret.Error(1)
+}
+
+func (_m *ItemTypeService) Create(itemType *models.ItemType, actorID uint) (*models.ItemType, error) {
+	ret := _m.Called(itemType, actorID)
+	if ret.Get(0) == nil {
+		return nil, ret.Error(1)
+	}
+	return ret.Get(0).(*models.ItemType), ret.Error(1)
+}
+
+func (_m *ItemTypeService) Update(kode string, itemType *models.ItemType, actorID uint) (*models.ItemType, error) {
+	ret := _m.Called(kode, itemType, actorID)
+	if ret.Get(0) == nil {
+		return nil, ret.Error(1)
+	}
+	return ret.Get(0).(*models.ItemType), ret.Error(1)
+}
@@ -23,14 +23,29 @@ func (_m *LostDocumentRepository) FindByID(id uint) (*models.LostDocument, error
 	return ret.Get(0).(*models.LostDocument), ret.Error(1)
 }
 
-func (_m *LostDocumentRepository) FindAll(query string, statusFilter string, archiveDurationDays int) ([]models.LostDocument, error) {
-	ret := _m.Called(query, statusFilter, archiveDurationDays)
-	return ret.Get(0).([]models.LostDocument), ret.Error(1)
+func (_m *LostDocumentRepository) FindByIDUnscoped(id uint) (*models.LostDocument, error) {
+	ret := _m.Called(id)
+	return ret.Get(0).(*models.LostDocument), ret.Error(1)
 }
 
-func (_m *LostDocumentRepository) SearchGlobal(query string) ([]models.LostDocument, error) {
-	ret := _m.Called(query)
-	return ret.Get(0).([]models.LostDocument), ret.Error(1)
+func (_m *LostDocumentRepository) FindPage(statusFilter string, scope repositories.DocumentScope, list repositories.ListQuery) ([]models.LostDocument, repositories.PageResult, error) {
+	ret := _m.Called(statusFilter, scope, list)
+	return ret.Get(0).([]models.LostDocument), ret.Get(1).(repositories.PageResult), ret.Error(2)
+}
+
+func (_m *LostDocumentRepository) SearchGlobal(query string, scope repositories.DocumentScope) ([]repositories.DocumentSearchHit, error) {
+	ret := _m.Called(query, scope)
+	return ret.Get(0).([]repositories.DocumentSearchHit), ret.Error(1)
+}
+
+func (_m *LostDocumentRepository) FindExistingNomorSurat() ([]string, error) {
+	ret := _m.Called()
+	return ret.Get(0).([]string), ret.Error(1)
+}
+
+func (_m *LostDocumentRepository) FindInBatches(filter repositories.DocumentFilter, scope repositories.DocumentScope, batchSize int, fn func(docs []models.LostDocument) error) error {
+	ret := _m.Called(filter, scope, batchSize, fn)
+	return ret.Error(0)
 }
 
 func (_m *LostDocumentRepository) Update(tx *gorm.DB, doc *models.LostDocument) (*models.LostDocument, error) {
@@ -42,11 +57,6 @@ func (_m *LostDocumentRepository) Delete(tx *gorm.DB, id uint) error {
 	return _m.Called(tx, id).Error(0)
 }
 
-func (_m *LostDocumentRepository) GetLastDocumentOfYear(year int) (*models.LostDocument, error) {
-	ret := _m.Called(year)
-	return ret.Get(0).(*models.LostDocument), ret.Error(1)
-}
-
 func (_m *LostDocumentRepository) CountByDateRange(start time.Time, end time.Time) (int64, error) {
 	ret := _m.Called(start, end)
 	return ret.Get(0).(int64), ret.Error(1)
@@ -57,6 +67,16 @@ func (_m *LostDocumentRepository) GetMonthlyIssuanceForYear(year int) ([]reposit
 	return ret.Get(0).([]repositories.MonthlyCount), ret.Error(1)
 }
 
+func (_m *LostDocumentRepository) FindIdentifierDuplicates() ([]repositories.IdentifierMatchGroup, error) {
+	ret := _m.Called()
+	return ret.Get(0).([]repositories.IdentifierMatchGroup), ret.Error(1)
+}
+
+func (_m *LostDocumentRepository) FindIdentifierMatches(docID uint) ([]repositories.IdentifierMatchGroup, error) {
+	ret := _m.Called(docID)
+	return ret.Get(0).([]repositories.IdentifierMatchGroup), ret.Error(1)
+}
+
 func (_m *LostDocumentRepository) GetItemCompositionStats() ([]repositories.ItemCompositionStat, error) {
 	ret := _m.Called()
 	return ret.Get(0).([]repositories.ItemCompositionStat), ret.Error(1)
@@ -65,4 +85,19 @@ func (_m *LostDocumentRepository) GetItemCompositionStats() ([]repositories.Item
 func (_m *LostDocumentRepository) FindExpiringDocumentsForUser(userID uint, expiryDateStart time.Time, expiryDateEnd time.Time) ([]models.LostDocument, error) {
 	ret := _m.Called(userID, expiryDateStart, expiryDateEnd)
 	return ret.Get(0).([]models.LostDocument), ret.Error(1)
-}
\ No newline at end of file
+}
+
+func (_m *LostDocumentRepository) ArchiveIssuedBefore(cutoff time.Time) (int64, error) {
+	ret := _m.Called(cutoff)
+	return ret.Get(0).(int64), ret.Error(1)
+}
+
+func (_m *LostDocumentRepository) FindAwaitingApproval(pejabatID uint) ([]models.LostDocument, error) {
+	ret := _m.Called(pejabatID)
+	return ret.Get(0).([]models.LostDocument), ret.Error(1)
+}
+
+func (_m *LostDocumentRepository) TransitionStatus(tx *gorm.DB, id uint, fromStatuses []string, changes map[string]interface{}) (bool, error) {
+	ret := _m.Called(tx, id, fromStatuses, changes)
+	return ret.Bool(0), ret.Error(1)
+}
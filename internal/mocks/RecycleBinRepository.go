@@ -0,0 +1,42 @@
+package mocks
+
+import (
+	"simdokpol/internal/models"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type RecycleBinRepository struct {
+	mock.Mock
+}
+
+func (_m *RecycleBinRepository) FindDeletedDocuments() ([]models.LostDocument, error) {
+	ret := _m.Called()
+	return ret.Get(0).([]models.LostDocument), ret.Error(1)
+}
+
+func (_m *RecycleBinRepository) FindDeletedDocument(id uint) (*models.LostDocument, error) {
+	ret := _m.Called(id)
+	return ret.Get(0).(*models.LostDocument), ret.Error(1)
+}
+
+func (_m *RecycleBinRepository) FindDeletedBefore(cutoff time.Time) ([]models.LostDocument, error) {
+	ret := _m.Called(cutoff)
+	return ret.Get(0).([]models.LostDocument), ret.Error(1)
+}
+
+func (_m *RecycleBinRepository) IsNomorSuratTaken(nomorSurat string, excludeID uint) (bool, error) {
+	ret := _m.Called(nomorSurat, excludeID)
+	return ret.Bool(0), ret.Error(1)
+}
+
+func (_m *RecycleBinRepository) Restore(id uint, changes map[string]interface{}, clashChanges map[string]interface{}) (bool, error) {
+	ret := _m.Called(id, changes, clashChanges)
+	return ret.Bool(0), ret.Error(1)
+}
+
+func (_m *RecycleBinRepository) Purge(id uint) error {
+	ret := _m.Called(id)
+	return ret.Error(0)
+}
@@ -0,0 +1,63 @@
+package mocks
+
+import (
+	"simdokpol/internal/models"
+	"simdokpol/internal/repositories"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type ReportRepository struct {
+	mock.Mock
+}
+
+func (_m *ReportRepository) FindIssuedByRegu(regu string, from time.Time, to time.Time) ([]models.LostDocument, error) {
+	ret := _m.Called(regu, from, to)
+	return ret.Get(0).([]models.LostDocument), ret.Error(1)
+}
+
+func (_m *ReportRepository) FindPendingByRegu(regu string) ([]models.LostDocument, error) {
+	ret := _m.Called(regu)
+	return ret.Get(0).([]models.LostDocument), ret.Error(1)
+}
+
+func (_m *ReportRepository) FindRevokedByRegu(regu string, from time.Time, to time.Time) ([]models.LostDocument, error) {
+	ret := _m.Called(regu, from, to)
+	return ret.Get(0).([]models.LostDocument), ret.Error(1)
+}
+
+func (_m *ReportRepository) FindNumberedDeletedByRegu(regu string, from time.Time, to time.Time) ([]models.LostDocument, error) {
+	ret := _m.Called(regu, from, to)
+	return ret.Get(0).([]models.LostDocument), ret.Error(1)
+}
+
+func (_m *ReportRepository) FindAuditLogsByRegu(regu string, from time.Time, to time.Time, aksi []string) ([]models.AuditLog, error) {
+	ret := _m.Called(regu, from, to, aksi)
+	return ret.Get(0).([]models.AuditLog), ret.Error(1)
+}
+
+func (_m *ReportRepository) FindEditedAfterIssue(regu string, from time.Time, to time.Time) ([]repositories.EditedAfterIssue, error) {
+	ret := _m.Called(regu, from, to)
+	return ret.Get(0).([]repositories.EditedAfterIssue), ret.Error(1)
+}
+
+func (_m *ReportRepository) FindIssuedSummaries(start time.Time, end time.Time) ([]repositories.IssuedSummary, error) {
+	ret := _m.Called(start, end)
+	return ret.Get(0).([]repositories.IssuedSummary), ret.Error(1)
+}
+
+func (_m *ReportRepository) CountIssuedByItem(start time.Time, end time.Time) ([]repositories.GroupCount, error) {
+	ret := _m.Called(start, end)
+	return ret.Get(0).([]repositories.GroupCount), ret.Error(1)
+}
+
+func (_m *ReportRepository) CountIssuedByOperator(start time.Time, end time.Time) ([]repositories.GroupCount, error) {
+	ret := _m.Called(start, end)
+	return ret.Get(0).([]repositories.GroupCount), ret.Error(1)
+}
+
+func (_m *ReportRepository) CountIssuedByPetugasPelapor(start time.Time, end time.Time) ([]repositories.GroupCount, error) {
+	ret := _m.Called(start, end)
+	return ret.Get(0).([]repositories.GroupCount), ret.Error(1)
+}
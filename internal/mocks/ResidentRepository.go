@@ -2,6 +2,8 @@ package mocks
 
 import (
 	"simdokpol/internal/models"
+	"simdokpol/internal/repositories"
+	"time"
 
 	"github.com/stretchr/testify/mock"
 	"gorm.io/gorm"
@@ -16,7 +18,42 @@ func (_m *ResidentRepository) FindByNIK(tx *gorm.DB, nik string) (*models.Reside
 	return ret.Get(0).(*models.Resident), ret.Error(1)
 }
 
+func (_m *ResidentRepository) FindByID(id uint) (*models.Resident, error) {
+	ret := _m.Called(id)
+	return ret.Get(0).(*models.Resident), ret.Error(1)
+}
+
+func (_m *ResidentRepository) FindByIDs(ids []uint) ([]models.Resident, error) {
+	ret := _m.Called(ids)
+	return ret.Get(0).([]models.Resident), ret.Error(1)
+}
+
+func (_m *ResidentRepository) FindByNameAndBirthDate(tx *gorm.DB, namaLengkap string, tanggalLahir time.Time) (*models.Resident, error) {
+	ret := _m.Called(tx, namaLengkap, tanggalLahir)
+	return ret.Get(0).(*models.Resident), ret.Error(1)
+}
+
+func (_m *ResidentRepository) Search(query string, limit int) ([]models.Resident, error) {
+	ret := _m.Called(query, limit)
+	return ret.Get(0).([]models.Resident), ret.Error(1)
+}
+
+func (_m *ResidentRepository) FindDuplicateGroups() ([]repositories.DuplicateResidentGroup, error) {
+	ret := _m.Called()
+	return ret.Get(0).([]repositories.DuplicateResidentGroup), ret.Error(1)
+}
+
 func (_m *ResidentRepository) Create(tx *gorm.DB, resident *models.Resident) (*models.Resident, error) {
 	ret := _m.Called(tx, resident)
 	return ret.Get(0).(*models.Resident), ret.Error(1)
-}
\ No newline at end of file
+}
+
+func (_m *ResidentRepository) Update(tx *gorm.DB, resident *models.Resident) (*models.Resident, error) {
+	ret := _m.Called(tx, resident)
+	return ret.Get(0).(*models.Resident), ret.Error(1)
+}
+
+func (_m *ResidentRepository) Merge(tx *gorm.DB, primaryID uint, duplicateIDs []uint) (int64, error) {
+	ret := _m.Called(tx, primaryID, duplicateIDs)
+	return ret.Get(0).(int64), ret.Error(1)
+}
@@ -2,6 +2,7 @@ package mocks
 
 import (
 	"simdokpol/internal/models"
+	"simdokpol/internal/repositories"
 	"github.com/stretchr/testify/mock"
 )
 
@@ -34,6 +35,11 @@ func (_m *UserRepository) FindAll(statusFilter string) ([]models.User, error) {
 	return r0, r1
 }
 
+func (_m *UserRepository) FindPage(statusFilter string, list repositories.ListQuery) ([]models.User, repositories.PageResult, error) {
+	ret := _m.Called(statusFilter, list)
+	return ret.Get(0).([]models.User), ret.Get(1).(repositories.PageResult), ret.Error(2)
+}
+
 func (_m *UserRepository) FindByID(id uint) (*models.User, error) {
 	ret := _m.Called(id)
 	var r0 *models.User
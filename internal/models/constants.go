@@ -1,28 +1,126 @@
 package models
 
+// SystemUserID dipakai sebagai aktor untuk aksi otomatis sistem; dicatat tanpa pengguna di audit log.
+const SystemUserID uint = 0
+
 // Konstanta untuk Peran Pengguna
 const (
 	RoleSuperAdmin = "SUPER_ADMIN"
 	RoleOperator   = "OPERATOR"
+	RoleKanitSPKT  = "KANIT_SPKT"
+	RoleAuditor    = "AUDITOR"
+)
+
+// Konstanta untuk Izin yang dibundel oleh Peran
+const (
+	PermDocumentCreate     = "document.create"           // Membuat surat dan mengelola surat milik sendiri
+	PermDocumentViewAll    = "document.view_all"         // Melihat dan mencetak surat milik operator lain
+	PermDocumentEditAll    = "document.edit_all"         // Mengubah, mengajukan, dan menghapus surat milik operator lain
+	PermDocumentApprove    = "document.approve"          // Dapat ditunjuk sebagai pejabat persetuju surat
+	PermDocumentRevoke     = "document.revoke"           // Mencabut surat yang sudah terbit
+	PermDocumentRestore    = "document.restore_revision" // Memulihkan surat ke revisi sebelumnya
+	PermDocumentTypeManage = "document_type.manage"
+	PermResidentMerge      = "resident.merge"
+	PermUserManage         = "user.manage" // Termasuk mengelola peran dan izin
+	PermAuditView          = "audit.view"  // Log audit dan laporan celah penomoran
+	PermBackupRun          = "backup.run"
+	PermBackupRestore      = "backup.restore"
+	PermSettingsEdit       = "settings.edit"
+	PermArchiveRun         = "archive.run"
+	PermReportView         = "report.view"         // Laporan statistik periodik untuk Polres
+	PermReportHandover     = "report.handover"     // Laporan serah terima jaga regu
+	PermDocumentImport     = "document.import"     // Impor surat lama dari file Excel/CSV
+	PermItemTypeManage     = "item_type.manage"    // Katalog jenis barang hilang
+	PermDocumentCrossCheck = "document.crosscheck" // Melihat laporan lain dengan nomor identitas barang yang sama
+	PermRecycleBinManage   = "recycle_bin.manage"  // Memulihkan dan memusnahkan dokumen terhapus
+)
+
+// Konstanta untuk Jenis Dokumen bawaan (kunci tabel document_types dan document_sequences)
+const (
+	DocumentTypeLostDocument = "LOST_DOCUMENT"
+	DocumentTypeSTPL         = "STPL"
+	DocumentTypeSKCKReferral = "PENGANTAR_SKCK"
+	DocumentTypePoliceReport = "LAPORAN_POLISI"
+)
+
+// Key isian jenis dokumen yang merujuk ke kolom bawaan dokumen, bukan ke DataTambahan
+const (
+	FieldNIK          = "nik"
+	FieldLokasiHilang = "lokasi_hilang"
+	FieldLostItems    = "lost_items"
+)
+
+// Tipe atribut jenis barang di katalog barang hilang
+const (
+	AttributeText    = "text"
+	AttributeAngka   = "angka"   // Hanya angka, misalnya nomor rekening
+	AttributePilihan = "pilihan" // Salah satu dari ItemAttribute.Opsi
+	AttributeNIK     = "nik"     // Struktur NIK 16 digit
+	AttributeNopol   = "nopol"   // Nomor polisi kendaraan, disimpan sebagai "B 1234 XYZ"
+	AttributeIMEI    = "imei"    // 15 digit dengan digit pemeriksa Luhn
+)
+
+// Template cetak yang tersedia untuk jenis dokumen
+const (
+	PrintTemplateLostDocument = "print_preview.html"
+	PrintTemplateGeneric      = "print_generic.html"
 )
 
 // Konstanta untuk Status Dokumen
+// Alur: DRAF -> MENUNGGU_PERSETUJUAN -> DITERBITKAN -> (DIARSIPKAN) -> DICABUT.
+// Nomor Surat baru diberikan saat dokumen disetujui.
 const (
-	StatusDiterbitkan = "DITERBITKAN"
-	StatusDiarsipkan  = "DIARSIPKAN"
+	StatusDraf                = "DRAF"
+	StatusMenungguPersetujuan = "MENUNGGU_PERSETUJUAN"
+	StatusDiterbitkan         = "DITERBITKAN"
+	StatusDiarsipkan          = "DIARSIPKAN"
+	StatusDicabut             = "DICABUT"
+	StatusDihapus             = "DIHAPUS" // Hanya untuk tampilan verifikasi publik, tidak disimpan di database
+)
+
+// Konstanta untuk Aksi Revisi Dokumen
+const (
+	RevisionBaseline  = "DATA AWAL" // Revisi pertama untuk dokumen yang terbit sebelum riwayat revisi ada
+	RevisionCreated   = "DIBUAT"
+	RevisionUpdated   = "DIPERBARUI"
+	RevisionRestored  = "DIPULIHKAN"
+	RevisionApproved  = "DISETUJUI"
+	RevisionRevoked   = "DICABUT"
+	RevisionRecovered = "DIPULIHKAN DARI BACKUP" // Dokumen yang dimusnahkan dibuat kembali dari file backup
 )
 
 // Konstanta untuk Aksi Audit Log
 const (
-	AuditCreateUser      = "BUAT PENGGUNA"
-	AuditUpdateUser      = "UPDATE PENGGUNA"
-	AuditDeactivateUser  = "NONAKTIFKAN PENGGUNA"
-	AuditActivateUser    = "AKTIFKAN PENGGUNA"
-	AuditCreateDocument  = "BUAT DOKUMEN"
-	AuditUpdateDocument  = "UPDATE DOKUMEN"
-	AuditDeleteDocument  = "HAPUS DOKUMEN"
-	AuditSystemSetup     = "SETUP SISTEM"
-	AuditBackupCreated   = "BUAT BACKUP"
-	AuditRestoreFromFile = "PULIHKAN DARI FILE"
-	AuditSettingsUpdated = "PERBARUI PENGATURAN"
-)
\ No newline at end of file
+	AuditCreateUser         = "BUAT PENGGUNA"
+	AuditUpdateUser         = "UPDATE PENGGUNA"
+	AuditDeactivateUser     = "NONAKTIFKAN PENGGUNA"
+	AuditActivateUser       = "AKTIFKAN PENGGUNA"
+	AuditCreateDocument     = "BUAT DOKUMEN"
+	AuditUpdateDocument     = "UPDATE DOKUMEN"
+	AuditDeleteDocument     = "HAPUS DOKUMEN"
+	AuditSystemSetup        = "SETUP SISTEM"
+	AuditBackupCreated      = "BUAT BACKUP"
+	AuditRestoreFromFile    = "PULIHKAN DARI FILE"
+	AuditSettingsUpdated    = "PERBARUI PENGATURAN"
+	AuditMergeResidents     = "GABUNGKAN DATA PENDUDUK"
+	AuditRestoreRevision    = "PULIHKAN REVISI DOKUMEN"
+	AuditArchiveDocuments   = "ARSIPKAN DOKUMEN"
+	AuditCreateDocumentType = "BUAT JENIS DOKUMEN"
+	AuditUpdateDocumentType = "UPDATE JENIS DOKUMEN"
+	AuditSubmitDocument     = "AJUKAN DOKUMEN"
+	AuditApproveDocument    = "SETUJUI DOKUMEN"
+	AuditRejectDocument     = "TOLAK DOKUMEN"
+	AuditRevokeDocument     = "CABUT DOKUMEN"
+	AuditCreateRole         = "BUAT PERAN"
+	AuditUpdateRole         = "UPDATE PERAN"
+	AuditDeleteRole         = "HAPUS PERAN"
+	AuditExportDocuments    = "EKSPOR DOKUMEN"
+	AuditImportDocument     = "IMPOR DOKUMEN"
+	AuditCreateItemType     = "BUAT JENIS BARANG"
+	AuditUpdateItemType     = "UPDATE JENIS BARANG"
+	AuditRestoreDeleted     = "PULIHKAN DOKUMEN TERHAPUS"
+	AuditPurgeDocument      = "MUSNAHKAN DOKUMEN"
+	AuditBackupPruned       = "HAPUS BACKUP LAMA"
+	AuditBrowseBackup       = "BUKA BACKUP"
+	AuditRecoverFromBackup  = "PULIHKAN DOKUMEN DARI BACKUP"
+)
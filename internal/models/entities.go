@@ -12,18 +12,41 @@ type User struct {
 	NRP         string         `gorm:"size:20;not null;unique" json:"nrp"`
 	KataSandi   string         `gorm:"size:255;not null" json:"-"` // Kata sandi tidak diekspos di JSON
 	Pangkat     string         `gorm:"size:100" json:"pangkat"`
-	Peran       string         `gorm:"size:50;not null;default:'OPERATOR'" json:"peran"` // SUPER_ADMIN, OPERATOR
+	Peran       string         `gorm:"size:50;not null;default:'OPERATOR'" json:"peran"` // Kode peran di tabel roles, mis. SUPER_ADMIN, OPERATOR, KANIT_SPKT
 	Jabatan     string         `gorm:"size:100" json:"jabatan"` // KANIT SPKT, ANGGOTA JAGA REGU
 	Regu        string         `gorm:"size:10" json:"regu"` // I, II, III
 	CreatedAt   time.Time      `json:"created_at"`
 	UpdatedAt   time.Time      `json:"updated_at"`
 	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
+
+	// Role dimuat oleh UserRepository agar izin pengguna bisa diperiksa tanpa query tambahan
+	Role        *Role          `gorm:"foreignKey:Peran;references:Kode" json:"role,omitempty"`
+}
+
+// HasPermission memeriksa apakah peran pengguna memuat izin tertentu.
+// Super Admin selalu memiliki semua izin agar perubahan peran tidak dapat mengunci sistem.
+func (u *User) HasPermission(permission string) bool {
+	if u == nil {
+		return false
+	}
+	if u.Peran == RoleSuperAdmin {
+		return true
+	}
+	if u.Role == nil {
+		return false
+	}
+	for _, p := range u.Role.Permissions {
+		if p == permission {
+			return true
+		}
+	}
+	return false
 }
 
 // Resident merepresentasikan model penduduk/pemohon.
 type Resident struct {
 	ID           uint           `gorm:"primarykey" json:"id"`
-	NIK          string         `gorm:"size:16;not null;unique" json:"nik"`
+	NIK          *string        `gorm:"size:16;unique" json:"nik"` // Opsional; nil jika pemohon tidak menyebutkan NIK
 	NamaLengkap  string         `gorm:"size:255;not null" json:"nama_lengkap"`
 	TempatLahir  string         `gorm:"size:100;not null" json:"tempat_lahir"`
 	TanggalLahir time.Time      `gorm:"not null" json:"tanggal_lahir"`
@@ -39,10 +62,16 @@ type Resident struct {
 // LostDocument diperbarui dengan field OperatorID dan LastUpdatedByID
 type LostDocument struct {
 	ID                 uint           `gorm:"primarykey" json:"id"`
-	NomorSurat         string         `gorm:"size:255;not null;unique" json:"nomor_surat"`
+	NomorSurat         string         `gorm:"size:255;not null;default:''" json:"nomor_surat"` // Kosong selama draf; unik setelah terbit
+	NomorUrut          int            `gorm:"not null;default:0" json:"nomor_urut"`
+	TahunNomor         int            `gorm:"not null;default:0" json:"tahun_nomor"`
 	TanggalLaporan     time.Time      `gorm:"not null" json:"tanggal_laporan"`
-	Status             string         `gorm:"size:50;not null;default:'DITERBITKAN'" json:"status"`
+	Status             string         `gorm:"size:50;not null;default:'DRAF'" json:"status"`
 	LokasiHilang       string         `gorm:"type:text" json:"lokasi_hilang"`
+
+	// JenisDokumen merujuk ke DocumentType.Kode; isian khusus jenis tersebut disimpan di DataTambahan
+	JenisDokumen       string            `gorm:"size:50;not null;default:'LOST_DOCUMENT'" json:"jenis_dokumen"`
+	DataTambahan       map[string]string `gorm:"serializer:json;type:text" json:"data_tambahan"`
 	
 	ResidentID         uint           `gorm:"not null" json:"resident_id"`
 	Resident           Resident       `gorm:"foreignKey:ResidentID" json:"resident"`
@@ -66,25 +95,120 @@ type LostDocument struct {
 	LastUpdatedBy      User           `gorm:"foreignKey:LastUpdatedByID" json:"last_updated_by"`
 
 	TanggalPersetujuan *time.Time     `json:"tanggal_persetujuan"`
+	AlasanPenolakan    string         `gorm:"type:text" json:"alasan_penolakan"` // Alasan penolakan terakhir; dikosongkan saat diajukan ulang
+
+	// Pencabutan surat yang sudah terbit
+	TanggalPencabutan  *time.Time     `json:"tanggal_pencabutan"`
+	AlasanPencabutan   string         `gorm:"type:text" json:"alasan_pencabutan"`
+	DicabutOlehID      *uint          `json:"dicabut_oleh_id"`
+	DicabutOleh        User           `gorm:"foreignKey:DicabutOlehID" json:"dicabut_oleh"`
+
+	// Penghapusan (soft delete); dokumen terhapus menunggu di tong sampah sampai dipulihkan atau dimusnahkan
+	AlasanPenghapusan  string         `gorm:"type:text" json:"alasan_penghapusan"`
+	DihapusOlehID      *uint          `json:"dihapus_oleh_id"`
+	DihapusOleh        User           `gorm:"foreignKey:DihapusOlehID" json:"dihapus_oleh"`
+
 	CreatedAt          time.Time      `json:"created_at"`
 	UpdatedAt          time.Time      `json:"updated_at"`
 	DeletedAt          gorm.DeletedAt `gorm:"index" json:"-"`
 }
 
+// DocumentField adalah satu isian pada formulir sebuah jenis dokumen. Key "nik", "lokasi_hilang",
+// dan "lost_items" merujuk ke kolom bawaan dokumen; key lain disimpan di LostDocument.DataTambahan.
+type DocumentField struct {
+	Key   string `json:"key"`
+	Label string `json:"label"`
+	Tipe  string `json:"tipe"` // text, textarea, date
+	Wajib bool   `json:"wajib"`
+}
+
+// DocumentType adalah entri registri jenis surat yang dapat diterbitkan (Surat Keterangan Hilang, STPL, dst.).
+type DocumentType struct {
+	Kode          string          `gorm:"primaryKey;size:50" json:"kode"`
+	Nama          string          `gorm:"size:255;not null" json:"nama"`
+	FormatNomor   string          `gorm:"size:255" json:"format_nomor"` // Kosong berarti memakai Format Nomor Surat di Pengaturan
+	PrintTemplate string          `gorm:"size:100;not null" json:"print_template"`
+	Fields        []DocumentField `gorm:"serializer:json;type:text;not null" json:"fields"`
+	Aktif         bool            `gorm:"not null;default:true" json:"aktif"`
+	CreatedAt     time.Time       `json:"created_at"`
+	UpdatedAt     time.Time       `json:"updated_at"`
+}
+
+// Role membundel sejumlah izin bernama yang diberikan kepada pengguna melalui User.Peran.
+type Role struct {
+	Kode        string    `gorm:"primaryKey;size:50" json:"kode"`
+	Nama        string    `gorm:"size:255;not null" json:"nama"`
+	Deskripsi   string    `gorm:"type:text" json:"deskripsi"`
+	Permissions []string  `gorm:"serializer:json;type:text;not null" json:"permissions"`
+	Bawaan      bool      `gorm:"not null;default:false" json:"bawaan"` // Peran bawaan tidak dapat dihapus
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
 // LostItem merepresentasikan barang yang hilang.
+// Barang dari katalog menyimpan kode jenisnya di JenisBarang dan isian bertipe di Atribut; NamaBarang dan
+// Deskripsi tetap diisi (dari nama jenis dan atribut) karena dipakai untuk cetak, ekspor, dan pencarian.
+// Barang di luar katalog hanya memiliki NamaBarang dan Deskripsi bebas.
 type LostItem struct {
-	ID             uint   `gorm:"primarykey" json:"id"`
-	LostDocumentID uint   `gorm:"not null" json:"lost_document_id"`
-	NamaBarang     string `gorm:"size:255;not null" json:"nama_barang"`
-	Deskripsi      string `gorm:"type:text" json:"deskripsi"`
+	ID             uint              `gorm:"primarykey" json:"id"`
+	LostDocumentID uint              `gorm:"not null" json:"lost_document_id"`
+	NamaBarang     string            `gorm:"size:255;not null" json:"nama_barang"`
+	Deskripsi      string            `gorm:"type:text" json:"deskripsi"`
+	JenisBarang    string            `gorm:"size:50" json:"jenis_barang"` // Merujuk ke ItemType.Kode; kosong jika tidak dikenali katalog
+	Atribut        map[string]string `gorm:"serializer:json;type:text" json:"atribut"`
+}
+
+// ItemAttribute adalah satu isian bertipe pada sebuah jenis barang, misalnya NIK pada KTP atau IMEI pada HP.
+type ItemAttribute struct {
+	Key   string   `json:"key"`
+	Label string   `json:"label"`
+	Tipe  string   `json:"tipe"` // text, angka, pilihan, nik, nopol, imei
+	Wajib bool     `json:"wajib"`
+	Opsi  []string `json:"opsi,omitempty"` // Hanya untuk tipe pilihan
+	// Cocokkan menandai nomor identitas (NIK, nomor polisi, IMEI, dst.) yang dibandingkan antar laporan
+	// untuk menemukan laporan berulang. Nilai dicocokkan per Key, lintas jenis barang.
+	Cocokkan bool `json:"cocokkan"`
+}
+
+// ItemType adalah entri katalog jenis barang hilang (KTP, SIM, STNK, dst.) yang dikelola admin.
+// Alias memuat ejaan lain (misalnya E-KTP) agar barang yang diketik bebas tetap dikelompokkan ke jenis yang sama.
+type ItemType struct {
+	Kode      string          `gorm:"primaryKey;size:50" json:"kode"`
+	Nama      string          `gorm:"size:255;not null" json:"nama"`
+	Alias     []string        `gorm:"serializer:json;type:text;not null" json:"alias"`
+	Atribut   []ItemAttribute `gorm:"serializer:json;type:text;not null" json:"atribut"`
+	Aktif     bool            `gorm:"not null;default:true" json:"aktif"`
+	CreatedAt time.Time       `json:"created_at"`
+	UpdatedAt time.Time       `json:"updated_at"`
 }
 
 // AuditLog untuk mencatat aktivitas penting.
+// UserID kosong (nil) menandakan aksi otomatis oleh sistem, misalnya pengarsipan terjadwal.
 type AuditLog struct {
 	ID        uint      `gorm:"primarykey"`
-	UserID    uint      `gorm:"not null"`
-	User      User      `gorm:"foreignKey:UserID"`
+	UserID    *uint
+	User      *User     `gorm:"foreignKey:UserID"`
 	Aksi      string    `gorm:"size:255;not null"`
 	Detail    string    `gorm:"type:text"`
 	Timestamp time.Time `gorm:"not null"`
-}
\ No newline at end of file
+}
+
+// DocumentSequence menyimpan nomor urut terakhir per jenis dokumen dan tahun.
+type DocumentSequence struct {
+	DocumentType string    `gorm:"primaryKey;size:50" json:"document_type"`
+	Year         int       `gorm:"primaryKey" json:"year"`
+	LastNumber   int       `gorm:"not null;default:0" json:"last_number"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// DocumentRevision menyimpan salinan lengkap (snapshot JSON) dokumen setiap kali dokumen disimpan.
+type DocumentRevision struct {
+	ID             uint      `gorm:"primarykey" json:"id"`
+	LostDocumentID uint      `gorm:"not null;uniqueIndex:idx_document_revisions_document_revision" json:"lost_document_id"`
+	RevisionNumber int       `gorm:"not null;uniqueIndex:idx_document_revisions_document_revision" json:"revision_number"`
+	Aksi           string    `gorm:"size:50;not null" json:"aksi"` // DIBUAT, DIPERBARUI, DIPULIHKAN, DATA AWAL
+	Snapshot       string    `gorm:"type:text;not null" json:"-"`
+	ChangedByID    uint      `gorm:"not null" json:"changed_by_id"`
+	ChangedBy      User      `gorm:"foreignKey:ChangedByID" json:"changed_by"`
+	CreatedAt      time.Time `json:"created_at"`
+}
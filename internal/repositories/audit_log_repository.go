@@ -8,7 +8,12 @@ import (
 
 type AuditLogRepository interface {
 	Create(log *models.AuditLog) error
-	FindAll() ([]models.AuditLog, error)
+	// CreateBatch menyimpan banyak entri sekaligus. Menggunakan transaksi jika disediakan, agar entri
+	// audit ikut batal bersama perubahan yang dicatatnya.
+	CreateBatch(tx *gorm.DB, logs []models.AuditLog) error
+	// FindPage mengambil satu halaman log audit. Search mencocokkan aksi, detail, dan nama pengguna;
+	// From/To membatasi waktu dan OperatorID membatasi pengguna yang melakukan aksi.
+	FindPage(list ListQuery) ([]models.AuditLog, PageResult, error)
 }
 
 type auditLogRepository struct {
@@ -23,9 +28,55 @@ func (r *auditLogRepository) Create(log *models.AuditLog) error {
 	return r.db.Create(log).Error
 }
 
-func (r *auditLogRepository) FindAll() ([]models.AuditLog, error) {
+func (r *auditLogRepository) CreateBatch(tx *gorm.DB, logs []models.AuditLog) error {
+	if len(logs) == 0 {
+		return nil
+	}
+	db := r.db
+	if tx != nil {
+		db = tx
+	}
+	return db.CreateInBatches(logs, 500).Error
+}
+
+// auditLogSortColumns adalah kolom daftar log audit yang boleh dipakai untuk mengurutkan.
+var auditLogSortColumns = map[string]string{
+	"timestamp": "audit_logs.timestamp",
+	"user":      "(SELECT nama_lengkap FROM users WHERE users.id = audit_logs.user_id)",
+	"aksi":      "audit_logs.aksi",
+}
+
+func (r *auditLogRepository) FindPage(list ListQuery) ([]models.AuditLog, PageResult, error) {
+	var result PageResult
+	filtered := func(db *gorm.DB) *gorm.DB {
+		if list.Search != "" {
+			searchQuery := "%" + list.Search + "%"
+			db = db.Where("(audit_logs.aksi LIKE ? OR audit_logs.detail LIKE ? OR audit_logs.user_id IN (SELECT id FROM users WHERE nama_lengkap LIKE ?))", searchQuery, searchQuery, searchQuery)
+		}
+		if list.From != nil {
+			db = db.Where("audit_logs.timestamp >= ?", *list.From)
+		}
+		if list.To != nil {
+			db = db.Where("audit_logs.timestamp < ?", *list.To)
+		}
+		if list.OperatorID != 0 {
+			db = db.Where("audit_logs.user_id = ?", list.OperatorID)
+		}
+		return db
+	}
+
+	if err := r.db.Model(&models.AuditLog{}).Count(&result.Total).Error; err != nil {
+		return nil, result, err
+	}
+	result.Filtered = result.Total
+	if list.hasFilters() {
+		if err := filtered(r.db.Model(&models.AuditLog{})).Count(&result.Filtered).Error; err != nil {
+			return nil, result, err
+		}
+	}
+
 	var logs []models.AuditLog
 	// Preload User untuk mendapatkan data pengguna yang melakukan aksi
-	err := r.db.Preload("User").Order("timestamp desc").Find(&logs).Error
-	return logs, err
+	err := paginate(filtered(r.db.Preload("User")), list, auditLogSortColumns, "audit_logs.timestamp desc, audit_logs.id desc").Find(&logs).Error
+	return logs, result, err
 }
\ No newline at end of file
@@ -0,0 +1,78 @@
+package repositories
+
+import (
+	"simdokpol/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// DocumentRevisionRepository mendefinisikan kontrak untuk riwayat revisi dokumen.
+type DocumentRevisionRepository interface {
+	// Create menyimpan revisi baru dengan nomor revisi berikutnya untuk dokumen tersebut.
+	// Harus dipanggil di dalam transaksi penyimpanan dokumen agar nomor revisi tidak bentrok.
+	Create(tx *gorm.DB, revision *models.DocumentRevision) (*models.DocumentRevision, error)
+	// FindByDocumentID mengambil semua revisi sebuah dokumen, dari yang terbaru.
+	FindByDocumentID(docID uint) ([]models.DocumentRevision, error)
+	// FindByNumber mengambil satu revisi berdasarkan nomor revisinya.
+	FindByNumber(docID uint, revisionNumber int) (*models.DocumentRevision, error)
+	// LatestNumber mengembalikan nomor revisi terakhir sebuah dokumen, atau 0 jika belum ada.
+	LatestNumber(docID uint) (int, error)
+}
+
+type documentRevisionRepository struct {
+	db *gorm.DB
+}
+
+// NewDocumentRevisionRepository adalah factory untuk DocumentRevisionRepository.
+func NewDocumentRevisionRepository(db *gorm.DB) DocumentRevisionRepository {
+	return &documentRevisionRepository{db: db}
+}
+
+func (r *documentRevisionRepository) Create(tx *gorm.DB, revision *models.DocumentRevision) (*models.DocumentRevision, error) {
+	db := r.db
+	if tx != nil {
+		db = tx
+	}
+	var latest int
+	err := db.Model(&models.DocumentRevision{}).
+		Where("lost_document_id = ?", revision.LostDocumentID).
+		Select("COALESCE(MAX(revision_number), 0)").
+		Scan(&latest).Error
+	if err != nil {
+		return nil, err
+	}
+	revision.RevisionNumber = latest + 1
+	if err := db.Create(revision).Error; err != nil {
+		return nil, err
+	}
+	return revision, nil
+}
+
+func (r *documentRevisionRepository) FindByDocumentID(docID uint) ([]models.DocumentRevision, error) {
+	var revisions []models.DocumentRevision
+	err := r.db.Preload("ChangedBy", func(db *gorm.DB) *gorm.DB { return db.Unscoped() }).
+		Where("lost_document_id = ?", docID).
+		Order("revision_number desc").
+		Find(&revisions).Error
+	return revisions, err
+}
+
+func (r *documentRevisionRepository) FindByNumber(docID uint, revisionNumber int) (*models.DocumentRevision, error) {
+	var revision models.DocumentRevision
+	err := r.db.Preload("ChangedBy", func(db *gorm.DB) *gorm.DB { return db.Unscoped() }).
+		Where("lost_document_id = ? AND revision_number = ?", docID, revisionNumber).
+		First(&revision).Error
+	if err != nil {
+		return nil, err
+	}
+	return &revision, nil
+}
+
+func (r *documentRevisionRepository) LatestNumber(docID uint) (int, error) {
+	var latest int
+	err := r.db.Model(&models.DocumentRevision{}).
+		Where("lost_document_id = ?", docID).
+		Select("COALESCE(MAX(revision_number), 0)").
+		Scan(&latest).Error
+	return latest, err
+}
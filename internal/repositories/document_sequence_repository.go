@@ -0,0 +1,104 @@
+package repositories
+
+import (
+	"simdokpol/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// UsedNumber adalah nomor urut yang sudah terpakai oleh sebuah dokumen (termasuk yang dihapus).
+type UsedNumber struct {
+	NomorUrut  int            `gorm:"column:nomor_urut"`
+	NomorSurat string         `gorm:"column:nomor_surat"`
+	DeletedAt  gorm.DeletedAt `gorm:"column:deleted_at"`
+}
+
+// DocumentSequenceRepository mendefinisikan kontrak untuk penomoran surat per jenis dokumen dan tahun.
+type DocumentSequenceRepository interface {
+	// NextNumber menaikkan nomor urut secara atomik dan mengembalikan nomor baru.
+	// Harus dipanggil di dalam transaksi pembuatan dokumen agar nomor ikut di-rollback saat gagal.
+	NextNumber(tx *gorm.DB, documentType string, year int) (int, error)
+	// SetLastNumber mengatur nomor terakhir untuk tahun tertentu.
+	SetLastNumber(tx *gorm.DB, documentType string, year int, lastNumber int) error
+	// RaiseLastNumber menaikkan nomor terakhir menjadi lastNumber bila masih lebih kecil; nomor yang sudah
+	// lebih tinggi tidak pernah diturunkan. Dipakai saat memasukkan surat bernomor (impor, pemulihan).
+	RaiseLastNumber(tx *gorm.DB, documentType string, year int, lastNumber int) error
+	Get(documentType string, year int) (*models.DocumentSequence, error)
+	// FindUsedNumbers mengambil semua nomor urut dokumen suatu jenis pada tahun tertentu, termasuk yang di-soft delete.
+	FindUsedNumbers(documentType string, year int) ([]UsedNumber, error)
+}
+
+type documentSequenceRepository struct {
+	db *gorm.DB
+}
+
+// NewDocumentSequenceRepository adalah factory untuk DocumentSequenceRepository.
+func NewDocumentSequenceRepository(db *gorm.DB) DocumentSequenceRepository {
+	return &documentSequenceRepository{db: db}
+}
+
+func (r *documentSequenceRepository) NextNumber(tx *gorm.DB, documentType string, year int) (int, error) {
+	db := r.db
+	if tx != nil {
+		db = tx
+	}
+	// UPSERT ... RETURNING dieksekusi sebagai satu pernyataan tulis, sehingga SQLite
+	// mengambil write lock sekaligus dan dua transaksi tidak bisa mendapat nomor yang sama.
+	// Baris baru (tahun baru) otomatis dimulai dari 1, yang sekaligus menjadi reset tahunan.
+	var next int
+	err := db.Raw(
+		"INSERT INTO document_sequences (document_type, year, last_number, updated_at) VALUES (?, ?, 1, ?) "+
+			"ON CONFLICT (document_type, year) DO UPDATE SET last_number = last_number + 1, updated_at = excluded.updated_at "+
+			"RETURNING last_number",
+		documentType, year, time.Now(),
+	).Scan(&next).Error
+	if err != nil {
+		return 0, err
+	}
+	return next, nil
+}
+
+func (r *documentSequenceRepository) SetLastNumber(tx *gorm.DB, documentType string, year int, lastNumber int) error {
+	db := r.db
+	if tx != nil {
+		db = tx
+	}
+	return db.Exec(
+		"INSERT INTO document_sequences (document_type, year, last_number, updated_at) VALUES (?, ?, ?, ?) "+
+			"ON CONFLICT (document_type, year) DO UPDATE SET last_number = excluded.last_number, updated_at = excluded.updated_at",
+		documentType, year, lastNumber, time.Now(),
+	).Error
+}
+
+func (r *documentSequenceRepository) RaiseLastNumber(tx *gorm.DB, documentType string, year int, lastNumber int) error {
+	db := r.db
+	if tx != nil {
+		db = tx
+	}
+	// MAX dihitung di dalam pernyataan yang sama, sehingga nomor yang diterbitkan transaksi lain
+	// di antara pembacaan dan penulisan tidak tertimpa nilai yang lebih kecil.
+	return db.Exec(
+		"INSERT INTO document_sequences (document_type, year, last_number, updated_at) VALUES (?, ?, ?, ?) "+
+			"ON CONFLICT (document_type, year) DO UPDATE SET last_number = MAX(document_sequences.last_number, excluded.last_number), updated_at = excluded.updated_at",
+		documentType, year, lastNumber, time.Now(),
+	).Error
+}
+
+func (r *documentSequenceRepository) Get(documentType string, year int) (*models.DocumentSequence, error) {
+	var seq models.DocumentSequence
+	if err := r.db.Where("document_type = ? AND year = ?", documentType, year).First(&seq).Error; err != nil {
+		return nil, err
+	}
+	return &seq, nil
+}
+
+func (r *documentSequenceRepository) FindUsedNumbers(documentType string, year int) ([]UsedNumber, error) {
+	var results []UsedNumber
+	err := r.db.Unscoped().Model(&models.LostDocument{}).
+		Select("nomor_urut, nomor_surat, deleted_at").
+		Where("jenis_dokumen = ? AND tahun_nomor = ? AND nomor_urut > 0", documentType, year).
+		Order("nomor_urut asc").
+		Scan(&results).Error
+	return results, err
+}
@@ -0,0 +1,59 @@
+package repositories
+
+import (
+	"simdokpol/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// DocumentTypeRepository mendefinisikan kontrak untuk registri jenis dokumen.
+type DocumentTypeRepository interface {
+	// FindAll mengambil semua jenis dokumen, atau hanya yang aktif bila activeOnly bernilai true.
+	FindAll(activeOnly bool) ([]models.DocumentType, error)
+	FindByCode(kode string) (*models.DocumentType, error)
+	Create(docType *models.DocumentType) (*models.DocumentType, error)
+	Update(docType *models.DocumentType) (*models.DocumentType, error)
+}
+
+type documentTypeRepository struct {
+	db *gorm.DB
+}
+
+// NewDocumentTypeRepository adalah factory untuk DocumentTypeRepository.
+func NewDocumentTypeRepository(db *gorm.DB) DocumentTypeRepository {
+	return &documentTypeRepository{db: db}
+}
+
+func (r *documentTypeRepository) FindAll(activeOnly bool) ([]models.DocumentType, error) {
+	var docTypes []models.DocumentType
+	db := r.db.Order("created_at asc, kode asc")
+	if activeOnly {
+		db = db.Where("aktif = ?", true)
+	}
+	err := db.Find(&docTypes).Error
+	return docTypes, err
+}
+
+func (r *documentTypeRepository) FindByCode(kode string) (*models.DocumentType, error) {
+	var docType models.DocumentType
+	if err := r.db.Where("kode = ?", kode).First(&docType).Error; err != nil {
+		return nil, err
+	}
+	return &docType, nil
+}
+
+func (r *documentTypeRepository) Create(docType *models.DocumentType) (*models.DocumentType, error) {
+	// Select("*") agar jenis yang langsung dibuat nonaktif tidak tertimpa default aktif dari kolom.
+	if err := r.db.Select("*").Create(docType).Error; err != nil {
+		return nil, err
+	}
+	return docType, nil
+}
+
+func (r *documentTypeRepository) Update(docType *models.DocumentType) (*models.DocumentType, error) {
+	// Select("*") agar nilai kosong (format nomor dikosongkan, jenis dinonaktifkan) ikut tersimpan.
+	if err := r.db.Model(docType).Select("*").Omit("created_at").Updates(docType).Error; err != nil {
+		return nil, err
+	}
+	return docType, nil
+}
@@ -0,0 +1,80 @@
+package repositories
+
+import (
+	"simdokpol/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// ItemTypeRepository mendefinisikan kontrak untuk katalog jenis barang hilang.
+type ItemTypeRepository interface {
+	// FindAll mengambil semua jenis barang, atau hanya yang aktif bila activeOnly bernilai true.
+	FindAll(activeOnly bool) ([]models.ItemType, error)
+	FindByCode(kode string) (*models.ItemType, error)
+	Create(itemType *models.ItemType) (*models.ItemType, error)
+	Update(itemType *models.ItemType) (*models.ItemType, error)
+	// ClassifyUntypedItems mengelompokkan barang yang belum memiliki jenis ke jenis barang yang kode,
+	// nama, atau aliasnya sama dengan nama barang, lalu mengembalikan jumlah barang yang dikelompokkan.
+	ClassifyUntypedItems() (int64, error)
+}
+
+type itemTypeRepository struct {
+	db *gorm.DB
+}
+
+// NewItemTypeRepository adalah factory untuk ItemTypeRepository.
+func NewItemTypeRepository(db *gorm.DB) ItemTypeRepository {
+	return &itemTypeRepository{db: db}
+}
+
+func (r *itemTypeRepository) FindAll(activeOnly bool) ([]models.ItemType, error) {
+	var itemTypes []models.ItemType
+	db := r.db.Order("created_at asc, kode asc")
+	if activeOnly {
+		db = db.Where("aktif = ?", true)
+	}
+	err := db.Find(&itemTypes).Error
+	return itemTypes, err
+}
+
+func (r *itemTypeRepository) FindByCode(kode string) (*models.ItemType, error) {
+	var itemType models.ItemType
+	if err := r.db.Where("kode = ?", kode).First(&itemType).Error; err != nil {
+		return nil, err
+	}
+	return &itemType, nil
+}
+
+func (r *itemTypeRepository) Create(itemType *models.ItemType) (*models.ItemType, error) {
+	// Select("*") agar jenis yang langsung dibuat nonaktif tidak tertimpa default aktif dari kolom.
+	if err := r.db.Select("*").Create(itemType).Error; err != nil {
+		return nil, err
+	}
+	return itemType, nil
+}
+
+func (r *itemTypeRepository) Update(itemType *models.ItemType) (*models.ItemType, error) {
+	// Select("*") agar nilai kosong (alias dikosongkan, jenis dinonaktifkan) ikut tersimpan.
+	if err := r.db.Model(itemType).Select("*").Omit("created_at").Updates(itemType).Error; err != nil {
+		return nil, err
+	}
+	return itemType, nil
+}
+
+// matchingItemTypeSQL memilih kode jenis barang yang kode, nama, atau aliasnya sama dengan nama barang.
+// Alias disimpan dalam huruf kapital; aturannya sama dengan classifyItemName di layanan.
+const matchingItemTypeSQL = `(SELECT t.kode FROM item_types t
+	WHERE UPPER(TRIM(lost_items.nama_barang)) IN (UPPER(t.kode), UPPER(t.nama))
+	   OR EXISTS (SELECT 1 FROM json_each(t.alias) WHERE value = UPPER(TRIM(lost_items.nama_barang)))
+	ORDER BY t.kode
+	LIMIT 1)`
+
+// itemLabelSQL memberi label statistik barang hilang: nama jenis dari katalog, atau nama barang
+// apa adanya untuk barang isian bebas yang belum masuk katalog.
+const itemLabelSQL = "COALESCE((SELECT t.nama FROM item_types t WHERE t.kode = lost_items.jenis_barang), lost_items.nama_barang)"
+
+func (r *itemTypeRepository) ClassifyUntypedItems() (int64, error) {
+	result := r.db.Exec("UPDATE lost_items SET jenis_barang = " + matchingItemTypeSQL +
+		" WHERE COALESCE(jenis_barang, '') = '' AND " + matchingItemTypeSQL + " IS NOT NULL")
+	return result.RowsAffected, result.Error
+}
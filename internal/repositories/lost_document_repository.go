@@ -2,7 +2,9 @@ package repositories
 
 import (
 	"fmt"
+	"html"
 	"simdokpol/internal/models"
+	"strings"
 	"time"
 
 	"gorm.io/gorm"
@@ -19,20 +21,70 @@ type ItemCompositionStat struct {
 	Count      int    `gorm:"column:count"`
 }
 
+// DocumentScope membatasi dokumen yang terlihat oleh seorang pengguna.
+// All berarti tanpa batasan. Selain itu hanya dokumen buatan UserID, dokumen yang diajukan kepadanya,
+// dan dokumen buatan anggota Regu yang sama (jika Regu diisi) yang terlihat.
+type DocumentScope struct {
+	All    bool
+	UserID uint
+	Regu   string
+}
+
+// DocumentFilter adalah filter daftar dokumen yang dipakai bersama oleh halaman daftar dan ekspor.
+// Status mengikuti filter halaman daftar (active, archived, draft, revoked); "all" berarti semua status
+// seperti halaman pencarian. From dan To (opsional) membatasi tanggal laporan, To bersifat eksklusif.
+// OperatorID dan ItemType (opsional) membatasi pembuat dokumen dan jenis barang yang hilang; ItemType
+// berupa kode katalog barang, atau nama barang untuk barang isian bebas.
+type DocumentFilter struct {
+	Query      string
+	Status     string
+	From       *time.Time
+	To         *time.Time
+	OperatorID uint
+	ItemType   string
+}
+
 type LostDocumentRepository interface {
 	Create(tx *gorm.DB, doc *models.LostDocument) (*models.LostDocument, error)
 	FindByID(id uint) (*models.LostDocument, error)
-	FindAll(query string, statusFilter string, archiveDurationDays int) ([]models.LostDocument, error)
-	SearchGlobal(query string) ([]models.LostDocument, error)
+	FindByIDUnscoped(id uint) (*models.LostDocument, error)
+	// FindPage mengambil satu halaman dokumen berstatus statusFilter yang terlihat dalam scope,
+	// beserta jumlah total dan jumlah setelah filter list.
+	FindPage(statusFilter string, scope DocumentScope, list ListQuery) ([]models.LostDocument, PageResult, error)
+	// SearchGlobal mencari dokumen lewat indeks teks penuh document_search (Nomor Surat, data pemohon,
+	// lokasi hilang, serta nama dan keterangan barang), diurutkan dari yang paling relevan.
+	SearchGlobal(query string, scope DocumentScope) ([]DocumentSearchHit, error)
+	// FindInBatches mengambil dokumen yang cocok dengan filter per batchSize dokumen dan memanggil fn
+	// untuk setiap batch, sehingga ekspor daftar besar tidak perlu memuat semuanya sekaligus.
+	FindInBatches(filter DocumentFilter, scope DocumentScope, batchSize int, fn func(docs []models.LostDocument) error) error
+	// FindIdentifierDuplicates mengelompokkan nomor identitas barang (atribut katalog bertanda cocokkan)
+	// yang tercatat pada lebih dari satu dokumen yang belum dihapus.
+	FindIdentifierDuplicates() ([]IdentifierMatchGroup, error)
+	// FindIdentifierMatches mengambil dokumen lain yang memuat nomor identitas barang yang sama dengan
+	// barang pada dokumen docID, dikelompokkan per nomor identitas.
+	FindIdentifierMatches(docID uint) ([]IdentifierMatchGroup, error)
+	// FindExistingNomorSurat mengambil semua Nomor Surat yang sudah terpakai, termasuk dokumen terhapus.
+	FindExistingNomorSurat() ([]string, error)
 	Update(tx *gorm.DB, doc *models.LostDocument) (*models.LostDocument, error)
 	Delete(tx *gorm.DB, id uint) error
-	GetLastDocumentOfYear(year int) (*models.LostDocument, error)
 	CountByDateRange(start time.Time, end time.Time) (int64, error)
 	GetMonthlyIssuanceForYear(year int) ([]MonthlyCount, error)
 	GetItemCompositionStats() ([]ItemCompositionStat, error)
 	FindExpiringDocumentsForUser(userID uint, expiryDateStart time.Time, expiryDateEnd time.Time) ([]models.LostDocument, error) // <-- METHOD BARU
+	// ArchiveIssuedBefore mengubah status dokumen DITERBITKAN yang dilaporkan sebelum cutoff menjadi DIARSIPKAN,
+	// lalu mengembalikan jumlah dokumen yang diarsipkan.
+	ArchiveIssuedBefore(cutoff time.Time) (int64, error)
+	// FindAwaitingApproval mengambil dokumen berstatus MENUNGGU_PERSETUJUAN yang menunjuk pejabatID sebagai pejabat persetuju.
+	FindAwaitingApproval(pejabatID uint) ([]models.LostDocument, error)
+	// TransitionStatus menerapkan changes hanya jika status dokumen saat ini termasuk fromStatuses.
+	// Nilai false berarti dokumen sudah berpindah status lebih dulu (misalnya disetujui pengguna lain).
+	TransitionStatus(tx *gorm.DB, id uint, fromStatuses []string, changes map[string]interface{}) (bool, error)
 }
 
+// issuedStatuses adalah status dokumen yang sudah pernah terbit (memiliki Nomor Surat).
+// Statistik dasbor hanya menghitung dokumen dengan status ini.
+var issuedStatuses = []string{models.StatusDiterbitkan, models.StatusDiarsipkan, models.StatusDicabut}
+
 type lostDocumentRepository struct {
 	db *gorm.DB
 }
@@ -46,6 +98,7 @@ func (r *lostDocumentRepository) FindExpiringDocumentsForUser(userID uint, expir
 	var docs []models.LostDocument
 	err := r.db.
 		Where("operator_id = ?", userID).
+		Where("status = ?", models.StatusDiterbitkan).
 		Where("tanggal_laporan BETWEEN ? AND ?", expiryDateStart, expiryDateEnd).
 		Order("tanggal_laporan asc").
 		Find(&docs).Error
@@ -55,66 +108,325 @@ func (r *lostDocumentRepository) FindExpiringDocumentsForUser(userID uint, expir
 
 func (r *lostDocumentRepository) CountByDateRange(start time.Time, end time.Time) (int64, error) {
 	var count int64
-	err := r.db.Model(&models.LostDocument{}).Where("tanggal_laporan BETWEEN ? AND ?", start, end).Count(&count).Error
+	err := r.db.Model(&models.LostDocument{}).Where("tanggal_laporan BETWEEN ? AND ?", start, end).Where("status IN ?", issuedStatuses).Count(&count).Error
 	if err != nil {
 		return 0, err
 	}
 	return count, nil
 }
 
-func (r *lostDocumentRepository) FindAll(query string, statusFilter string, archiveDurationDays int) ([]models.LostDocument, error) {
+// applyScope menambahkan batasan visibilitas scope pada query dokumen. Subquery regu sengaja ikut
+// mencakup pengguna nonaktif, sejalan dengan relasi Operator yang dimuat tanpa scope: dokumen anggota
+// regu yang sudah dinonaktifkan tetap dapat dilanjutkan oleh regunya.
+func applyScope(db *gorm.DB, scope DocumentScope) *gorm.DB {
+	if scope.All {
+		return db
+	}
+	if scope.Regu == "" {
+		return db.Where("(lost_documents.operator_id = ? OR lost_documents.pejabat_persetuju_id = ?)", scope.UserID, scope.UserID)
+	}
+	return db.Where("(lost_documents.operator_id = ? OR lost_documents.pejabat_persetuju_id = ? OR lost_documents.operator_id IN (SELECT id FROM users WHERE regu = ?))", scope.UserID, scope.UserID, scope.Regu)
+}
+
+// documentSortColumns adalah kolom daftar dokumen yang boleh dipakai untuk mengurutkan.
+// Nama pemohon dan operator diambil lewat subquery agar tidak bentrok dengan JOIN filter pencarian.
+var documentSortColumns = map[string]string{
+	"nomor_surat":     "lost_documents.nomor_surat",
+	"jenis_dokumen":   "lost_documents.jenis_dokumen",
+	"nama_pemohon":    "(SELECT nama_lengkap FROM residents WHERE residents.id = lost_documents.resident_id)",
+	"tanggal_laporan": "lost_documents.tanggal_laporan",
+	"status":          "lost_documents.status",
+	"operator":        "(SELECT nama_lengkap FROM users WHERE users.id = lost_documents.operator_id)",
+}
+
+func (r *lostDocumentRepository) FindPage(statusFilter string, scope DocumentScope, list ListQuery) ([]models.LostDocument, PageResult, error) {
+	var result PageResult
+	base := DocumentFilter{Status: statusFilter}
+	if err := applyScope(applyDocumentFilter(r.db.Model(&models.LostDocument{}), base), scope).Count(&result.Total).Error; err != nil {
+		return nil, result, err
+	}
+	filter := DocumentFilter{
+		Query:      list.Search,
+		Status:     statusFilter,
+		From:       list.From,
+		To:         list.To,
+		OperatorID: list.OperatorID,
+		ItemType:   list.ItemType,
+	}
+	result.Filtered = result.Total
+	if list.hasFilters() {
+		if err := applyScope(applyDocumentFilter(r.db.Model(&models.LostDocument{}), filter), scope).Count(&result.Filtered).Error; err != nil {
+			return nil, result, err
+		}
+	}
+
 	var docs []models.LostDocument
 	db := r.db.
 		Preload("Resident").
 		Preload("LostItems").
 		Preload("PetugasPelapor").
 		Preload("PejabatPersetuju").
-		Preload("Operator").
-		Order("tanggal_laporan desc")
+		Preload("Operator", unscopedUser)
+	db = applyScope(applyDocumentFilter(db, filter), scope)
+	if err := paginate(db, list, documentSortColumns, "lost_documents.tanggal_laporan desc, lost_documents.id desc").Find(&docs).Error; err != nil {
+		return nil, result, err
+	}
+	return docs, result, nil
+}
 
-	archiveDate := time.Now().Add(-time.Duration(archiveDurationDays) * 24 * time.Hour)
-	if statusFilter == "archived" {
-		db = db.Where("tanggal_laporan <= ?", archiveDate)
-	} else {
-		db = db.Where("tanggal_laporan > ?", archiveDate)
+func (r *lostDocumentRepository) FindInBatches(filter DocumentFilter, scope DocumentScope, batchSize int, fn func(docs []models.LostDocument) error) error {
+	for offset := 0; ; offset += batchSize {
+		var docs []models.LostDocument
+		db := r.db.
+			Preload("Resident").
+			Preload("LostItems").
+			Preload("PetugasPelapor").
+			Preload("PejabatPersetuju").
+			Preload("Operator", unscopedUser).
+			Order("lost_documents.tanggal_laporan desc, lost_documents.id desc")
+		db = applyScope(applyDocumentFilter(db, filter), scope)
+		if err := db.Limit(batchSize).Offset(offset).Find(&docs).Error; err != nil {
+			return err
+		}
+		if len(docs) == 0 {
+			return nil
+		}
+		if err := fn(docs); err != nil {
+			return err
+		}
+		if len(docs) < batchSize {
+			return nil
+		}
 	}
+}
+
+func (r *lostDocumentRepository) FindExistingNomorSurat() ([]string, error) {
+	var numbers []string
+	err := r.db.Unscoped().Model(&models.LostDocument{}).Where("nomor_surat <> ''").Pluck("nomor_surat", &numbers).Error
+	return numbers, err
+}
 
-	if query != "" {
-		searchQuery := fmt.Sprintf("%%%s%%", query)
+// applyDocumentFilter menambahkan filter status, kata kunci (Nomor Surat / nama pemohon / indeks teks penuh),
+// rentang tanggal laporan, operator, dan jenis barang pada query dokumen.
+func applyDocumentFilter(db *gorm.DB, filter DocumentFilter) *gorm.DB {
+	switch filter.Status {
+	case "all":
+	case "archived":
+		db = db.Where("lost_documents.status = ?", models.StatusDiarsipkan)
+	case "draft":
+		db = db.Where("lost_documents.status IN ?", []string{models.StatusDraf, models.StatusMenungguPersetujuan})
+	case "revoked":
+		db = db.Where("lost_documents.status = ?", models.StatusDicabut)
+	default:
+		db = db.Where("lost_documents.status = ?", models.StatusDiterbitkan)
+	}
+	if filter.From != nil {
+		db = db.Where("lost_documents.tanggal_laporan >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		db = db.Where("lost_documents.tanggal_laporan < ?", *filter.To)
+	}
+	if filter.OperatorID != 0 {
+		db = db.Where("lost_documents.operator_id = ?", filter.OperatorID)
+	}
+	if filter.ItemType != "" {
+		db = db.Where("EXISTS (SELECT 1 FROM lost_items WHERE lost_items.lost_document_id = lost_documents.id AND (lost_items.jenis_barang = ? OR UPPER(lost_items.nama_barang) = UPPER(?)))", filter.ItemType, filter.ItemType)
+	}
+	if filter.Query != "" {
+		// Selain potongan Nomor Surat / nama, kata kunci juga dicocokkan ke indeks teks penuh
+		// agar ekspor dari halaman pencarian memuat dokumen yang sama dengan hasil pencarian.
+		searchQuery := fmt.Sprintf("%%%s%%", filter.Query)
+		condition := "lost_documents.nomor_surat LIKE ? OR residents.nama_lengkap LIKE ?"
+		args := []interface{}{searchQuery, searchQuery}
+		if match := ftsMatchQuery(filter.Query); match != "" {
+			condition += " OR lost_documents.id IN (SELECT rowid FROM document_search WHERE document_search MATCH ?)"
+			args = append(args, match)
+		}
 		db = db.Joins("JOIN residents ON lost_documents.resident_id = residents.id").
-			Where("lost_documents.nomor_surat LIKE ? OR residents.nama_lengkap LIKE ?", searchQuery, searchQuery)
+			Where("("+condition+")", args...)
 	}
+	return db
+}
 
-	err := db.Find(&docs).Error
-	if err != nil {
-		return nil, err
+// searchResultLimit membatasi jumlah hasil pencarian global; hasil diurutkan menurut relevansi
+// sehingga dokumen di luar batas ini jarang dibutuhkan.
+const searchResultLimit = 200
+
+// Penanda awal dan akhir kata yang cocok pada cuplikan FTS5. Dipakai karakter kontrol agar teks dokumen
+// bisa di-escape terlebih dahulu sebelum penanda diganti dengan tag <mark>.
+const (
+	snippetOpen  = "\x02"
+	snippetClose = "\x03"
+)
+
+// DocumentSearchHit adalah satu hasil pencarian global. Snippet berisi cuplikan HTML yang sudah di-escape
+// dengan kata yang cocok dibungkus <mark>.
+type DocumentSearchHit struct {
+	models.LostDocument
+	Snippet string `json:"snippet"`
+}
+
+type documentSearchRow struct {
+	ID      uint    `gorm:"column:id"`
+	Snippet string  `gorm:"column:snippet"`
+	Skor    float64 `gorm:"column:skor"`
+}
+
+// ftsMatchQuery mengubah kata kunci pengguna menjadi ekspresi MATCH FTS5: setiap kata menjadi frasa
+// berawalan (prefix) dan semua kata harus ada. Tanda kutip dibuang agar sintaks FTS5 tidak bisa disisipkan.
+func ftsMatchQuery(query string) string {
+	var terms []string
+	for _, word := range strings.Fields(strings.ReplaceAll(query, `"`, " ")) {
+		terms = append(terms, `"`+word+`"*`)
 	}
-	return docs, nil
+	return strings.Join(terms, " ")
+}
+
+// highlightSnippet meng-escape cuplikan FTS5 lalu mengganti penanda kata yang cocok dengan <mark>.
+func highlightSnippet(snippet string) string {
+	escaped := html.EscapeString(snippet)
+	return strings.NewReplacer(snippetOpen, "<mark>", snippetClose, "</mark>").Replace(escaped)
 }
 
-func (r *lostDocumentRepository) SearchGlobal(query string) ([]models.LostDocument, error) {
+func (r *lostDocumentRepository) SearchGlobal(query string, scope DocumentScope) ([]DocumentSearchHit, error) {
+	match := ftsMatchQuery(query)
+	if match == "" {
+		return []DocumentSearchHit{}, nil
+	}
+
+	// Bobot bm25 mengikuti urutan kolom document_search: Nomor Surat, nama, dan NIK paling menentukan.
+	var rows []documentSearchRow
+	db := r.db.Table("document_search").
+		Select("document_search.rowid AS id, snippet(document_search, -1, ?, ?, '…', 12) AS snippet, bm25(document_search, 10.0, 5.0, 5.0, 1.0, 1.0, 1.0, 2.0, 2.0) AS skor", snippetOpen, snippetClose).
+		Joins("JOIN lost_documents ON lost_documents.id = document_search.rowid AND lost_documents.deleted_at IS NULL").
+		Where("document_search MATCH ?", match)
+	db = applyScope(db, scope)
+	if err := db.Order("skor, lost_documents.tanggal_laporan desc").Limit(searchResultLimit).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return []DocumentSearchHit{}, nil
+	}
+
+	ids := make([]uint, len(rows))
+	for i, row := range rows {
+		ids[i] = row.ID
+	}
 	var docs []models.LostDocument
-	db := r.db.
+	err := r.db.
 		Preload("Resident").
 		Preload("LostItems").
 		Preload("PetugasPelapor").
 		Preload("PejabatPersetuju").
-		Preload("Operator").
-		Order("tanggal_laporan desc")
+		Preload("Operator", unscopedUser).
+		Where("id IN ?", ids).
+		Find(&docs).Error
+	if err != nil {
+		return nil, err
+	}
+	docsByID := make(map[uint]models.LostDocument, len(docs))
+	for _, doc := range docs {
+		docsByID[doc.ID] = doc
+	}
 
-	if query != "" {
-		searchQuery := fmt.Sprintf("%%%s%%", query)
-		db = db.Joins("JOIN residents ON lost_documents.resident_id = residents.id").
-			Where("lost_documents.nomor_surat LIKE ? OR residents.nama_lengkap LIKE ?", searchQuery, searchQuery)
-	} else {
-		return docs, nil
+	hits := make([]DocumentSearchHit, 0, len(rows))
+	for _, row := range rows {
+		doc, ok := docsByID[row.ID]
+		if !ok {
+			continue
+		}
+		hits = append(hits, DocumentSearchHit{LostDocument: doc, Snippet: highlightSnippet(row.Snippet)})
 	}
+	return hits, nil
+}
 
-	err := db.Find(&docs).Error
+// IdentifierDocument adalah satu laporan yang memuat nomor identitas barang tertentu.
+type IdentifierDocument struct {
+	ID             uint      `gorm:"column:lost_document_id" json:"id"`
+	NomorSurat     string    `json:"nomor_surat"`
+	Status         string    `json:"status"`
+	TanggalLaporan time.Time `json:"tanggal_laporan"`
+	NamaPemohon    string    `json:"nama_pemohon"`
+	NamaBarang     string    `json:"nama_barang"`
+}
+
+// IdentifierMatchGroup adalah satu nomor identitas barang (NIK, nomor polisi, IMEI, dst.) beserta laporan
+// yang memuatnya, sebagai petunjuk laporan berulang atau klaim palsu.
+type IdentifierMatchGroup struct {
+	Atribut   string               `json:"atribut"` // Key atribut katalog, misalnya nopol
+	Label     string               `json:"label"`
+	Nilai     string               `json:"nilai"`
+	Documents []IdentifierDocument `json:"documents"`
+}
+
+type identifierMatchRow struct {
+	IdentifierDocument
+	Atribut string
+	Label   string
+	Nilai   string
+}
+
+// identifierMatchSQL mendaftar nilai atribut barang yang ditandai cocokkan pada katalog, satu baris per
+// barang dan atribut dari dokumen yang belum dihapus. Nilai dicocokkan per key atribut tanpa membedakan
+// huruf besar/kecil, sehingga nomor polisi pada STNK dan BPKB saling terhubung.
+const identifierMatchSQL = `WITH identifiers AS (
+	SELECT li.lost_document_id, li.nama_barang, a.key AS atribut,
+		json_extract(ta.value, '$.label') AS label, UPPER(TRIM(a.value)) AS nilai
+	FROM lost_items li
+	JOIN lost_documents d ON d.id = li.lost_document_id AND d.deleted_at IS NULL
+	JOIN item_types t ON t.kode = li.jenis_barang
+	JOIN json_each(t.atribut) ta ON json_extract(ta.value, '$.cocokkan') = 1
+	JOIN json_each(li.atribut) a ON a.key = json_extract(ta.value, '$.key')
+	WHERE li.atribut IS NOT NULL AND TRIM(a.value) <> ''
+)
+SELECT i.atribut, i.label, i.nilai, i.lost_document_id, i.nama_barang,
+	d.nomor_surat, d.status, d.tanggal_laporan, r.nama_lengkap AS nama_pemohon
+FROM identifiers i
+JOIN lost_documents d ON d.id = i.lost_document_id
+JOIN residents r ON r.id = d.resident_id
+WHERE `
+
+const identifierMatchOrder = ` ORDER BY i.atribut, i.nilai, d.tanggal_laporan, i.lost_document_id`
+
+func (r *lostDocumentRepository) FindIdentifierDuplicates() ([]IdentifierMatchGroup, error) {
+	var rows []identifierMatchRow
+	err := r.db.Raw(identifierMatchSQL +
+		"(i.atribut, i.nilai) IN (SELECT atribut, nilai FROM identifiers GROUP BY atribut, nilai HAVING COUNT(DISTINCT lost_document_id) > 1)" +
+		identifierMatchOrder).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	return groupIdentifierMatches(rows), nil
+}
+
+func (r *lostDocumentRepository) FindIdentifierMatches(docID uint) ([]IdentifierMatchGroup, error) {
+	var rows []identifierMatchRow
+	err := r.db.Raw(identifierMatchSQL+
+		"i.lost_document_id <> ? AND (i.atribut, i.nilai) IN (SELECT atribut, nilai FROM identifiers WHERE lost_document_id = ?)"+
+		identifierMatchOrder, docID, docID).Scan(&rows).Error
 	if err != nil {
 		return nil, err
 	}
-	return docs, nil
+	return groupIdentifierMatches(rows), nil
+}
+
+// groupIdentifierMatches memotong baris yang sudah terurut per atribut dan nilai menjadi kelompok.
+// Dokumen yang memuat nilai yang sama pada lebih dari satu barang (misalnya STNK dan BPKB) dicatat sekali.
+func groupIdentifierMatches(rows []identifierMatchRow) []IdentifierMatchGroup {
+	groups := []IdentifierMatchGroup{}
+	for _, row := range rows {
+		last := len(groups) - 1
+		if last < 0 || groups[last].Atribut != row.Atribut || groups[last].Nilai != row.Nilai {
+			groups = append(groups, IdentifierMatchGroup{Atribut: row.Atribut, Label: row.Label, Nilai: row.Nilai})
+			last++
+		}
+		docs := groups[last].Documents
+		if len(docs) > 0 && docs[len(docs)-1].ID == row.ID {
+			continue
+		}
+		groups[last].Documents = append(docs, row.IdentifierDocument)
+	}
+	return groups
 }
 
 func (r *lostDocumentRepository) Create(tx *gorm.DB, doc *models.LostDocument) (*models.LostDocument, error) {
@@ -130,13 +442,22 @@ func (r *lostDocumentRepository) Create(tx *gorm.DB, doc *models.LostDocument) (
 
 func (r *lostDocumentRepository) FindByID(id uint) (*models.LostDocument, error) {
 	var doc models.LostDocument
-	err := r.db.Preload("Resident").Preload("LostItems").Preload("PetugasPelapor").Preload("PejabatPersetuju").Preload("Operator").Preload("LastUpdatedBy").First(&doc, id).Error
+	err := r.db.Preload("Resident").Preload("LostItems").Preload("PetugasPelapor").Preload("PejabatPersetuju").Preload("Operator", unscopedUser).Preload("LastUpdatedBy").Preload("DicabutOleh").First(&doc, id).Error
 	if err != nil {
 		return nil, err
 	}
 	return &doc, nil
 }
 
+// FindByIDUnscoped mengambil dokumen tanpa preload, termasuk yang sudah di-soft delete.
+func (r *lostDocumentRepository) FindByIDUnscoped(id uint) (*models.LostDocument, error) {
+	var doc models.LostDocument
+	if err := r.db.Unscoped().First(&doc, id).Error; err != nil {
+		return nil, err
+	}
+	return &doc, nil
+}
+
 func (r *lostDocumentRepository) Update(tx *gorm.DB, doc *models.LostDocument) (*models.LostDocument, error) {
 	db := r.db
 	if tx != nil {
@@ -156,25 +477,47 @@ func (r *lostDocumentRepository) Delete(tx *gorm.DB, id uint) error {
 	return db.Delete(&models.LostDocument{}, id).Error
 }
 
-func (r *lostDocumentRepository) GetLastDocumentOfYear(year int) (*models.LostDocument, error) {
-	var doc models.LostDocument
-	startOfYear := time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC)
-	endOfYear := startOfYear.AddDate(1, 0, 0).Add(-time.Nanosecond)
-	err := r.db.Unscoped().Where("created_at BETWEEN ? AND ?", startOfYear, endOfYear).Where("nomor_surat NOT LIKE ?", "DELETED_%").Order("id desc").First(&doc).Error
-	if err != nil {
-		return nil, err
+func (r *lostDocumentRepository) ArchiveIssuedBefore(cutoff time.Time) (int64, error) {
+	result := r.db.Model(&models.LostDocument{}).
+		Where("status = ? AND tanggal_laporan <= ?", models.StatusDiterbitkan, cutoff).
+		Update("status", models.StatusDiarsipkan)
+	return result.RowsAffected, result.Error
+}
+
+func (r *lostDocumentRepository) FindAwaitingApproval(pejabatID uint) ([]models.LostDocument, error) {
+	var docs []models.LostDocument
+	db := r.db.
+		Preload("Resident").
+		Preload("LostItems").
+		Preload("PetugasPelapor").
+		Preload("PejabatPersetuju").
+		Preload("Operator", unscopedUser).
+		Where("status = ? AND pejabat_persetuju_id = ?", models.StatusMenungguPersetujuan, pejabatID).
+		Order("updated_at asc")
+	err := db.Find(&docs).Error
+	return docs, err
+}
+
+func (r *lostDocumentRepository) TransitionStatus(tx *gorm.DB, id uint, fromStatuses []string, changes map[string]interface{}) (bool, error) {
+	db := r.db
+	if tx != nil {
+		db = tx
 	}
-	return &doc, nil
+	result := db.Model(&models.LostDocument{}).Where("id = ? AND status IN ?", id, fromStatuses).Updates(changes)
+	return result.RowsAffected > 0, result.Error
 }
 
 func (r *lostDocumentRepository) GetMonthlyIssuanceForYear(year int) ([]MonthlyCount, error) {
 	var results []MonthlyCount
-	err := r.db.Model(&models.LostDocument{}).Select("CAST(strftime('%Y', tanggal_laporan) AS INTEGER) as year, CAST(strftime('%m', tanggal_laporan) AS INTEGER) as month, COUNT(id) as count").Where("CAST(strftime('%Y', tanggal_laporan) AS INTEGER) = ?", year).Group("year, month").Order("month asc").Scan(&results).Error
+	err := r.db.Model(&models.LostDocument{}).Select("CAST(strftime('%Y', tanggal_laporan) AS INTEGER) as year, CAST(strftime('%m', tanggal_laporan) AS INTEGER) as month, COUNT(id) as count").Where("CAST(strftime('%Y', tanggal_laporan) AS INTEGER) = ?", year).Where("status IN ?", issuedStatuses).Group("year, month").Order("month asc").Scan(&results).Error
 	return results, err
 }
 
 func (r *lostDocumentRepository) GetItemCompositionStats() ([]ItemCompositionStat, error) {
 	var results []ItemCompositionStat
-	err := r.db.Model(&models.LostItem{}).Select("nama_barang, COUNT(id) as count").Group("nama_barang").Order("count desc").Scan(&results).Error
+	err := r.db.Model(&models.LostItem{}).Select(itemLabelSQL+" as nama_barang, COUNT(lost_items.id) as count").
+		Joins("JOIN lost_documents ON lost_documents.id = lost_items.lost_document_id AND lost_documents.deleted_at IS NULL").
+		Where("lost_documents.status IN ?", issuedStatuses).
+		Group(itemLabelSQL).Order("count desc").Scan(&results).Error
 	return results, err
 }
\ No newline at end of file
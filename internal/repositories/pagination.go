@@ -0,0 +1,73 @@
+package repositories
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	DefaultPageSize = 25
+	MaxPageSize     = 500
+)
+
+// ListQuery adalah kontrak bersama untuk endpoint daftar yang dipaginasi di server: halaman, ukuran halaman,
+// pengurutan, dan filter. Setiap daftar hanya memakai filter yang relevan dan mengabaikan sisanya;
+// Sort yang tidak dikenal daftar tersebut berarti urutan bawaan.
+type ListQuery struct {
+	Page       int // dimulai dari 1
+	Size       int
+	Sort       string
+	Desc       bool
+	Search     string
+	From       *time.Time // inklusif
+	To         *time.Time // eksklusif
+	OperatorID uint
+	ItemType   string
+}
+
+// PageResult memuat jumlah baris untuk satu halaman daftar. Total adalah jumlah tanpa filter
+// pencarian, Filtered adalah jumlah setelah semua filter diterapkan.
+type PageResult struct {
+	Total    int64
+	Filtered int64
+}
+
+// Limit mengembalikan ukuran halaman yang sudah dibatasi ke rentang yang diizinkan.
+func (q ListQuery) Limit() int {
+	if q.Size <= 0 {
+		return DefaultPageSize
+	}
+	if q.Size > MaxPageSize {
+		return MaxPageSize
+	}
+	return q.Size
+}
+
+// Offset mengembalikan jumlah baris yang dilewati sebelum halaman yang diminta.
+func (q ListQuery) Offset() int {
+	if q.Page <= 1 {
+		return 0
+	}
+	return (q.Page - 1) * q.Limit()
+}
+
+// hasFilters bernilai true bila ada filter selain batasan dasar daftar, sehingga jumlah
+// setelah filter perlu dihitung terpisah dari jumlah total.
+func (q ListQuery) hasFilters() bool {
+	return q.Search != "" || q.From != nil || q.To != nil || q.OperatorID != 0 || q.ItemType != ""
+}
+
+// paginate menerapkan urutan dan batas halaman. sortColumns memetakan kunci Sort ke ekspresi kolom
+// yang aman dipakai di ORDER BY; defaultOrder selalu ditambahkan di belakang agar urutan antarhalaman stabil.
+func paginate(db *gorm.DB, q ListQuery, sortColumns map[string]string, defaultOrder string) *gorm.DB {
+	order := defaultOrder
+	if column, ok := sortColumns[q.Sort]; ok {
+		direction := " asc"
+		if q.Desc {
+			direction = " desc"
+		}
+		order = column + direction + ", " + defaultOrder
+	}
+	return db.Order(order).Limit(q.Limit()).Offset(q.Offset())
+}
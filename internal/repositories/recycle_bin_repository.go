@@ -0,0 +1,140 @@
+package repositories
+
+import (
+	"simdokpol/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// RecycleBinRepository mengelola dokumen yang sudah dihapus (soft delete): daftar tong sampah,
+// pemulihan, dan pemusnahan permanen.
+type RecycleBinRepository interface {
+	// FindDeletedDocuments mengambil semua dokumen terhapus, yang terakhir dihapus lebih dulu.
+	FindDeletedDocuments() ([]models.LostDocument, error)
+	// FindDeletedDocument mengambil satu dokumen terhapus; dokumen yang belum dihapus dianggap tidak ditemukan.
+	FindDeletedDocument(id uint) (*models.LostDocument, error)
+	// FindDeletedBefore mengambil dokumen yang dihapus sebelum cutoff, tanpa preload.
+	FindDeletedBefore(cutoff time.Time) ([]models.LostDocument, error)
+	// IsNomorSuratTaken memeriksa apakah nomor surat dipakai dokumen selain excludeID, termasuk dokumen terhapus.
+	IsNomorSuratTaken(nomorSurat string, excludeID uint) (bool, error)
+	// Restore mengembalikan dokumen terhapus ke daftar aktif sambil menerapkan changes. Bila nomor_surat
+	// pada changes sudah dipakai dokumen lain, clashChanges ikut diterapkan dan nomorBentrok bernilai true.
+	// Pemeriksaan nomor dan pemulihan berjalan dalam satu transaksi.
+	Restore(id uint, changes map[string]interface{}, clashChanges map[string]interface{}) (nomorBentrok bool, err error)
+	// Purge menghapus permanen dokumen terhapus beserta barang dan riwayat revisinya.
+	Purge(id uint) error
+}
+
+type recycleBinRepository struct {
+	db *gorm.DB
+}
+
+// NewRecycleBinRepository adalah factory untuk RecycleBinRepository.
+func NewRecycleBinRepository(db *gorm.DB) RecycleBinRepository {
+	return &recycleBinRepository{db: db}
+}
+
+// unscopedUser memuat relasi pengguna termasuk akun yang sudah dinonaktifkan.
+func unscopedUser(db *gorm.DB) *gorm.DB {
+	return db.Unscoped()
+}
+
+func (r *recycleBinRepository) FindDeletedDocuments() ([]models.LostDocument, error) {
+	var docs []models.LostDocument
+	err := r.db.Unscoped().Preload("Resident").Preload("DihapusOleh", unscopedUser).
+		Where("lost_documents.deleted_at IS NOT NULL").
+		Order("lost_documents.deleted_at desc").
+		Find(&docs).Error
+	return docs, err
+}
+
+func (r *recycleBinRepository) FindDeletedDocument(id uint) (*models.LostDocument, error) {
+	var doc models.LostDocument
+	err := r.db.Unscoped().Preload("Resident").Preload("DihapusOleh", unscopedUser).
+		Where("lost_documents.deleted_at IS NOT NULL").
+		First(&doc, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &doc, nil
+}
+
+func (r *recycleBinRepository) FindDeletedBefore(cutoff time.Time) ([]models.LostDocument, error) {
+	var docs []models.LostDocument
+	err := r.db.Unscoped().
+		Where("deleted_at IS NOT NULL AND deleted_at < ?", cutoff).
+		Order("deleted_at asc").
+		Find(&docs).Error
+	return docs, err
+}
+
+func (r *recycleBinRepository) IsNomorSuratTaken(nomorSurat string, excludeID uint) (bool, error) {
+	var count int64
+	err := r.db.Unscoped().Model(&models.LostDocument{}).
+		Where("nomor_surat = ? AND id <> ?", nomorSurat, excludeID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func (r *recycleBinRepository) Restore(id uint, changes map[string]interface{}, clashChanges map[string]interface{}) (bool, error) {
+	var nomorBentrok bool
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		updates := map[string]interface{}{"deleted_at": nil}
+		for column, value := range changes {
+			updates[column] = value
+		}
+		// Nomor diperiksa di transaksi yang sama agar surat lain tidak sempat memakai nomor yang sama
+		// di antara pemeriksaan dan pemulihan.
+		if nomorSurat, ok := changes["nomor_surat"].(string); ok && nomorSurat != "" {
+			var count int64
+			err := tx.Unscoped().Model(&models.LostDocument{}).
+				Where("nomor_surat = ? AND id <> ?", nomorSurat, id).
+				Count(&count).Error
+			if err != nil {
+				return err
+			}
+			if count > 0 {
+				nomorBentrok = true
+				for column, value := range clashChanges {
+					updates[column] = value
+				}
+			}
+		}
+		result := tx.Unscoped().Model(&models.LostDocument{}).
+			Where("id = ? AND deleted_at IS NOT NULL", id).
+			Updates(updates)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+	return nomorBentrok, nil
+}
+
+func (r *recycleBinRepository) Purge(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		// Barang dan revisi dihapus lebih dulu karena merujuk ke dokumen lewat foreign key;
+		// jika dokumen ternyata tidak ada di tong sampah, transaksi dibatalkan.
+		if err := tx.Where("lost_document_id = ?", id).Delete(&models.LostItem{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("lost_document_id = ?", id).Delete(&models.DocumentRevision{}).Error; err != nil {
+			return err
+		}
+		result := tx.Unscoped().Where("id = ? AND deleted_at IS NOT NULL", id).Delete(&models.LostDocument{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+}
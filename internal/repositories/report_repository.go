@@ -0,0 +1,175 @@
+package repositories
+
+import (
+	"simdokpol/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// EditedAfterIssue adalah revisi yang dibuat setelah dokumennya terbit.
+type EditedAfterIssue struct {
+	LostDocumentID uint      `gorm:"column:lost_document_id"`
+	NomorSurat     string    `gorm:"column:nomor_surat"`
+	RevisionNumber int       `gorm:"column:revision_number"`
+	ChangedBy      string    `gorm:"column:changed_by"`
+	CreatedAt      time.Time `gorm:"column:created_at"`
+}
+
+// GroupCount adalah jumlah dokumen terbit per kelompok (jenis barang, operator, dst.).
+type GroupCount struct {
+	Label string `gorm:"column:label"`
+	Count int    `gorm:"column:count"`
+}
+
+// IssuedSummary adalah kolom dokumen terbit yang dikelompokkan di sisi aplikasi,
+// karena pengelompokan per hari dan bulan harus mengikuti zona waktu kantor.
+type IssuedSummary struct {
+	TanggalLaporan time.Time `gorm:"column:tanggal_laporan"`
+	LokasiHilang   string    `gorm:"column:lokasi_hilang"`
+}
+
+// ReportRepository menyediakan query lintas tabel untuk laporan. Regu selalu merujuk ke regu
+// operator pembuat dokumen atau regu pengguna pelaku aksi.
+type ReportRepository interface {
+	// FindIssuedByRegu mengambil dokumen yang disetujui dalam rentang waktu, termasuk yang kemudian diarsipkan atau dicabut.
+	FindIssuedByRegu(regu string, from time.Time, to time.Time) ([]models.LostDocument, error)
+	// FindPendingByRegu mengambil draf dan dokumen yang menunggu persetujuan saat ini.
+	FindPendingByRegu(regu string) ([]models.LostDocument, error)
+	// FindRevokedByRegu mengambil dokumen yang dicabut dalam rentang waktu.
+	FindRevokedByRegu(regu string, from time.Time, to time.Time) ([]models.LostDocument, error)
+	// FindNumberedDeletedByRegu mengambil dokumen bernomor yang dihapus dalam rentang waktu.
+	FindNumberedDeletedByRegu(regu string, from time.Time, to time.Time) ([]models.LostDocument, error)
+	// FindAuditLogsByRegu mengambil log audit dengan aksi tertentu yang dilakukan anggota regu.
+	FindAuditLogsByRegu(regu string, from time.Time, to time.Time, aksi []string) ([]models.AuditLog, error)
+	// FindEditedAfterIssue mengambil perubahan isi oleh anggota regu yang dibuat setelah dokumennya disetujui.
+	FindEditedAfterIssue(regu string, from time.Time, to time.Time) ([]EditedAfterIssue, error)
+
+	// Query laporan statistik. Semuanya menghitung dokumen yang pernah terbit dengan
+	// tanggal laporan dalam rentang start (inklusif) sampai end (eksklusif).
+	FindIssuedSummaries(start time.Time, end time.Time) ([]IssuedSummary, error)
+	CountIssuedByItem(start time.Time, end time.Time) ([]GroupCount, error)
+	CountIssuedByOperator(start time.Time, end time.Time) ([]GroupCount, error)
+	CountIssuedByPetugasPelapor(start time.Time, end time.Time) ([]GroupCount, error)
+}
+
+type reportRepository struct {
+	db *gorm.DB
+}
+
+func NewReportRepository(db *gorm.DB) ReportRepository {
+	return &reportRepository{db: db}
+}
+
+// reguOperators adalah subquery id pengguna anggota regu, termasuk anggota yang sudah dinonaktifkan.
+const reguOperators = "lost_documents.operator_id IN (SELECT id FROM users WHERE regu = ?)"
+
+func (r *reportRepository) FindIssuedByRegu(regu string, from time.Time, to time.Time) ([]models.LostDocument, error) {
+	var docs []models.LostDocument
+	err := r.db.Preload("Resident").Preload("Operator", unscopedUser).
+		Where(reguOperators, regu).
+		Where("lost_documents.status IN ?", issuedStatuses).
+		Where("lost_documents.tanggal_persetujuan BETWEEN ? AND ?", from, to).
+		Order("lost_documents.tanggal_persetujuan asc").
+		Find(&docs).Error
+	return docs, err
+}
+
+func (r *reportRepository) FindPendingByRegu(regu string) ([]models.LostDocument, error) {
+	var docs []models.LostDocument
+	err := r.db.Preload("Resident").Preload("Operator", unscopedUser).Preload("PejabatPersetuju").
+		Where(reguOperators, regu).
+		Where("lost_documents.status IN ?", []string{models.StatusDraf, models.StatusMenungguPersetujuan}).
+		Order("lost_documents.updated_at asc").
+		Find(&docs).Error
+	return docs, err
+}
+
+func (r *reportRepository) FindRevokedByRegu(regu string, from time.Time, to time.Time) ([]models.LostDocument, error) {
+	var docs []models.LostDocument
+	err := r.db.Preload("Resident").Preload("DicabutOleh").
+		Where(reguOperators, regu).
+		Where("lost_documents.status = ?", models.StatusDicabut).
+		Where("lost_documents.tanggal_pencabutan BETWEEN ? AND ?", from, to).
+		Order("lost_documents.tanggal_pencabutan asc").
+		Find(&docs).Error
+	return docs, err
+}
+
+func (r *reportRepository) FindNumberedDeletedByRegu(regu string, from time.Time, to time.Time) ([]models.LostDocument, error) {
+	var docs []models.LostDocument
+	// Nomor surat dokumen terhapus diberi awalan DELETED_ oleh DeleteLostDocument.
+	err := r.db.Unscoped().Preload("Resident").
+		Where(reguOperators, regu).
+		Where("lost_documents.deleted_at BETWEEN ? AND ?", from, to).
+		Where("lost_documents.nomor_surat LIKE ?", "DELETED_%").
+		Order("lost_documents.deleted_at asc").
+		Find(&docs).Error
+	return docs, err
+}
+
+func (r *reportRepository) FindAuditLogsByRegu(regu string, from time.Time, to time.Time, aksi []string) ([]models.AuditLog, error) {
+	var logs []models.AuditLog
+	err := r.db.Preload("User").
+		Joins("JOIN users ON users.id = audit_logs.user_id").
+		Where("users.regu = ?", regu).
+		Where("audit_logs.aksi IN ?", aksi).
+		Where("audit_logs.timestamp BETWEEN ? AND ?", from, to).
+		Order("audit_logs.timestamp asc").
+		Find(&logs).Error
+	return logs, err
+}
+
+func (r *reportRepository) FindEditedAfterIssue(regu string, from time.Time, to time.Time) ([]EditedAfterIssue, error) {
+	var results []EditedAfterIssue
+	err := r.db.Model(&models.DocumentRevision{}).
+		Select("document_revisions.lost_document_id, lost_documents.nomor_surat, document_revisions.revision_number, users.nama_lengkap as changed_by, document_revisions.created_at").
+		Joins("JOIN lost_documents ON lost_documents.id = document_revisions.lost_document_id AND lost_documents.deleted_at IS NULL").
+		Joins("JOIN users ON users.id = document_revisions.changed_by_id").
+		Where("users.regu = ?", regu).
+		Where("document_revisions.aksi IN ?", []string{models.RevisionUpdated, models.RevisionRestored}).
+		Where("document_revisions.created_at BETWEEN ? AND ?", from, to).
+		Where("lost_documents.tanggal_persetujuan IS NOT NULL AND document_revisions.created_at > lost_documents.tanggal_persetujuan").
+		Order("document_revisions.created_at asc").
+		Scan(&results).Error
+	return results, err
+}
+
+func (r *reportRepository) issuedBetween(start time.Time, end time.Time) *gorm.DB {
+	return r.db.Model(&models.LostDocument{}).
+		Where("lost_documents.status IN ?", issuedStatuses).
+		Where("lost_documents.tanggal_laporan >= ? AND lost_documents.tanggal_laporan < ?", start, end)
+}
+
+func (r *reportRepository) FindIssuedSummaries(start time.Time, end time.Time) ([]IssuedSummary, error) {
+	var results []IssuedSummary
+	err := r.issuedBetween(start, end).Select("lost_documents.tanggal_laporan, lost_documents.lokasi_hilang").Scan(&results).Error
+	return results, err
+}
+
+func (r *reportRepository) CountIssuedByItem(start time.Time, end time.Time) ([]GroupCount, error) {
+	var results []GroupCount
+	err := r.issuedBetween(start, end).
+		Select(itemLabelSQL+" as label, COUNT(lost_items.id) as count").
+		Joins("JOIN lost_items ON lost_items.lost_document_id = lost_documents.id").
+		Group(itemLabelSQL).Order("count desc, label asc").Scan(&results).Error
+	return results, err
+}
+
+func (r *reportRepository) CountIssuedByOperator(start time.Time, end time.Time) ([]GroupCount, error) {
+	return r.countIssuedByUser(start, end, "lost_documents.operator_id")
+}
+
+func (r *reportRepository) CountIssuedByPetugasPelapor(start time.Time, end time.Time) ([]GroupCount, error) {
+	return r.countIssuedByUser(start, end, "lost_documents.petugas_pelapor_id")
+}
+
+// countIssuedByUser mengelompokkan dokumen terbit menurut kolom ID pengguna, termasuk pengguna yang sudah dinonaktifkan.
+func (r *reportRepository) countIssuedByUser(start time.Time, end time.Time, column string) ([]GroupCount, error) {
+	var results []GroupCount
+	err := r.issuedBetween(start, end).
+		Select("users.nama_lengkap as label, COUNT(lost_documents.id) as count").
+		Joins("JOIN users ON users.id = " + column).
+		Group("users.id, users.nama_lengkap").Order("count desc, label asc").Scan(&results).Error
+	return results, err
+}
@@ -2,16 +2,41 @@ package repositories
 
 import (
 	"simdokpol/internal/models"
+	"strings"
+	"time"
 
 	"gorm.io/gorm"
 )
 
+// DuplicateResidentGroup adalah sekumpulan penduduk dengan nama dan tanggal lahir yang sama,
+// kandidat untuk digabungkan oleh Super Admin.
+type DuplicateResidentGroup struct {
+	NamaLengkap  string            `json:"nama_lengkap"`
+	TanggalLahir time.Time         `json:"tanggal_lahir"`
+	Residents    []models.Resident `json:"residents"`
+}
+
 // ResidentRepository mendefinisikan kontrak untuk operasi data penduduk.
 type ResidentRepository interface {
 	// FindByNIK mencari penduduk berdasarkan NIK. Menggunakan transaksi jika disediakan.
 	FindByNIK(tx *gorm.DB, nik string) (*models.Resident, error)
+	FindByID(id uint) (*models.Resident, error)
+	FindByIDs(ids []uint) ([]models.Resident, error)
+	// FindByNameAndBirthDate mencari penduduk berdasarkan nama lengkap dan tanggal lahir.
+	// Menggunakan transaksi jika disediakan.
+	FindByNameAndBirthDate(tx *gorm.DB, namaLengkap string, tanggalLahir time.Time) (*models.Resident, error)
+	// Search mencari penduduk berdasarkan awalan NIK atau potongan nama, untuk autocomplete.
+	Search(query string, limit int) ([]models.Resident, error)
+	// FindDuplicateGroups mengelompokkan penduduk dengan nama (tanpa membedakan huruf besar/kecil)
+	// dan tanggal lahir yang sama.
+	FindDuplicateGroups() ([]DuplicateResidentGroup, error)
 	// Create menyimpan data penduduk baru. Menggunakan transaksi jika disediakan.
 	Create(tx *gorm.DB, resident *models.Resident) (*models.Resident, error)
+	// Update menyimpan perubahan data penduduk. Menggunakan transaksi jika disediakan.
+	Update(tx *gorm.DB, resident *models.Resident) (*models.Resident, error)
+	// Merge memindahkan semua dokumen (termasuk yang dihapus) dari duplicateIDs ke primaryID,
+	// lalu menghapus (soft delete) data duplikat beserta NIK-nya.
+	Merge(tx *gorm.DB, primaryID uint, duplicateIDs []uint) (int64, error)
 }
 
 type residentRepository struct {
@@ -35,6 +60,73 @@ func (r *residentRepository) FindByNIK(tx *gorm.DB, nik string) (*models.Residen
 	return &resident, nil
 }
 
+func (r *residentRepository) FindByID(id uint) (*models.Resident, error) {
+	var resident models.Resident
+	if err := r.db.First(&resident, id).Error; err != nil {
+		return nil, err
+	}
+	return &resident, nil
+}
+
+func (r *residentRepository) FindByIDs(ids []uint) ([]models.Resident, error) {
+	var residents []models.Resident
+	err := r.db.Where("id IN ?", ids).Order("id asc").Find(&residents).Error
+	return residents, err
+}
+
+func (r *residentRepository) FindByNameAndBirthDate(tx *gorm.DB, namaLengkap string, tanggalLahir time.Time) (*models.Resident, error) {
+	db := r.db
+	if tx != nil {
+		db = tx
+	}
+	var resident models.Resident
+	if err := db.Where("nama_lengkap = ? AND tanggal_lahir = ?", namaLengkap, tanggalLahir).First(&resident).Error; err != nil {
+		return nil, err
+	}
+	return &resident, nil
+}
+
+func (r *residentRepository) Search(query string, limit int) ([]models.Resident, error) {
+	var residents []models.Resident
+	query = strings.TrimSpace(query)
+	db := r.db.Order("nama_lengkap asc").Limit(limit)
+	if query != "" {
+		db = db.Where("nik LIKE ? OR nama_lengkap LIKE ?", query+"%", "%"+query+"%")
+	}
+	err := db.Find(&residents).Error
+	return residents, err
+}
+
+func (r *residentRepository) FindDuplicateGroups() ([]DuplicateResidentGroup, error) {
+	const groupKey = "lower(trim(nama_lengkap)) || '|' || tanggal_lahir"
+	duplicateKeys := r.db.Model(&models.Resident{}).Select(groupKey).Group(groupKey).Having("COUNT(id) > 1")
+
+	var residents []models.Resident
+	err := r.db.Where(groupKey+" IN (?)", duplicateKeys).
+		Order("lower(trim(nama_lengkap)) asc, tanggal_lahir asc, id asc").
+		Find(&residents).Error
+	if err != nil {
+		return nil, err
+	}
+
+	// Hasil sudah terurut per kelompok, jadi cukup memotong setiap kali kuncinya berubah.
+	var groups []DuplicateResidentGroup
+	for _, resident := range residents {
+		last := len(groups) - 1
+		if last < 0 ||
+			!strings.EqualFold(strings.TrimSpace(groups[last].NamaLengkap), strings.TrimSpace(resident.NamaLengkap)) ||
+			!groups[last].TanggalLahir.Equal(resident.TanggalLahir) {
+			groups = append(groups, DuplicateResidentGroup{
+				NamaLengkap:  resident.NamaLengkap,
+				TanggalLahir: resident.TanggalLahir,
+			})
+			last++
+		}
+		groups[last].Residents = append(groups[last].Residents, resident)
+	}
+	return groups, nil
+}
+
 func (r *residentRepository) Create(tx *gorm.DB, resident *models.Resident) (*models.Resident, error) {
 	db := r.db
 	if tx != nil {
@@ -44,4 +136,36 @@ func (r *residentRepository) Create(tx *gorm.DB, resident *models.Resident) (*mo
 		return nil, err
 	}
 	return resident, nil
-}
\ No newline at end of file
+}
+
+func (r *residentRepository) Update(tx *gorm.DB, resident *models.Resident) (*models.Resident, error) {
+	db := r.db
+	if tx != nil {
+		db = tx
+	}
+	if err := db.Save(resident).Error; err != nil {
+		return nil, err
+	}
+	return resident, nil
+}
+
+func (r *residentRepository) Merge(tx *gorm.DB, primaryID uint, duplicateIDs []uint) (int64, error) {
+	db := r.db
+	if tx != nil {
+		db = tx
+	}
+	result := db.Unscoped().Model(&models.LostDocument{}).
+		Where("resident_id IN ?", duplicateIDs).
+		Update("resident_id", primaryID)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	// NIK dikosongkan lebih dulu agar indeks UNIQUE tidak menahan NIK yang dipindahkan ke data utama.
+	if err := db.Model(&models.Resident{}).Where("id IN ?", duplicateIDs).Update("nik", nil).Error; err != nil {
+		return 0, err
+	}
+	if err := db.Delete(&models.Resident{}, duplicateIDs).Error; err != nil {
+		return 0, err
+	}
+	return result.RowsAffected, nil
+}
@@ -0,0 +1,66 @@
+package repositories
+
+import (
+	"simdokpol/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// RoleRepository mendefinisikan kontrak untuk data peran dan izinnya.
+type RoleRepository interface {
+	FindAll() ([]models.Role, error)
+	FindByCode(kode string) (*models.Role, error)
+	Create(role *models.Role) (*models.Role, error)
+	Update(role *models.Role) (*models.Role, error)
+	Delete(kode string) error
+	// CountUsers menghitung pengguna (termasuk yang nonaktif) yang memakai peran tersebut.
+	CountUsers(kode string) (int64, error)
+}
+
+type roleRepository struct {
+	db *gorm.DB
+}
+
+// NewRoleRepository adalah factory untuk RoleRepository.
+func NewRoleRepository(db *gorm.DB) RoleRepository {
+	return &roleRepository{db: db}
+}
+
+func (r *roleRepository) FindAll() ([]models.Role, error) {
+	var roles []models.Role
+	err := r.db.Order("bawaan desc, created_at asc, kode asc").Find(&roles).Error
+	return roles, err
+}
+
+func (r *roleRepository) FindByCode(kode string) (*models.Role, error) {
+	var role models.Role
+	if err := r.db.Where("kode = ?", kode).First(&role).Error; err != nil {
+		return nil, err
+	}
+	return &role, nil
+}
+
+func (r *roleRepository) Create(role *models.Role) (*models.Role, error) {
+	if err := r.db.Create(role).Error; err != nil {
+		return nil, err
+	}
+	return role, nil
+}
+
+func (r *roleRepository) Update(role *models.Role) (*models.Role, error) {
+	// Select("*") agar daftar izin yang dikosongkan ikut tersimpan.
+	if err := r.db.Model(role).Select("*").Omit("created_at").Updates(role).Error; err != nil {
+		return nil, err
+	}
+	return role, nil
+}
+
+func (r *roleRepository) Delete(kode string) error {
+	return r.db.Where("kode = ?", kode).Delete(&models.Role{}).Error
+}
+
+func (r *roleRepository) CountUsers(kode string) (int64, error) {
+	var count int64
+	err := r.db.Unscoped().Model(&models.User{}).Where("peran = ?", kode).Count(&count).Error
+	return count, err
+}
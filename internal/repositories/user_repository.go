@@ -8,6 +8,8 @@ import (
 type UserRepository interface {
 	Create(user *models.User) error
 	FindAll(statusFilter string) ([]models.User, error)
+	// FindPage mengambil satu halaman pengguna aktif atau non-aktif; Search mencocokkan nama, NRP, pangkat, dan jabatan.
+	FindPage(statusFilter string, list ListQuery) ([]models.User, PageResult, error)
 	FindByID(id uint) (*models.User, error)
 	FindByNRP(nrp string) (*models.User, error)
 	FindOperators() ([]models.User, error)
@@ -31,7 +33,7 @@ func (r *userRepository) Create(user *models.User) error {
 
 func (r *userRepository) FindAll(statusFilter string) ([]models.User, error) {
 	var users []models.User
-	db := r.db.Order("nama_lengkap asc")
+	db := r.db.Preload("Role").Order("nama_lengkap asc")
 	if statusFilter == "inactive" {
 		db = db.Unscoped().Where("deleted_at IS NOT NULL")
 	}
@@ -39,9 +41,51 @@ func (r *userRepository) FindAll(statusFilter string) ([]models.User, error) {
 	return users, err
 }
 
+// userSortColumns adalah kolom daftar pengguna yang boleh dipakai untuk mengurutkan.
+var userSortColumns = map[string]string{
+	"nama_lengkap": "users.nama_lengkap",
+	"nrp":          "users.nrp",
+	"pangkat":      "users.pangkat",
+	"jabatan":      "users.jabatan",
+	"regu":         "users.regu",
+	"peran":        "users.peran",
+}
+
+func (r *userRepository) FindPage(statusFilter string, list ListQuery) ([]models.User, PageResult, error) {
+	var result PageResult
+	scoped := func() *gorm.DB {
+		db := r.db.Model(&models.User{})
+		if statusFilter == "inactive" {
+			db = db.Unscoped().Where("users.deleted_at IS NOT NULL")
+		}
+		return db
+	}
+	filtered := func(db *gorm.DB) *gorm.DB {
+		if list.Search != "" {
+			searchQuery := "%" + list.Search + "%"
+			db = db.Where("(users.nama_lengkap LIKE ? OR users.nrp LIKE ? OR users.pangkat LIKE ? OR users.jabatan LIKE ?)", searchQuery, searchQuery, searchQuery, searchQuery)
+		}
+		return db
+	}
+
+	if err := scoped().Count(&result.Total).Error; err != nil {
+		return nil, result, err
+	}
+	result.Filtered = result.Total
+	if list.Search != "" {
+		if err := filtered(scoped()).Count(&result.Filtered).Error; err != nil {
+			return nil, result, err
+		}
+	}
+
+	var users []models.User
+	err := paginate(filtered(scoped()).Preload("Role"), list, userSortColumns, "users.nama_lengkap asc, users.id asc").Find(&users).Error
+	return users, result, err
+}
+
 func (r *userRepository) FindByID(id uint) (*models.User, error) {
 	var user models.User
-	if err := r.db.Unscoped().First(&user, id).Error; err != nil {
+	if err := r.db.Unscoped().Preload("Role").First(&user, id).Error; err != nil {
 		return nil, err
 	}
 	return &user, nil
@@ -58,12 +102,14 @@ func (r *userRepository) FindByNRP(nrp string) (*models.User, error) {
 
 func (r *userRepository) FindOperators() ([]models.User, error) {
 	var users []models.User
-	err := r.db.Where("peran = ?", "OPERATOR").Order("nama_lengkap asc").Find(&users).Error
+	// Semua personel selain Super Admin dapat dipilih sebagai petugas atau pejabat di surat.
+	err := r.db.Where("peran <> ?", models.RoleSuperAdmin).Order("nama_lengkap asc").Find(&users).Error
 	return users, err
 }
 
 func (r *userRepository) Update(user *models.User) error {
-	return r.db.Save(user).Error
+	// Peran yang ikut dimuat tidak boleh ikut tersimpan; hanya kode peran di kolom peran.
+	return r.db.Omit("Role").Save(user).Error
 }
 
 func (r *userRepository) Delete(id uint) error {
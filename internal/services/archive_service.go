@@ -0,0 +1,132 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"simdokpol/internal/dto"
+	"simdokpol/internal/models"
+	"simdokpol/internal/repositories"
+	"sync"
+	"time"
+)
+
+// DefaultArchiveInterval adalah jeda antar eksekusi pengarsip otomatis.
+const DefaultArchiveInterval = time.Hour
+
+// ArchiveService memindahkan dokumen DITERBITKAN yang melewati masa berlaku ke status DIARSIPKAN,
+// sehingga kolom status menjadi satu-satunya acuan daftar aktif/arsip.
+type ArchiveService interface {
+	// Start menjalankan pengarsip sekali saat dipanggil lalu berulang setiap interval
+	// sampai ctx dibatalkan. Dipanggil sebagai goroutine.
+	Start(ctx context.Context, interval time.Duration)
+	// RunNow menjalankan pengarsipan segera atas nama actorID.
+	RunNow(actorID uint) (*dto.ArchiveRunStatus, error)
+	Status() *dto.ArchiveRunStatus
+}
+
+type archiveService struct {
+	docRepo       repositories.LostDocumentRepository
+	configService ConfigService
+	auditService  AuditLogService
+
+	runMu    sync.Mutex // Mencegah dua pengarsipan berjalan bersamaan
+	statusMu sync.RWMutex
+	status   dto.ArchiveRunStatus
+}
+
+func NewArchiveService(docRepo repositories.LostDocumentRepository, configService ConfigService, auditService AuditLogService) ArchiveService {
+	return &archiveService{
+		docRepo:       docRepo,
+		configService: configService,
+		auditService:  auditService,
+	}
+}
+
+func (s *archiveService) Start(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultArchiveInterval
+	}
+	s.statusMu.Lock()
+	s.status.IntervalMinutes = int(interval / time.Minute)
+	s.statusMu.Unlock()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.RunNow(models.SystemUserID); err != nil {
+			log.Printf("PERINGATAN: Pengarsipan otomatis gagal: %v", err)
+		}
+		next := time.Now().Add(interval)
+		s.statusMu.Lock()
+		s.status.NextRunAt = &next
+		s.statusMu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *archiveService) RunNow(actorID uint) (*dto.ArchiveRunStatus, error) {
+	s.runMu.Lock()
+	defer s.runMu.Unlock()
+
+	s.setRunning(true)
+	count, cutoff, err := s.archive()
+	now := time.Now()
+
+	s.statusMu.Lock()
+	s.status.Running = false
+	s.status.LastRunAt = &now
+	s.status.LastArchivedCount = count
+	s.status.LastError = ""
+	if err != nil {
+		s.status.LastError = err.Error()
+	} else {
+		s.status.LastCutoff = &cutoff
+	}
+	s.statusMu.Unlock()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if count > 0 {
+		s.auditService.LogActivity(actorID, models.AuditArchiveDocuments,
+			fmt.Sprintf("Mengarsipkan %d dokumen yang dilaporkan sebelum %s", count, cutoff.Format("02-01-2006 15:04")))
+	}
+	return s.Status(), nil
+}
+
+func (s *archiveService) Status() *dto.ArchiveRunStatus {
+	s.statusMu.RLock()
+	defer s.statusMu.RUnlock()
+	status := s.status
+	return &status
+}
+
+func (s *archiveService) setRunning(running bool) {
+	s.statusMu.Lock()
+	s.status.Running = running
+	s.statusMu.Unlock()
+}
+
+// archive menghitung batas tanggal dari durasi arsip di Pengaturan lalu memperbarui status dokumen.
+func (s *archiveService) archive() (int64, time.Time, error) {
+	appConfig, err := s.configService.GetConfig()
+	if err != nil {
+		return 0, time.Time{}, fmt.Errorf("gagal memuat konfigurasi: %w", err)
+	}
+	loc, _ := s.configService.GetLocation()
+	cutoff := time.Now().In(loc).Add(-time.Duration(appConfig.ArchiveDurationDays) * 24 * time.Hour)
+
+	count, err := s.docRepo.ArchiveIssuedBefore(cutoff)
+	if err != nil {
+		return 0, cutoff, fmt.Errorf("gagal mengarsipkan dokumen: %w", err)
+	}
+	return count, cutoff, nil
+}
@@ -0,0 +1,68 @@
+package services
+
+import (
+	"errors"
+	"simdokpol/internal/dto"
+	"simdokpol/internal/mocks"
+	"simdokpol/internal/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestArchiveService_RunNow(t *testing.T) {
+	actorID := uint(1)
+
+	testCases := []struct {
+		name          string
+		archived      int64
+		repoErr       error
+		expectAudit   bool
+		expectedError bool
+	}{
+		{name: "Sukses - Dokumen Diarsipkan dan Dicatat", archived: 3, expectAudit: true},
+		{name: "Sukses - Tidak Ada Dokumen, Tanpa Audit", archived: 0},
+		{name: "Gagal - Error Database", repoErr: errors.New("db error"), expectedError: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			docRepo := new(mocks.LostDocumentRepository)
+			configService := new(mocks.ConfigService)
+			auditService := new(mocks.AuditLogService)
+
+			configService.On("GetConfig").Return(&dto.AppConfig{ArchiveDurationDays: 15}, nil)
+			configService.On("GetLocation").Return(time.UTC, nil)
+
+			before := time.Now()
+			docRepo.On("ArchiveIssuedBefore", mock.MatchedBy(func(cutoff time.Time) bool {
+				expected := before.Add(-15 * 24 * time.Hour)
+				return !cutoff.Before(expected) && cutoff.Sub(expected) < time.Minute
+			})).Return(tc.archived, tc.repoErr).Once()
+			if tc.expectAudit {
+				auditService.On("LogActivity", actorID, models.AuditArchiveDocuments, mock.AnythingOfType("string")).Once()
+			}
+
+			service := NewArchiveService(docRepo, configService, auditService)
+			status, err := service.RunNow(actorID)
+
+			if tc.expectedError {
+				assert.Error(t, err)
+				assert.Nil(t, status)
+				assert.NotEmpty(t, service.Status().LastError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.archived, status.LastArchivedCount)
+				assert.NotNil(t, status.LastRunAt)
+				assert.NotNil(t, status.LastCutoff)
+				assert.Empty(t, status.LastError)
+			}
+			assert.False(t, service.Status().Running)
+
+			docRepo.AssertExpectations(t)
+			auditService.AssertExpectations(t)
+		})
+	}
+}
@@ -8,7 +8,7 @@ import (
 
 type AuditLogService interface {
 	LogActivity(userID uint, action string, details string)
-	FindAll() ([]models.AuditLog, error)
+	FindPage(list repositories.ListQuery) ([]models.AuditLog, repositories.PageResult, error)
 }
 
 type auditLogService struct {
@@ -20,10 +20,15 @@ func NewAuditLogService(repo repositories.AuditLogRepository) AuditLogService {
 }
 
 // LogActivity berjalan sebagai goroutine agar tidak memblokir proses utama.
+// userID models.SystemUserID dicatat sebagai aksi sistem tanpa pengguna.
 func (s *auditLogService) LogActivity(userID uint, action string, details string) {
+	var actorID *uint
+	if userID != models.SystemUserID {
+		actorID = &userID
+	}
 	go func() {
 		logEntry := &models.AuditLog{
-			UserID:    userID,
+			UserID:    actorID,
 			Aksi:      action,
 			Detail:    details,
 			Timestamp: time.Now(),
@@ -32,6 +37,6 @@ func (s *auditLogService) LogActivity(userID uint, action string, details string
 	}()
 }
 
-func (s *auditLogService) FindAll() ([]models.AuditLog, error) {
-	return s.repo.FindAll()
+func (s *auditLogService) FindPage(list repositories.ListQuery) ([]models.AuditLog, repositories.PageResult, error) {
+	return s.repo.FindPage(list)
 }
\ No newline at end of file
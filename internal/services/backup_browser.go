@@ -0,0 +1,706 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"simdokpol/internal/config"
+	"simdokpol/internal/database"
+	"simdokpol/internal/dto"
+	"simdokpol/internal/models"
+	"simdokpol/internal/repositories"
+	"sort"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// DefaultBackupSessionSweep adalah jeda pemeriksaan sesi penjelajah backup yang kedaluwarsa.
+const DefaultBackupSessionSweep = time.Minute
+
+const (
+	// backupSessionTTL adalah lama sesi penjelajah backup bertahan tanpa dipakai. Salinan backup
+	// memuat data pribadi, sehingga tidak dibiarkan tergeletak di folder sementara.
+	backupSessionTTL = 30 * time.Minute
+	// maxBackupSessions membatasi jumlah backup yang terbuka bersamaan; sesi tertua ditutup lebih dulu.
+	maxBackupSessions = 3
+	// backupSessionDirPattern adalah pola nama folder sementara tempat salinan backup dibuka.
+	backupSessionDirPattern = "simdokpol-backup-browser-"
+)
+
+// Jenis perbedaan dokumen antara file backup dan database saat ini.
+const (
+	BackupDiffMissing = "HILANG"  // Sudah dimusnahkan; dapat dipulihkan dari backup
+	BackupDiffDeleted = "DIHAPUS" // Masih di tong sampah; pulihkan dari Tong Sampah
+	BackupDiffChanged = "BERUBAH"
+	BackupDiffAdded   = "BARU"
+)
+
+// BackupBrowserService membuka file backup hanya-baca untuk dijelajahi, dibandingkan dengan database
+// saat ini, dan dipulihkan sebagian. File backup tidak pernah diubah: yang dibuka adalah salinannya
+// di folder sementara, dimigrasikan ke versi skema aplikasi agar dapat dibaca dengan model yang sama.
+type BackupBrowserService interface {
+	// OpenStoredBackup membuka file di folder backup. fileName harus salah satu nama file dari ListBackups.
+	OpenStoredBackup(fileName string, password string, actorID uint) (*dto.BackupSession, error)
+	// OpenUploadedBackup membuka file backup yang diunggah, misalnya dari media penyimpanan lain.
+	OpenUploadedBackup(fileName string, upload io.Reader, password string, actorID uint) (*dto.BackupSession, error)
+	CloseBackup(sessionID string) error
+	// FindDocuments mengambil satu halaman dokumen di dalam backup (semua status), dengan filter
+	// dan pengurutan yang sama seperti daftar dokumen.
+	FindDocuments(sessionID string, list repositories.ListQuery) ([]models.LostDocument, repositories.PageResult, error)
+	FindDocument(sessionID string, id uint) (*models.LostDocument, error)
+	// Compare membandingkan dokumen di backup dengan database saat ini berdasarkan ID dan waktu pembuatannya.
+	Compare(sessionID string) (*dto.BackupDiff, error)
+	// RecoverDocuments membuat kembali dokumen terpilih dari backup di database saat ini, beserta
+	// pemohon, barang, dan riwayat revisinya, dalam satu transaksi. Dokumen yang masih ada (termasuk
+	// yang masih di tong sampah) dilewati. Bila Nomor Surat aslinya sudah dipakai dokumen lain, dokumen
+	// dipulihkan sebagai draf tanpa nomor, sama seperti pemulihan dari tong sampah.
+	RecoverDocuments(sessionID string, ids []uint, actorID uint) (*dto.BackupRecoveryResult, error)
+	// Start menghapus sisa salinan backup dari eksekusi sebelumnya, lalu menutup sesi yang kedaluwarsa
+	// setiap interval sampai ctx dibatalkan. Dipanggil sebagai goroutine.
+	Start(ctx context.Context, interval time.Duration)
+}
+
+type backupBrowserService struct {
+	db               *gorm.DB
+	cfg              *config.Config
+	configService    ConfigService
+	residentRepo     repositories.ResidentRepository
+	seqRepo          repositories.DocumentSequenceRepository
+	revisionService  DocumentRevisionService
+	auditService     AuditLogService
+	migrationsSource string
+	now              func() time.Time
+
+	mu       sync.Mutex
+	sessions map[string]*backupSession
+}
+
+// backupSession adalah salinan backup yang sedang terbuka.
+type backupSession struct {
+	info     dto.BackupSession
+	dir      string
+	db       *gorm.DB
+	docRepo  repositories.LostDocumentRepository
+	lastUsed time.Time
+}
+
+func NewBackupBrowserService(db *gorm.DB, cfg *config.Config, configService ConfigService, residentRepo repositories.ResidentRepository, seqRepo repositories.DocumentSequenceRepository, revisionService DocumentRevisionService, auditService AuditLogService) BackupBrowserService {
+	return &backupBrowserService{
+		db:               db,
+		cfg:              cfg,
+		configService:    configService,
+		residentRepo:     residentRepo,
+		seqRepo:          seqRepo,
+		revisionService:  revisionService,
+		auditService:     auditService,
+		migrationsSource: database.MigrationsSource,
+		now:              time.Now,
+		sessions:         make(map[string]*backupSession),
+	}
+}
+
+func (s *backupBrowserService) OpenStoredBackup(fileName string, password string, actorID uint) (*dto.BackupSession, error) {
+	dir, err := backupDirectory(s.configService)
+	if err != nil {
+		return nil, err
+	}
+	files, err := listBackupFiles(dir)
+	if err != nil {
+		return nil, fmt.Errorf("gagal membaca folder backup '%s': %w", dir, err)
+	}
+	// Hanya nama file dari daftar backup yang diterima, sehingga path di luar folder backup tidak bisa dibuka.
+	for _, file := range files {
+		if file.Name != fileName {
+			continue
+		}
+		f, err := os.Open(filepath.Join(dir, file.Name))
+		if err != nil {
+			return nil, fmt.Errorf("gagal membuka file backup: %w", err)
+		}
+		defer f.Close()
+		createdAt := file.CreatedAt
+		return s.open(file.Name, f, password, &createdAt, actorID)
+	}
+	return nil, ErrNotFound
+}
+
+func (s *backupBrowserService) OpenUploadedBackup(fileName string, upload io.Reader, password string, actorID uint) (*dto.BackupSession, error) {
+	return s.open(filepath.Base(fileName), upload, password, nil, actorID)
+}
+
+// open menyalin (dan bila perlu mendekripsi) backup ke folder sementara, memeriksanya seperti file
+// yang akan di-restore, memigrasikan salinannya, lalu membukanya hanya-baca.
+func (s *backupBrowserService) open(fileName string, source io.Reader, password string, createdAt *time.Time, actorID uint) (*dto.BackupSession, error) {
+	dir, err := os.MkdirTemp("", backupSessionDirPattern)
+	if err != nil {
+		return nil, fmt.Errorf("gagal membuat folder sementara: %w", err)
+	}
+	session, err := s.prepareSession(dir, fileName, source, password)
+	if err != nil {
+		os.RemoveAll(dir)
+		return nil, err
+	}
+	session.info.BackupCreatedAt = createdAt
+
+	now := s.now()
+	session.lastUsed = now
+	session.info.OpenedAt = now
+	session.info.ExpiresAt = now.Add(backupSessionTTL)
+
+	s.mu.Lock()
+	s.expireSessions(now)
+	for len(s.sessions) >= maxBackupSessions {
+		s.closeOldestSession()
+	}
+	s.sessions[session.info.ID] = session
+	s.mu.Unlock()
+
+	s.auditService.LogActivity(actorID, models.AuditBrowseBackup, fmt.Sprintf("Membuka file backup %s (versi skema %d) di penjelajah backup", fileName, session.info.SchemaVersion))
+	info := session.info
+	return &info, nil
+}
+
+func (s *backupBrowserService) prepareSession(dir, fileName string, source io.Reader, password string) (*backupSession, error) {
+	path := filepath.Join(dir, "backup.db")
+	encrypted, err := stageBackupFile(source, path, password, s.cfg.BackupEncryptionKey)
+	if err != nil {
+		return nil, err
+	}
+	latestVersion, err := database.LatestVersion(s.migrationsSource)
+	if err != nil {
+		return nil, fmt.Errorf("gagal membaca daftar migrasi aplikasi: %w", err)
+	}
+	version, err := checkRestoreCandidate(path, latestVersion)
+	if err != nil {
+		return nil, err
+	}
+	if err := migrateBackupFile(path, s.cfg.DBDSN, s.migrationsSource); err != nil {
+		return nil, fmt.Errorf("gagal menjalankan migrasi pada salinan backup: %w", err)
+	}
+
+	db, err := openBackupFile(path)
+	if err != nil {
+		return nil, err
+	}
+	session := &backupSession{
+		info: dto.BackupSession{
+			ID:            newBackupSessionID(),
+			FileName:      fileName,
+			Encrypted:     encrypted,
+			SchemaVersion: version,
+		},
+		dir:     dir,
+		db:      db,
+		docRepo: repositories.NewLostDocumentRepository(db),
+	}
+	if err := db.Model(&models.LostDocument{}).Count(&session.info.Documents).Error; err != nil {
+		session.close()
+		return nil, fmt.Errorf("gagal membaca dokumen di dalam backup: %w", err)
+	}
+	return session, nil
+}
+
+func (s *backupBrowserService) CloseBackup(sessionID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	session, ok := s.sessions[sessionID]
+	if !ok {
+		return ErrBackupSessionNotFound
+	}
+	delete(s.sessions, sessionID)
+	session.close()
+	return nil
+}
+
+func (s *backupBrowserService) FindDocuments(sessionID string, list repositories.ListQuery) ([]models.LostDocument, repositories.PageResult, error) {
+	session, err := s.session(sessionID)
+	if err != nil {
+		return nil, repositories.PageResult{}, err
+	}
+	return session.docRepo.FindPage("all", repositories.DocumentScope{All: true}, list)
+}
+
+func (s *backupBrowserService) FindDocument(sessionID string, id uint) (*models.LostDocument, error) {
+	session, err := s.session(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := session.docRepo.FindByID(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+	return doc, err
+}
+
+// documentVersion adalah ringkasan satu dokumen untuk perbandingan backup dengan database saat ini.
+type documentVersion struct {
+	ID             uint
+	NomorSurat     string
+	JenisDokumen   string
+	Status         string
+	NamaPemohon    string
+	TanggalLaporan time.Time
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	DeletedAt      gorm.DeletedAt
+}
+
+func loadDocumentVersions(db *gorm.DB) (map[uint]documentVersion, error) {
+	var rows []documentVersion
+	// Unscoped: dokumen di tong sampah ikut dibaca agar dapat dibedakan dari dokumen yang sudah dimusnahkan.
+	err := db.Unscoped().Table("lost_documents").
+		Select("lost_documents.id, lost_documents.nomor_surat, lost_documents.jenis_dokumen, lost_documents.status, residents.nama_lengkap AS nama_pemohon, lost_documents.tanggal_laporan, lost_documents.created_at, lost_documents.updated_at, lost_documents.deleted_at").
+		Joins("LEFT JOIN residents ON residents.id = lost_documents.resident_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	versions := make(map[uint]documentVersion, len(rows))
+	for _, row := range rows {
+		versions[row.ID] = row
+	}
+	return versions, nil
+}
+
+// sameDocument memastikan dua baris dengan ID sama memang dokumen yang sama. ID dapat dipakai ulang
+// bila database pernah dipulihkan dari backup yang lebih lama.
+func sameDocument(a, b documentVersion) bool {
+	return a.ID == b.ID && a.CreatedAt.Equal(b.CreatedAt)
+}
+
+func (s *backupBrowserService) Compare(sessionID string) (*dto.BackupDiff, error) {
+	session, err := s.session(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	backupDocs, err := loadDocumentVersions(session.db)
+	if err != nil {
+		return nil, fmt.Errorf("gagal membaca dokumen di dalam backup: %w", err)
+	}
+	liveDocs, err := loadDocumentVersions(s.db)
+	if err != nil {
+		return nil, fmt.Errorf("gagal membaca dokumen di database saat ini: %w", err)
+	}
+
+	diff := &dto.BackupDiff{Entries: []dto.BackupDiffEntry{}}
+	matched := map[uint]bool{}
+	for id, backup := range backupDocs {
+		// Dokumen yang sudah berada di tong sampah saat backup dibuat tidak ikut dibandingkan.
+		if backup.DeletedAt.Valid {
+			continue
+		}
+		entry := dto.BackupDiffEntry{
+			ID:             backup.ID,
+			NomorSurat:     backup.NomorSurat,
+			JenisDokumen:   backup.JenisDokumen,
+			NamaPemohon:    backup.NamaPemohon,
+			TanggalLaporan: backup.TanggalLaporan,
+			Status:         backup.Status,
+			DiubahPada:     timePtr(backup.UpdatedAt),
+		}
+		live, ok := liveDocs[id]
+		switch {
+		case !ok || !sameDocument(backup, live):
+			entry.Perubahan = BackupDiffMissing
+			diff.Hilang++
+		case live.DeletedAt.Valid:
+			matched[id] = true
+			entry.Perubahan = BackupDiffDeleted
+			entry.StatusSaatIni = live.Status
+			entry.DiubahSaatIni = timePtr(live.DeletedAt.Time)
+			diff.Dihapus++
+		case !live.UpdatedAt.Equal(backup.UpdatedAt):
+			matched[id] = true
+			entry.Perubahan = BackupDiffChanged
+			entry.StatusSaatIni = live.Status
+			entry.DiubahSaatIni = timePtr(live.UpdatedAt)
+			diff.Berubah++
+		default:
+			matched[id] = true
+			diff.Sama++
+			continue
+		}
+		diff.Entries = append(diff.Entries, entry)
+	}
+	for id, live := range liveDocs {
+		if matched[id] || live.DeletedAt.Valid {
+			continue
+		}
+		diff.Baru++
+		diff.Entries = append(diff.Entries, dto.BackupDiffEntry{
+			ID:             live.ID,
+			Perubahan:      BackupDiffAdded,
+			NomorSurat:     live.NomorSurat,
+			JenisDokumen:   live.JenisDokumen,
+			NamaPemohon:    live.NamaPemohon,
+			TanggalLaporan: live.TanggalLaporan,
+			StatusSaatIni:  live.Status,
+			DiubahSaatIni:  timePtr(live.UpdatedAt),
+		})
+	}
+
+	order := map[string]int{BackupDiffMissing: 0, BackupDiffDeleted: 1, BackupDiffChanged: 2, BackupDiffAdded: 3}
+	sort.Slice(diff.Entries, func(i, j int) bool {
+		a, b := diff.Entries[i], diff.Entries[j]
+		if a.Perubahan != b.Perubahan {
+			return order[a.Perubahan] < order[b.Perubahan]
+		}
+		if !a.TanggalLaporan.Equal(b.TanggalLaporan) {
+			return a.TanggalLaporan.After(b.TanggalLaporan)
+		}
+		return a.ID > b.ID
+	})
+	return diff, nil
+}
+
+// recoveryPlan adalah dokumen backup yang lolos pemeriksaan dan siap dibuat kembali.
+type recoveryPlan struct {
+	doc       *models.LostDocument
+	keepID    bool
+	revisions []models.DocumentRevision
+}
+
+func (s *backupBrowserService) RecoverDocuments(sessionID string, ids []uint, actorID uint) (*dto.BackupRecoveryResult, error) {
+	session, err := s.session(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	result := &dto.BackupRecoveryResult{Recovered: []dto.RecoveredDocument{}, Skipped: []dto.SkippedRecovery{}}
+	if len(ids) == 0 {
+		return result, nil
+	}
+
+	liveDocs, err := loadDocumentVersions(s.db)
+	if err != nil {
+		return nil, fmt.Errorf("gagal membaca dokumen di database saat ini: %w", err)
+	}
+	var plans []recoveryPlan
+	seen := map[uint]bool{}
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		plan, reason, err := s.planRecovery(session, id, liveDocs)
+		if err != nil {
+			return nil, err
+		}
+		if reason != "" {
+			skipped := dto.SkippedRecovery{BackupID: id, Alasan: reason}
+			if plan != nil {
+				skipped.NomorSurat = plan.doc.NomorSurat
+			}
+			result.Skipped = append(result.Skipped, skipped)
+			continue
+		}
+		plans = append(plans, *plan)
+	}
+	if len(plans) == 0 {
+		return result, nil
+	}
+
+	type sequenceKey struct {
+		docType string
+		year    int
+	}
+	var labels []string
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		highest := map[sequenceKey]int{}
+		for i := range plans {
+			recovered, err := s.recoverDocument(tx, &plans[i], actorID)
+			if err != nil {
+				return fmt.Errorf("gagal memulihkan dokumen %s: %w", documentLabel(plans[i].doc), err)
+			}
+			result.Recovered = append(result.Recovered, *recovered)
+			labels = append(labels, recoveryAuditDetail(plans[i].doc, recovered, session.info.FileName))
+
+			key := sequenceKey{plans[i].doc.JenisDokumen, plans[i].doc.TahunNomor}
+			if !recovered.NomorBentrok && plans[i].doc.NomorUrut > highest[key] {
+				highest[key] = plans[i].doc.NomorUrut
+			}
+		}
+		// Seperti pada impor surat lama, nomor terakhir hanya dinaikkan di dalam transaksi ini.
+		for key, number := range highest {
+			if err := s.seqRepo.RaiseLastNumber(tx, key.docType, key.year, number); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, detail := range labels {
+		s.auditService.LogActivity(actorID, models.AuditRecoverFromBackup, detail)
+	}
+	return result, nil
+}
+
+// planRecovery memuat dokumen dari backup dan memeriksa apakah dokumen tersebut boleh dibuat kembali.
+// reason berisi alasan bila dokumen dilewati.
+func (s *backupBrowserService) planRecovery(session *backupSession, id uint, liveDocs map[uint]documentVersion) (plan *recoveryPlan, reason string, err error) {
+	doc, err := session.docRepo.FindByID(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, "Dokumen tidak ada di dalam backup.", nil
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("gagal membaca dokumen di dalam backup: %w", err)
+	}
+	plan = &recoveryPlan{doc: doc, keepID: true}
+
+	if live, ok := liveDocs[id]; ok {
+		if sameDocument(documentVersion{ID: doc.ID, CreatedAt: doc.CreatedAt}, live) {
+			if live.DeletedAt.Valid {
+				return plan, "Dokumen masih ada di Tong Sampah; pulihkan dari menu Tong Sampah.", nil
+			}
+			return plan, "Dokumen masih ada di database saat ini.", nil
+		}
+		// ID asli sudah dipakai dokumen lain, sehingga dokumen dibuat dengan ID baru.
+		plan.keepID = false
+	}
+
+	var revisions []models.DocumentRevision
+	if err := session.db.Where("lost_document_id = ?", id).Order("revision_number asc").Find(&revisions).Error; err != nil {
+		return nil, "", fmt.Errorf("gagal membaca riwayat revisi di dalam backup: %w", err)
+	}
+	userIDs := []uint{doc.PetugasPelaporID, doc.OperatorID}
+	for _, optional := range []*uint{doc.PejabatPersetujuID, doc.LastUpdatedByID, doc.DicabutOlehID} {
+		if optional != nil {
+			userIDs = append(userIDs, *optional)
+		}
+	}
+	for _, revision := range revisions {
+		userIDs = append(userIDs, revision.ChangedByID)
+	}
+	var existing []uint
+	if err := s.db.Unscoped().Model(&models.User{}).Where("id IN ?", userIDs).Pluck("id", &existing).Error; err != nil {
+		return nil, "", err
+	}
+	found := map[uint]bool{}
+	for _, userID := range existing {
+		found[userID] = true
+	}
+
+	// Petugas pelapor dan operator wajib ada; rujukan pengguna lain yang sudah dimusnahkan dikosongkan.
+	for _, userID := range []uint{doc.PetugasPelaporID, doc.OperatorID} {
+		if !found[userID] {
+			return plan, fmt.Sprintf("Petugas atau operator dengan ID %d tidak ada di database saat ini.", userID), nil
+		}
+	}
+	for _, optional := range []**uint{&doc.PejabatPersetujuID, &doc.LastUpdatedByID, &doc.DicabutOlehID} {
+		if *optional != nil && !found[**optional] {
+			*optional = nil
+		}
+	}
+	for _, revision := range revisions {
+		if found[revision.ChangedByID] {
+			plan.revisions = append(plan.revisions, revision)
+		}
+	}
+	return plan, "", nil
+}
+
+// recoverDocument membuat kembali satu dokumen di dalam tx.
+func (s *backupBrowserService) recoverDocument(tx *gorm.DB, plan *recoveryPlan, actorID uint) (*dto.RecoveredDocument, error) {
+	source := plan.doc
+	resident, err := findOrCreateResident(tx, s.residentRepo, source.Resident)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := *source
+	if !plan.keepID {
+		doc.ID = 0
+	}
+	doc.ResidentID = resident.ID
+	// Relasi tidak ikut disimpan; hanya ID-nya.
+	doc.Resident = models.Resident{}
+	doc.PetugasPelapor, doc.PejabatPersetuju, doc.Operator = models.User{}, models.User{}, models.User{}
+	doc.LastUpdatedBy, doc.DicabutOleh, doc.DihapusOleh = models.User{}, models.User{}, models.User{}
+	doc.AlasanPenghapusan, doc.DihapusOlehID, doc.DeletedAt = "", nil, gorm.DeletedAt{}
+	doc.LostItems = make([]models.LostItem, len(source.LostItems))
+	for i, item := range source.LostItems {
+		item.ID, item.LostDocumentID = 0, 0
+		doc.LostItems[i] = item
+	}
+
+	nomorAsli := source.NomorSurat
+	recovered := &dto.RecoveredDocument{BackupID: source.ID, RestoredDocument: dto.RestoredDocument{NomorSurat: nomorAsli, NomorAsli: nomorAsli, Status: source.Status}}
+	if nomorAsli != "" {
+		var count int64
+		if err := tx.Unscoped().Model(&models.LostDocument{}).Where("nomor_surat = ?", nomorAsli).Count(&count).Error; err != nil {
+			return nil, err
+		}
+		if count > 0 {
+			// Nomor yang sama tidak boleh beredar di dua surat; dokumen diajukan ulang untuk mendapat nomor baru.
+			doc.NomorSurat, doc.NomorUrut, doc.TahunNomor = "", 0, 0
+			doc.Status = models.StatusDraf
+			doc.TanggalPersetujuan, doc.TanggalPencabutan = nil, nil
+			doc.AlasanPencabutan, doc.DicabutOlehID = "", nil
+			recovered.NomorSurat = ""
+			recovered.Status = models.StatusDraf
+			recovered.NomorBentrok = true
+		}
+	}
+
+	if err := tx.Create(&doc).Error; err != nil {
+		return nil, err
+	}
+	recovered.ID = doc.ID
+
+	for _, revision := range plan.revisions {
+		revision.ID = 0
+		revision.LostDocumentID = doc.ID
+		revision.ChangedBy = models.User{}
+		if err := tx.Create(&revision).Error; err != nil {
+			return nil, fmt.Errorf("gagal menyalin riwayat revisi: %w", err)
+		}
+	}
+	doc.Resident = *resident
+	doc.PetugasPelapor, doc.PejabatPersetuju = source.PetugasPelapor, source.PejabatPersetuju
+	if err := s.revisionService.RecordRevision(tx, &doc, models.RevisionRecovered, actorID); err != nil {
+		return nil, err
+	}
+	return recovered, nil
+}
+
+// findOrCreateResident mencari pemohon dokumen backup di database saat ini (berdasarkan NIK, lalu nama
+// dan tanggal lahir) atau membuatnya bila tidak ada. Berbeda dengan resolveResident, data penduduk yang
+// sudah ada tidak ditimpa, karena data di backup lebih lama daripada data saat ini.
+func findOrCreateResident(tx *gorm.DB, residentRepo repositories.ResidentRepository, residentData models.Resident) (*models.Resident, error) {
+	if residentData.NIK != nil {
+		existing, err := residentRepo.FindByNIK(tx, *residentData.NIK)
+		if err == nil {
+			return existing, nil
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+	}
+	existing, err := residentRepo.FindByNameAndBirthDate(tx, residentData.NamaLengkap, residentData.TanggalLahir)
+	if err == nil && (residentData.NIK == nil || existing.NIK == nil) {
+		return existing, nil
+	}
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	resident := residentData
+	resident.ID = 0
+	resident.DeletedAt = gorm.DeletedAt{}
+	return residentRepo.Create(tx, &resident)
+}
+
+func recoveryAuditDetail(source *models.LostDocument, recovered *dto.RecoveredDocument, fileName string) string {
+	detail := fmt.Sprintf("Memulihkan dokumen %s atas nama %s dari file backup %s", documentLabel(source), source.Resident.NamaLengkap, fileName)
+	if recovered.ID != source.ID {
+		detail += fmt.Sprintf(" dengan ID baru %d", recovered.ID)
+	}
+	if recovered.NomorBentrok {
+		detail += fmt.Sprintf(" sebagai draf karena Nomor Surat %s sudah dipakai dokumen lain", recovered.NomorAsli)
+	}
+	return detail
+}
+
+func (s *backupBrowserService) Start(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultBackupSessionSweep
+	}
+	// Salinan yang tertinggal karena aplikasi berhenti mendadak tidak lagi dimiliki sesi mana pun.
+	if leftovers, err := filepath.Glob(filepath.Join(os.TempDir(), backupSessionDirPattern+"*")); err == nil {
+		for _, dir := range leftovers {
+			if err := os.RemoveAll(dir); err != nil {
+				log.Printf("PERINGATAN: Gagal menghapus salinan backup sementara %s: %v", dir, err)
+			}
+		}
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			s.mu.Lock()
+			for id, session := range s.sessions {
+				delete(s.sessions, id)
+				session.close()
+			}
+			s.mu.Unlock()
+			return
+		case <-ticker.C:
+			s.mu.Lock()
+			s.expireSessions(s.now())
+			s.mu.Unlock()
+		}
+	}
+}
+
+// session mengembalikan sesi yang masih berlaku dan memperpanjang masa berlakunya.
+func (s *backupBrowserService) session(sessionID string) (*backupSession, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	s.expireSessions(now)
+	session, ok := s.sessions[sessionID]
+	if !ok {
+		return nil, ErrBackupSessionNotFound
+	}
+	session.lastUsed = now
+	session.info.ExpiresAt = now.Add(backupSessionTTL)
+	return session, nil
+}
+
+// expireSessions menutup sesi yang tidak dipakai melewati backupSessionTTL. s.mu harus sudah dikunci.
+func (s *backupBrowserService) expireSessions(now time.Time) {
+	for id, session := range s.sessions {
+		if now.Sub(session.lastUsed) >= backupSessionTTL {
+			delete(s.sessions, id)
+			session.close()
+		}
+	}
+}
+
+// closeOldestSession menutup sesi yang paling lama tidak dipakai. s.mu harus sudah dikunci.
+func (s *backupBrowserService) closeOldestSession() {
+	var oldest *backupSession
+	for _, session := range s.sessions {
+		if oldest == nil || session.lastUsed.Before(oldest.lastUsed) {
+			oldest = session
+		}
+	}
+	if oldest != nil {
+		delete(s.sessions, oldest.info.ID)
+		oldest.close()
+	}
+}
+
+// close menutup koneksi ke salinan backup dan menghapus folder sementaranya.
+func (session *backupSession) close() {
+	if sqlDB, err := session.db.DB(); err == nil {
+		sqlDB.Close()
+	}
+	if err := os.RemoveAll(session.dir); err != nil {
+		log.Printf("PERINGATAN: Gagal menghapus salinan backup sementara %s: %v", session.dir, err)
+	}
+}
+
+func newBackupSessionID() string {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		panic(fmt.Sprintf("gagal membuat ID sesi backup: %v", err))
+	}
+	return hex.EncodeToString(id)
+}
+
+func timePtr(t time.Time) *time.Time {
+	return &t
+}
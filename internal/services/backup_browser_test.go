@@ -0,0 +1,234 @@
+package services
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"simdokpol/internal/config"
+	"simdokpol/internal/database"
+	"simdokpol/internal/dto"
+	"simdokpol/internal/mocks"
+	"simdokpol/internal/models"
+	"simdokpol/internal/repositories"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// browserFixture menyiapkan database aktif dan folder backup. Skema dibuat dengan AutoMigrate di atas
+// migrasi kecil versi 1, karena migrasi aplikasi membutuhkan FTS5.
+type browserFixture struct {
+	dir          string
+	backupDir    string
+	source       string
+	db           *gorm.DB
+	service      *backupBrowserService
+	auditService *mocks.AuditLogService
+	revisions    *mocks.DocumentRevisionService
+}
+
+var browserBase = time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC)
+
+func newBrowserFixture(t *testing.T) *browserFixture {
+	dir := t.TempDir()
+	migrationsDir := filepath.Join(dir, "migrations")
+	require.NoError(t, os.MkdirAll(migrationsDir, 0755))
+	files := map[string]string{
+		"000001_init.up.sql":   "CREATE TABLE configurations (key text primary key, value text);\nCREATE TABLE audit_logs (id integer primary key);",
+		"000001_init.down.sql": "DROP TABLE configurations;",
+	}
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(migrationsDir, name), []byte(content), 0644))
+	}
+	f := &browserFixture{dir: dir, backupDir: filepath.Join(dir, "backups"), source: "file://" + filepath.ToSlash(migrationsDir)}
+	require.NoError(t, os.MkdirAll(f.backupDir, 0755))
+
+	f.db = f.openDatabase(t, filepath.Join(dir, "simdokpol.db"))
+	t.Cleanup(func() {
+		sqlDB, _ := f.db.DB()
+		sqlDB.Close()
+	})
+
+	configService := new(mocks.ConfigService)
+	configService.On("GetConfig").Return(&dto.AppConfig{BackupPath: f.backupDir}, nil)
+	f.auditService = new(mocks.AuditLogService)
+	f.revisions = new(mocks.DocumentRevisionService)
+	f.service = NewBackupBrowserService(f.db, &config.Config{DBDSN: filepath.Join(dir, "simdokpol.db")}, configService,
+		repositories.NewResidentRepository(f.db), repositories.NewDocumentSequenceRepository(f.db), f.revisions, f.auditService).(*backupBrowserService)
+	f.service.migrationsSource = f.source
+	return f
+}
+
+func (f *browserFixture) openDatabase(t *testing.T, path string) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(path), &gorm.Config{Logger: logger.Discard})
+	require.NoError(t, err)
+	sqlDB, _ := db.DB()
+	require.NoError(t, database.Migrate(sqlDB, f.source))
+	require.NoError(t, db.AutoMigrate(&models.User{}, &models.Resident{}, &models.LostDocument{}, &models.LostItem{}, &models.DocumentRevision{}, &models.DocumentSequence{}))
+	require.NoError(t, db.Create(&models.User{ID: 1, NamaLengkap: "PETUGAS", NRP: "1", KataSandi: "x"}).Error)
+	return db
+}
+
+// writeBackup membuat file backup di folder backup; prepare mengisi datanya.
+func (f *browserFixture) writeBackup(t *testing.T, name string, prepare func(db *gorm.DB)) {
+	db := f.openDatabase(t, filepath.Join(f.backupDir, name))
+	prepare(db)
+	sqlDB, _ := db.DB()
+	sqlDB.Close()
+}
+
+func browserDocument(id uint, nomor string, nomorUrut int, resident models.Resident, createdAt time.Time) *models.LostDocument {
+	return &models.LostDocument{
+		ID:               id,
+		NomorSurat:       nomor,
+		NomorUrut:        nomorUrut,
+		TahunNomor:       2026,
+		JenisDokumen:     "LOST_DOCUMENT",
+		TanggalLaporan:   createdAt,
+		Status:           models.StatusDiterbitkan,
+		Resident:         resident,
+		LostItems:        []models.LostItem{{NamaBarang: "KTP"}},
+		PetugasPelaporID: 1,
+		OperatorID:       1,
+		CreatedAt:        createdAt,
+		UpdatedAt:        createdAt,
+	}
+}
+
+func browserResident(nik, nama string) models.Resident {
+	return models.Resident{NIK: &nik, NamaLengkap: nama, TempatLahir: "BANDUNG", TanggalLahir: browserBase.AddDate(-30, 0, 0), JenisKelamin: "LAKI-LAKI", Agama: "ISLAM", Pekerjaan: "SWASTA", Alamat: "JL. MERDEKA"}
+}
+
+func TestBackupBrowserService_CompareAndRecover(t *testing.T) {
+	f := newBrowserFixture(t)
+	actorID := uint(1)
+
+	kept := browserDocument(1, "SKH/1/III/2026", 1, browserResident("3201000000000001", "TETAP"), browserBase)
+	reused := browserDocument(2, "SKH/2/III/2026", 2, browserResident("3201000000000002", "ID DIPAKAI ULANG"), browserBase.Add(time.Hour))
+	trashed := browserDocument(3, "SKH/3/III/2026", 3, browserResident("3201000000000003", "TONG SAMPAH"), browserBase.Add(2*time.Hour))
+	changed := browserDocument(4, "SKH/4/III/2026", 4, browserResident("3201000000000004", "BERUBAH"), browserBase.Add(3*time.Hour))
+	purged := browserDocument(6, "SKH/6/III/2026", 6, browserResident("3201000000000006", "NAMA DI BACKUP"), browserBase.Add(4*time.Hour))
+	f.writeBackup(t, "backup-simdokpol-2026-03-02_08-00-00.db", func(db *gorm.DB) {
+		for _, doc := range []*models.LostDocument{kept, reused, trashed, changed, purged} {
+			doc := *doc
+			require.NoError(t, db.Create(&doc).Error)
+		}
+		require.NoError(t, db.Create(&models.DocumentRevision{LostDocumentID: 6, RevisionNumber: 1, Aksi: models.RevisionCreated, Snapshot: "{}", ChangedByID: 1, CreatedAt: purged.CreatedAt}).Error)
+	})
+
+	// Database saat ini: dokumen 2 dan 6 sudah dimusnahkan, ID 2 dipakai dokumen baru dengan nomor
+	// yang sama, dokumen 3 di tong sampah, dan dokumen 4 diubah setelah backup dibuat.
+	for _, doc := range []*models.LostDocument{kept, trashed, changed} {
+		doc := *doc
+		require.NoError(t, f.db.Create(&doc).Error)
+	}
+	require.NoError(t, f.db.Delete(&models.LostDocument{}, 3).Error)
+	require.NoError(t, f.db.Model(&models.LostDocument{}).Where("id = ?", 4).UpdateColumn("updated_at", browserBase.AddDate(0, 0, 2)).Error)
+	newer := browserDocument(2, "SKH/2/III/2026", 2, browserResident("3201000000000005", "BARU"), browserBase.AddDate(0, 0, 2))
+	require.NoError(t, f.db.Create(newer).Error)
+	require.NoError(t, f.db.Model(&models.Resident{}).Create(&models.Resident{NIK: purged.Resident.NIK, NamaLengkap: "NAMA SAAT INI", TempatLahir: "BANDUNG", TanggalLahir: purged.Resident.TanggalLahir, JenisKelamin: "LAKI-LAKI", Agama: "ISLAM", Pekerjaan: "PNS", Alamat: "JL. BARU"}).Error)
+	require.NoError(t, f.db.Create(&models.DocumentSequence{DocumentType: "LOST_DOCUMENT", Year: 2026, LastNumber: 4}).Error)
+
+	f.auditService.On("LogActivity", actorID, models.AuditBrowseBackup, mock.AnythingOfType("string")).Once()
+	session, err := f.service.OpenStoredBackup("backup-simdokpol-2026-03-02_08-00-00.db", "", actorID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), session.Documents)
+	assert.Equal(t, uint(1), session.SchemaVersion)
+
+	docs, page, err := f.service.FindDocuments(session.ID, repositories.ListQuery{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), page.Total)
+	assert.Len(t, docs, 5)
+
+	diff, err := f.service.Compare(session.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 2, diff.Hilang)
+	assert.Equal(t, 1, diff.Dihapus)
+	assert.Equal(t, 1, diff.Berubah)
+	assert.Equal(t, 1, diff.Baru)
+	assert.Equal(t, 1, diff.Sama)
+	changes := map[string][]uint{}
+	for _, entry := range diff.Entries {
+		changes[entry.Perubahan] = append(changes[entry.Perubahan], entry.ID)
+	}
+	assert.Equal(t, map[string][]uint{BackupDiffMissing: {6, 2}, BackupDiffDeleted: {3}, BackupDiffChanged: {4}, BackupDiffAdded: {2}}, changes)
+
+	f.revisions.On("RecordRevision", mock.Anything, mock.Anything, models.RevisionRecovered, actorID).Return(nil).Twice()
+	f.auditService.On("LogActivity", actorID, models.AuditRecoverFromBackup, mock.AnythingOfType("string")).Twice()
+	result, err := f.service.RecoverDocuments(session.ID, []uint{2, 6, 1, 3}, actorID)
+	require.NoError(t, err)
+
+	require.Len(t, result.Recovered, 2)
+	assert.Equal(t, uint(2), result.Recovered[0].BackupID)
+	assert.NotEqual(t, uint(2), result.Recovered[0].ID, "ID yang sudah dipakai diganti dengan ID baru")
+	assert.True(t, result.Recovered[0].NomorBentrok)
+	assert.Equal(t, models.StatusDraf, result.Recovered[0].Status)
+	assert.Equal(t, uint(6), result.Recovered[1].ID)
+	assert.Equal(t, "SKH/6/III/2026", result.Recovered[1].NomorSurat)
+	require.Len(t, result.Skipped, 2)
+	assert.Contains(t, result.Skipped[0].Alasan, "masih ada di database")
+	assert.Contains(t, result.Skipped[1].Alasan, "Tong Sampah")
+
+	var recovered models.LostDocument
+	require.NoError(t, f.db.Preload("Resident").Preload("LostItems").First(&recovered, 6).Error)
+	assert.Equal(t, "NAMA SAAT INI", recovered.Resident.NamaLengkap, "data penduduk yang sudah ada tidak ditimpa")
+	assert.Len(t, recovered.LostItems, 1)
+	assert.True(t, recovered.CreatedAt.Equal(purged.CreatedAt))
+	var revisionCount int64
+	require.NoError(t, f.db.Model(&models.DocumentRevision{}).Where("lost_document_id = ?", 6).Count(&revisionCount).Error)
+	assert.Equal(t, int64(1), revisionCount)
+	var sequence models.DocumentSequence
+	require.NoError(t, f.db.First(&sequence, "document_type = ? AND year = ?", "LOST_DOCUMENT", 2026).Error)
+	assert.Equal(t, 6, sequence.LastNumber)
+
+	// Setelah dipulihkan, dokumen tidak lagi tercatat hilang.
+	diff, err = f.service.Compare(session.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, diff.Hilang)
+
+	f.revisions.AssertExpectations(t)
+	f.auditService.AssertExpectations(t)
+}
+
+func TestBackupBrowserService_Sessions(t *testing.T) {
+	f := newBrowserFixture(t)
+	f.writeBackup(t, "backup-simdokpol-2026-03-02_08-00-00.db", func(db *gorm.DB) {})
+	f.auditService.On("LogActivity", uint(1), models.AuditBrowseBackup, mock.AnythingOfType("string"))
+
+	_, err := f.service.OpenStoredBackup("../simdokpol.db", "", 1)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	content, err := os.ReadFile(filepath.Join(f.backupDir, "backup-simdokpol-2026-03-02_08-00-00.db"))
+	require.NoError(t, err)
+	_, err = f.service.OpenUploadedBackup("bukan-backup.db", bytes.NewReader([]byte("bukan database")), "", 1)
+	assert.ErrorIs(t, err, ErrIncompatibleBackup)
+
+	now := browserBase
+	f.service.now = func() time.Time { return now }
+	session, err := f.service.OpenUploadedBackup("salinan.db", bytes.NewReader(content), "", 1)
+	require.NoError(t, err)
+	assert.Equal(t, "salinan.db", session.FileName)
+	dir := f.service.sessions[session.ID].dir
+	assert.DirExists(t, dir)
+
+	// Sesi yang tidak dipakai melewati batas waktu ditutup dan salinannya dihapus.
+	now = now.Add(backupSessionTTL)
+	_, _, err = f.service.FindDocuments(session.ID, repositories.ListQuery{})
+	assert.ErrorIs(t, err, ErrBackupSessionNotFound)
+	assert.NoDirExists(t, dir)
+
+	session, err = f.service.OpenStoredBackup("backup-simdokpol-2026-03-02_08-00-00.db", "", 1)
+	require.NoError(t, err)
+	require.NotNil(t, session.BackupCreatedAt)
+	_, err = f.service.FindDocument(session.ID, 99)
+	assert.ErrorIs(t, err, ErrNotFound)
+	require.NoError(t, f.service.CloseBackup(session.ID))
+	assert.ErrorIs(t, f.service.CloseBackup(session.ID), ErrBackupSessionNotFound)
+	assert.Empty(t, f.service.sessions)
+}
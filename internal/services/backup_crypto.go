@@ -0,0 +1,304 @@
+package services
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"simdokpol/internal/config"
+	"simdokpol/internal/dto"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/hkdf"
+)
+
+// Format file .simdokpol-backup:
+//
+//	magic (16 byte) | panjang header (uint32) | header JSON | potongan...
+//	potongan: penanda akhir (1 byte) | panjang ciphertext (uint32) | ciphertext AES-GCM
+//
+// Nonce setiap potongan tersusun dari awalan acak di header, nomor urut potongan, dan penanda akhir,
+// sehingga potongan yang ditukar, dibuang, atau dipotong di tengah jalan gagal didekripsi. Header JSON
+// dipakai sebagai additional data di setiap potongan.
+const (
+	backupContainerExt    = ".simdokpol-backup"
+	backupContainerFormat = 1
+	backupChunkSize       = 1 << 20
+	backupNoncePrefixSize = 7
+	maxBackupHeaderSize   = 64 << 10
+
+	backupKDFPassword = "argon2id"
+	backupKDFKey      = "hkdf-sha256"
+
+	// MinBackupPasswordLength adalah panjang minimal kata sandi backup.
+	MinBackupPasswordLength = 8
+)
+
+var backupContainerMagic = []byte("SIMDOKPOL-BACKUP")
+
+// Parameter argon2id untuk kata sandi backup (rekomendasi RFC 9106 untuk memori terbatas).
+const (
+	backupArgonTime    = 3
+	backupArgonMemory  = 64 * 1024
+	backupArgonThreads = 4
+)
+
+// Batas atas parameter argon2id yang diterima dari header. Header berasal dari file yang belum
+// terautentikasi, sehingga tanpa batas ini file rekayasa dapat menghabiskan memori atau CPU server.
+const (
+	maxBackupArgonTime    = 10
+	maxBackupArgonMemory  = 1024 * 1024 // KiB, yaitu 1 GiB
+	maxBackupArgonThreads = 16
+)
+
+// newBackupEncryption menyiapkan parameter enkripsi dan kuncinya. Kata sandi diutamakan; bila kosong
+// dipakai kunci server. Nilai nil dikembalikan bila keduanya tidak tersedia (backup tidak dienkripsi).
+func newBackupEncryption(password string, serverKey []byte) (*dto.BackupEncryption, []byte, error) {
+	if password == "" && len(serverKey) == 0 {
+		return nil, nil, nil
+	}
+	if password != "" && len([]rune(password)) < MinBackupPasswordLength {
+		return nil, nil, ErrWeakBackupPassword
+	}
+
+	enc := &dto.BackupEncryption{
+		Cipher:      "AES-256-GCM",
+		Salt:        make([]byte, 16),
+		NoncePrefix: make([]byte, backupNoncePrefixSize),
+		ChunkSize:   backupChunkSize,
+	}
+	if _, err := rand.Read(enc.Salt); err != nil {
+		return nil, nil, err
+	}
+	if _, err := rand.Read(enc.NoncePrefix); err != nil {
+		return nil, nil, err
+	}
+	if password != "" {
+		enc.KDF = backupKDFPassword
+		enc.Time, enc.MemoryKiB, enc.Threads = backupArgonTime, backupArgonMemory, backupArgonThreads
+	} else {
+		enc.KDF = backupKDFKey
+	}
+
+	key, err := deriveBackupKey(enc, password, serverKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	return enc, key, nil
+}
+
+// deriveBackupKey menurunkan kunci AES-256 dari kata sandi atau kunci server sesuai header.
+func deriveBackupKey(enc *dto.BackupEncryption, password string, serverKey []byte) ([]byte, error) {
+	switch enc.KDF {
+	case backupKDFPassword:
+		if password == "" {
+			return nil, ErrBackupPasswordRequired
+		}
+		if enc.Time == 0 || enc.MemoryKiB == 0 || enc.Threads == 0 {
+			return nil, fmt.Errorf("%w: parameter argon2id tidak lengkap", ErrBackupDecryption)
+		}
+		return argon2.IDKey([]byte(password), enc.Salt, enc.Time, enc.MemoryKiB, enc.Threads, config.BackupKeySize), nil
+	case backupKDFKey:
+		if len(serverKey) == 0 {
+			return nil, fmt.Errorf("%w: file dienkripsi dengan kunci server, tetapi BACKUP_ENCRYPTION_KEY belum diatur", ErrBackupDecryption)
+		}
+		key := make([]byte, config.BackupKeySize)
+		if _, err := io.ReadFull(hkdf.New(sha256.New, serverKey, enc.Salt, []byte("simdokpol-backup")), key); err != nil {
+			return nil, err
+		}
+		return key, nil
+	default:
+		return nil, fmt.Errorf("%w: metode penurunan kunci '%s' tidak dikenal", ErrBackupDecryption, enc.KDF)
+	}
+}
+
+// encryptBackupFile menulis plainPath ke containerPath dalam format .simdokpol-backup.
+func encryptBackupFile(plainPath, containerPath string, header *dto.BackupHeader, key []byte) error {
+	aead, err := newBackupAEAD(key)
+	if err != nil {
+		return err
+	}
+	headerJSON, err := json.Marshal(header)
+	if err != nil {
+		return fmt.Errorf("gagal menyusun header backup: %w", err)
+	}
+
+	plain, err := os.Open(plainPath)
+	if err != nil {
+		return err
+	}
+	defer plain.Close()
+
+	out, err := os.OpenFile(containerPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(out)
+	err = writeBackupContainer(w, bufio.NewReaderSize(plain, backupChunkSize), aead, header, headerJSON)
+	if err == nil {
+		err = w.Flush()
+	}
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(containerPath)
+		return fmt.Errorf("gagal mengenkripsi file backup: %w", err)
+	}
+	return nil
+}
+
+func writeBackupContainer(w io.Writer, plain *bufio.Reader, aead cipher.AEAD, header *dto.BackupHeader, headerJSON []byte) error {
+	if _, err := w.Write(backupContainerMagic); err != nil {
+		return err
+	}
+	if err := binary.Write(w, binary.BigEndian, uint32(len(headerJSON))); err != nil {
+		return err
+	}
+	if _, err := w.Write(headerJSON); err != nil {
+		return err
+	}
+
+	buf := make([]byte, header.Encryption.ChunkSize)
+	for counter := uint32(0); ; counter++ {
+		n, err := io.ReadFull(plain, buf)
+		if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+			return err
+		}
+		// Potongan terakhir adalah potongan yang tidak diikuti data lagi (boleh kosong).
+		_, peekErr := plain.Peek(1)
+		final := err != nil || peekErr == io.EOF
+
+		sealed := aead.Seal(nil, backupNonce(header.Encryption.NoncePrefix, counter, final), buf[:n], headerJSON)
+		if err := writeBackupChunk(w, final, sealed); err != nil {
+			return err
+		}
+		if final {
+			return nil
+		}
+		if counter == ^uint32(0) {
+			return errors.New("file backup terlalu besar")
+		}
+	}
+}
+
+func writeBackupChunk(w io.Writer, final bool, sealed []byte) error {
+	flag := byte(0)
+	if final {
+		flag = 1
+	}
+	if _, err := w.Write([]byte{flag}); err != nil {
+		return err
+	}
+	if err := binary.Write(w, binary.BigEndian, uint32(len(sealed))); err != nil {
+		return err
+	}
+	_, err := w.Write(sealed)
+	return err
+}
+
+// readBackupHeader membaca magic dan header file .simdokpol-backup dari r.
+func readBackupHeader(r io.Reader) (*dto.BackupHeader, []byte, error) {
+	magic := make([]byte, len(backupContainerMagic))
+	if _, err := io.ReadFull(r, magic); err != nil || !bytes.Equal(magic, backupContainerMagic) {
+		return nil, nil, fmt.Errorf("%w: file bukan backup terenkripsi SIMDOKPOL", ErrIncompatibleBackup)
+	}
+	var size uint32
+	if err := binary.Read(r, binary.BigEndian, &size); err != nil || size == 0 || size > maxBackupHeaderSize {
+		return nil, nil, fmt.Errorf("%w: header backup rusak", ErrIncompatibleBackup)
+	}
+	headerJSON := make([]byte, size)
+	if _, err := io.ReadFull(r, headerJSON); err != nil {
+		return nil, nil, fmt.Errorf("%w: header backup terpotong", ErrIncompatibleBackup)
+	}
+
+	var header dto.BackupHeader
+	if err := json.Unmarshal(headerJSON, &header); err != nil {
+		return nil, nil, fmt.Errorf("%w: header backup rusak", ErrIncompatibleBackup)
+	}
+	if header.Format != backupContainerFormat {
+		return nil, nil, fmt.Errorf("%w: format backup versi %d tidak dikenal, perbarui aplikasi terlebih dahulu", ErrIncompatibleBackup, header.Format)
+	}
+	enc := header.Encryption
+	if enc.ChunkSize <= 0 || enc.ChunkSize > 16*backupChunkSize || len(enc.NoncePrefix) != backupNoncePrefixSize {
+		return nil, nil, fmt.Errorf("%w: parameter enkripsi di header tidak valid", ErrIncompatibleBackup)
+	}
+	if enc.Time > maxBackupArgonTime || enc.MemoryKiB > maxBackupArgonMemory || enc.Threads > maxBackupArgonThreads {
+		return nil, nil, fmt.Errorf("%w: parameter argon2id di header melebihi batas yang diizinkan", ErrIncompatibleBackup)
+	}
+	return &header, headerJSON, nil
+}
+
+// decryptBackup mendekripsi potongan setelah header ke w dan memastikan checksum database cocok
+// dengan header. Kegagalan autentikasi berarti kata sandi atau kunci salah, atau file telah berubah.
+func decryptBackup(r io.Reader, w io.Writer, header *dto.BackupHeader, headerJSON []byte, key []byte) error {
+	aead, err := newBackupAEAD(key)
+	if err != nil {
+		return err
+	}
+
+	hash := sha256.New()
+	out := io.MultiWriter(w, hash)
+	var written int64
+	maxSealed := uint32(header.Encryption.ChunkSize + aead.Overhead())
+	sealed := make([]byte, maxSealed)
+
+	for counter := uint32(0); ; counter++ {
+		var prefix [5]byte
+		if _, err := io.ReadFull(r, prefix[:]); err != nil {
+			return fmt.Errorf("%w: file backup terpotong", ErrBackupDecryption)
+		}
+		final := prefix[0] == 1
+		size := binary.BigEndian.Uint32(prefix[1:])
+		if prefix[0] > 1 || size < uint32(aead.Overhead()) || size > maxSealed {
+			return fmt.Errorf("%w: struktur file backup rusak", ErrBackupDecryption)
+		}
+		if _, err := io.ReadFull(r, sealed[:size]); err != nil {
+			return fmt.Errorf("%w: file backup terpotong", ErrBackupDecryption)
+		}
+
+		plain, err := aead.Open(sealed[:0], backupNonce(header.Encryption.NoncePrefix, counter, final), sealed[:size], headerJSON)
+		if err != nil {
+			return fmt.Errorf("%w: kata sandi atau kunci salah, atau file telah berubah", ErrBackupDecryption)
+		}
+		if _, err := out.Write(plain); err != nil {
+			return err
+		}
+		written += int64(len(plain))
+		if final {
+			break
+		}
+	}
+
+	if written != header.Size || hex.EncodeToString(hash.Sum(nil)) != header.SHA256 {
+		return fmt.Errorf("%w: checksum database tidak cocok dengan header", ErrBackupDecryption)
+	}
+	return nil
+}
+
+func newBackupAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func backupNonce(prefix []byte, counter uint32, final bool) []byte {
+	nonce := make([]byte, 12)
+	copy(nonce, prefix)
+	binary.BigEndian.PutUint32(nonce[backupNoncePrefixSize:], counter)
+	if final {
+		nonce[11] = 1
+	}
+	return nonce
+}
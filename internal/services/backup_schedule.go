@@ -0,0 +1,159 @@
+package services
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// BackupSchedule adalah jadwal backup otomatis berformat cron lima kolom:
+// menit (0-59), jam (0-23), tanggal (1-31), bulan (1-12), dan hari (0-6, 0 = Minggu).
+// Setiap kolom menerima *, angka, rentang (1-5), daftar (1,15), dan langkah (*/15).
+type BackupSchedule struct {
+	minute, hour, dom, month, dow uint64 // Bit ke-n menyala bila nilai n cocok
+	domAny, dowAny                bool
+}
+
+// backupScheduleAliases adalah singkatan jadwal yang umum dipakai.
+var backupScheduleAliases = map[string]string{
+	"@hourly":  "0 * * * *",
+	"@daily":   "0 0 * * *",
+	"@weekly":  "0 0 * * 0",
+	"@monthly": "0 0 1 * *",
+}
+
+// backupScheduleSearchYears membatasi pencarian waktu berikutnya, misalnya untuk 30 Februari.
+const backupScheduleSearchYears = 5
+
+// ParseBackupSchedule mengurai ekspresi jadwal. Ekspresi yang tidak valid atau tidak pernah
+// terjadi dibungkus dengan ErrInvalidBackupSchedule.
+func ParseBackupSchedule(expr string) (*BackupSchedule, error) {
+	expr = strings.TrimSpace(expr)
+	if alias, ok := backupScheduleAliases[expr]; ok {
+		expr = alias
+	}
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("%w: harus terdiri dari 5 kolom (menit jam tanggal bulan hari)", ErrInvalidBackupSchedule)
+	}
+
+	schedule := &BackupSchedule{domAny: fields[2] == "*", dowAny: fields[4] == "*"}
+	specs := []struct {
+		name     string
+		min, max int
+		target   *uint64
+	}{
+		{"menit", 0, 59, &schedule.minute},
+		{"jam", 0, 23, &schedule.hour},
+		{"tanggal", 1, 31, &schedule.dom},
+		{"bulan", 1, 12, &schedule.month},
+		{"hari", 0, 7, &schedule.dow},
+	}
+	for i, spec := range specs {
+		bits, err := parseScheduleField(fields[i], spec.min, spec.max)
+		if err != nil {
+			return nil, fmt.Errorf("%w: kolom %s %v", ErrInvalidBackupSchedule, spec.name, err)
+		}
+		*spec.target = bits
+	}
+	// Hari 7 sama dengan 0 (Minggu).
+	if schedule.dow&(1<<7) != 0 {
+		schedule.dow |= 1
+	}
+
+	if schedule.Next(time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)).IsZero() {
+		return nil, fmt.Errorf("%w: tanggal pada jadwal tidak pernah terjadi", ErrInvalidBackupSchedule)
+	}
+	return schedule, nil
+}
+
+func parseScheduleField(field string, min, max int) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepPart)
+			if err != nil || n < 1 {
+				return 0, fmt.Errorf("memiliki langkah '%s' yang tidak valid", stepPart)
+			}
+			step = n
+		}
+
+		start, end := min, max
+		switch {
+		case rangePart == "*":
+		case strings.Contains(rangePart, "-"):
+			from, to, _ := strings.Cut(rangePart, "-")
+			var err error
+			if start, err = strconv.Atoi(from); err != nil {
+				return 0, fmt.Errorf("memiliki nilai '%s' yang tidak valid", from)
+			}
+			if end, err = strconv.Atoi(to); err != nil {
+				return 0, fmt.Errorf("memiliki nilai '%s' yang tidak valid", to)
+			}
+		default:
+			n, err := strconv.Atoi(rangePart)
+			if err != nil {
+				return 0, fmt.Errorf("memiliki nilai '%s' yang tidak valid", rangePart)
+			}
+			start = n
+			if !hasStep {
+				end = n
+			}
+		}
+		if start < min || end > max || start > end {
+			return 0, fmt.Errorf("harus berada di antara %d dan %d", min, max)
+		}
+		for v := start; v <= end; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+// Next mengembalikan menit pertama setelah after yang cocok dengan jadwal, di zona waktu after.
+// Nilai nol dikembalikan bila tidak ada waktu yang cocok dalam beberapa tahun ke depan.
+func (s *BackupSchedule) Next(after time.Time) time.Time {
+	loc := after.Location()
+	t := time.Date(after.Year(), after.Month(), after.Day(), after.Hour(), after.Minute(), 0, 0, loc).Add(time.Minute)
+	limit := after.Year() + backupScheduleSearchYears
+
+	for t.Year() <= limit {
+		if s.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if !s.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if s.hour&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+			continue
+		}
+		if s.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+// dayMatches mengikuti aturan cron: bila tanggal dan hari sama-sama dibatasi, cukup salah satu yang cocok.
+func (s *BackupSchedule) dayMatches(t time.Time) bool {
+	domMatch := s.dom&(1<<uint(t.Day())) != 0
+	dowMatch := s.dow&(1<<uint(t.Weekday())) != 0
+	switch {
+	case s.domAny && s.dowAny:
+		return true
+	case s.domAny:
+		return dowMatch
+	case s.dowAny:
+		return domMatch
+	default:
+		return domMatch || dowMatch
+	}
+}
@@ -0,0 +1,45 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseBackupSchedule_Next(t *testing.T) {
+	wib := time.FixedZone("WIB", 7*60*60)
+	// Rabu, 15 Mei 2024 10.20 WIB
+	after := time.Date(2024, 5, 15, 10, 20, 30, 0, wib)
+
+	testCases := []struct {
+		name string
+		expr string
+		want time.Time
+	}{
+		{"Setiap Hari Pukul 02.00", "0 2 * * *", time.Date(2024, 5, 16, 2, 0, 0, 0, wib)},
+		{"Hari Ini Masih Terkejar", "30 12 * * 1-5", time.Date(2024, 5, 15, 12, 30, 0, 0, wib)},
+		{"Setiap 15 Menit", "*/15 * * * *", time.Date(2024, 5, 15, 10, 30, 0, 0, wib)},
+		{"Akhir Pekan", "0 8 * * 6,0", time.Date(2024, 5, 18, 8, 0, 0, 0, wib)},
+		{"Minggu Ditulis 7", "0 8 * * 7", time.Date(2024, 5, 19, 8, 0, 0, 0, wib)},
+		{"Tanggal Atau Hari", "0 0 1 * 5", time.Date(2024, 5, 17, 0, 0, 0, 0, wib)},
+		{"Bulanan", "@monthly", time.Date(2024, 6, 1, 0, 0, 0, 0, wib)},
+		{"Tahun Kabisat", "0 0 29 2 *", time.Date(2028, 2, 29, 0, 0, 0, 0, wib)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			schedule, err := ParseBackupSchedule(tc.expr)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, schedule.Next(after))
+		})
+	}
+}
+
+func TestParseBackupSchedule_Invalid(t *testing.T) {
+	for _, expr := range []string{"", "0 2 * *", "60 2 * * *", "0 24 * * *", "0 2 0 * *", "0 2 * 13 *", "0 2 * * 8", "5-1 * * * *", "*/0 * * * *", "a 2 * * *", "0 0 30 2 *"} {
+		_, err := ParseBackupSchedule(expr)
+		assert.ErrorIs(t, err, ErrInvalidBackupSchedule, "ekspresi %q", expr)
+	}
+}
@@ -0,0 +1,330 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"simdokpol/internal/dto"
+	"simdokpol/internal/models"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gen2brain/beeep"
+)
+
+// DefaultBackupScheduleTick adalah jeda pemeriksaan jadwal backup otomatis.
+const DefaultBackupScheduleTick = time.Minute
+
+// Jumlah generasi backup terjadwal yang disimpan bila belum diatur di Pengaturan.
+const (
+	DefaultBackupKeepDaily   = 7
+	DefaultBackupKeepWeekly  = 4
+	DefaultBackupKeepMonthly = 12
+)
+
+const (
+	manualBackupPrefix    = "backup-simdokpol-"
+	scheduledBackupPrefix = "backup-simdokpol-auto-"
+	backupFileExt         = ".db"
+	backupTimestampLayout = "2006-01-02_15-04-05"
+)
+
+func (s *backupService) Start(ctx context.Context, tick time.Duration) {
+	if tick <= 0 {
+		tick = DefaultBackupScheduleTick
+	}
+
+	// Jadwal yang terlewat selama aplikasi mati dijalankan sekali begitu aplikasi menyala.
+	last := s.now()
+	if newest, ok := s.newestScheduledBackup(); ok {
+		last = newest
+	}
+
+	ticker := time.NewTicker(tick)
+	defer ticker.Stop()
+
+	for {
+		now := s.now()
+		if s.backupDue(last, now) {
+			s.runScheduledBackup()
+		}
+		last = now
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *backupService) ListBackups() (*dto.BackupList, error) {
+	dir, err := s.backupDir()
+	if err != nil {
+		return nil, err
+	}
+	files, err := listBackupFiles(dir)
+	if err != nil {
+		return nil, fmt.Errorf("gagal membaca folder backup '%s': %w", dir, err)
+	}
+	return &dto.BackupList{
+		Directory:     dir,
+		KeyConfigured: len(s.cfg.BackupEncryptionKey) > 0,
+		Schedule:      s.scheduleStatus(),
+		Files:         files,
+	}, nil
+}
+
+// loadSchedule membaca jadwal dari Pengaturan; schedule bernilai nil bila backup otomatis nonaktif.
+func (s *backupService) loadSchedule() (*BackupSchedule, *dto.AppConfig, error) {
+	appConfig, err := s.configService.GetConfig()
+	if err != nil {
+		return nil, nil, err
+	}
+	if strings.TrimSpace(appConfig.BackupSchedule) == "" {
+		return nil, appConfig, nil
+	}
+	schedule, err := ParseBackupSchedule(appConfig.BackupSchedule)
+	return schedule, appConfig, err
+}
+
+// backupDue memeriksa apakah ada waktu terjadwal di antara last (eksklusif) dan now (inklusif).
+func (s *backupService) backupDue(last, now time.Time) bool {
+	schedule, _, err := s.loadSchedule()
+	if err != nil || schedule == nil {
+		return false
+	}
+	loc, _ := s.configService.GetLocation()
+	next := schedule.Next(last.In(loc))
+	return !next.IsZero() && !next.After(now)
+}
+
+// runScheduledBackup membuat backup terjadwal lalu merotasi backup terjadwal lama. Kegagalan
+// dicatat di status, log, dan notifikasi desktop karena tidak ada pengguna yang menunggu hasilnya.
+func (s *backupService) runScheduledBackup() {
+	s.scheduleMu.Lock()
+	defer s.scheduleMu.Unlock()
+
+	s.statusMu.Lock()
+	s.status.Running = true
+	s.statusMu.Unlock()
+
+	backupPath, _, err := s.createBackup(models.SystemUserID, scheduledBackupPrefix, "")
+	pruned := 0
+	if err == nil {
+		pruned, err = s.pruneScheduledBackups()
+		if err != nil {
+			err = fmt.Errorf("backup %s berhasil dibuat, tetapi backup lama gagal dirotasi: %w", filepath.Base(backupPath), err)
+		}
+	}
+
+	now := s.now()
+	s.statusMu.Lock()
+	s.status.Running = false
+	s.status.LastRunAt = &now
+	s.status.LastFile = ""
+	if backupPath != "" {
+		s.status.LastFile = filepath.Base(backupPath)
+	}
+	s.status.LastPruned = pruned
+	s.status.LastError = ""
+	if err != nil {
+		s.status.LastError = err.Error()
+	}
+	s.statusMu.Unlock()
+
+	if err != nil {
+		log.Printf("ERROR: Backup terjadwal gagal: %v", err)
+		if notifyErr := s.notify("SIMDOKPOL: Backup Terjadwal Gagal", err.Error()); notifyErr != nil {
+			log.Printf("PERINGATAN: Gagal menampilkan notifikasi: %v", notifyErr)
+		}
+	}
+}
+
+// pruneScheduledBackups menghapus backup terjadwal yang tidak termasuk generasi harian, mingguan,
+// atau bulanan yang disimpan. Backup manual tidak pernah dihapus.
+func (s *backupService) pruneScheduledBackups() (int, error) {
+	dir, err := s.backupDir()
+	if err != nil {
+		return 0, err
+	}
+	appConfig, err := s.configService.GetConfig()
+	if err != nil {
+		return 0, err
+	}
+	files, err := listBackupFiles(dir)
+	if err != nil {
+		return 0, err
+	}
+
+	var scheduled []dto.BackupFile
+	for _, file := range files {
+		if file.Scheduled {
+			scheduled = append(scheduled, file)
+		}
+	}
+	loc, _ := s.configService.GetLocation()
+	keep := selectBackupsToKeep(scheduled, appConfig.BackupKeepDaily, appConfig.BackupKeepWeekly, appConfig.BackupKeepMonthly, loc)
+
+	var removed []string
+	for _, file := range scheduled {
+		if keep[file.Name] {
+			continue
+		}
+		path := filepath.Join(dir, file.Name)
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			err = fmt.Errorf("gagal menghapus %s: %w", file.Name, err)
+			s.logPruned(removed)
+			return len(removed), err
+		}
+		os.Remove(backupManifestPath(path))
+		removed = append(removed, file.Name)
+	}
+	s.logPruned(removed)
+	return len(removed), nil
+}
+
+func (s *backupService) logPruned(removed []string) {
+	if len(removed) == 0 {
+		return
+	}
+	s.auditService.LogActivity(models.SystemUserID, models.AuditBackupPruned,
+		fmt.Sprintf("Menghapus %d backup terjadwal lama: %s", len(removed), strings.Join(removed, ", ")))
+}
+
+// selectBackupsToKeep memilih backup yang disimpan menurut rotasi GFS: backup terbaru dari setiap
+// hari, minggu, dan bulan terakhir sebanyak jumlah generasi masing-masing. Backup paling baru
+// selalu disimpan. files harus berurutan dari yang terbaru.
+func selectBackupsToKeep(files []dto.BackupFile, daily, weekly, monthly int, loc *time.Location) map[string]bool {
+	keep := make(map[string]bool)
+	if len(files) > 0 {
+		keep[files[0].Name] = true
+	}
+
+	tiers := []struct {
+		count  int
+		period func(t time.Time) string
+	}{
+		{daily, func(t time.Time) string { return t.Format("2006-01-02") }},
+		{weekly, func(t time.Time) string {
+			year, week := t.ISOWeek()
+			return fmt.Sprintf("%d-W%02d", year, week)
+		}},
+		{monthly, func(t time.Time) string { return t.Format("2006-01") }},
+	}
+	for _, tier := range tiers {
+		seen := make(map[string]bool)
+		for _, file := range files {
+			period := tier.period(file.CreatedAt.In(loc))
+			if seen[period] {
+				continue
+			}
+			if len(seen) >= tier.count {
+				break
+			}
+			seen[period] = true
+			keep[file.Name] = true
+		}
+	}
+	return keep
+}
+
+// newestScheduledBackup mengembalikan waktu backup terjadwal terakhir di folder backup.
+func (s *backupService) newestScheduledBackup() (time.Time, bool) {
+	dir, err := s.backupDir()
+	if err != nil {
+		return time.Time{}, false
+	}
+	files, err := listBackupFiles(dir)
+	if err != nil {
+		return time.Time{}, false
+	}
+	for _, file := range files {
+		if file.Scheduled {
+			return file.CreatedAt, true
+		}
+	}
+	return time.Time{}, false
+}
+
+func (s *backupService) scheduleStatus() dto.BackupScheduleStatus {
+	s.statusMu.RLock()
+	status := s.status
+	s.statusMu.RUnlock()
+
+	schedule, appConfig, err := s.loadSchedule()
+	if appConfig != nil {
+		status.Schedule = appConfig.BackupSchedule
+		status.KeepDaily = appConfig.BackupKeepDaily
+		status.KeepWeekly = appConfig.BackupKeepWeekly
+		status.KeepMonthly = appConfig.BackupKeepMonthly
+	}
+	if err == nil && schedule != nil {
+		loc, _ := s.configService.GetLocation()
+		if next := schedule.Next(s.now().In(loc)); !next.IsZero() {
+			status.Enabled = true
+			status.NextRunAt = &next
+		}
+	}
+	return status
+}
+
+// listBackupFiles mengembalikan file backup di dir, yang terbaru lebih dulu. Folder yang belum
+// dibuat dianggap kosong.
+func listBackupFiles(dir string) ([]dto.BackupFile, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []dto.BackupFile{}, nil
+		}
+		return nil, err
+	}
+
+	files := []dto.BackupFile{}
+	for _, entry := range entries {
+		name := entry.Name()
+		encrypted := strings.HasSuffix(name, backupContainerExt)
+		if entry.IsDir() || !strings.HasPrefix(name, manualBackupPrefix) || !(encrypted || strings.HasSuffix(name, backupFileExt)) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		file := dto.BackupFile{
+			Name:      name,
+			Size:      info.Size(),
+			CreatedAt: info.ModTime(),
+			Scheduled: strings.HasPrefix(name, scheduledBackupPrefix),
+			Encrypted: encrypted,
+		}
+		if manifest, err := readBackupManifest(filepath.Join(dir, name)); err == nil {
+			file.SHA256 = manifest.SHA256
+			file.CreatedAt = manifest.CreatedAt
+		}
+		files = append(files, file)
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].CreatedAt.After(files[j].CreatedAt) })
+	return files, nil
+}
+
+func readBackupManifest(backupPath string) (*dto.BackupManifest, error) {
+	content, err := os.ReadFile(backupManifestPath(backupPath))
+	if err != nil {
+		return nil, err
+	}
+	var manifest dto.BackupManifest
+	if err := json.Unmarshal(content, &manifest); err != nil {
+		return nil, err
+	}
+	return &manifest, nil
+}
+
+// notifyDesktop menampilkan notifikasi desktop dengan ikon aplikasi.
+func notifyDesktop(title, message string) error {
+	return beeep.Notify(title, message, "web/static/img/icon.png")
+}
@@ -1,31 +1,77 @@
 package services
 
 import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 	"simdokpol/internal/config"
+	"simdokpol/internal/database"
+	"simdokpol/internal/dto"
 	"simdokpol/internal/models"
 	"strings"
+	"sync"
 	"time"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
 )
 
 type BackupService interface {
-	CreateBackup(actorID uint) (backupPath string, err error)
-	RestoreBackup(uploadedFile io.Reader, actorID uint) error
+	// CreateBackup menyalin database yang sedang berjalan ke folder backup, memeriksa integritas
+	// salinannya, dan menulis manifest SHA-256 di sampingnya. Salinan yang gagal diperiksa dihapus
+	// dan ErrBackupVerification dikembalikan. Bila password diisi, atau kunci enkripsi backup diatur,
+	// salinan disimpan terenkripsi sebagai file .simdokpol-backup.
+	CreateBackup(actorID uint, password string) (backupPath string, manifest *dto.BackupManifest, err error)
+	// RestoreBackup menggantikan database aktif dengan file backup setelah memastikan file tersebut
+	// database SIMDOKPOL yang utuh dan skemanya dapat dimigrasikan ke versi aplikasi ini. File yang
+	// ditolak menghasilkan ErrBackupVerification atau ErrIncompatibleBackup. File .simdokpol-backup
+	// didekripsi lebih dulu dengan password atau kunci enkripsi backup sesuai header-nya.
+	RestoreBackup(uploadedFile io.Reader, password string, actorID uint) error
+	// ListBackups menampilkan file backup di folder backup beserta status backup terjadwal.
+	ListBackups() (*dto.BackupList, error)
+	// Start memeriksa jadwal backup otomatis setiap tick sampai ctx dibatalkan, menjalankan backup
+	// yang jatuh tempo, lalu merotasi backup terjadwal lama. Dipanggil sebagai goroutine.
+	Start(ctx context.Context, tick time.Duration)
 }
 
 type backupService struct {
-	cfg           *config.Config
-	configService ConfigService
-	auditService  AuditLogService
+	db               *gorm.DB
+	pool             *database.Pool
+	cfg              *config.Config
+	configService    ConfigService
+	auditService     AuditLogService
+	migrationsSource string
+	now              func() time.Time
+	notify           func(title, message string) error
+
+	restoreMu  sync.Mutex // Mencegah dua restore berjalan bersamaan
+	scheduleMu sync.Mutex // Mencegah dua backup terjadwal berjalan bersamaan
+	statusMu   sync.RWMutex
+	status     dto.BackupScheduleStatus
 }
 
-func NewBackupService(cfg *config.Config, configService ConfigService, auditService AuditLogService) BackupService {
+// NewBackupService membuat BackupService. pool harus koneksi yang dipakai db, karena restore
+// menutup dan membukanya kembali saat mengganti file database.
+func NewBackupService(db *gorm.DB, pool *database.Pool, cfg *config.Config, configService ConfigService, auditService AuditLogService) BackupService {
 	return &backupService{
-		cfg:           cfg,
-		configService: configService,
-		auditService:  auditService,
+		db:               db,
+		pool:             pool,
+		cfg:              cfg,
+		configService:    configService,
+		auditService:     auditService,
+		migrationsSource: database.MigrationsSource,
+		now:              time.Now,
+		notify:           notifyDesktop,
 	}
 }
 
@@ -34,87 +80,427 @@ func (s *backupService) getCleanDBPath() string {
 	return dsnParts[0]
 }
 
-func (s *backupService) CreateBackup(actorID uint) (string, error) {
-	sourcePath := s.getCleanDBPath()
+func (s *backupService) CreateBackup(actorID uint, password string) (string, *dto.BackupManifest, error) {
+	return s.createBackup(actorID, manualBackupPrefix, password)
+}
+
+// createBackup membuat backup di folder backup dengan nama <prefix><waktu>.db, atau
+// <prefix><waktu>.simdokpol-backup bila backup dienkripsi.
+func (s *backupService) createBackup(actorID uint, prefix string, password string) (string, *dto.BackupManifest, error) {
+	encryption, key, err := newBackupEncryption(password, s.cfg.BackupEncryptionKey)
+	if err != nil {
+		return "", nil, err
+	}
+
+	backupDir, err := s.backupDir()
+	if err != nil {
+		return "", nil, err
+	}
+
+	if err := os.MkdirAll(backupDir, 0755); err != nil {
+		return "", nil, fmt.Errorf("gagal membuat direktori backup di '%s': %w", backupDir, err)
+	}
+
+	timestamp := s.now().Format(backupTimestampLayout)
+	destinationPath := filepath.Join(backupDir, prefix+timestamp+backupFileExt)
+
+	manifest, err := s.snapshot(destinationPath)
+	if err != nil {
+		// Salinan yang tidak lolos pemeriksaan tidak boleh tertinggal dan dikira backup yang sah.
+		os.Remove(destinationPath)
+		return "", nil, err
+	}
+	if encryption != nil {
+		// Salinan polos hanya hidup selama enkripsi berlangsung.
+		plainPath := destinationPath
+		destinationPath = filepath.Join(backupDir, prefix+timestamp+backupContainerExt)
+		manifest, err = sealBackup(plainPath, destinationPath, manifest, encryption, key)
+		os.Remove(plainPath)
+		if err != nil {
+			return "", nil, err
+		}
+	}
+	if err := writeBackupManifest(destinationPath, manifest); err != nil {
+		os.Remove(destinationPath)
+		os.Remove(backupManifestPath(destinationPath))
+		return "", nil, err
+	}
 
-	if _, err := os.Stat(sourcePath); os.IsNotExist(err) {
-		return "", fmt.Errorf("file database sumber tidak ditemukan di: %s (path asli dari DSN: %s)", sourcePath, s.cfg.DBDSN)
+	detail := fmt.Sprintf("Membuat file backup baru: %s (SHA-256 %s)", destinationPath, manifest.SHA256)
+	if encryption != nil {
+		detail = fmt.Sprintf("Membuat file backup terenkripsi baru: %s (SHA-256 %s, kunci dari %s)", destinationPath, manifest.SHA256, backupKeySource(encryption))
 	}
+	s.auditService.LogActivity(actorID, models.AuditBackupCreated, detail)
 
-	appConfig, err := s.configService.GetConfig()
+	return destinationPath, manifest, nil
+}
+
+// sealBackup mengenkripsi salinan database yang sudah diperiksa ke containerPath dan mengembalikan
+// manifest untuk file terenkripsinya.
+func sealBackup(plainPath, containerPath string, plain *dto.BackupManifest, encryption *dto.BackupEncryption, key []byte) (*dto.BackupManifest, error) {
+	header := &dto.BackupHeader{
+		Format:        backupContainerFormat,
+		AppVersion:    config.AppVersion,
+		SchemaVersion: plain.SchemaVersion,
+		CreatedAt:     plain.CreatedAt,
+		Size:          plain.Size,
+		SHA256:        plain.SHA256,
+		Encryption:    *encryption,
+	}
+	if err := encryptBackupFile(plainPath, containerPath, header, key); err != nil {
+		return nil, err
+	}
+	checksum, size, err := fileSHA256(containerPath)
+	if err != nil {
+		os.Remove(containerPath)
+		return nil, fmt.Errorf("gagal menghitung checksum file backup: %w", err)
+	}
+	sealed := *plain
+	sealed.File = filepath.Base(containerPath)
+	sealed.Size = size
+	sealed.SHA256 = checksum
+	sealed.Encrypted = true
+	return &sealed, nil
+}
+
+func backupKeySource(encryption *dto.BackupEncryption) string {
+	if encryption.KDF == backupKDFPassword {
+		return "kata sandi"
+	}
+	return "kunci server"
+}
+
+func (s *backupService) backupDir() (string, error) {
+	return backupDirectory(s.configService)
+}
+
+// backupDirectory mengembalikan folder backup dari Pengaturan.
+func backupDirectory(configService ConfigService) (string, error) {
+	appConfig, err := configService.GetConfig()
 	if err != nil {
 		return "", fmt.Errorf("gagal mendapatkan konfigurasi aplikasi: %w", err)
 	}
-	
-	backupDir := appConfig.BackupPath
-	if backupDir == "" {
-		backupDir = "./backups"
+	if appConfig.BackupPath == "" {
+		return "./backups", nil
 	}
+	return appConfig.BackupPath, nil
+}
 
-	if err := os.MkdirAll(backupDir, 0755); err != nil {
-		return "", fmt.Errorf("gagal membuat direktori backup di '%s': %w", backupDir, err)
+// snapshot menyalin database lewat VACUUM INTO. SQLite menyalin dari satu transaksi baca, sehingga
+// salinannya konsisten meskipun ada penulisan bersamaan dan ikut memuat isi file -wal yang belum
+// di-checkpoint. Menyalin file .db apa adanya bisa menghasilkan backup yang setengah tertulis.
+func (s *backupService) snapshot(destinationPath string) (*dto.BackupManifest, error) {
+	if _, err := os.Stat(destinationPath); err == nil {
+		return nil, fmt.Errorf("file backup '%s' sudah ada", destinationPath)
+	}
+	if err := s.db.Exec("VACUUM INTO ?", destinationPath).Error; err != nil {
+		return nil, fmt.Errorf("gagal menyalin database ke file backup: %w", err)
 	}
 
-	timestamp := time.Now().Format("2006-01-02_15-04-05")
-	destinationPath := fmt.Sprintf("%s/backup-simdokpol-%s.db", backupDir, timestamp)
+	schemaVersion, err := verifyBackupFile(destinationPath)
+	if err != nil {
+		return nil, err
+	}
+	checksum, size, err := fileSHA256(destinationPath)
+	if err != nil {
+		return nil, fmt.Errorf("gagal menghitung checksum file backup: %w", err)
+	}
+	return &dto.BackupManifest{
+		File:           filepath.Base(destinationPath),
+		Size:           size,
+		SHA256:         checksum,
+		SchemaVersion:  schemaVersion,
+		IntegrityCheck: "ok",
+		CreatedAt:      s.now(),
+	}, nil
+}
 
-	sourceFile, err := os.Open(sourcePath)
+// verifyBackupFile membuka file backup hanya-baca, menjalankan PRAGMA integrity_check, dan
+// mengembalikan versi migrasi skema di dalamnya.
+func verifyBackupFile(path string) (uint, error) {
+	db, err := openBackupFile(path)
 	if err != nil {
-		return "", fmt.Errorf("gagal membuka file database sumber: %w", err)
+		return 0, err
+	}
+	sqlDB, _ := db.DB()
+	defer sqlDB.Close()
+
+	var results []string
+	if err := db.Raw("PRAGMA integrity_check").Scan(&results).Error; err != nil {
+		return 0, fmt.Errorf("%w: file tidak dapat dibaca sebagai database SQLite: %v", ErrBackupVerification, err)
+	}
+	if len(results) != 1 || results[0] != "ok" {
+		return 0, fmt.Errorf("%w: integrity_check melaporkan kerusakan: %s", ErrBackupVerification, strings.Join(results, "; "))
 	}
-	defer sourceFile.Close()
 
-	destinationFile, err := os.Create(destinationPath)
+	var schemaVersion uint
+	if err := db.Raw("SELECT version FROM schema_migrations LIMIT 1").Scan(&schemaVersion).Error; err != nil {
+		return 0, fmt.Errorf("%w: tabel schema_migrations tidak ditemukan: %v", ErrBackupVerification, err)
+	}
+	return schemaVersion, nil
+}
+
+// openBackupFile membuka file backup hanya-baca dengan koneksi terpisah dari database aktif.
+func openBackupFile(path string) (*gorm.DB, error) {
+	db, err := gorm.Open(sqlite.Open("file:"+filepath.ToSlash(path)+"?mode=ro"), &gorm.Config{Logger: logger.Discard})
 	if err != nil {
-		return "", fmt.Errorf("gagal membuat file database backup: %w", err)
+		return nil, fmt.Errorf("%w: file tidak dapat dibuka sebagai database SQLite: %v", ErrBackupVerification, err)
 	}
-	defer destinationFile.Close()
+	return db, nil
+}
 
-	_, err = io.Copy(destinationFile, sourceFile)
+func fileSHA256(path string) (string, int64, error) {
+	file, err := os.Open(path)
 	if err != nil {
-		return "", fmt.Errorf("gagal menyalin data ke file backup: %w", err)
+		return "", 0, err
 	}
+	defer file.Close()
 
-	s.auditService.LogActivity(actorID, models.AuditBackupCreated, fmt.Sprintf("Membuat file backup baru: %s", destinationPath))
+	hash := sha256.New()
+	size, err := io.Copy(hash, file)
+	if err != nil {
+		return "", 0, err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), size, nil
+}
 
-	return destinationPath, nil
+// backupManifestPath mengembalikan lokasi manifest untuk sebuah file backup.
+func backupManifestPath(backupPath string) string {
+	return backupPath + ".manifest.json"
 }
 
-func (s *backupService) RestoreBackup(uploadedFile io.Reader, actorID uint) error {
+func writeBackupManifest(backupPath string, manifest *dto.BackupManifest) error {
+	content, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("gagal menyusun manifest backup: %w", err)
+	}
+	if err := os.WriteFile(backupManifestPath(backupPath), content, 0644); err != nil {
+		return fmt.Errorf("gagal menulis manifest backup: %w", err)
+	}
+	return nil
+}
+
+// RestoreBackup memasang file backup yang diunggah sebagai database aktif. File diperiksa dan
+// dimigrasikan di samping database aktif lebih dulu, sehingga database aktif tidak tersentuh bila
+// file ditolak. Database lama disisihkan sebagai <db>.before-restore-<waktu> dan dipasang kembali
+// bila database hasil restore gagal dibuka.
+func (s *backupService) RestoreBackup(uploadedFile io.Reader, password string, actorID uint) error {
+	s.restoreMu.Lock()
+	defer s.restoreMu.Unlock()
+
 	targetPath := s.getCleanDBPath()
+	timestamp := s.now().Format("20060102150405")
+	stagedPath := fmt.Sprintf("%s.restore-%s", targetPath, timestamp)
+	preRestorePath := fmt.Sprintf("%s.before-restore-%s", targetPath, timestamp)
+	defer removeDatabaseFile(stagedPath)
 
-	if _, err := os.Stat(targetPath); err == nil {
-		preRestoreBackupPath := fmt.Sprintf("%s.before-restore-%s", targetPath, time.Now().Format("20060102150405"))
-		
-		sourceFile, err := os.Open(targetPath)
-		if err != nil {
-			return fmt.Errorf("gagal membuka database saat ini untuk backup darurat: %w", err)
+	encrypted, err := stageBackupFile(uploadedFile, stagedPath, password, s.cfg.BackupEncryptionKey)
+	if err != nil {
+		return err
+	}
+	latestVersion, err := database.LatestVersion(s.migrationsSource)
+	if err != nil {
+		return fmt.Errorf("gagal membaca daftar migrasi aplikasi: %w", err)
+	}
+	fromVersion, err := checkRestoreCandidate(stagedPath, latestVersion)
+	if err != nil {
+		return err
+	}
+	if err := migrateBackupFile(stagedPath, s.cfg.DBDSN, s.migrationsSource); err != nil {
+		return fmt.Errorf("gagal menjalankan migrasi pada file backup: %w", err)
+	}
+	if _, err := verifyBackupFile(stagedPath); err != nil {
+		return err
+	}
+
+	replaced := false
+	err = s.pool.Swap(func() error {
+		if err := moveDatabaseFile(targetPath, preRestorePath); err != nil {
+			return fmt.Errorf("gagal menyisihkan database saat ini: %w", err)
+		}
+		replaced = true
+		if err := os.Rename(stagedPath, targetPath); err != nil {
+			return fmt.Errorf("gagal memasang database hasil restore: %w", err)
+		}
+		return nil
+	})
+	if err == nil {
+		err = s.checkLiveSchema(latestVersion)
+	}
+	if err != nil {
+		if !replaced {
+			return err
 		}
-		defer sourceFile.Close()
+		return s.rollbackRestore(preRestorePath, targetPath, err)
+	}
 
-		emergencyBackupFile, err := os.Create(preRestoreBackupPath)
-		if err != nil {
-			return fmt.Errorf("gagal membuat file backup darurat: %w", err)
+	s.configService.InvalidateCache()
+
+	detail := "Database dipulihkan dari file backup"
+	if encrypted {
+		detail = "Database dipulihkan dari file backup terenkripsi"
+	}
+	if fromVersion < latestVersion {
+		detail += fmt.Sprintf(" dan skemanya dimigrasikan dari versi %d ke %d", fromVersion, latestVersion)
+	}
+	detail += "."
+	s.auditService.LogActivity(actorID, models.AuditRestoreFromFile, detail)
+
+	return nil
+}
+
+// sqliteHeader adalah 16 byte pertama setiap file database SQLite 3.
+var sqliteHeader = []byte("SQLite format 3\x00")
+
+// requiredBackupTables adalah tabel yang harus ada agar sebuah file dikenali sebagai database SIMDOKPOL.
+var requiredBackupTables = []string{"users", "residents", "lost_documents", "lost_items", "configurations", "audit_logs"}
+
+// stageBackupFile menulis file backup ke stagedPath. File .simdokpol-backup didekripsi lebih dulu;
+// file lain harus diawali header SQLite. encrypted bernilai true bila file backup terenkripsi.
+func stageBackupFile(uploadedFile io.Reader, stagedPath string, password string, serverKey []byte) (encrypted bool, err error) {
+	reader := bufio.NewReader(uploadedFile)
+	magic, _ := reader.Peek(len(sqliteHeader))
+
+	var header *dto.BackupHeader
+	var headerJSON, key []byte
+	switch {
+	case bytes.Equal(magic, backupContainerMagic):
+		if header, headerJSON, err = readBackupHeader(reader); err != nil {
+			return false, err
+		}
+		if key, err = deriveBackupKey(&header.Encryption, password, serverKey); err != nil {
+			return true, err
 		}
-		defer emergencyBackupFile.Close()
+	case !bytes.Equal(magic, sqliteHeader):
+		return false, fmt.Errorf("%w: file bukan database SQLite maupun backup terenkripsi SIMDOKPOL", ErrIncompatibleBackup)
+	}
 
-		if _, err := io.Copy(emergencyBackupFile, sourceFile); err != nil {
-			return fmt.Errorf("gagal menyalin untuk backup darurat: %w", err)
+	stagedFile, err := os.OpenFile(stagedPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err != nil {
+		return header != nil, fmt.Errorf("gagal membuat file sementara: %w", err)
+	}
+	if header != nil {
+		err = decryptBackup(reader, stagedFile, header, headerJSON, key)
+	} else {
+		_, err = io.Copy(stagedFile, reader)
+	}
+	if closeErr := stagedFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		if errors.Is(err, ErrBackupDecryption) {
+			return true, err
 		}
+		return header != nil, fmt.Errorf("gagal menyalin data dari file backup: %w", err)
+	}
+	return header != nil, nil
+}
+
+// checkRestoreCandidate memastikan file adalah database SIMDOKPOL yang utuh dengan versi skema
+// yang dapat dimigrasikan ke latestVersion, lalu mengembalikan versi skemanya.
+func checkRestoreCandidate(path string, latestVersion uint) (uint, error) {
+	version, err := verifyBackupFile(path)
+	if err != nil {
+		return 0, err
+	}
+	if version > latestVersion {
+		return 0, fmt.Errorf("%w: versi skema backup (%d) lebih baru dari aplikasi ini (%d), perbarui aplikasi terlebih dahulu", ErrIncompatibleBackup, version, latestVersion)
 	}
 
-	destinationFile, err := os.Create(targetPath)
+	db, err := openBackupFile(path)
 	if err != nil {
-		return fmt.Errorf("gagal membuat/menimpa file database tujuan: %w", err)
+		return 0, err
+	}
+	sqlDB, _ := db.DB()
+	defer sqlDB.Close()
+
+	var dirty bool
+	if err := db.Raw("SELECT dirty FROM schema_migrations LIMIT 1").Scan(&dirty).Error; err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrBackupVerification, err)
+	}
+	if dirty {
+		return 0, fmt.Errorf("%w: migrasi skema versi %d di dalam backup tidak selesai", ErrIncompatibleBackup, version)
+	}
+
+	var found []string
+	if err := db.Raw("SELECT name FROM sqlite_master WHERE type = 'table' AND name IN ?", requiredBackupTables).Scan(&found).Error; err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrBackupVerification, err)
+	}
+	if len(found) != len(requiredBackupTables) {
+		return 0, fmt.Errorf("%w: file bukan database SIMDOKPOL", ErrIncompatibleBackup)
 	}
-	defer destinationFile.Close()
+	return version, nil
+}
 
-	_, err = io.Copy(destinationFile, uploadedFile)
+// migrateBackupFile menjalankan migrasi yang belum diterapkan dari sourceURL pada file database di
+// path, dengan parameter koneksi yang sama seperti liveDSN.
+func migrateBackupFile(path string, liveDSN string, sourceURL string) error {
+	dsn := path
+	if _, params, ok := strings.Cut(liveDSN, "?"); ok {
+		dsn += "?" + params
+	}
+	sqlDB, err := sql.Open(database.DriverName, dsn)
 	if err != nil {
-		return fmt.Errorf("gagal menyalin data dari file yang diunggah: %w", err)
+		return err
 	}
+	defer sqlDB.Close()
+	return database.Migrate(sqlDB, sourceURL)
+}
 
-	s.auditService.LogActivity(actorID, models.AuditRestoreFromFile, "Database dipulihkan dari file backup.")
+// checkLiveSchema memastikan database yang baru dipasang dapat dibaca dan berada di versi skema terbaru.
+func (s *backupService) checkLiveSchema(latestVersion uint) error {
+	var state struct {
+		Version uint
+		Dirty   bool
+	}
+	if err := s.db.Raw("SELECT version, dirty FROM schema_migrations LIMIT 1").Scan(&state).Error; err != nil {
+		return fmt.Errorf("database hasil restore tidak dapat dibaca: %w", err)
+	}
+	if state.Dirty || state.Version != latestVersion {
+		return fmt.Errorf("database hasil restore berada di versi skema %d, seharusnya %d", state.Version, latestVersion)
+	}
+	return nil
+}
 
+// rollbackRestore memasang kembali database lama yang disisihkan di preRestorePath.
+func (s *backupService) rollbackRestore(preRestorePath, targetPath string, cause error) error {
+	err := s.pool.Swap(func() error {
+		if err := removeDatabaseFile(targetPath); err != nil {
+			return err
+		}
+		return moveDatabaseFile(preRestorePath, targetPath)
+	})
+	if err != nil {
+		return fmt.Errorf("restore gagal (%v) dan database lama gagal dikembalikan, pulihkan manual dari %s: %w", cause, preRestorePath, err)
+	}
+	return fmt.Errorf("restore dibatalkan dan database lama dikembalikan: %w", cause)
+}
+
+// moveDatabaseFile memindahkan file database beserta journal yang tertinggal. File -shm hanya
+// berisi indeks WAL dan dibuat ulang oleh SQLite, sehingga cukup dihapus.
+func moveDatabaseFile(from, to string) error {
+	if err := os.Rename(from, to); err != nil {
+		return err
+	}
+	for _, suffix := range []string{"-wal", "-journal"} {
+		if _, err := os.Stat(from + suffix); err == nil {
+			if err := os.Rename(from+suffix, to+suffix); err != nil {
+				return err
+			}
+		}
+	}
+	if err := os.Remove(from + "-shm"); err != nil && !os.IsNotExist(err) {
+		return err
+	}
 	return nil
-}
\ No newline at end of file
+}
+
+// removeDatabaseFile menghapus file database beserta file -wal, -shm, dan -journal-nya.
+func removeDatabaseFile(path string) error {
+	for _, suffix := range []string{"", "-wal", "-shm", "-journal"} {
+		if err := os.Remove(path + suffix); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
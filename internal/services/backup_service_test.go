@@ -0,0 +1,465 @@
+package services
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"simdokpol/internal/config"
+	"simdokpol/internal/database"
+	"simdokpol/internal/dto"
+	"simdokpol/internal/mocks"
+	"simdokpol/internal/models"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestBackupService_CreateBackup(t *testing.T) {
+	dir := t.TempDir()
+	dbPath := filepath.Join(dir, "simdokpol.db")
+	pool, err := database.NewPool(dbPath + "?_journal_mode=WAL")
+	require.NoError(t, err)
+	db, err := gorm.Open(sqlite.New(sqlite.Config{Conn: pool}), &gorm.Config{Logger: logger.Discard})
+	require.NoError(t, err)
+	sqlDB, _ := db.DB()
+	defer sqlDB.Close()
+
+	// Baris ini masih berada di file -wal saat backup dibuat; salinan biasa atas file .db akan kehilangannya.
+	require.NoError(t, db.Exec("CREATE TABLE schema_migrations (version uint64, dirty bool)").Error)
+	require.NoError(t, db.Exec("INSERT INTO schema_migrations VALUES (14, false)").Error)
+	require.NoError(t, db.Exec("CREATE TABLE residents (id integer primary key, nama_lengkap text)").Error)
+	require.NoError(t, db.Exec("INSERT INTO residents (nama_lengkap) VALUES ('BUDI'), ('SITI')").Error)
+
+	configService := new(mocks.ConfigService)
+	auditService := new(mocks.AuditLogService)
+	configService.On("GetConfig").Return(&dto.AppConfig{BackupPath: filepath.Join(dir, "backups")}, nil)
+	auditService.On("LogActivity", uint(1), models.AuditBackupCreated, mock.AnythingOfType("string")).Once()
+
+	service := NewBackupService(db, pool, &config.Config{DBDSN: dbPath}, configService, auditService)
+	backupPath, manifest, err := service.CreateBackup(1, "")
+
+	require.NoError(t, err)
+	assert.Equal(t, uint(14), manifest.SchemaVersion)
+	assert.Equal(t, "ok", manifest.IntegrityCheck)
+	assert.Equal(t, filepath.Base(backupPath), manifest.File)
+
+	checksum, size, err := fileSHA256(backupPath)
+	require.NoError(t, err)
+	assert.Equal(t, checksum, manifest.SHA256)
+	assert.Equal(t, size, manifest.Size)
+
+	content, err := os.ReadFile(backupManifestPath(backupPath))
+	require.NoError(t, err)
+	var saved dto.BackupManifest
+	require.NoError(t, json.Unmarshal(content, &saved))
+	assert.Equal(t, manifest.SHA256, saved.SHA256)
+
+	backupDB, err := gorm.Open(sqlite.Open(backupPath), &gorm.Config{Logger: logger.Discard})
+	require.NoError(t, err)
+	backupSQL, _ := backupDB.DB()
+	defer backupSQL.Close()
+	var count int64
+	require.NoError(t, backupDB.Raw("SELECT COUNT(*) FROM residents").Scan(&count).Error)
+	assert.Equal(t, int64(2), count)
+
+	auditService.AssertExpectations(t)
+}
+
+func TestVerifyBackupFile_Invalid(t *testing.T) {
+	dir := t.TempDir()
+
+	notSQLite := filepath.Join(dir, "bukan-database.db")
+	require.NoError(t, os.WriteFile(notSQLite, []byte("ini bukan file SQLite, hanya teks biasa yang cukup panjang"), 0644))
+	_, err := verifyBackupFile(notSQLite)
+	assert.ErrorIs(t, err, ErrBackupVerification)
+
+	withoutSchema := filepath.Join(dir, "tanpa-migrasi.db")
+	db, err := gorm.Open(sqlite.Open(withoutSchema), &gorm.Config{Logger: logger.Discard})
+	require.NoError(t, err)
+	require.NoError(t, db.Exec("CREATE TABLE residents (id integer primary key)").Error)
+	sqlDB, _ := db.DB()
+	sqlDB.Close()
+	_, err = verifyBackupFile(withoutSchema)
+	assert.ErrorIs(t, err, ErrBackupVerification)
+}
+
+// restoreFixture menyiapkan database aktif berisi penduduk "LIVE" pada versi skema 2 dan folder
+// migrasi kecil yang tidak membutuhkan FTS5.
+type restoreFixture struct {
+	dir           string
+	livePath      string
+	db            *gorm.DB
+	service       *backupService
+	configService *mocks.ConfigService
+	auditService  *mocks.AuditLogService
+}
+
+func newRestoreFixture(t *testing.T) *restoreFixture {
+	dir := t.TempDir()
+	migrationsDir := filepath.Join(dir, "migrations")
+	require.NoError(t, os.MkdirAll(migrationsDir, 0755))
+	files := map[string]string{
+		"000001_init.up.sql": `CREATE TABLE users (id integer primary key);
+CREATE TABLE residents (id integer primary key, nama_lengkap text);
+CREATE TABLE lost_documents (id integer primary key);
+CREATE TABLE lost_items (id integer primary key);
+CREATE TABLE configurations (key text primary key, value text);
+CREATE TABLE audit_logs (id integer primary key);`,
+		"000001_init.down.sql":         "DROP TABLE residents;",
+		"000002_resident_nik.up.sql":   "ALTER TABLE residents ADD COLUMN nik text;",
+		"000002_resident_nik.down.sql": "ALTER TABLE residents DROP COLUMN nik;",
+	}
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(migrationsDir, name), []byte(content), 0644))
+	}
+	source := "file://" + filepath.ToSlash(migrationsDir)
+
+	livePath := filepath.Join(dir, "simdokpol.db")
+	dsn := livePath + "?_journal_mode=WAL"
+	pool, err := database.NewPool(dsn)
+	require.NoError(t, err)
+	db, err := gorm.Open(sqlite.New(sqlite.Config{Conn: pool}), &gorm.Config{Logger: logger.Discard})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		sqlDB, _ := db.DB()
+		sqlDB.Close()
+	})
+	sqlDB, _ := db.DB()
+	require.NoError(t, database.Migrate(sqlDB, source))
+	require.NoError(t, db.Exec("INSERT INTO residents (nama_lengkap, nik) VALUES ('LIVE', '1')").Error)
+
+	configService := new(mocks.ConfigService)
+	auditService := new(mocks.AuditLogService)
+	service := NewBackupService(db, pool, &config.Config{DBDSN: dsn}, configService, auditService).(*backupService)
+	service.migrationsSource = source
+	return &restoreFixture{dir: dir, livePath: livePath, db: db, service: service, configService: configService, auditService: auditService}
+}
+
+// backupFile membuat file database pada versi skema 1 berisi penduduk "BACKUP"; prepare dapat
+// mengubahnya sebelum isi file dikembalikan.
+func (f *restoreFixture) backupFile(t *testing.T, name string, prepare func(db *gorm.DB)) []byte {
+	path := filepath.Join(f.dir, name)
+	db, err := gorm.Open(sqlite.Open(path), &gorm.Config{Logger: logger.Discard})
+	require.NoError(t, err)
+	sqlDB, _ := db.DB()
+	require.NoError(t, database.Migrate(sqlDB, f.service.migrationsSource))
+	require.NoError(t, db.Exec("DELETE FROM schema_migrations").Error)
+	require.NoError(t, db.Exec("INSERT INTO schema_migrations (version, dirty) VALUES (1, false)").Error)
+	require.NoError(t, db.Exec("ALTER TABLE residents DROP COLUMN nik").Error)
+	require.NoError(t, db.Exec("INSERT INTO residents (nama_lengkap) VALUES ('BACKUP')").Error)
+	if prepare != nil {
+		prepare(db)
+	}
+	sqlDB.Close()
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	return content
+}
+
+func (f *restoreFixture) residentNames(t *testing.T) []string {
+	var names []string
+	require.NoError(t, f.db.Raw("SELECT nama_lengkap FROM residents ORDER BY id").Scan(&names).Error)
+	return names
+}
+
+func TestBackupService_RestoreBackup(t *testing.T) {
+	f := newRestoreFixture(t)
+	content := f.backupFile(t, "backup.db", nil)
+	f.configService.On("InvalidateCache").Once()
+	f.auditService.On("LogActivity", uint(1), models.AuditRestoreFromFile, "Database dipulihkan dari file backup dan skemanya dimigrasikan dari versi 1 ke 2.").Once()
+
+	require.NoError(t, f.service.RestoreBackup(bytes.NewReader(content), "", 1))
+
+	// Koneksi yang sama kini membaca database hasil restore yang sudah dimigrasikan ke versi terbaru.
+	assert.Equal(t, []string{"BACKUP"}, f.residentNames(t))
+	require.NoError(t, f.db.Exec("UPDATE residents SET nik = '2'").Error)
+
+	matches, err := filepath.Glob(f.livePath + ".before-restore-*")
+	require.NoError(t, err)
+	require.Len(t, matches, 1)
+	oldDB, err := gorm.Open(sqlite.Open(matches[0]), &gorm.Config{Logger: logger.Discard})
+	require.NoError(t, err)
+	var oldNames []string
+	require.NoError(t, oldDB.Raw("SELECT nama_lengkap FROM residents").Scan(&oldNames).Error)
+	oldSQL, _ := oldDB.DB()
+	oldSQL.Close()
+	assert.Equal(t, []string{"LIVE"}, oldNames)
+
+	staged, _ := filepath.Glob(f.livePath + ".restore-*")
+	assert.Empty(t, staged)
+	f.configService.AssertExpectations(t)
+	f.auditService.AssertExpectations(t)
+}
+
+func TestBackupService_RestoreBackup_Rejected(t *testing.T) {
+	testCases := []struct {
+		name    string
+		content func(f *restoreFixture, t *testing.T) []byte
+		wantErr error
+	}{
+		{
+			name: "Bukan File SQLite",
+			content: func(f *restoreFixture, t *testing.T) []byte {
+				return []byte("ini bukan file SQLite, hanya teks biasa yang cukup panjang")
+			},
+			wantErr: ErrIncompatibleBackup,
+		},
+		{
+			name: "Versi Skema Lebih Baru",
+			content: func(f *restoreFixture, t *testing.T) []byte {
+				return f.backupFile(t, "baru.db", func(db *gorm.DB) {
+					require.NoError(t, db.Exec("UPDATE schema_migrations SET version = 3").Error)
+				})
+			},
+			wantErr: ErrIncompatibleBackup,
+		},
+		{
+			name: "Migrasi Tidak Selesai",
+			content: func(f *restoreFixture, t *testing.T) []byte {
+				return f.backupFile(t, "dirty.db", func(db *gorm.DB) {
+					require.NoError(t, db.Exec("UPDATE schema_migrations SET dirty = true").Error)
+				})
+			},
+			wantErr: ErrIncompatibleBackup,
+		},
+		{
+			name: "Bukan Database SIMDOKPOL",
+			content: func(f *restoreFixture, t *testing.T) []byte {
+				return f.backupFile(t, "lain.db", func(db *gorm.DB) {
+					require.NoError(t, db.Exec("DROP TABLE lost_documents").Error)
+				})
+			},
+			wantErr: ErrIncompatibleBackup,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			f := newRestoreFixture(t)
+
+			err := f.service.RestoreBackup(bytes.NewReader(tc.content(f, t)), "", 1)
+
+			assert.ErrorIs(t, err, tc.wantErr)
+			assert.Equal(t, []string{"LIVE"}, f.residentNames(t))
+			leftovers, _ := filepath.Glob(f.livePath + ".*-*")
+			assert.Empty(t, leftovers)
+			f.configService.AssertNotCalled(t, "InvalidateCache")
+			f.auditService.AssertNotCalled(t, "LogActivity", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestBackupService_EncryptedBackup(t *testing.T) {
+	testCases := []struct {
+		name      string
+		password  string
+		serverKey []byte
+	}{
+		{name: "Kata Sandi", password: "rahasia-polsek"},
+		{name: "Kunci Server", serverKey: bytes.Repeat([]byte{7}, config.BackupKeySize)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			f := newRestoreFixture(t)
+			f.service.cfg.BackupEncryptionKey = tc.serverKey
+			f.configService.On("GetConfig").Return(&dto.AppConfig{BackupPath: filepath.Join(f.dir, "backups")}, nil)
+			f.configService.On("InvalidateCache").Once()
+			f.auditService.On("LogActivity", uint(1), models.AuditBackupCreated, mock.AnythingOfType("string")).Once()
+			f.auditService.On("LogActivity", uint(1), models.AuditRestoreFromFile, "Database dipulihkan dari file backup terenkripsi.").Once()
+
+			backupPath, manifest, err := f.service.CreateBackup(1, tc.password)
+			require.NoError(t, err)
+			assert.Equal(t, backupContainerExt, filepath.Ext(backupPath))
+			assert.True(t, manifest.Encrypted)
+			assert.NoFileExists(t, strings.TrimSuffix(backupPath, backupContainerExt)+backupFileExt)
+			checksum, _, err := fileSHA256(backupPath)
+			require.NoError(t, err)
+			assert.Equal(t, checksum, manifest.SHA256)
+
+			content, err := os.ReadFile(backupPath)
+			require.NoError(t, err)
+			header, _, err := readBackupHeader(bytes.NewReader(content))
+			require.NoError(t, err)
+			assert.Equal(t, config.AppVersion, header.AppVersion)
+			assert.Equal(t, uint(2), header.SchemaVersion)
+			assert.NotContains(t, string(content), "LIVE")
+
+			require.NoError(t, f.db.Exec("UPDATE residents SET nama_lengkap = 'BERUBAH'").Error)
+			require.NoError(t, f.service.RestoreBackup(bytes.NewReader(content), tc.password, 1))
+
+			assert.Equal(t, []string{"LIVE"}, f.residentNames(t))
+			f.configService.AssertExpectations(t)
+			f.auditService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestBackupService_EncryptedBackup_Rejected(t *testing.T) {
+	f := newRestoreFixture(t)
+	f.configService.On("GetConfig").Return(&dto.AppConfig{BackupPath: filepath.Join(f.dir, "backups")}, nil)
+	f.auditService.On("LogActivity", uint(1), models.AuditBackupCreated, mock.AnythingOfType("string")).Once()
+
+	_, _, err := f.service.CreateBackup(1, "pendek")
+	assert.ErrorIs(t, err, ErrWeakBackupPassword)
+
+	backupPath, _, err := f.service.CreateBackup(1, "rahasia-polsek")
+	require.NoError(t, err)
+	content, err := os.ReadFile(backupPath)
+	require.NoError(t, err)
+	_, headerJSON, err := readBackupHeader(bytes.NewReader(content))
+	require.NoError(t, err)
+	headerEnd := len(backupContainerMagic) + 4 + len(headerJSON)
+
+	tamper := func(offset int) []byte {
+		changed := bytes.Clone(content)
+		changed[offset] ^= 0x01
+		return changed
+	}
+	// withHeader mengganti header JSON beserta panjangnya, sehingga yang ditolak adalah isinya.
+	withHeader := func(from, to string) []byte {
+		changedHeader := bytes.Replace(headerJSON, []byte(from), []byte(to), 1)
+		require.NotEqual(t, headerJSON, changedHeader)
+		var buf bytes.Buffer
+		buf.Write(backupContainerMagic)
+		require.NoError(t, binary.Write(&buf, binary.BigEndian, uint32(len(changedHeader))))
+		buf.Write(changedHeader)
+		buf.Write(content[headerEnd:])
+		return buf.Bytes()
+	}
+	testCases := []struct {
+		name     string
+		content  []byte
+		password string
+		wantErr  error
+	}{
+		{name: "Tanpa Kata Sandi", content: content, wantErr: ErrBackupPasswordRequired},
+		{name: "Kata Sandi Salah", content: content, password: "kata-sandi-lain", wantErr: ErrBackupDecryption},
+		// Versi skema di header diubah sehingga tidak lagi cocok dengan additional data saat enkripsi.
+		{name: "Header Diubah", content: bytes.Replace(content, []byte(`"schema_version":2`), []byte(`"schema_version":1`), 1), password: "rahasia-polsek", wantErr: ErrBackupDecryption},
+		{name: "Isi Diubah", content: tamper(headerEnd + 100), password: "rahasia-polsek", wantErr: ErrBackupDecryption},
+		{name: "File Terpotong", content: content[:len(content)-10], password: "rahasia-polsek", wantErr: ErrBackupDecryption},
+		// Parameter argon2id yang berlebihan ditolak sebelum kunci diturunkan.
+		{name: "Memori Argon2id Berlebihan", content: withHeader(`"memory_kib":65536`, `"memory_kib":4194304`), password: "rahasia-polsek", wantErr: ErrIncompatibleBackup},
+		{name: "Iterasi Argon2id Berlebihan", content: withHeader(`"time":3`, `"time":1000`), password: "rahasia-polsek", wantErr: ErrIncompatibleBackup},
+		{name: "Thread Argon2id Berlebihan", content: withHeader(`"threads":4`, `"threads":255`), password: "rahasia-polsek", wantErr: ErrIncompatibleBackup},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := f.service.RestoreBackup(bytes.NewReader(tc.content), tc.password, 1)
+
+			assert.ErrorIs(t, err, tc.wantErr)
+			assert.Equal(t, []string{"LIVE"}, f.residentNames(t))
+			leftovers, _ := filepath.Glob(f.livePath + ".*-*")
+			assert.Empty(t, leftovers)
+		})
+	}
+	f.configService.AssertNotCalled(t, "InvalidateCache")
+}
+
+func TestSelectBackupsToKeep(t *testing.T) {
+	// Satu backup setiap hari pukul 02.00 selama 40 hari sampai Rabu, 15 Mei 2024; terbaru lebih dulu.
+	newest := time.Date(2024, 5, 15, 2, 0, 0, 0, time.UTC)
+	var files []dto.BackupFile
+	for i := 0; i < 40; i++ {
+		createdAt := newest.AddDate(0, 0, -i)
+		files = append(files, dto.BackupFile{Name: createdAt.Format("2006-01-02"), CreatedAt: createdAt})
+	}
+
+	keep := selectBackupsToKeep(files, 3, 2, 2, time.UTC)
+
+	var kept []string
+	for _, file := range files {
+		if keep[file.Name] {
+			kept = append(kept, file.Name)
+		}
+	}
+	// 3 hari terakhir, terbaru dari minggu sebelumnya (Minggu 12 Mei), dan terbaru dari April.
+	assert.Equal(t, []string{"2024-05-15", "2024-05-14", "2024-05-13", "2024-05-12", "2024-04-30"}, kept)
+
+	assert.Equal(t, map[string]bool{"2024-05-15": true}, selectBackupsToKeep(files, 0, 0, 0, time.UTC))
+}
+
+func TestBackupService_RunScheduledBackup(t *testing.T) {
+	f := newRestoreFixture(t)
+	backupDir := filepath.Join(f.dir, "backups")
+	require.NoError(t, os.MkdirAll(backupDir, 0755))
+	now := time.Date(2024, 5, 15, 2, 0, 10, 0, time.UTC)
+	f.service.now = func() time.Time { return now }
+
+	// Backup terjadwal kemarin dirotasi karena hanya satu generasi harian yang disimpan,
+	// sedangkan backup manual yang lebih lama tetap dipertahankan.
+	oldScheduled := filepath.Join(backupDir, "backup-simdokpol-auto-2024-05-14_02-00-00.db")
+	oldManual := filepath.Join(backupDir, "backup-simdokpol-2024-05-01_09-00-00.db")
+	for _, path := range []string{oldScheduled, oldManual} {
+		require.NoError(t, os.WriteFile(path, []byte("backup lama"), 0644))
+	}
+	// Backup manual tanpa manifest diurutkan menurut waktu modifikasi file.
+	require.NoError(t, os.Chtimes(oldManual, now.AddDate(0, 0, -14), now.AddDate(0, 0, -14)))
+	require.NoError(t, writeBackupManifest(oldScheduled, &dto.BackupManifest{SHA256: "lama", CreatedAt: now.AddDate(0, 0, -1)}))
+
+	f.configService.On("GetConfig").Return(&dto.AppConfig{BackupPath: backupDir, BackupSchedule: "0 2 * * *", BackupKeepDaily: 1}, nil)
+	f.configService.On("GetLocation").Return(time.UTC, nil)
+	f.auditService.On("LogActivity", models.SystemUserID, models.AuditBackupCreated, mock.AnythingOfType("string")).Once()
+	f.auditService.On("LogActivity", models.SystemUserID, models.AuditBackupPruned, "Menghapus 1 backup terjadwal lama: backup-simdokpol-auto-2024-05-14_02-00-00.db").Once()
+	f.service.notify = func(title, message string) error {
+		t.Fatalf("notifikasi tidak diharapkan: %s", message)
+		return nil
+	}
+
+	assert.True(t, f.service.backupDue(now.Add(-time.Minute), now))
+	assert.False(t, f.service.backupDue(now, now.Add(time.Minute)))
+	f.service.runScheduledBackup()
+
+	list, err := f.service.ListBackups()
+	require.NoError(t, err)
+	require.Len(t, list.Files, 2)
+	assert.Equal(t, "backup-simdokpol-auto-2024-05-15_02-00-10.db", list.Files[0].Name)
+	assert.True(t, list.Files[0].Scheduled)
+	assert.NotEmpty(t, list.Files[0].SHA256)
+	assert.Equal(t, filepath.Base(oldManual), list.Files[1].Name)
+	assert.False(t, list.Files[1].Scheduled)
+
+	assert.Equal(t, list.Files[0].Name, list.Schedule.LastFile)
+	assert.Equal(t, 1, list.Schedule.LastPruned)
+	assert.Empty(t, list.Schedule.LastError)
+	assert.True(t, list.Schedule.Enabled)
+	assert.Equal(t, time.Date(2024, 5, 16, 2, 0, 0, 0, time.UTC), *list.Schedule.NextRunAt)
+	assert.NoFileExists(t, backupManifestPath(oldScheduled))
+	f.auditService.AssertExpectations(t)
+}
+
+func TestBackupService_RunScheduledBackup_NotifiesOnFailure(t *testing.T) {
+	f := newRestoreFixture(t)
+	// Folder backup menunjuk ke file biasa sehingga tidak dapat dibuat.
+	notADir := filepath.Join(f.dir, "bukan-folder")
+	require.NoError(t, os.WriteFile(notADir, []byte("x"), 0644))
+	f.configService.On("GetConfig").Return(&dto.AppConfig{BackupPath: notADir, BackupSchedule: "0 2 * * *"}, nil)
+	f.configService.On("GetLocation").Return(time.UTC, nil)
+
+	var notified []string
+	f.service.notify = func(title, message string) error {
+		notified = append(notified, message)
+		return nil
+	}
+
+	f.service.runScheduledBackup()
+
+	require.Len(t, notified, 1)
+	assert.Contains(t, notified[0], "gagal membuat direktori backup")
+	status := f.service.scheduleStatus()
+	assert.Equal(t, notified[0], status.LastError)
+	assert.Empty(t, status.LastFile)
+	f.auditService.AssertNotCalled(t, "LogActivity", mock.Anything, mock.Anything, mock.Anything)
+}
@@ -18,6 +18,9 @@ type ConfigService interface {
 	GetConfig() (*dto.AppConfig, error) // <-- DIUBAH
 	SaveConfig(configData map[string]string) error
 	GetLocation() (*time.Location, error)
+	// InvalidateCache membuang konfigurasi yang tersimpan di memori agar dibaca ulang dari database,
+	// misalnya setelah database dipulihkan dari backup.
+	InvalidateCache()
 }
 
 type configService struct {
@@ -36,6 +39,11 @@ func (s *configService) SaveConfig(configData map[string]string) error {
 	return s.configRepo.SetMultiple(configData)
 }
 
+func (s *configService) InvalidateCache() {
+	s.cachedLocation = nil
+	s.cachedConfig = nil
+}
+
 func (s *configService) GetLocation() (*time.Location, error) {
 	if s.cachedLocation != nil {
 		return s.cachedLocation, nil
@@ -81,6 +89,14 @@ func (s *configService) GetConfig() (*dto.AppConfig, error) { // <-- DIUBAH
 	}
 
 	archiveDays, _ := strconv.Atoi(allConfigs["archive_duration_days"])
+	retentionDays, err := strconv.Atoi(allConfigs["recycle_bin_retention_days"])
+	if err != nil || retentionDays < 1 {
+		retentionDays = DefaultRecycleBinRetentionDays
+	}
+
+	keepDaily := parseBackupKeep(allConfigs["backup_keep_daily"], DefaultBackupKeepDaily)
+	keepWeekly := parseBackupKeep(allConfigs["backup_keep_weekly"], DefaultBackupKeepWeekly)
+	keepMonthly := parseBackupKeep(allConfigs["backup_keep_monthly"], DefaultBackupKeepMonthly)
 
 	// Gunakan dto.AppConfig
 	appConfig := &dto.AppConfig{
@@ -90,13 +106,31 @@ func (s *configService) GetConfig() (*dto.AppConfig, error) { // <-- DIUBAH
 		KopBaris3:           allConfigs["kop_baris_3"],
 		NamaKantor:          allConfigs["nama_kantor"],
 		TempatSurat:         allConfigs["tempat_surat"],
+		KodeKantor:          allConfigs["kode_kantor"],
 		FormatNomorSurat:    allConfigs["format_nomor_surat"],
 		NomorSuratTerakhir:  allConfigs["nomor_surat_terakhir"],
 		ZonaWaktu:           allConfigs["zona_waktu"],
 		BackupPath:          allConfigs["backup_path"],
 		ArchiveDurationDays: archiveDays,
+		VerificationBaseURL: allConfigs["verification_base_url"],
+
+		RecycleBinRetentionDays: retentionDays,
+
+		BackupSchedule:    allConfigs["backup_schedule"],
+		BackupKeepDaily:   keepDaily,
+		BackupKeepWeekly:  keepWeekly,
+		BackupKeepMonthly: keepMonthly,
 	}
 
 	s.cachedConfig = appConfig
 	return appConfig, nil
-}
\ No newline at end of file
+}
+
+// parseBackupKeep membaca jumlah generasi backup yang disimpan; nilai kosong atau negatif diganti fallback.
+func parseBackupKeep(value string, fallback int) int {
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		return fallback
+	}
+	return n
+}
@@ -0,0 +1,140 @@
+package services
+
+import (
+	"fmt"
+	"simdokpol/internal/models"
+	"simdokpol/internal/repositories"
+	"strings"
+	"time"
+)
+
+// exportBatchSize adalah jumlah dokumen yang dimuat per query saat ekspor daftar dokumen.
+const exportBatchSize = 500
+
+// documentExportColumn adalah satu pilihan kolom ekspor daftar dokumen. Satu pilihan bisa
+// menghasilkan beberapa kolom, misalnya "resident" menjadi nama, NIK, dan alamat pemohon.
+type documentExportColumn struct {
+	headers []string
+	values  func(doc *models.LostDocument, loc *time.Location) []interface{}
+}
+
+// DocumentExportColumnKeys adalah pilihan kolom ekspor sesuai urutan bawaannya.
+var DocumentExportColumnKeys = []string{"number", "date", "resident", "items", "officers"}
+
+var documentExportColumns = map[string]documentExportColumn{
+	"number": {
+		headers: []string{"Nomor Surat", "Status"},
+		values: func(doc *models.LostDocument, _ *time.Location) []interface{} {
+			return []interface{}{doc.NomorSurat, doc.Status}
+		},
+	},
+	"date": {
+		headers: []string{"Tanggal Laporan", "Tanggal Persetujuan"},
+		values: func(doc *models.LostDocument, loc *time.Location) []interface{} {
+			approved := ""
+			if doc.TanggalPersetujuan != nil {
+				approved = doc.TanggalPersetujuan.In(loc).Format("02-01-2006 15:04")
+			}
+			return []interface{}{doc.TanggalLaporan.In(loc).Format("02-01-2006 15:04"), approved}
+		},
+	},
+	"resident": {
+		headers: []string{"Nama Pemohon", "NIK", "Alamat"},
+		values: func(doc *models.LostDocument, _ *time.Location) []interface{} {
+			nik := ""
+			if doc.Resident.NIK != nil {
+				nik = *doc.Resident.NIK
+			}
+			return []interface{}{doc.Resident.NamaLengkap, nik, doc.Resident.Alamat}
+		},
+	},
+	"items": {
+		headers: []string{"Barang Hilang", "Lokasi Hilang"},
+		values: func(doc *models.LostDocument, _ *time.Location) []interface{} {
+			items := make([]string, 0, len(doc.LostItems))
+			for _, item := range doc.LostItems {
+				if item.Deskripsi != "" {
+					items = append(items, fmt.Sprintf("%s (%s)", item.NamaBarang, item.Deskripsi))
+				} else {
+					items = append(items, item.NamaBarang)
+				}
+			}
+			return []interface{}{strings.Join(items, "; "), doc.LokasiHilang}
+		},
+	},
+	"officers": {
+		headers: []string{"Petugas Pelapor", "Pejabat Persetuju", "Operator"},
+		values: func(doc *models.LostDocument, _ *time.Location) []interface{} {
+			return []interface{}{doc.PetugasPelapor.NamaLengkap, doc.PejabatPersetuju.NamaLengkap, doc.Operator.NamaLengkap}
+		},
+	},
+}
+
+func (s *lostDocumentService) ExportDocuments(w SpreadsheetRowWriter, filter repositories.DocumentFilter, columns []string, actorID uint) (int, error) {
+	selected, err := selectExportColumns(columns)
+	if err != nil {
+		return 0, err
+	}
+	scope, err := s.visibilityScope(actorID)
+	if err != nil {
+		return 0, err
+	}
+	loc, err := s.configService.GetLocation()
+	if err != nil {
+		loc = time.UTC
+	}
+
+	var header []interface{}
+	for _, column := range selected {
+		for _, h := range column.headers {
+			header = append(header, h)
+		}
+	}
+	if err := w.WriteRow(header); err != nil {
+		return 0, err
+	}
+
+	count := 0
+	err = s.docRepo.FindInBatches(filter, scope, exportBatchSize, func(docs []models.LostDocument) error {
+		for i := range docs {
+			var row []interface{}
+			for _, column := range selected {
+				row = append(row, column.values(&docs[i], loc)...)
+			}
+			if err := w.WriteRow(row); err != nil {
+				return err
+			}
+			count++
+		}
+		return nil
+	})
+	if err != nil {
+		return count, err
+	}
+
+	s.auditService.LogActivity(actorID, models.AuditExportDocuments, fmt.Sprintf("Mengekspor %d dokumen (status: %s, kata kunci: %q)", count, filter.Status, filter.Query))
+	return count, nil
+}
+
+// selectExportColumns mengubah kunci kolom pilihan pengguna menjadi definisi kolom, tanpa duplikat.
+// Daftar kosong berarti semua kolom.
+func selectExportColumns(keys []string) ([]documentExportColumn, error) {
+	if len(keys) == 0 {
+		keys = DocumentExportColumnKeys
+	}
+	seen := map[string]bool{}
+	var selected []documentExportColumn
+	for _, key := range keys {
+		key = strings.TrimSpace(key)
+		column, ok := documentExportColumns[key]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidExportColumn, key)
+		}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		selected = append(selected, column)
+	}
+	return selected, nil
+}
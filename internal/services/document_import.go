@@ -0,0 +1,551 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"simdokpol/internal/dto"
+	"simdokpol/internal/models"
+	"simdokpol/internal/repositories"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// maxImportRows membatasi jumlah baris data per file impor agar satu transaksi tetap wajar.
+const maxImportRows = 20000
+
+// Isian dokumen yang dikenali dari judul kolom file impor.
+const (
+	importNomorSurat         = "nomor_surat"
+	importTanggalLaporan     = "tanggal_laporan"
+	importTanggalPersetujuan = "tanggal_persetujuan"
+	importJenisDokumen       = "jenis_dokumen"
+	importNIK                = "nik"
+	importNamaLengkap        = "nama_lengkap"
+	importTempatLahir        = "tempat_lahir"
+	importTanggalLahir       = "tanggal_lahir"
+	importJenisKelamin       = "jenis_kelamin"
+	importAgama              = "agama"
+	importPekerjaan          = "pekerjaan"
+	importAlamat             = "alamat"
+	importLokasiHilang       = "lokasi_hilang"
+	importBarang             = "barang"
+	importPetugasPelapor     = "petugas_pelapor"
+	importPejabatPersetuju   = "pejabat_persetuju"
+)
+
+// importColumnAliases memetakan judul kolom (sudah dinormalisasi dengan normalizeHeader) ke isian dokumen.
+// Judul kolom hasil ekspor daftar dokumen memakai nama yang sama sehingga ikut dikenali.
+var importColumnAliases = map[string]string{
+	"nomor surat":         importNomorSurat,
+	"no surat":            importNomorSurat,
+	"nomor":               importNomorSurat,
+	"tanggal laporan":     importTanggalLaporan,
+	"tgl laporan":         importTanggalLaporan,
+	"tanggal":             importTanggalLaporan,
+	"tanggal persetujuan": importTanggalPersetujuan,
+	"tgl persetujuan":     importTanggalPersetujuan,
+	"jenis dokumen":       importJenisDokumen,
+	"jenis surat":         importJenisDokumen,
+	"nik":                 importNIK,
+	"nama pemohon":        importNamaLengkap,
+	"nama lengkap":        importNamaLengkap,
+	"nama":                importNamaLengkap,
+	"tempat lahir":        importTempatLahir,
+	"tanggal lahir":       importTanggalLahir,
+	"tgl lahir":           importTanggalLahir,
+	"jenis kelamin":       importJenisKelamin,
+	"jk":                  importJenisKelamin,
+	"l/p":                 importJenisKelamin,
+	"agama":               importAgama,
+	"pekerjaan":           importPekerjaan,
+	"alamat":              importAlamat,
+	"lokasi hilang":       importLokasiHilang,
+	"lokasi":              importLokasiHilang,
+	"barang hilang":       importBarang,
+	"barang":              importBarang,
+	"petugas pelapor":     importPetugasPelapor,
+	"petugas":             importPetugasPelapor,
+	"pejabat persetuju":   importPejabatPersetuju,
+	"pejabat":             importPejabatPersetuju,
+}
+
+// importRequiredColumns harus ada di baris judul file; tanpa kolom ini tidak satu baris pun bisa diimpor.
+var importRequiredColumns = map[string]string{
+	importNomorSurat:     "Nomor Surat",
+	importTanggalLaporan: "Tanggal Laporan",
+	importNamaLengkap:    "Nama Pemohon",
+}
+
+var importDateLayouts = []string{
+	"2006-01-02 15:04:05", "2006-01-02 15:04", "2006-01-02",
+	"02-01-2006 15:04:05", "02-01-2006 15:04", "02-01-2006", "2-1-2006",
+	"02/01/2006 15:04:05", "02/01/2006 15:04", "02/01/2006", "2/1/2006",
+}
+
+// DocumentImportService mengimpor surat lama (yang sudah terbit sebelum memakai SIMDOKPOL) dari file Excel/CSV.
+type DocumentImportService interface {
+	// Import membaca file lalu memeriksa setiap baris. Tanpa commit hanya laporan pemeriksaan yang
+	// dikembalikan (dry-run). Dengan commit, semua baris disimpan dalam satu transaksi sebagai dokumen
+	// DITERBITKAN dengan Nomor Surat dan Tanggal Laporan asli; jika ada satu baris saja yang tidak valid,
+	// tidak ada yang disimpan dan ErrImportHasErrors dikembalikan bersama laporannya.
+	Import(fileName string, data []byte, actorID uint, commit bool) (*dto.ImportReport, error)
+}
+
+type documentImportService struct {
+	db              *gorm.DB
+	docRepo         repositories.LostDocumentRepository
+	residentRepo    repositories.ResidentRepository
+	userRepo        repositories.UserRepository
+	seqRepo         repositories.DocumentSequenceRepository
+	docTypeService  DocumentTypeService
+	itemTypeService ItemTypeService
+	revisionService DocumentRevisionService
+	auditRepo       repositories.AuditLogRepository
+	configService   ConfigService
+}
+
+func NewDocumentImportService(db *gorm.DB, docRepo repositories.LostDocumentRepository, residentRepo repositories.ResidentRepository, userRepo repositories.UserRepository, seqRepo repositories.DocumentSequenceRepository, docTypeService DocumentTypeService, itemTypeService ItemTypeService, revisionService DocumentRevisionService, auditRepo repositories.AuditLogRepository, configService ConfigService) DocumentImportService {
+	return &documentImportService{
+		db:              db,
+		docRepo:         docRepo,
+		residentRepo:    residentRepo,
+		userRepo:        userRepo,
+		seqRepo:         seqRepo,
+		docTypeService:  docTypeService,
+		itemTypeService: itemTypeService,
+		revisionService: revisionService,
+		auditRepo:       auditRepo,
+		configService:   configService,
+	}
+}
+
+// importRow adalah satu baris file yang sudah diurai menjadi dokumen siap simpan.
+type importRow struct {
+	line     int
+	doc      models.LostDocument
+	resident models.Resident
+}
+
+// importContext memuat data acuan yang dipakai untuk mengurai setiap baris.
+type importContext struct {
+	actor          *models.User
+	loc            *time.Location
+	columns        map[string]int
+	extraColumns   map[string]int // judul kolom ternormalisasi yang bukan isian bawaan
+	docTypes       map[string]*models.DocumentType
+	itemTypes      []models.ItemType
+	officers       map[string]*models.User
+	existingNumber map[string]bool
+}
+
+func (s *documentImportService) Import(fileName string, data []byte, actorID uint, commit bool) (*dto.ImportReport, error) {
+	actor, err := s.userRepo.FindByID(actorID)
+	if err != nil {
+		return nil, errors.New("pengguna tidak valid")
+	}
+	if !actor.HasPermission(models.PermDocumentImport) {
+		return nil, ErrAccessDenied
+	}
+
+	rows, err := ReadSpreadsheet(fileName, data)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) < 2 {
+		return nil, fmt.Errorf("%w: file harus memuat baris judul kolom dan minimal satu baris data", ErrInvalidImportFile)
+	}
+	if len(rows)-1 > maxImportRows {
+		return nil, fmt.Errorf("%w: maksimal %d baris data per file", ErrInvalidImportFile, maxImportRows)
+	}
+
+	ctx, report, err := s.prepareImport(fileName, rows[0], actor)
+	if err != nil {
+		return nil, err
+	}
+	report.DryRun = !commit
+
+	parsed := make([]importRow, 0, len(rows)-1)
+	seenNumbers := map[string]int{}
+	for i, values := range rows[1:] {
+		// Baris kosong (sering tersisa di akhir file Excel) dilewati tanpa menggeser nomor baris laporan.
+		if isEmptyRow(values) {
+			continue
+		}
+		report.TotalRows++
+		line := i + 2
+		row, messages := s.parseRow(ctx, line, values)
+		if key := strings.ToUpper(row.doc.NomorSurat); key != "" {
+			if firstLine, ok := seenNumbers[key]; ok {
+				messages = append(messages, fmt.Sprintf("Nomor Surat sama dengan baris %d", firstLine))
+			} else {
+				seenNumbers[key] = line
+			}
+		}
+		if len(messages) > 0 {
+			report.Errors = append(report.Errors, dto.ImportRowError{Row: line, NomorSurat: row.doc.NomorSurat, Messages: messages})
+			continue
+		}
+		parsed = append(parsed, row)
+	}
+	report.ValidRows = len(parsed)
+	if report.TotalRows == 0 {
+		return nil, fmt.Errorf("%w: file harus memuat baris judul kolom dan minimal satu baris data", ErrInvalidImportFile)
+	}
+
+	if !commit {
+		return report, nil
+	}
+	if len(report.Errors) > 0 {
+		return report, ErrImportHasErrors
+	}
+	if err := s.saveRows(parsed, ctx, fileName); err != nil {
+		return nil, err
+	}
+	report.Imported = len(parsed)
+	return report, nil
+}
+
+// prepareImport memetakan baris judul kolom dan memuat data acuan (jenis dokumen, petugas, Nomor Surat terpakai).
+func (s *documentImportService) prepareImport(fileName string, header []string, actor *models.User) (*importContext, *dto.ImportReport, error) {
+	loc, err := s.configService.GetLocation()
+	if err != nil {
+		loc = time.UTC
+	}
+	ctx := &importContext{
+		actor:          actor,
+		loc:            loc,
+		columns:        map[string]int{},
+		extraColumns:   map[string]int{},
+		docTypes:       map[string]*models.DocumentType{},
+		officers:       map[string]*models.User{},
+		existingNumber: map[string]bool{},
+	}
+	report := &dto.ImportReport{FileName: fileName, Columns: []dto.ImportColumn{}, IgnoredColumns: []string{}, Errors: []dto.ImportRowError{}}
+
+	docTypes, err := s.docTypeService.FindAll(false)
+	if err != nil {
+		return nil, nil, err
+	}
+	if ctx.itemTypes, err = s.itemTypeService.FindAll(false); err != nil {
+		return nil, nil, err
+	}
+	extraFieldNames := map[string]bool{}
+	for i := range docTypes {
+		docType := &docTypes[i]
+		ctx.docTypes[strings.ToUpper(docType.Kode)] = docType
+		ctx.docTypes[strings.ToUpper(docType.Nama)] = docType
+		for _, field := range docType.Fields {
+			extraFieldNames[normalizeHeader(field.Key)] = true
+			extraFieldNames[normalizeHeader(field.Label)] = true
+		}
+	}
+
+	for i, title := range header {
+		normalized := normalizeHeader(title)
+		if normalized == "" {
+			continue
+		}
+		if field, ok := importColumnAliases[normalized]; ok {
+			if _, exists := ctx.columns[field]; !exists {
+				ctx.columns[field] = i
+				report.Columns = append(report.Columns, dto.ImportColumn{Header: strings.TrimSpace(title), Field: field})
+				continue
+			}
+		}
+		if extraFieldNames[normalized] {
+			ctx.extraColumns[normalized] = i
+			report.Columns = append(report.Columns, dto.ImportColumn{Header: strings.TrimSpace(title), Field: "data_tambahan"})
+			continue
+		}
+		report.IgnoredColumns = append(report.IgnoredColumns, strings.TrimSpace(title))
+	}
+	var missing []string
+	for field, label := range importRequiredColumns {
+		if _, ok := ctx.columns[field]; !ok {
+			missing = append(missing, label)
+		}
+	}
+	sort.Strings(missing)
+	if len(missing) > 0 {
+		return nil, nil, fmt.Errorf("%w: kolom %s tidak ditemukan", ErrInvalidImportFile, strings.Join(missing, ", "))
+	}
+
+	// Petugas lama yang sudah dinonaktifkan tetap dikenali karena namanya tercantum di surat lama.
+	for _, status := range []string{"active", "inactive"} {
+		users, err := s.userRepo.FindAll(status)
+		if err != nil {
+			return nil, nil, err
+		}
+		for i := range users {
+			user := users[i]
+			ctx.officers[strings.ToUpper(strings.TrimSpace(user.NRP))] = &user
+			if _, exists := ctx.officers[strings.ToUpper(user.NamaLengkap)]; !exists {
+				ctx.officers[strings.ToUpper(user.NamaLengkap)] = &user
+			}
+		}
+	}
+
+	existing, err := s.docRepo.FindExistingNomorSurat()
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, nomor := range existing {
+		ctx.existingNumber[strings.ToUpper(nomor)] = true
+	}
+	return ctx, report, nil
+}
+
+// parseRow mengurai satu baris file. Semua kesalahan pada baris dikumpulkan agar bisa diperbaiki sekaligus.
+func (s *documentImportService) parseRow(ctx *importContext, line int, values []string) (importRow, []string) {
+	var messages []string
+	cell := func(field string) string {
+		if i, ok := ctx.columns[field]; ok && i < len(values) {
+			return strings.TrimSpace(values[i])
+		}
+		return ""
+	}
+	row := importRow{line: line}
+	doc := &row.doc
+	resident := &row.resident
+
+	doc.NomorSurat = cell(importNomorSurat)
+	if doc.NomorSurat == "" {
+		messages = append(messages, "Nomor Surat wajib diisi")
+	} else if strings.HasPrefix(strings.ToUpper(doc.NomorSurat), "DELETED_") {
+		messages = append(messages, "Nomor Surat tidak boleh diawali DELETED_")
+	} else if ctx.existingNumber[strings.ToUpper(doc.NomorSurat)] {
+		messages = append(messages, "Nomor Surat sudah terdaftar di database")
+	}
+
+	tanggalLaporan, err := parseImportDate(cell(importTanggalLaporan), ctx.loc)
+	if err != nil {
+		messages = append(messages, "Tanggal Laporan "+err.Error())
+	}
+	doc.TanggalLaporan = tanggalLaporan
+	approvedAt := tanggalLaporan
+	doc.TanggalPersetujuan = &approvedAt
+	if value := cell(importTanggalPersetujuan); value != "" {
+		if approvedAt, err = parseImportDate(value, ctx.loc); err != nil {
+			messages = append(messages, "Tanggal Persetujuan "+err.Error())
+		}
+	}
+
+	docType := ctx.docTypes[models.DocumentTypeLostDocument]
+	if value := cell(importJenisDokumen); value != "" {
+		docType = ctx.docTypes[strings.ToUpper(value)]
+	}
+	if docType == nil {
+		messages = append(messages, fmt.Sprintf("Jenis dokumen %q tidak dikenal", cell(importJenisDokumen)))
+	}
+
+	resident.NamaLengkap = strings.ToUpper(cell(importNamaLengkap))
+	resident.TempatLahir = strings.ToUpper(cell(importTempatLahir))
+	resident.Agama = cell(importAgama)
+	resident.Pekerjaan = cell(importPekerjaan)
+	resident.Alamat = strings.ToUpper(cell(importAlamat))
+	if resident.NamaLengkap == "" {
+		messages = append(messages, "Nama Pemohon wajib diisi")
+	}
+	if resident.Alamat == "" {
+		messages = append(messages, "Alamat wajib diisi")
+	}
+	// Tanggal lahir disimpan tanpa zona waktu, sama seperti isian formulir.
+	tanggalLahir, birthErr := parseImportDate(cell(importTanggalLahir), time.UTC)
+	if birthErr != nil {
+		messages = append(messages, "Tanggal Lahir "+birthErr.Error())
+	}
+	resident.TanggalLahir = time.Date(tanggalLahir.Year(), tanggalLahir.Month(), tanggalLahir.Day(), 0, 0, 0, 0, time.UTC)
+	jenisKelamin, ok := normalizeJenisKelamin(cell(importJenisKelamin))
+	if !ok {
+		messages = append(messages, "Jenis Kelamin harus Laki-laki/L atau Perempuan/P")
+	}
+	resident.JenisKelamin = jenisKelamin
+	if nik := strings.Trim(strings.ReplaceAll(cell(importNIK), " ", ""), "'"); nik != "" {
+		resident.NIK = &nik
+		if birthErr == nil && ok {
+			if err := ValidateNIK(nik, resident.TanggalLahir, jenisKelamin); err != nil {
+				messages = append(messages, err.Error())
+			}
+		}
+	}
+
+	doc.Status = models.StatusDiterbitkan
+	doc.LokasiHilang = cell(importLokasiHilang)
+	doc.LostItems = parseImportItems(cell(importBarang))
+	// Barang surat lama tidak memiliki atribut bertipe; jenisnya hanya dikenali dari nama agar ikut terhitung di statistik.
+	for i := range doc.LostItems {
+		doc.LostItems[i].JenisBarang = classifyItemName(ctx.itemTypes, doc.LostItems[i].NamaBarang)
+	}
+	doc.OperatorID = ctx.actor.ID
+	doc.PetugasPelaporID = ctx.actor.ID
+	doc.PetugasPelapor = *ctx.actor
+	if value := cell(importPetugasPelapor); value != "" {
+		if officer := ctx.officers[strings.ToUpper(value)]; officer != nil {
+			doc.PetugasPelaporID = officer.ID
+			doc.PetugasPelapor = *officer
+		} else {
+			messages = append(messages, fmt.Sprintf("Petugas pelapor %q tidak dikenal (isi dengan NRP atau nama lengkap pengguna)", value))
+		}
+	}
+	if value := cell(importPejabatPersetuju); value != "" {
+		if officer := ctx.officers[strings.ToUpper(value)]; officer != nil {
+			doc.PejabatPersetujuID = &officer.ID
+			doc.PejabatPersetuju = *officer
+		} else {
+			messages = append(messages, fmt.Sprintf("Pejabat persetuju %q tidak dikenal (isi dengan NRP atau nama lengkap pengguna)", value))
+		}
+	}
+
+	if docType != nil {
+		doc.JenisDokumen = docType.Kode
+		extraData := map[string]string{}
+		for _, field := range docType.Fields {
+			for _, name := range []string{normalizeHeader(field.Key), normalizeHeader(field.Label)} {
+				if i, ok := ctx.extraColumns[name]; ok && i < len(values) && extraData[field.Key] == "" {
+					extraData[field.Key] = values[i]
+				}
+			}
+		}
+		dataTambahan, err := validateDocumentFields(docType, resident.NIK, doc.LokasiHilang, doc.LostItems, extraData)
+		if err != nil {
+			messages = append(messages, err.Error())
+		}
+		doc.DataTambahan = dataTambahan
+	}
+
+	// Nomor urut diambil dari segmen kedua Nomor Surat (contoh: SKH/<urut>/X/.../2025), seperti data lama
+	// pada migrasi penomoran, agar surat hasil impor ikut dihitung di laporan celah penomoran.
+	if parts := strings.Split(doc.NomorSurat, "/"); len(parts) > 2 {
+		if urut, err := strconv.Atoi(strings.TrimSpace(parts[1])); err == nil && urut > 0 {
+			doc.NomorUrut = urut
+			doc.TahunNomor = doc.TanggalLaporan.In(ctx.loc).Year()
+		}
+	}
+	return row, messages
+}
+
+// saveRows menyimpan semua baris beserta entri audit log-nya dalam satu transaksi, lalu menaikkan tabel
+// penomoran di transaksi yang sama bila nomor surat hasil impor melampaui nomor terakhir, sehingga nomor
+// baru tidak bentrok dengan surat lama. Audit log ditulis langsung (bukan lewat AuditLogService yang asinkron) agar
+// ikut batal bersama transaksi dan tetap tersimpan saat impor dijalankan dari baris perintah.
+func (s *documentImportService) saveRows(rows []importRow, ctx *importContext, fileName string) error {
+	type sequenceKey struct {
+		docType string
+		year    int
+	}
+	highest := map[sequenceKey]int{}
+	for _, row := range rows {
+		key := sequenceKey{row.doc.JenisDokumen, row.doc.TahunNomor}
+		if row.doc.NomorUrut > highest[key] {
+			highest[key] = row.doc.NomorUrut
+		}
+	}
+	actorID := &ctx.actor.ID
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		auditLogs := make([]models.AuditLog, 0, len(rows))
+		for i := range rows {
+			row := &rows[i]
+			resident, err := resolveResident(tx, s.residentRepo, row.resident)
+			if err != nil {
+				return fmt.Errorf("baris %d: %w", row.line, err)
+			}
+			doc := row.doc
+			doc.ResidentID = resident.ID
+			petugasPelapor, pejabatPersetuju := doc.PetugasPelapor, doc.PejabatPersetuju
+			// Relasi pengguna tidak ikut disimpan; hanya ID-nya.
+			doc.PetugasPelapor, doc.PejabatPersetuju = models.User{}, models.User{}
+			created, err := s.docRepo.Create(tx, &doc)
+			if err != nil {
+				return fmt.Errorf("baris %d: %w", row.line, err)
+			}
+			created.Resident = *resident
+			created.PetugasPelapor, created.PejabatPersetuju = petugasPelapor, pejabatPersetuju
+			if err := s.revisionService.RecordRevision(tx, created, models.RevisionBaseline, ctx.actor.ID); err != nil {
+				return fmt.Errorf("baris %d: %w", row.line, err)
+			}
+			auditLogs = append(auditLogs, models.AuditLog{
+				UserID:    actorID,
+				Aksi:      models.AuditImportDocument,
+				Detail:    fmt.Sprintf("Mengimpor dokumen %s atas nama %s dari file %s", created.NomorSurat, resident.NamaLengkap, fileName),
+				Timestamp: time.Now(),
+			})
+		}
+		for key, number := range highest {
+			if err := s.seqRepo.RaiseLastNumber(tx, key.docType, key.year, number); err != nil {
+				return err
+			}
+		}
+		return s.auditRepo.CreateBatch(tx, auditLogs)
+	})
+}
+
+// normalizeHeader menyeragamkan judul kolom: huruf kecil, tanpa titik, garis bawah menjadi spasi.
+func normalizeHeader(title string) string {
+	title = strings.ToLower(strings.TrimSpace(title))
+	title = strings.NewReplacer(".", "", "_", " ").Replace(title)
+	return strings.Join(strings.Fields(title), " ")
+}
+
+// parseImportDate menerima tanggal dalam format umum Indonesia (02-01-2006), ISO (2006-01-02),
+// atau nomor seri tanggal Excel, dengan atau tanpa jam.
+func parseImportDate(value string, loc *time.Location) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, errors.New("wajib diisi")
+	}
+	for _, layout := range importDateLayouts {
+		if t, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return t, nil
+		}
+	}
+	// Excel menyimpan tanggal sebagai jumlah hari sejak 30 Desember 1899.
+	if serial, err := strconv.ParseFloat(value, 64); err == nil && serial >= 1 && serial < 2958466 {
+		days := math.Floor(serial)
+		minutes := math.Round((serial - days) * 24 * 60)
+		return time.Date(1899, time.December, 30, 0, 0, 0, 0, loc).AddDate(0, 0, int(days)).Add(time.Duration(minutes) * time.Minute), nil
+	}
+	return time.Time{}, fmt.Errorf("%q tidak dikenali (gunakan format DD-MM-YYYY)", value)
+}
+
+func normalizeJenisKelamin(value string) (string, bool) {
+	switch strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(value), " ", "-")) {
+	case "L", "LAKI-LAKI", "LAKI", "PRIA":
+		return "Laki-laki", true
+	case "P", "PEREMPUAN", "WANITA":
+		return "Perempuan", true
+	}
+	return "", false
+}
+
+// parseImportItems mengurai daftar barang yang dipisah titik koma atau baris baru.
+// Keterangan barang boleh ditulis dalam kurung, sesuai format ekspor: "KTP (NIK: 3171...); SIM".
+func parseImportItems(value string) []models.LostItem {
+	var items []models.LostItem
+	for _, part := range strings.FieldsFunc(value, func(r rune) bool { return r == ';' || r == '\n' }) {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		item := models.LostItem{NamaBarang: strings.ToUpper(part)}
+		if open := strings.Index(part, " ("); open > 0 && strings.HasSuffix(part, ")") {
+			item.NamaBarang = strings.ToUpper(strings.TrimSpace(part[:open]))
+			item.Deskripsi = strings.TrimSpace(part[open+2 : len(part)-1])
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+func isEmptyRow(row []string) bool {
+	for _, value := range row {
+		if strings.TrimSpace(value) != "" {
+			return false
+		}
+	}
+	return true
+}
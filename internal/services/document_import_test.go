@@ -0,0 +1,159 @@
+package services
+
+import (
+	"errors"
+	"simdokpol/internal/mocks"
+	"simdokpol/internal/models"
+	"simdokpol/internal/repositories"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func newImportTestService() DocumentImportService {
+	mockUserRepo := new(mocks.UserRepository)
+	mockDocRepo := new(mocks.LostDocumentRepository)
+	mockDocTypeService := new(mocks.DocumentTypeService)
+	mockConfigService := new(mocks.ConfigService)
+	mockItemTypeService := new(mocks.ItemTypeService)
+
+	importer := &models.User{ID: 1, NRP: "100", NamaLengkap: "ADMIN IMPOR", Peran: models.RoleSuperAdmin}
+	mockUserRepo.On("FindByID", uint(1)).Return(importer, nil)
+	mockUserRepo.On("FindByID", uint(2)).Return(&models.User{ID: 2, Peran: models.RoleOperator, Role: &models.Role{Kode: models.RoleOperator, Permissions: []string{models.PermDocumentCreate}}}, nil)
+	mockUserRepo.On("FindAll", "active").Return([]models.User{*importer, {ID: 3, NRP: "111", NamaLengkap: "BRIPTU ANDI"}}, nil)
+	mockUserRepo.On("FindAll", "inactive").Return([]models.User{{ID: 4, NRP: "222", NamaLengkap: "AIPTU LAMA"}}, nil)
+	mockDocRepo.On("FindExistingNomorSurat").Return([]string{"SKH/1/X/2023/SPKT"}, nil)
+	mockDocTypeService.On("FindAll", false).Return([]models.DocumentType{{
+		Kode:   models.DocumentTypeLostDocument,
+		Nama:   "Surat Keterangan Hilang",
+		Fields: []models.DocumentField{{Key: models.FieldLostItems, Label: "Barang Hilang", Wajib: true}},
+	}}, nil)
+	mockConfigService.On("GetLocation").Return(time.UTC, nil)
+	mockItemTypeService.On("FindAll", false).Return([]models.ItemType{{Kode: "SIM", Nama: "SIM", Alias: []string{"SIM C"}, Aktif: true}}, nil)
+
+	return NewDocumentImportService(nil, mockDocRepo, nil, mockUserRepo, nil, mockDocTypeService, mockItemTypeService, nil, nil, mockConfigService)
+}
+
+const importTestCSV = `Nomor Surat,Tanggal Laporan,NIK,Nama,Tempat Lahir,Tanggal Lahir,JK,Agama,Pekerjaan,Alamat,Lokasi Hilang,Barang Hilang,Petugas Pelapor,Catatan
+SKH/12/X/2023/SPKT,05-10-2023 10:30,3171011501900001,Budi,Jakarta,15-01-1990,L,Islam,Swasta,Jl. Mawar,Pasar,"KTP (a.n. Budi); SIM C",,arsip
+skh/12/x/2023/spkt,32-10-2023,,Siti,Bogor,01-02-1995,P,Islam,Guru,Jl. Melati,Terminal,Dompet,99999,
+SKH/1/X/2023/SPKT,2023-10-06,,Agus,Depok,1995-03-04,Laki-laki,Islam,Buruh,Jl. Kenanga,Stasiun,ATM,,
+,,,,,,,,,,,,,
+SKH/13/X/2023/SPKT,45210,,Rina,Bekasi,04-05-1992,Perempuan,Kristen,Pedagang,Jl. Anggrek,Pasar,STNK,222,
+`
+
+func TestDocumentImportService_Import_DryRun(t *testing.T) {
+	service := newImportTestService()
+
+	report, err := service.Import("arsip.csv", []byte(importTestCSV), 1, false)
+	require.NoError(t, err)
+
+	assert.True(t, report.DryRun)
+	assert.Equal(t, 4, report.TotalRows, "baris kosong tidak dihitung")
+	assert.Equal(t, 2, report.ValidRows)
+	assert.Equal(t, 0, report.Imported)
+	assert.Equal(t, []string{"Catatan"}, report.IgnoredColumns)
+	assert.Len(t, report.Columns, 13)
+
+	require.Len(t, report.Errors, 2)
+	assert.Equal(t, 3, report.Errors[0].Row)
+	messages := strings.Join(report.Errors[0].Messages, "\n")
+	assert.Contains(t, messages, "Tanggal Laporan")
+	assert.Contains(t, messages, `Petugas pelapor "99999" tidak dikenal`)
+	assert.Contains(t, messages, "Nomor Surat sama dengan baris 2")
+
+	assert.Equal(t, 4, report.Errors[1].Row)
+	assert.Equal(t, []string{"Nomor Surat sudah terdaftar di database"}, report.Errors[1].Messages)
+}
+
+func TestDocumentImportService_Import_CommitRefusedWhenRowsInvalid(t *testing.T) {
+	service := newImportTestService()
+
+	report, err := service.Import("arsip.csv", []byte(importTestCSV), 1, true)
+	assert.True(t, errors.Is(err, ErrImportHasErrors))
+	require.NotNil(t, report)
+	assert.False(t, report.DryRun)
+	assert.Equal(t, 0, report.Imported)
+	assert.Len(t, report.Errors, 2)
+}
+
+func TestDocumentImportService_Import_Rejected(t *testing.T) {
+	service := newImportTestService()
+
+	_, err := service.Import("arsip.csv", []byte(importTestCSV), 2, false)
+	assert.True(t, errors.Is(err, ErrAccessDenied))
+
+	_, err = service.Import("arsip.csv", []byte("Nomor Surat,Nama\nSKH/1,Budi\n"), 1, false)
+	assert.True(t, errors.Is(err, ErrInvalidImportFile))
+	assert.Contains(t, err.Error(), "Tanggal Laporan")
+
+	_, err = service.Import("arsip.pdf", []byte("x"), 1, false)
+	assert.True(t, errors.Is(err, ErrInvalidImportFile))
+}
+
+// Nomor terakhir yang sudah lebih tinggi, misalnya karena surat diterbitkan selama impor berjalan,
+// tidak boleh diturunkan oleh nomor surat hasil impor.
+func TestDocumentSequenceRepository_RaiseLastNumber(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{Logger: logger.Discard})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.DocumentSequence{}))
+	seqRepo := repositories.NewDocumentSequenceRepository(db)
+	require.NoError(t, seqRepo.SetLastNumber(nil, models.DocumentTypeLostDocument, 2024, 10))
+
+	require.NoError(t, seqRepo.RaiseLastNumber(nil, models.DocumentTypeLostDocument, 2024, 7))
+	seq, err := seqRepo.Get(models.DocumentTypeLostDocument, 2024)
+	require.NoError(t, err)
+	assert.Equal(t, 10, seq.LastNumber)
+
+	require.NoError(t, seqRepo.RaiseLastNumber(nil, models.DocumentTypeLostDocument, 2024, 12))
+	seq, err = seqRepo.Get(models.DocumentTypeLostDocument, 2024)
+	require.NoError(t, err)
+	assert.Equal(t, 12, seq.LastNumber)
+
+	require.NoError(t, seqRepo.RaiseLastNumber(nil, models.DocumentTypeLostDocument, 2023, 3))
+	seq, err = seqRepo.Get(models.DocumentTypeLostDocument, 2023)
+	require.NoError(t, err)
+	assert.Equal(t, 3, seq.LastNumber)
+}
+
+func TestParseImportDate(t *testing.T) {
+	loc := time.FixedZone("WIB", 7*3600)
+	testCases := []struct {
+		value    string
+		expected time.Time
+	}{
+		{"05-10-2023", time.Date(2023, 10, 5, 0, 0, 0, 0, loc)},
+		{"5/10/2023", time.Date(2023, 10, 5, 0, 0, 0, 0, loc)},
+		{"2023-10-05 14:30", time.Date(2023, 10, 5, 14, 30, 0, 0, loc)},
+		{"45204", time.Date(2023, 10, 5, 0, 0, 0, 0, loc)},
+		{"45204.5", time.Date(2023, 10, 5, 12, 0, 0, 0, loc)},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.value, func(t *testing.T) {
+			parsed, err := parseImportDate(tc.value, loc)
+			require.NoError(t, err)
+			assert.True(t, tc.expected.Equal(parsed), "hasil %v", parsed)
+		})
+	}
+
+	for _, value := range []string{"", "kemarin", "32-13-2023"} {
+		_, err := parseImportDate(value, loc)
+		assert.Error(t, err, value)
+	}
+}
+
+func TestParseImportItems(t *testing.T) {
+	items := parseImportItems("KTP (NIK: 3171011501900001); sim c\nATM (BRI) ;")
+	assert.Equal(t, []models.LostItem{
+		{NamaBarang: "KTP", Deskripsi: "NIK: 3171011501900001"},
+		{NamaBarang: "SIM C"},
+		{NamaBarang: "ATM", Deskripsi: "BRI"},
+	}, items)
+	assert.Empty(t, parseImportItems("  "))
+}
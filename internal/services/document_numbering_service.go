@@ -0,0 +1,233 @@
+package services
+
+import (
+	"fmt"
+	"simdokpol/internal/dto"
+	"simdokpol/internal/models"
+	"simdokpol/internal/repositories"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type DocumentNumberingService interface {
+	NextDocumentNumber(tx *gorm.DB, docType *models.DocumentType, regu string) (*dto.IssuedNumber, error)
+	PreviewNextNumber(documentType string, format string, officeCode string, regu string) (*dto.IssuedNumber, error)
+	ValidateFormat(format string, officeCode string) error
+	SyncLastNumber(lastNumber string) error
+	GetGapReport(documentType string, year int) (*dto.NumberingGapReport, error)
+}
+
+type documentNumberingService struct {
+	seqRepo       repositories.DocumentSequenceRepository
+	typeRepo      repositories.DocumentTypeRepository
+	configService ConfigService
+}
+
+func NewDocumentNumberingService(seqRepo repositories.DocumentSequenceRepository, typeRepo repositories.DocumentTypeRepository, configService ConfigService) DocumentNumberingService {
+	return &documentNumberingService{
+		seqRepo:       seqRepo,
+		typeRepo:      typeRepo,
+		configService: configService,
+	}
+}
+
+func (s *documentNumberingService) now() time.Time {
+	loc, err := s.configService.GetLocation()
+	if err != nil {
+		loc = time.UTC
+	}
+	return time.Now().In(loc)
+}
+
+// NextDocumentNumber mengambil nomor urut berikutnya untuk jenis dokumen docType dari tabel
+// document_sequences di dalam transaksi tx, lalu menyusunnya menjadi Nomor Surat sesuai format
+// jenis dokumen tersebut. regu adalah regu petugas yang menerima laporan, untuk placeholder {REGU}.
+// docType dimuat oleh pemanggil sebelum transaksi dimulai, agar pernyataan pertama transaksi
+// tetap penulisan nomor urut.
+func (s *documentNumberingService) NextDocumentNumber(tx *gorm.DB, docType *models.DocumentType, regu string) (*dto.IssuedNumber, error) {
+	appConfig, err := s.configService.GetConfig()
+	if err != nil {
+		return nil, fmt.Errorf("gagal memuat konfigurasi untuk penomoran surat: %w", err)
+	}
+
+	// Format yang tersimpan diperiksa ulang: format rusak harus menggagalkan penerbitan, bukan
+	// menghasilkan Nomor Surat yang salah atau bentrok di tahun berikutnya.
+	format := numberFormatFor(docType, appConfig)
+	if err := validateNumberingTemplate(format, appConfig.KodeKantor); err != nil {
+		return nil, fmt.Errorf("format Nomor Surat jenis %s yang tersimpan tidak valid, perbaiki di Pengaturan: %w", docType.Kode, err)
+	}
+
+	now := s.now()
+	runningNumber, err := s.seqRepo.NextNumber(tx, docType.Kode, now.Year())
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengambil nomor urut surat: %w", err)
+	}
+
+	return s.buildNumber(format, appConfig.KodeKantor, regu, runningNumber, now)
+}
+
+// numberFormatFor mengembalikan format Nomor Surat sebuah jenis dokumen. Jenis tanpa format
+// sendiri (Surat Keterangan Hilang) memakai Format Nomor Surat di Pengaturan.
+func numberFormatFor(docType *models.DocumentType, appConfig *dto.AppConfig) string {
+	if docType.FormatNomor != "" {
+		return docType.FormatNomor
+	}
+	return appConfig.FormatNomorSurat
+}
+
+func (s *documentNumberingService) findDocumentType(documentType string) (*models.DocumentType, error) {
+	docType, err := s.typeRepo.FindByCode(documentType)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("%w: jenis dokumen %s tidak dikenal", ErrInvalidDocumentType, documentType)
+		}
+		return nil, err
+	}
+	return docType, nil
+}
+
+// PreviewNextNumber menyusun Nomor Surat jenis documentType yang akan terbit berikutnya tanpa
+// menaikkan nomor urut. Format dan officeCode yang kosong diganti dengan nilai yang tersimpan,
+// sehingga perubahan yang belum disimpan dapat dicoba lebih dulu.
+func (s *documentNumberingService) PreviewNextNumber(documentType string, format string, officeCode string, regu string) (*dto.IssuedNumber, error) {
+	appConfig, err := s.configService.GetConfig()
+	if err != nil {
+		return nil, fmt.Errorf("gagal memuat konfigurasi untuk penomoran surat: %w", err)
+	}
+	docType, err := s.findDocumentType(documentType)
+	if err != nil {
+		return nil, err
+	}
+	if format == "" {
+		format = numberFormatFor(docType, appConfig)
+	}
+	if officeCode == "" {
+		officeCode = appConfig.KodeKantor
+	}
+	if err := validateNumberingTemplate(format, officeCode); err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	lastNumber := 0
+	seq, err := s.seqRepo.Get(docType.Kode, now.Year())
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+	if seq != nil {
+		lastNumber = seq.LastNumber
+	}
+
+	return s.buildNumber(format, officeCode, regu, lastNumber+1, now)
+}
+
+// ValidateFormat memeriksa format Nomor Surat sebelum disimpan ke Pengaturan.
+func (s *documentNumberingService) ValidateFormat(format string, officeCode string) error {
+	return validateNumberingTemplate(format, officeCode)
+}
+
+func (s *documentNumberingService) buildNumber(format, officeCode, regu string, runningNumber int, now time.Time) (*dto.IssuedNumber, error) {
+	nomorSurat, err := renderNumberingTemplate(format, numberingValues{
+		Seq:        runningNumber,
+		Month:      int(now.Month()),
+		Year:       now.Year(),
+		OfficeCode: officeCode,
+		Regu:       regu,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &dto.IssuedNumber{
+		NomorSurat: nomorSurat,
+		NomorUrut:  runningNumber,
+		Tahun:      now.Year(),
+	}, nil
+}
+
+// SyncLastNumber menerapkan "Nomor Terakhir (Tahun Ini)" dari Pengaturan ke tabel penomoran
+// Surat Keterangan Hilang tahun berjalan. Nilai tidak pernah diturunkan di bawah nomor tertinggi yang sudah terpakai,
+// sehingga koreksi salah ketik tidak menyebabkan nomor ganda.
+func (s *documentNumberingService) SyncLastNumber(lastNumber string) error {
+	value, err := strconv.Atoi(strings.TrimSpace(lastNumber))
+	if err != nil || value < 0 {
+		return fmt.Errorf("nomor surat terakhir tidak valid: %q", lastNumber)
+	}
+
+	year := s.now().Year()
+	used, err := s.seqRepo.FindUsedNumbers(models.DocumentTypeLostDocument, year)
+	if err != nil {
+		return err
+	}
+	for _, u := range used {
+		if u.NomorUrut > value {
+			value = u.NomorUrut
+		}
+	}
+	return s.seqRepo.SetLastNumber(nil, models.DocumentTypeLostDocument, year, value)
+}
+
+// GetGapReport membandingkan nomor terakhir di tabel penomoran dengan nomor yang benar-benar
+// dipakai dokumen jenis documentType, untuk menemukan nomor yang terlewat, terhapus, atau terpakai ganda.
+func (s *documentNumberingService) GetGapReport(documentType string, year int) (*dto.NumberingGapReport, error) {
+	report := &dto.NumberingGapReport{
+		Year:             year,
+		MissingNumbers:   []int{},
+		DeletedNumbers:   []dto.DeletedNumber{},
+		DuplicateNumbers: []int{},
+	}
+
+	seq, err := s.seqRepo.Get(documentType, year)
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+	if seq != nil {
+		report.LastNumber = seq.LastNumber
+	}
+
+	used, err := s.seqRepo.FindUsedNumbers(documentType, year)
+	if err != nil {
+		return nil, err
+	}
+
+	// Nomor di bawah nomor pertama yang terpakai dianggap nomor register awal
+	// (diisi saat setup), bukan celah penomoran.
+	seen := make(map[int]int)
+	for _, u := range used {
+		if report.FirstNumber == 0 || u.NomorUrut < report.FirstNumber {
+			report.FirstNumber = u.NomorUrut
+		}
+		seen[u.NomorUrut]++
+		if seen[u.NomorUrut] == 2 {
+			report.DuplicateNumbers = append(report.DuplicateNumbers, u.NomorUrut)
+		}
+		if u.DeletedAt.Valid {
+			report.DeletedNumbers = append(report.DeletedNumbers, dto.DeletedNumber{NomorUrut: u.NomorUrut, NomorSurat: originalNomorSurat(u.NomorSurat)})
+		} else {
+			report.IssuedCount++
+		}
+		if u.NomorUrut > report.LastNumber {
+			report.LastNumber = u.NomorUrut
+		}
+	}
+
+	if report.FirstNumber > 0 {
+		for n := report.FirstNumber; n <= report.LastNumber; n++ {
+			if seen[n] == 0 {
+				report.MissingNumbers = append(report.MissingNumbers, n)
+			}
+		}
+	}
+
+	return report, nil
+}
+
+func intToRoman(num int) string {
+	romanNumeralMap := map[int]string{1: "I", 2: "II", 3: "III", 4: "IV", 5: "V", 6: "VI", 7: "VII", 8: "VIII", 9: "IX", 10: "X", 11: "XI", 12: "XII"}
+	if val, ok := romanNumeralMap[num]; ok {
+		return val
+	}
+	return ""
+}
@@ -0,0 +1,264 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"simdokpol/internal/dto"
+	"simdokpol/internal/models"
+	"simdokpol/internal/repositories"
+	"sort"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+type DocumentRevisionService interface {
+	// RecordRevision menyimpan snapshot dokumen sebagai revisi baru. Dipanggil di dalam
+	// transaksi yang sama dengan penyimpanan dokumen.
+	RecordRevision(tx *gorm.DB, doc *models.LostDocument, aksi string, actorID uint) error
+	ListRevisions(docID uint) ([]models.DocumentRevision, error)
+	GetRevision(docID uint, revisionNumber int) (*dto.RevisionDetail, error)
+	// Diff membandingkan dua revisi field demi field. toRevision 0 berarti revisi terakhir,
+	// fromRevision 0 berarti revisi tepat sebelum toRevision.
+	Diff(docID uint, fromRevision int, toRevision int) (*dto.RevisionDiff, error)
+}
+
+type documentRevisionService struct {
+	revisionRepo repositories.DocumentRevisionRepository
+	userRepo     repositories.UserRepository
+}
+
+func NewDocumentRevisionService(revisionRepo repositories.DocumentRevisionRepository, userRepo repositories.UserRepository) DocumentRevisionService {
+	return &documentRevisionService{
+		revisionRepo: revisionRepo,
+		userRepo:     userRepo,
+	}
+}
+
+func (s *documentRevisionService) RecordRevision(tx *gorm.DB, doc *models.LostDocument, aksi string, actorID uint) error {
+	snapshot, err := json.Marshal(s.buildSnapshot(doc))
+	if err != nil {
+		return fmt.Errorf("gagal menyusun snapshot revisi: %w", err)
+	}
+	_, err = s.revisionRepo.Create(tx, &models.DocumentRevision{
+		LostDocumentID: doc.ID,
+		Aksi:           aksi,
+		Snapshot:       string(snapshot),
+		ChangedByID:    actorID,
+	})
+	return err
+}
+
+func (s *documentRevisionService) ListRevisions(docID uint) ([]models.DocumentRevision, error) {
+	return s.revisionRepo.FindByDocumentID(docID)
+}
+
+func (s *documentRevisionService) GetRevision(docID uint, revisionNumber int) (*dto.RevisionDetail, error) {
+	revision, err := s.revisionRepo.FindByNumber(docID, revisionNumber)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+
+	var snapshot dto.DocumentSnapshot
+	if err := json.Unmarshal([]byte(revision.Snapshot), &snapshot); err != nil {
+		return nil, fmt.Errorf("snapshot revisi %d rusak: %w", revisionNumber, err)
+	}
+
+	return &dto.RevisionDetail{
+		RevisionNumber: revision.RevisionNumber,
+		Aksi:           revision.Aksi,
+		ChangedByID:    revision.ChangedByID,
+		ChangedBy:      officerName(revision.ChangedBy),
+		CreatedAt:      revision.CreatedAt,
+		Snapshot:       snapshot,
+	}, nil
+}
+
+func (s *documentRevisionService) Diff(docID uint, fromRevision int, toRevision int) (*dto.RevisionDiff, error) {
+	if toRevision <= 0 {
+		latest, err := s.revisionRepo.LatestNumber(docID)
+		if err != nil {
+			return nil, err
+		}
+		if latest == 0 {
+			return nil, ErrNotFound
+		}
+		toRevision = latest
+	}
+	if fromRevision <= 0 {
+		fromRevision = toRevision - 1
+	}
+
+	to, err := s.GetRevision(docID, toRevision)
+	if err != nil {
+		return nil, err
+	}
+	// Revisi pertama dibandingkan dengan dokumen kosong sehingga semua isinya tampil sebagai perubahan.
+	var fromSnapshot dto.DocumentSnapshot
+	if fromRevision > 0 {
+		from, err := s.GetRevision(docID, fromRevision)
+		if err != nil {
+			return nil, err
+		}
+		fromSnapshot = from.Snapshot
+	}
+
+	return &dto.RevisionDiff{
+		DocumentID:   docID,
+		FromRevision: fromRevision,
+		ToRevision:   toRevision,
+		Changes:      diffSnapshots(fromSnapshot, to.Snapshot),
+	}, nil
+}
+
+// buildSnapshot menyalin isi dokumen ke bentuk snapshot. Nama petugas diambil dari data
+// yang sudah dimuat bila ID-nya masih sama, karena petugas bisa diganti saat dokumen diedit.
+func (s *documentRevisionService) buildSnapshot(doc *models.LostDocument) dto.DocumentSnapshot {
+	snapshot := dto.DocumentSnapshot{
+		NomorSurat:         doc.NomorSurat,
+		Status:             doc.Status,
+		LokasiHilang:       doc.LokasiHilang,
+		PetugasPelaporID:   doc.PetugasPelaporID,
+		PetugasPelapor:     s.resolveOfficerName(doc.PetugasPelaporID, doc.PetugasPelapor),
+		PejabatPersetujuID: doc.PejabatPersetujuID,
+		Resident: dto.ResidentSnapshot{
+			ID:           doc.ResidentID,
+			NIK:          doc.Resident.NIK,
+			NamaLengkap:  doc.Resident.NamaLengkap,
+			TempatLahir:  doc.Resident.TempatLahir,
+			TanggalLahir: doc.Resident.TanggalLahir.Format("2006-01-02"),
+			JenisKelamin: doc.Resident.JenisKelamin,
+			Agama:        doc.Resident.Agama,
+			Pekerjaan:    doc.Resident.Pekerjaan,
+			Alamat:       doc.Resident.Alamat,
+		},
+		LostItems:    make([]dto.ItemSnapshot, 0, len(doc.LostItems)),
+		DataTambahan: doc.DataTambahan,
+	}
+	if doc.PejabatPersetujuID != nil {
+		snapshot.PejabatPersetuju = s.resolveOfficerName(*doc.PejabatPersetujuID, doc.PejabatPersetuju)
+	}
+	for _, item := range doc.LostItems {
+		snapshot.LostItems = append(snapshot.LostItems, dto.ItemSnapshot{NamaBarang: item.NamaBarang, Deskripsi: item.Deskripsi, JenisBarang: item.JenisBarang, Atribut: item.Atribut})
+	}
+	return snapshot
+}
+
+func (s *documentRevisionService) resolveOfficerName(id uint, loaded models.User) string {
+	if loaded.ID == id {
+		return officerName(loaded)
+	}
+	user, err := s.userRepo.FindByID(id)
+	if err != nil {
+		return ""
+	}
+	return officerName(*user)
+}
+
+func officerName(user models.User) string {
+	return strings.TrimSpace(user.Pangkat + " " + user.NamaLengkap)
+}
+
+// snapshotField adalah satu nilai snapshot yang sudah diratakan untuk dibandingkan.
+type snapshotField struct {
+	field string
+	label string
+	value string
+}
+
+// itemAttrKeys berisi kunci atribut barang per posisi, gabungan dari kedua snapshot yang dibandingkan.
+func flattenSnapshot(snapshot dto.DocumentSnapshot, itemAttrKeys [][]string, extraKeys []string) []snapshotField {
+	nik := ""
+	if snapshot.Resident.NIK != nil {
+		nik = *snapshot.Resident.NIK
+	}
+	fields := []snapshotField{
+		{"nomor_surat", "Nomor Surat", snapshot.NomorSurat},
+		{"status", "Status", snapshot.Status},
+		{"resident.nik", "NIK", nik},
+		{"resident.nama_lengkap", "Nama Lengkap", snapshot.Resident.NamaLengkap},
+		{"resident.tempat_lahir", "Tempat Lahir", snapshot.Resident.TempatLahir},
+		{"resident.tanggal_lahir", "Tanggal Lahir", snapshot.Resident.TanggalLahir},
+		{"resident.jenis_kelamin", "Jenis Kelamin", snapshot.Resident.JenisKelamin},
+		{"resident.agama", "Agama", snapshot.Resident.Agama},
+		{"resident.pekerjaan", "Pekerjaan", snapshot.Resident.Pekerjaan},
+		{"resident.alamat", "Alamat", snapshot.Resident.Alamat},
+		{"lokasi_hilang", "Lokasi Hilang", snapshot.LokasiHilang},
+		{"petugas_pelapor", "Penerima Laporan", snapshot.PetugasPelapor},
+		{"pejabat_persetuju", "Penanggung Jawab", snapshot.PejabatPersetuju},
+	}
+	for i, attrKeys := range itemAttrKeys {
+		var item dto.ItemSnapshot
+		if i < len(snapshot.LostItems) {
+			item = snapshot.LostItems[i]
+		}
+		fields = append(fields,
+			snapshotField{fmt.Sprintf("lost_items[%d].jenis_barang", i), fmt.Sprintf("Barang %d - Jenis", i+1), item.JenisBarang},
+			snapshotField{fmt.Sprintf("lost_items[%d].nama_barang", i), fmt.Sprintf("Barang %d - Nama", i+1), item.NamaBarang},
+			snapshotField{fmt.Sprintf("lost_items[%d].deskripsi", i), fmt.Sprintf("Barang %d - Deskripsi", i+1), item.Deskripsi},
+		)
+		for _, key := range attrKeys {
+			fields = append(fields, snapshotField{fmt.Sprintf("lost_items[%d].atribut.%s", i, key), fmt.Sprintf("Barang %d - Atribut %s", i+1, key), item.Atribut[key]})
+		}
+	}
+	for _, key := range extraKeys {
+		fields = append(fields, snapshotField{"data_tambahan." + key, "Isian " + key, snapshot.DataTambahan[key]})
+	}
+	return fields
+}
+
+// diffSnapshots mengembalikan field yang nilainya berbeda, dengan urutan sesuai tampilan surat.
+// Barang dibandingkan per posisi; barang yang ditambah atau dihapus tampil dengan nilai kosong di sisi lain.
+func diffSnapshots(from, to dto.DocumentSnapshot) []dto.FieldChange {
+	itemCount := len(from.LostItems)
+	if len(to.LostItems) > itemCount {
+		itemCount = len(to.LostItems)
+	}
+	itemAttrKeys := make([][]string, itemCount)
+	for i := range itemAttrKeys {
+		var fromAttr, toAttr map[string]string
+		if i < len(from.LostItems) {
+			fromAttr = from.LostItems[i].Atribut
+		}
+		if i < len(to.LostItems) {
+			toAttr = to.LostItems[i].Atribut
+		}
+		itemAttrKeys[i] = unionKeys(fromAttr, toAttr)
+	}
+	extraKeys := unionKeys(from.DataTambahan, to.DataTambahan)
+	oldFields := flattenSnapshot(from, itemAttrKeys, extraKeys)
+	newFields := flattenSnapshot(to, itemAttrKeys, extraKeys)
+
+	changes := []dto.FieldChange{}
+	for i := range newFields {
+		if oldFields[i].value != newFields[i].value {
+			changes = append(changes, dto.FieldChange{
+				Field:    newFields[i].field,
+				Label:    newFields[i].label,
+				OldValue: oldFields[i].value,
+				NewValue: newFields[i].value,
+			})
+		}
+	}
+	return changes
+}
+
+// unionKeys mengembalikan gabungan key DataTambahan kedua snapshot secara berurutan.
+func unionKeys(a, b map[string]string) []string {
+	seen := make(map[string]bool)
+	var keys []string
+	for _, m := range []map[string]string{a, b} {
+		for key := range m {
+			if !seen[key] {
+				seen[key] = true
+				keys = append(keys, key)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,47 @@
+package services
+
+import (
+	"simdokpol/internal/dto"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffSnapshots(t *testing.T) {
+	nik := "3171011501900001"
+	before := dto.DocumentSnapshot{
+		NomorSurat:     "SKH/1/X/TUK.7.2.1/2025",
+		Status:         "DITERBITKAN",
+		LokasiHilang:   "Pasar Senen",
+		Resident:       dto.ResidentSnapshot{NamaLengkap: "Budi Santoso", TanggalLahir: "1990-01-15"},
+		PetugasPelapor: "BRIPDA ANDI",
+		LostItems: []dto.ItemSnapshot{
+			{NamaBarang: "KTP", Deskripsi: "NIK: 12345", JenisBarang: "KTP", Atribut: map[string]string{"nik": "12345"}},
+			{NamaBarang: "STNK", Deskripsi: "Nopol: B 1234 XY"},
+		},
+	}
+	after := before
+	after.LokasiHilang = "Terminal Senen"
+	after.Resident.NIK = &nik
+	after.LostItems = []dto.ItemSnapshot{
+		{NamaBarang: "KTP", Deskripsi: "NIK: 12345", JenisBarang: "KTP", Atribut: map[string]string{"nik": "67890"}},
+		{NamaBarang: "STNK", Deskripsi: "Nopol: B 1234 XY", JenisBarang: "STNK", Atribut: map[string]string{"nopol": "B 1234 XY"}},
+		{NamaBarang: "SIM", Deskripsi: "Gol: C", JenisBarang: "SIM", Atribut: map[string]string{"golongan": "C"}},
+	}
+
+	changes := diffSnapshots(before, after)
+
+	assert.Equal(t, []dto.FieldChange{
+		{Field: "resident.nik", Label: "NIK", OldValue: "", NewValue: nik},
+		{Field: "lokasi_hilang", Label: "Lokasi Hilang", OldValue: "Pasar Senen", NewValue: "Terminal Senen"},
+		{Field: "lost_items[0].atribut.nik", Label: "Barang 1 - Atribut nik", OldValue: "12345", NewValue: "67890"},
+		{Field: "lost_items[1].jenis_barang", Label: "Barang 2 - Jenis", OldValue: "", NewValue: "STNK"},
+		{Field: "lost_items[1].atribut.nopol", Label: "Barang 2 - Atribut nopol", OldValue: "", NewValue: "B 1234 XY"},
+		{Field: "lost_items[2].jenis_barang", Label: "Barang 3 - Jenis", OldValue: "", NewValue: "SIM"},
+		{Field: "lost_items[2].nama_barang", Label: "Barang 3 - Nama", OldValue: "", NewValue: "SIM"},
+		{Field: "lost_items[2].deskripsi", Label: "Barang 3 - Deskripsi", OldValue: "", NewValue: "Gol: C"},
+		{Field: "lost_items[2].atribut.golongan", Label: "Barang 3 - Atribut golongan", OldValue: "", NewValue: "C"},
+	}, changes)
+
+	assert.Empty(t, diffSnapshots(after, after))
+}
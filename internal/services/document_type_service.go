@@ -0,0 +1,195 @@
+package services
+
+import (
+	"fmt"
+	"regexp"
+	"simdokpol/internal/models"
+	"simdokpol/internal/repositories"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+var (
+	documentTypeCodePattern = regexp.MustCompile(`^[A-Z][A-Z0-9_]{1,49}$`)
+	documentFieldKeyPattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,49}$`)
+)
+
+// Template cetak dan tipe isian yang dapat dipilih di registri jenis dokumen.
+var (
+	availablePrintTemplates = map[string]bool{
+		models.PrintTemplateLostDocument: true,
+		models.PrintTemplateGeneric:      true,
+	}
+	availableFieldTypes = map[string]bool{"text": true, "textarea": true, "date": true}
+)
+
+type DocumentTypeService interface {
+	FindAll(activeOnly bool) ([]models.DocumentType, error)
+	FindByCode(kode string) (*models.DocumentType, error)
+	Create(docType *models.DocumentType, actorID uint) (*models.DocumentType, error)
+	Update(kode string, docType *models.DocumentType, actorID uint) (*models.DocumentType, error)
+}
+
+type documentTypeService struct {
+	typeRepo      repositories.DocumentTypeRepository
+	configService ConfigService
+	auditService  AuditLogService
+}
+
+func NewDocumentTypeService(typeRepo repositories.DocumentTypeRepository, configService ConfigService, auditService AuditLogService) DocumentTypeService {
+	return &documentTypeService{
+		typeRepo:      typeRepo,
+		configService: configService,
+		auditService:  auditService,
+	}
+}
+
+func (s *documentTypeService) FindAll(activeOnly bool) ([]models.DocumentType, error) {
+	return s.typeRepo.FindAll(activeOnly)
+}
+
+func (s *documentTypeService) FindByCode(kode string) (*models.DocumentType, error) {
+	docType, err := s.typeRepo.FindByCode(kode)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return docType, nil
+}
+
+func (s *documentTypeService) Create(docType *models.DocumentType, actorID uint) (*models.DocumentType, error) {
+	docType.Kode = strings.ToUpper(strings.TrimSpace(docType.Kode))
+	if _, err := s.typeRepo.FindByCode(docType.Kode); err == nil {
+		return nil, fmt.Errorf("%w: kode %s sudah dipakai", ErrInvalidDocumentType, docType.Kode)
+	} else if err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+	if err := s.validateDefinition(docType); err != nil {
+		return nil, err
+	}
+
+	created, err := s.typeRepo.Create(docType)
+	if err != nil {
+		return nil, err
+	}
+	s.auditService.LogActivity(actorID, models.AuditCreateDocumentType, fmt.Sprintf("Menambahkan jenis dokumen %s (%s)", created.Nama, created.Kode))
+	return created, nil
+}
+
+// Update menimpa definisi jenis dokumen. Kode tidak dapat diubah karena dipakai sebagai kunci
+// nomor urut dan oleh dokumen yang sudah terbit.
+func (s *documentTypeService) Update(kode string, docType *models.DocumentType, actorID uint) (*models.DocumentType, error) {
+	existing, err := s.FindByCode(kode)
+	if err != nil {
+		return nil, err
+	}
+	docType.Kode = existing.Kode
+	docType.CreatedAt = existing.CreatedAt
+	if err := s.validateDefinition(docType); err != nil {
+		return nil, err
+	}
+
+	updated, err := s.typeRepo.Update(docType)
+	if err != nil {
+		return nil, err
+	}
+	s.auditService.LogActivity(actorID, models.AuditUpdateDocumentType, fmt.Sprintf("Memperbarui jenis dokumen %s (%s)", updated.Nama, updated.Kode))
+	return updated, nil
+}
+
+// validateDefinition memeriksa definisi jenis dokumen sebelum disimpan, termasuk memastikan
+// format nomornya tidak sama dengan jenis lain sehingga Nomor Surat tidak bentrok.
+func (s *documentTypeService) validateDefinition(docType *models.DocumentType) error {
+	docType.Nama = strings.TrimSpace(docType.Nama)
+	docType.FormatNomor = strings.TrimSpace(docType.FormatNomor)
+
+	if !documentTypeCodePattern.MatchString(docType.Kode) {
+		return fmt.Errorf("%w: kode hanya boleh berisi huruf kapital, angka, dan garis bawah", ErrInvalidDocumentType)
+	}
+	if docType.Nama == "" {
+		return fmt.Errorf("%w: nama jenis dokumen wajib diisi", ErrInvalidDocumentType)
+	}
+	if !availablePrintTemplates[docType.PrintTemplate] {
+		return fmt.Errorf("%w: template cetak %q tidak tersedia", ErrInvalidDocumentType, docType.PrintTemplate)
+	}
+
+	appConfig, err := s.configService.GetConfig()
+	if err != nil {
+		return fmt.Errorf("gagal memuat konfigurasi: %w", err)
+	}
+	if docType.FormatNomor == "" && docType.Kode != models.DocumentTypeLostDocument {
+		return fmt.Errorf("%w: format nomor wajib diisi", ErrInvalidDocumentType)
+	}
+	format := numberFormatFor(docType, appConfig)
+	if err := validateNumberingTemplate(format, appConfig.KodeKantor); err != nil {
+		return err
+	}
+	others, err := s.typeRepo.FindAll(false)
+	if err != nil {
+		return err
+	}
+	for i := range others {
+		if others[i].Kode != docType.Kode && numberFormatFor(&others[i], appConfig) == format {
+			return fmt.Errorf("%w: format nomor sama dengan jenis dokumen %s", ErrInvalidDocumentType, others[i].Nama)
+		}
+	}
+
+	seen := make(map[string]bool)
+	for i := range docType.Fields {
+		field := &docType.Fields[i]
+		field.Label = strings.TrimSpace(field.Label)
+		if field.Tipe == "" {
+			field.Tipe = "text"
+		}
+		if !documentFieldKeyPattern.MatchString(field.Key) {
+			return fmt.Errorf("%w: key isian %q hanya boleh berisi huruf kecil, angka, dan garis bawah", ErrInvalidDocumentType, field.Key)
+		}
+		if seen[field.Key] {
+			return fmt.Errorf("%w: key isian %q dipakai lebih dari sekali", ErrInvalidDocumentType, field.Key)
+		}
+		seen[field.Key] = true
+		if field.Label == "" {
+			return fmt.Errorf("%w: label isian %q wajib diisi", ErrInvalidDocumentType, field.Key)
+		}
+		if !availableFieldTypes[field.Tipe] {
+			return fmt.Errorf("%w: tipe isian %q tidak dikenal", ErrInvalidDocumentType, field.Tipe)
+		}
+	}
+	if docType.Fields == nil {
+		docType.Fields = []models.DocumentField{}
+	}
+	return nil
+}
+
+// validateDocumentFields memeriksa isian wajib sebuah jenis dokumen dan mengembalikan
+// DataTambahan yang sudah dirapikan: hanya isian yang terdaftar dan tidak kosong yang disimpan.
+func validateDocumentFields(docType *models.DocumentType, nik *string, lokasiHilang string, items []models.LostItem, extraData map[string]string) (map[string]string, error) {
+	cleaned := make(map[string]string)
+	var missing []string
+	for _, field := range docType.Fields {
+		var filled bool
+		switch field.Key {
+		case models.FieldNIK:
+			filled = nik != nil
+		case models.FieldLokasiHilang:
+			filled = strings.TrimSpace(lokasiHilang) != ""
+		case models.FieldLostItems:
+			filled = len(items) > 0
+		default:
+			if value := strings.TrimSpace(extraData[field.Key]); value != "" {
+				cleaned[field.Key] = value
+				filled = true
+			}
+		}
+		if field.Wajib && !filled {
+			missing = append(missing, field.Label)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("%w: %s", ErrMissingRequiredField, strings.Join(missing, ", "))
+	}
+	return cleaned, nil
+}
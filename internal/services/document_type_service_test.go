@@ -0,0 +1,51 @@
+package services
+
+import (
+	"errors"
+	"simdokpol/internal/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateDocumentFields(t *testing.T) {
+	skck := &models.DocumentType{
+		Kode: models.DocumentTypeSKCKReferral,
+		Fields: []models.DocumentField{
+			{Key: models.FieldNIK, Label: "NIK", Wajib: true},
+			{Key: "keperluan", Label: "Keperluan", Wajib: true},
+			{Key: "ditujukan_kepada", Label: "Ditujukan kepada"},
+		},
+	}
+	nik := "3171011501900001"
+
+	testCases := []struct {
+		name      string
+		nik       *string
+		extraData map[string]string
+		expected  map[string]string
+		valid     bool
+	}{
+		{
+			name:      "Sukses - Isian wajib lengkap, isian kosong dibuang",
+			nik:       &nik,
+			extraData: map[string]string{"keperluan": " Melamar kerja ", "ditujukan_kepada": "", "tidak_terdaftar": "x"},
+			expected:  map[string]string{"keperluan": "Melamar kerja"},
+			valid:     true,
+		},
+		{name: "Gagal - NIK wajib tidak diisi", extraData: map[string]string{"keperluan": "Melamar kerja"}, valid: false},
+		{name: "Gagal - Isian tambahan wajib kosong", nik: &nik, extraData: map[string]string{"keperluan": "  "}, valid: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cleaned, err := validateDocumentFields(skck, tc.nik, "", nil, tc.extraData)
+			if tc.valid {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expected, cleaned)
+			} else {
+				assert.True(t, errors.Is(err, ErrMissingRequiredField))
+			}
+		})
+	}
+}
@@ -30,4 +30,103 @@ var (
 	// ErrOldPasswordMismatch dikembalikan saat mengubah kata sandi tetapi
 	// kata sandi lama yang dimasukkan tidak cocok.
 	ErrOldPasswordMismatch = errors.New("kata sandi saat ini yang Anda masukkan salah")
+
+	// ErrInvalidVerificationToken dikembalikan saat token verifikasi pada QR code
+	// tidak dapat diurai atau tanda tangannya tidak cocok dengan dokumen.
+	ErrInvalidVerificationToken = errors.New("token verifikasi tidak valid")
+
+	// ErrVerificationBaseURLNotSet dikembalikan saat URL publik verifikasi belum diatur di Pengaturan,
+	// sehingga QR code verifikasi tidak dapat dicetak.
+	ErrVerificationBaseURLNotSet = errors.New("URL publik verifikasi dokumen belum diatur di Pengaturan")
+
+	// ErrInvalidNumberFormat dikembalikan saat format Nomor Surat di Pengaturan
+	// memuat placeholder yang tidak dikenal atau tidak memenuhi aturan penomoran.
+	ErrInvalidNumberFormat = errors.New("format nomor surat tidak valid")
+
+	// ErrInvalidNIK dikembalikan saat NIK tidak sesuai struktur NIK Dukcapil
+	// atau tidak cocok dengan tanggal lahir dan jenis kelamin pemohon.
+	ErrInvalidNIK = errors.New("NIK tidak valid")
+
+	// ErrResidentMergeConflict dikembalikan saat data penduduk yang akan digabungkan
+	// memiliki NIK berbeda, sehingga dipastikan bukan orang yang sama.
+	ErrResidentMergeConflict = errors.New("data penduduk memiliki NIK berbeda dan tidak dapat digabungkan")
+
+	// ErrInvalidResidentMerge dikembalikan saat permintaan penggabungan tidak memuat
+	// satu pun data duplikat selain data utama.
+	ErrInvalidResidentMerge = errors.New("pilih minimal satu data duplikat selain data utama")
+
+	// ErrInvalidDocumentType dikembalikan saat jenis dokumen tidak dikenal, sudah dinonaktifkan,
+	// atau definisinya di registri jenis dokumen tidak valid.
+	ErrInvalidDocumentType = errors.New("jenis dokumen tidak valid")
+
+	// ErrInvalidItemType dikembalikan saat definisi jenis barang di katalog tidak valid.
+	ErrInvalidItemType = errors.New("jenis barang tidak valid")
+
+	// ErrInvalidLostItem dikembalikan saat barang hilang merujuk jenis barang yang tidak dikenal atau
+	// nonaktif, atau atributnya tidak lengkap atau tidak sesuai tipenya.
+	ErrInvalidLostItem = errors.New("data barang hilang tidak valid")
+
+	// ErrMissingRequiredField dikembalikan saat isian yang diwajibkan oleh jenis dokumen belum diisi.
+	ErrMissingRequiredField = errors.New("isian wajib belum diisi")
+
+	// ErrInvalidStatusTransition dikembalikan saat aksi alur persetujuan (ajukan, setujui, tolak, cabut)
+	// tidak berlaku untuk status dokumen saat ini, misalnya menyetujui dokumen yang masih draf.
+	ErrInvalidStatusTransition = errors.New("aksi tidak dapat dilakukan pada status dokumen saat ini")
+
+	// ErrInvalidApprover dikembalikan saat pejabat persetuju yang dipilih tidak berizin menyetujui dokumen
+	// atau merupakan pembuat dokumen itu sendiri.
+	ErrInvalidApprover = errors.New("pejabat persetuju tidak valid")
+
+	// ErrReasonRequired dikembalikan saat penolakan atau pencabutan dokumen tidak disertai alasan.
+	ErrReasonRequired = errors.New("alasan wajib diisi")
+
+	// ErrInvalidRole dikembalikan saat peran tidak terdaftar atau definisinya tidak valid,
+	// misalnya memuat izin yang tidak dikenal.
+	ErrInvalidRole = errors.New("peran tidak valid")
+
+	// ErrRoleInUse dikembalikan saat peran yang akan dihapus masih dipakai oleh pengguna.
+	ErrRoleInUse = errors.New("peran masih dipakai oleh pengguna")
+
+	// ErrInvalidReportPeriod dikembalikan saat rentang waktu laporan kosong, terbalik,
+	// atau melebihi batas panjang yang diizinkan.
+	ErrInvalidReportPeriod = errors.New("rentang waktu laporan tidak valid")
+
+	// ErrInvalidExportColumn dikembalikan saat pilihan kolom ekspor daftar dokumen tidak dikenal.
+	ErrInvalidExportColumn = errors.New("kolom ekspor tidak dikenal")
+
+	// ErrInvalidImportFile dikembalikan saat file impor tidak dapat dibaca, formatnya tidak didukung,
+	// atau tidak memuat kolom wajib.
+	ErrInvalidImportFile = errors.New("file impor tidak valid")
+
+	// ErrImportHasErrors dikembalikan saat impor diminta disimpan tetapi masih ada baris yang tidak valid.
+	// Tidak ada satu pun baris yang disimpan.
+	ErrImportHasErrors = errors.New("masih ada baris yang tidak valid, tidak ada data yang disimpan")
+
+	// ErrBackupVerification dikembalikan saat file backup gagal diperiksa: tidak dapat dibuka sebagai
+	// database SQLite atau PRAGMA integrity_check melaporkan kerusakan.
+	ErrBackupVerification = errors.New("verifikasi file backup gagal")
+
+	// ErrIncompatibleBackup dikembalikan saat file yang akan dipulihkan bukan database SIMDOKPOL,
+	// migrasinya terhenti di tengah jalan (dirty), atau versi skemanya lebih baru dari aplikasi ini.
+	ErrIncompatibleBackup = errors.New("file backup tidak dapat dipulihkan ke aplikasi ini")
+
+	// ErrInvalidBackupSchedule dikembalikan saat jadwal backup otomatis di Pengaturan bukan ekspresi
+	// cron lima kolom yang valid.
+	ErrInvalidBackupSchedule = errors.New("jadwal backup tidak valid")
+
+	// ErrWeakBackupPassword dikembalikan saat kata sandi enkripsi backup lebih pendek dari
+	// MinBackupPasswordLength karakter.
+	ErrWeakBackupPassword = errors.New("kata sandi backup minimal 8 karakter")
+
+	// ErrBackupPasswordRequired dikembalikan saat file backup yang dipulihkan dienkripsi dengan
+	// kata sandi tetapi kata sandinya tidak diisi.
+	ErrBackupPasswordRequired = errors.New("file backup ini dienkripsi dengan kata sandi, masukkan kata sandinya")
+
+	// ErrBackupDecryption dikembalikan saat file backup terenkripsi tidak dapat dibuka: kata sandi atau
+	// kunci salah, kunci server belum diatur, atau isi file telah berubah.
+	ErrBackupDecryption = errors.New("file backup gagal didekripsi")
+
+	// ErrBackupSessionNotFound dikembalikan saat sesi penjelajah backup tidak ada, sudah ditutup,
+	// atau kedaluwarsa karena terlalu lama tidak dipakai.
+	ErrBackupSessionNotFound = errors.New("sesi penjelajah backup tidak ditemukan atau sudah ditutup")
 )
\ No newline at end of file
@@ -0,0 +1,166 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"simdokpol/internal/dto"
+	"strings"
+
+	"github.com/go-pdf/fpdf"
+)
+
+// Format waktu pada laporan serah terima, mengikuti format tanggal surat (dd-mm-yyyy).
+const handoverTimeFormat = "02-01-2006 15:04"
+
+// handoverAnomalyLabels adalah judul yang dicetak untuk setiap jenis anomali.
+var handoverAnomalyLabels = map[string]string{
+	dto.AnomalyNumberedDeleted:   "Nomor dihapus",
+	dto.AnomalyRevoked:           "Dicabut",
+	dto.AnomalyEditedAfterIssued: "Diubah setelah terbit",
+	dto.AnomalyStaleApproval:     "Persetujuan tertunda",
+}
+
+func (s *pdfService) GenerateHandoverReportPDF(report *dto.HandoverReport) ([]byte, error) {
+	appConfig, err := s.configService.GetConfig()
+	if err != nil {
+		return nil, fmt.Errorf("gagal memuat konfigurasi aplikasi: %w", err)
+	}
+
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.SetMargins(pdfMargin, pdfMargin, pdfMargin)
+	pdf.SetAutoPageBreak(true, pdfMargin)
+	pdf.SetTitle(fmt.Sprintf("Laporan Serah Terima Regu %s", report.Regu), true)
+	pdf.SetCreator("SIMDOKPOL", true)
+	pdf.AddPage()
+
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+	l := &letterWriter{pdf: pdf, tr: tr}
+
+	l.writeHeader(appConfig)
+	l.font("BU", 10)
+	l.pdf.CellFormat(0, 5, l.tr(fmt.Sprintf("LAPORAN SERAH TERIMA JAGA REGU %s", strings.ToUpper(report.Regu))), "", 1, "C", false, 0, "")
+	l.font("", pdfSmallFont)
+	l.pdf.CellFormat(0, pdfSmallLine, l.tr(fmt.Sprintf("Periode: %s s.d. %s", report.From.Format(handoverTimeFormat), report.To.Format(handoverTimeFormat))), "", 1, "C", false, 0, "")
+	l.pdf.Ln(3)
+
+	l.writeRows([]letterRow{
+		{"Dokumen terbit", fmt.Sprint(report.Summary.Issued), false},
+		{"Dokumen diubah", fmt.Sprint(report.Summary.Edited), false},
+		{"Dokumen dihapus", fmt.Sprint(report.Summary.Deleted), false},
+		{"Belum selesai", fmt.Sprint(report.Summary.Pending), false},
+		{"Anomali", fmt.Sprint(report.Summary.Anomalies), report.Summary.Anomalies > 0},
+	})
+
+	l.writeSection("A. Dokumen Terbit")
+	issuedRows := make([][]string, 0, len(report.Issued))
+	for _, doc := range report.Issued {
+		issuedRows = append(issuedRows, []string{doc.Waktu.Format(handoverTimeFormat), doc.NomorSurat, doc.NamaPemohon, doc.Operator})
+	}
+	l.writeTable([]string{"Waktu Terbit", "Nomor Surat", "Pemohon", "Operator"}, []float64{0.18, 0.37, 0.25, 0.20}, issuedRows)
+
+	l.writeSection("B. Dokumen Diubah")
+	l.writeTable([]string{"Waktu", "Pengguna", "Keterangan"}, []float64{0.18, 0.22, 0.60}, handoverActivityRows(report.Edited))
+
+	l.writeSection("C. Dokumen Dihapus")
+	l.writeTable([]string{"Waktu", "Pengguna", "Keterangan"}, []float64{0.18, 0.22, 0.60}, handoverActivityRows(report.Deleted))
+
+	l.writeSection("D. Draf dan Pengajuan yang Belum Selesai")
+	pendingRows := make([][]string, 0, len(report.Pending))
+	for _, doc := range report.Pending {
+		pendingRows = append(pendingRows, []string{doc.Waktu.Format(handoverTimeFormat), strings.ReplaceAll(doc.Status, "_", " "), doc.NamaPemohon, doc.Operator})
+	}
+	l.writeTable([]string{"Perubahan Terakhir", "Status", "Pemohon", "Operator"}, []float64{0.18, 0.27, 0.30, 0.25}, pendingRows)
+
+	l.writeSection("E. Anomali")
+	anomalyRows := make([][]string, 0, len(report.Anomalies))
+	for _, anomaly := range report.Anomalies {
+		anomalyRows = append(anomalyRows, []string{anomaly.Waktu.Format(handoverTimeFormat), handoverAnomalyLabels[anomaly.Jenis], anomaly.Keterangan})
+	}
+	l.writeTable([]string{"Waktu", "Jenis", "Keterangan"}, []float64{0.18, 0.22, 0.60}, anomalyRows)
+
+	l.writeHandoverSignatures(report)
+
+	if err := pdf.Error(); err != nil {
+		return nil, fmt.Errorf("gagal merender PDF: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, fmt.Errorf("gagal menulis PDF: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func handoverActivityRows(activities []dto.HandoverActivity) [][]string {
+	rows := make([][]string, 0, len(activities))
+	for _, activity := range activities {
+		rows = append(rows, []string{activity.Waktu.Format(handoverTimeFormat), activity.Pengguna, activity.Detail})
+	}
+	return rows
+}
+
+func (l *letterWriter) writeSection(title string) {
+	l.pdf.Ln(2)
+	l.font("B", pdfBodyFont)
+	l.pdf.CellFormat(0, pdfLineHeight+1, l.tr(title), "", 1, "L", false, 0, "")
+}
+
+// writeTable menulis tabel berbingkai. widths adalah proporsi lebar tiap kolom terhadap lebar isi halaman;
+// tinggi baris mengikuti sel dengan teks terpanjang.
+func (l *letterWriter) writeTable(headers []string, widths []float64, rows [][]string) {
+	left, _, _, _ := l.pdf.GetMargins()
+	colWidths := make([]float64, len(widths))
+	for i, w := range widths {
+		colWidths[i] = w * l.contentWidth()
+	}
+
+	l.font("B", pdfSmallFont)
+	for i, header := range headers {
+		l.pdf.CellFormat(colWidths[i], pdfSmallLine+1, l.tr(header), "1", 0, "C", false, 0, "")
+	}
+	l.pdf.Ln(-1)
+
+	l.font("", pdfSmallFont)
+	if len(rows) == 0 {
+		l.pdf.CellFormat(l.contentWidth(), pdfSmallLine+1, "Tidak ada.", "1", 1, "C", false, 0, "")
+		return
+	}
+	for _, row := range rows {
+		lines := 1
+		for i, cell := range row {
+			if n := len(l.pdf.SplitText(l.tr(cell), colWidths[i]-2)); n > lines {
+				lines = n
+			}
+		}
+		height := float64(lines)*pdfSmallLine + 1
+		_, pageHeight := l.pdf.GetPageSize()
+		_, _, _, bottom := l.pdf.GetMargins()
+		if l.pdf.GetY()+height > pageHeight-bottom {
+			l.pdf.AddPage()
+		}
+		y := l.pdf.GetY()
+		x := left
+		for i, cell := range row {
+			l.pdf.Rect(x, y, colWidths[i], height, "D")
+			l.pdf.SetXY(x+1, y+0.5)
+			l.pdf.MultiCell(colWidths[i]-2, pdfSmallLine, l.tr(cell), "", "L", false)
+			x += colWidths[i]
+		}
+		l.pdf.SetXY(left, y+height)
+	}
+}
+
+// writeHandoverSignatures menulis kolom tanda tangan regu yang menyerahkan dan yang menerima.
+func (l *letterWriter) writeHandoverSignatures(report *dto.HandoverReport) {
+	l.pdf.Ln(6)
+	half := l.contentWidth() / 2
+	l.font("", pdfBodyFont)
+	l.pdf.CellFormat(half, pdfLineHeight, "Yang Menyerahkan,", "", 0, "C", false, 0, "")
+	l.pdf.CellFormat(half, pdfLineHeight, "Yang Menerima,", "", 1, "C", false, 0, "")
+	l.pdf.Ln(pdfSignSpace + 4)
+	l.font("B", pdfBodyFont)
+	l.pdf.CellFormat(half, pdfLineHeight, l.tr(strings.ToUpper(report.GeneratedBy)), "", 0, "C", false, 0, "")
+	l.pdf.CellFormat(half, pdfLineHeight, "(...............................)", "", 1, "C", false, 0, "")
+	l.font("", pdfSmallFont)
+	l.pdf.CellFormat(0, pdfSmallLine, l.tr(fmt.Sprintf("Dibuat %s", report.GeneratedAt.Format(handoverTimeFormat))), "", 1, "L", false, 0, "")
+}
@@ -0,0 +1,338 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"simdokpol/internal/models"
+	"simdokpol/internal/repositories"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	itemTypeCodePattern = regexp.MustCompile(`^[A-Z][A-Z0-9_]{1,49}$`)
+	nopolPattern        = regexp.MustCompile(`^([A-Z]{1,2}) ?([0-9]{1,4}) ?([A-Z]{0,3})$`)
+)
+
+// availableAttributeTypes adalah tipe atribut jenis barang yang dikenal normalizeAttribute.
+var availableAttributeTypes = map[string]bool{
+	models.AttributeText: true, models.AttributeAngka: true, models.AttributePilihan: true,
+	models.AttributeNIK: true, models.AttributeNopol: true, models.AttributeIMEI: true,
+}
+
+type ItemTypeService interface {
+	FindAll(activeOnly bool) ([]models.ItemType, error)
+	FindByCode(kode string) (*models.ItemType, error)
+	Create(itemType *models.ItemType, actorID uint) (*models.ItemType, error)
+	Update(kode string, itemType *models.ItemType, actorID uint) (*models.ItemType, error)
+}
+
+type itemTypeService struct {
+	typeRepo     repositories.ItemTypeRepository
+	auditService AuditLogService
+}
+
+func NewItemTypeService(typeRepo repositories.ItemTypeRepository, auditService AuditLogService) ItemTypeService {
+	return &itemTypeService{
+		typeRepo:     typeRepo,
+		auditService: auditService,
+	}
+}
+
+func (s *itemTypeService) FindAll(activeOnly bool) ([]models.ItemType, error) {
+	return s.typeRepo.FindAll(activeOnly)
+}
+
+func (s *itemTypeService) FindByCode(kode string) (*models.ItemType, error) {
+	itemType, err := s.typeRepo.FindByCode(kode)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return itemType, nil
+}
+
+func (s *itemTypeService) Create(itemType *models.ItemType, actorID uint) (*models.ItemType, error) {
+	itemType.Kode = strings.ToUpper(strings.TrimSpace(itemType.Kode))
+	if _, err := s.typeRepo.FindByCode(itemType.Kode); err == nil {
+		return nil, fmt.Errorf("%w: kode %s sudah dipakai", ErrInvalidItemType, itemType.Kode)
+	} else if err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+	if err := s.validateDefinition(itemType); err != nil {
+		return nil, err
+	}
+
+	created, err := s.typeRepo.Create(itemType)
+	if err != nil {
+		return nil, err
+	}
+	s.classifyUntypedItems()
+	s.auditService.LogActivity(actorID, models.AuditCreateItemType, fmt.Sprintf("Menambahkan jenis barang %s (%s)", created.Nama, created.Kode))
+	return created, nil
+}
+
+// Update menimpa definisi jenis barang. Kode tidak dapat diubah karena dipakai oleh barang yang sudah tersimpan.
+// Atribut yang dihapus tidak menghapus nilai lama pada barang yang sudah tersimpan.
+func (s *itemTypeService) Update(kode string, itemType *models.ItemType, actorID uint) (*models.ItemType, error) {
+	existing, err := s.FindByCode(kode)
+	if err != nil {
+		return nil, err
+	}
+	itemType.Kode = existing.Kode
+	itemType.CreatedAt = existing.CreatedAt
+	if err := s.validateDefinition(itemType); err != nil {
+		return nil, err
+	}
+
+	updated, err := s.typeRepo.Update(itemType)
+	if err != nil {
+		return nil, err
+	}
+	s.classifyUntypedItems()
+	s.auditService.LogActivity(actorID, models.AuditUpdateItemType, fmt.Sprintf("Memperbarui jenis barang %s (%s)", updated.Nama, updated.Kode))
+	return updated, nil
+}
+
+// classifyUntypedItems mengelompokkan ulang barang lama yang belum memiliki jenis setelah nama atau alias
+// katalog berubah. Kegagalan hanya dicatat karena tidak memengaruhi definisi yang sudah tersimpan.
+func (s *itemTypeService) classifyUntypedItems() {
+	count, err := s.typeRepo.ClassifyUntypedItems()
+	if err != nil {
+		log.Printf("ERROR: Gagal mengelompokkan barang lama ke katalog jenis barang: %v", err)
+		return
+	}
+	if count > 0 {
+		log.Printf("INFO: %d barang lama dikelompokkan ke katalog jenis barang.", count)
+	}
+}
+
+// validateDefinition memeriksa definisi jenis barang sebelum disimpan. Alias disimpan dalam huruf kapital
+// dan tidak boleh sama dengan kode, nama, atau alias jenis barang lain agar pengelompokan tidak ambigu.
+func (s *itemTypeService) validateDefinition(itemType *models.ItemType) error {
+	itemType.Nama = strings.TrimSpace(itemType.Nama)
+	if !itemTypeCodePattern.MatchString(itemType.Kode) {
+		return fmt.Errorf("%w: kode hanya boleh berisi huruf kapital, angka, dan garis bawah", ErrInvalidItemType)
+	}
+	if itemType.Nama == "" {
+		return fmt.Errorf("%w: nama jenis barang wajib diisi", ErrInvalidItemType)
+	}
+
+	names := map[string]bool{normalizeItemName(itemType.Kode): true, normalizeItemName(itemType.Nama): true}
+	aliases := make([]string, 0, len(itemType.Alias))
+	for _, alias := range itemType.Alias {
+		alias = normalizeItemName(alias)
+		if alias == "" || names[alias] {
+			continue
+		}
+		names[alias] = true
+		aliases = append(aliases, alias)
+	}
+	itemType.Alias = aliases
+
+	others, err := s.typeRepo.FindAll(false)
+	if err != nil {
+		return err
+	}
+	for i := range others {
+		if others[i].Kode == itemType.Kode {
+			continue
+		}
+		for _, name := range append([]string{others[i].Kode, others[i].Nama}, others[i].Alias...) {
+			if names[normalizeItemName(name)] {
+				return fmt.Errorf("%w: nama atau alias %q sudah dipakai jenis barang %s", ErrInvalidItemType, name, others[i].Nama)
+			}
+		}
+	}
+
+	seen := make(map[string]bool)
+	for i := range itemType.Atribut {
+		attr := &itemType.Atribut[i]
+		attr.Label = strings.TrimSpace(attr.Label)
+		if attr.Tipe == "" {
+			attr.Tipe = models.AttributeText
+		}
+		if !documentFieldKeyPattern.MatchString(attr.Key) {
+			return fmt.Errorf("%w: key atribut %q hanya boleh berisi huruf kecil, angka, dan garis bawah", ErrInvalidItemType, attr.Key)
+		}
+		if seen[attr.Key] {
+			return fmt.Errorf("%w: key atribut %q dipakai lebih dari sekali", ErrInvalidItemType, attr.Key)
+		}
+		seen[attr.Key] = true
+		if attr.Label == "" {
+			return fmt.Errorf("%w: label atribut %q wajib diisi", ErrInvalidItemType, attr.Key)
+		}
+		if !availableAttributeTypes[attr.Tipe] {
+			return fmt.Errorf("%w: tipe atribut %q tidak dikenal", ErrInvalidItemType, attr.Tipe)
+		}
+		if attr.Tipe != models.AttributePilihan {
+			attr.Opsi = nil
+			continue
+		}
+		if attr.Cocokkan {
+			return fmt.Errorf("%w: atribut pilihan %q tidak dapat dicocokkan antar laporan", ErrInvalidItemType, attr.Label)
+		}
+		options := make([]string, 0, len(attr.Opsi))
+		for _, option := range attr.Opsi {
+			if option = strings.TrimSpace(option); option != "" {
+				options = append(options, option)
+			}
+		}
+		if len(options) == 0 {
+			return fmt.Errorf("%w: atribut pilihan %q harus memiliki minimal satu opsi", ErrInvalidItemType, attr.Label)
+		}
+		attr.Opsi = options
+	}
+	if itemType.Atribut == nil {
+		itemType.Atribut = []models.ItemAttribute{}
+	}
+	return nil
+}
+
+// normalizeItemName menyeragamkan nama barang untuk dicocokkan dengan kode, nama, dan alias katalog.
+func normalizeItemName(name string) string {
+	return strings.ToUpper(strings.TrimSpace(name))
+}
+
+// classifyItemName mengembalikan kode jenis barang yang kode, nama, atau aliasnya sama dengan name,
+// atau string kosong bila tidak ada yang cocok.
+func classifyItemName(catalogue []models.ItemType, name string) string {
+	name = normalizeItemName(name)
+	if name == "" {
+		return ""
+	}
+	for _, itemType := range catalogue {
+		if name == normalizeItemName(itemType.Kode) || name == normalizeItemName(itemType.Nama) {
+			return itemType.Kode
+		}
+		for _, alias := range itemType.Alias {
+			if name == alias {
+				return itemType.Kode
+			}
+		}
+	}
+	return ""
+}
+
+// normalizeLostItems memvalidasi barang hilang terhadap katalog dan mengembalikan barang yang siap disimpan.
+// Barang dengan JenisBarang harus merujuk jenis yang dikenal (dan aktif, kecuali allowInactive), atributnya
+// diperiksa sesuai tipe, lalu NamaBarang dan Deskripsi disusun dari nama jenis dan atributnya.
+// Barang tanpa JenisBarang adalah barang bebas; jenisnya dikenali dari nama bila cocok dengan katalog.
+func normalizeLostItems(catalogue []models.ItemType, items []models.LostItem, allowInactive bool) ([]models.LostItem, error) {
+	byCode := make(map[string]*models.ItemType, len(catalogue))
+	for i := range catalogue {
+		byCode[catalogue[i].Kode] = &catalogue[i]
+	}
+
+	normalized := make([]models.LostItem, 0, len(items))
+	for i, item := range items {
+		position := fmt.Sprintf("barang %d", i+1)
+		if item.JenisBarang == "" {
+			item.NamaBarang = strings.TrimSpace(item.NamaBarang)
+			item.Deskripsi = strings.TrimSpace(item.Deskripsi)
+			if item.NamaBarang == "" {
+				return nil, fmt.Errorf("%w: nama %s wajib diisi", ErrInvalidLostItem, position)
+			}
+			item.JenisBarang = classifyItemName(catalogue, item.NamaBarang)
+			item.Atribut = nil
+			normalized = append(normalized, item)
+			continue
+		}
+
+		itemType, ok := byCode[item.JenisBarang]
+		if !ok {
+			return nil, fmt.Errorf("%w: jenis %s tidak dikenal", ErrInvalidLostItem, item.JenisBarang)
+		}
+		if !itemType.Aktif && !allowInactive {
+			return nil, fmt.Errorf("%w: jenis barang %s sudah dinonaktifkan", ErrInvalidLostItem, itemType.Nama)
+		}
+		attributes := make(map[string]string)
+		var parts []string
+		for _, attr := range itemType.Atribut {
+			value, err := normalizeAttribute(attr, item.Atribut[attr.Key])
+			if err != nil {
+				return nil, fmt.Errorf("%w: %s pada %s (%s) %s", ErrInvalidLostItem, attr.Label, position, itemType.Nama, err.Error())
+			}
+			if value == "" {
+				if attr.Wajib {
+					return nil, fmt.Errorf("%w: %s pada %s (%s) wajib diisi", ErrInvalidLostItem, attr.Label, position, itemType.Nama)
+				}
+				continue
+			}
+			attributes[attr.Key] = value
+			parts = append(parts, attr.Label+": "+value)
+		}
+		normalized = append(normalized, models.LostItem{
+			NamaBarang:  itemType.Nama,
+			Deskripsi:   strings.Join(parts, ", "),
+			JenisBarang: itemType.Kode,
+			Atribut:     attributes,
+		})
+	}
+	return normalized, nil
+}
+
+// normalizeAttribute memeriksa satu nilai atribut sesuai tipenya dan mengembalikan bentuk bakunya:
+// nomor polisi ditulis "B 1234 XYZ", sedangkan NIK, IMEI, dan angka tanpa spasi atau tanda hubung.
+// Nilai kosong dikembalikan apa adanya; pemeriksaan wajib dilakukan pemanggil.
+func normalizeAttribute(attr models.ItemAttribute, value string) (string, error) {
+	value = strings.Join(strings.Fields(value), " ")
+	if value == "" {
+		return "", nil
+	}
+	switch attr.Tipe {
+	case models.AttributeAngka, models.AttributeNIK, models.AttributeIMEI:
+		value = strings.NewReplacer(" ", "", "-", "", ".", "").Replace(value)
+		for _, r := range value {
+			if r < '0' || r > '9' {
+				return "", fmt.Errorf("hanya boleh berisi angka")
+			}
+		}
+		if attr.Tipe == models.AttributeNIK {
+			if err := ValidateNIK(value, time.Time{}, ""); err != nil {
+				return "", fmt.Errorf("tidak valid: %s", strings.TrimPrefix(err.Error(), ErrInvalidNIK.Error()+": "))
+			}
+		}
+		if attr.Tipe == models.AttributeIMEI && !isValidIMEI(value) {
+			return "", fmt.Errorf("harus 15 digit dengan digit pemeriksa yang benar")
+		}
+	case models.AttributeNopol:
+		match := nopolPattern.FindStringSubmatch(strings.ToUpper(value))
+		if match == nil {
+			return "", fmt.Errorf("tidak sesuai format nomor polisi, contoh: B 1234 XYZ")
+		}
+		value = strings.TrimSpace(match[1] + " " + match[2] + " " + match[3])
+	case models.AttributePilihan:
+		for _, option := range attr.Opsi {
+			if strings.EqualFold(option, value) {
+				return option, nil
+			}
+		}
+		return "", fmt.Errorf("harus salah satu dari: %s", strings.Join(attr.Opsi, ", "))
+	}
+	return value, nil
+}
+
+// isValidIMEI memeriksa IMEI 15 digit dengan algoritma Luhn pada digit terakhirnya.
+func isValidIMEI(imei string) bool {
+	if len(imei) != 15 {
+		return false
+	}
+	sum := 0
+	for i, r := range imei {
+		digit := int(r - '0')
+		if i%2 == 1 {
+			digit *= 2
+			if digit > 9 {
+				digit -= 9
+			}
+		}
+		sum += digit
+	}
+	return sum%10 == 0
+}
@@ -0,0 +1,93 @@
+package services
+
+import (
+	"errors"
+	"simdokpol/internal/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func itemCatalogue() []models.ItemType {
+	return []models.ItemType{
+		{Kode: "KTP", Nama: "KTP", Alias: []string{"E-KTP"}, Aktif: true, Atribut: []models.ItemAttribute{
+			{Key: "nik", Label: "NIK", Tipe: models.AttributeNIK},
+		}},
+		{Kode: "STNK", Nama: "STNK", Aktif: true, Atribut: []models.ItemAttribute{
+			{Key: "nopol", Label: "No. Pol", Tipe: models.AttributeNopol, Wajib: true},
+			{Key: "nomor_rangka", Label: "No. Rangka", Tipe: models.AttributeText},
+		}},
+		{Kode: "HP", Nama: "Handphone", Aktif: true, Atribut: []models.ItemAttribute{
+			{Key: "merek", Label: "Merek", Tipe: models.AttributePilihan, Opsi: []string{"Samsung", "Xiaomi"}},
+			{Key: "imei", Label: "IMEI", Tipe: models.AttributeIMEI},
+		}},
+		{Kode: "PASPOR", Nama: "Paspor", Aktif: false},
+	}
+}
+
+func TestClassifyItemName(t *testing.T) {
+	catalogue := itemCatalogue()
+	assert.Equal(t, "KTP", classifyItemName(catalogue, " e-ktp "))
+	assert.Equal(t, "HP", classifyItemName(catalogue, "handphone"))
+	assert.Equal(t, "", classifyItemName(catalogue, "Dompet"))
+	assert.Equal(t, "", classifyItemName(catalogue, "  "))
+}
+
+func TestNormalizeLostItems(t *testing.T) {
+	testCases := []struct {
+		name          string
+		items         []models.LostItem
+		allowInactive bool
+		expected      []models.LostItem
+		valid         bool
+	}{
+		{
+			name: "Sukses - Nomor polisi dibakukan dan deskripsi disusun dari atribut",
+			items: []models.LostItem{{JenisBarang: "STNK", NamaBarang: "diabaikan", Deskripsi: "diabaikan",
+				Atribut: map[string]string{"nopol": "b1234xyz", "nomor_rangka": " MH1JF  123 ", "lain": "x"}}},
+			expected: []models.LostItem{{JenisBarang: "STNK", NamaBarang: "STNK", Deskripsi: "No. Pol: B 1234 XYZ, No. Rangka: MH1JF 123",
+				Atribut: map[string]string{"nopol": "B 1234 XYZ", "nomor_rangka": "MH1JF 123"}}},
+			valid: true,
+		},
+		{
+			name:  "Sukses - IMEI valid dan opsi pilihan mengikuti katalog",
+			items: []models.LostItem{{JenisBarang: "HP", Atribut: map[string]string{"merek": "samsung", "imei": "49-015420-323751-8"}}},
+			expected: []models.LostItem{{JenisBarang: "HP", NamaBarang: "Handphone", Deskripsi: "Merek: Samsung, IMEI: 490154203237518",
+				Atribut: map[string]string{"merek": "Samsung", "imei": "490154203237518"}}},
+			valid: true,
+		},
+		{
+			name:     "Sukses - Barang bebas dikenali dari alias",
+			items:    []models.LostItem{{NamaBarang: " E-KTP ", Deskripsi: "NIK: 3171011501900001"}},
+			expected: []models.LostItem{{JenisBarang: "KTP", NamaBarang: "E-KTP", Deskripsi: "NIK: 3171011501900001"}},
+			valid:    true,
+		},
+		{
+			name:          "Sukses - Jenis nonaktif diizinkan saat menyunting",
+			items:         []models.LostItem{{JenisBarang: "PASPOR"}},
+			allowInactive: true,
+			expected:      []models.LostItem{{JenisBarang: "PASPOR", NamaBarang: "Paspor", Atribut: map[string]string{}}},
+			valid:         true,
+		},
+		{name: "Gagal - Atribut wajib kosong", items: []models.LostItem{{JenisBarang: "STNK", Atribut: map[string]string{"nomor_rangka": "MH1"}}}},
+		{name: "Gagal - Nomor polisi tidak sesuai format", items: []models.LostItem{{JenisBarang: "STNK", Atribut: map[string]string{"nopol": "1234 B"}}}},
+		{name: "Gagal - Digit pemeriksa IMEI salah", items: []models.LostItem{{JenisBarang: "HP", Atribut: map[string]string{"imei": "490154203237517"}}}},
+		{name: "Gagal - NIK tidak valid", items: []models.LostItem{{JenisBarang: "KTP", Atribut: map[string]string{"nik": "12345"}}}},
+		{name: "Gagal - Opsi di luar daftar", items: []models.LostItem{{JenisBarang: "HP", Atribut: map[string]string{"merek": "Nokia"}}}},
+		{name: "Gagal - Jenis tidak dikenal", items: []models.LostItem{{JenisBarang: "SENJATA"}}},
+		{name: "Gagal - Jenis nonaktif untuk surat baru", items: []models.LostItem{{JenisBarang: "PASPOR"}}},
+		{name: "Gagal - Barang bebas tanpa nama", items: []models.LostItem{{NamaBarang: "  ", Deskripsi: "Warna hitam"}}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			normalized, err := normalizeLostItems(itemCatalogue(), tc.items, tc.allowInactive)
+			if tc.valid {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expected, normalized)
+			} else {
+				assert.True(t, errors.Is(err, ErrInvalidLostItem), "error: %v", err)
+			}
+		})
+	}
+}
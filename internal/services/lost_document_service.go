@@ -4,40 +4,78 @@ import (
 	"errors"
 	"fmt"
 	"gorm.io/gorm"
-	"log"
 	"simdokpol/internal/models"
 	"simdokpol/internal/repositories"
-	"strconv"
 	"strings"
 	"time"
 )
 
 type LostDocumentService interface {
-	CreateLostDocument(residentData models.Resident, items []models.LostItem, operatorID uint, lokasiHilang string, petugasPelaporID uint, pejabatPersetujuID uint) (*models.LostDocument, error)
-	UpdateLostDocument(docID uint, residentData models.Resident, items []models.LostItem, lokasiHilang string, petugasPelaporID uint, pejabatPersetujuID uint, loggedInUserID uint) (*models.LostDocument, error)
-	FindAll(query string, statusFilter string) ([]models.LostDocument, error)
-	SearchGlobal(query string) ([]models.LostDocument, error)
+	// CreateLostDocument menyimpan dokumen baru berjenis documentType (kode di registri jenis dokumen)
+	// sebagai DRAF tanpa Nomor Surat. extraData berisi isian khusus jenis dokumen tersebut.
+	CreateLostDocument(documentType string, residentData models.Resident, items []models.LostItem, extraData map[string]string, operatorID uint, lokasiHilang string, petugasPelaporID uint, pejabatPersetujuID uint) (*models.LostDocument, error)
+	UpdateLostDocument(docID uint, residentData models.Resident, items []models.LostItem, extraData map[string]string, lokasiHilang string, petugasPelaporID uint, pejabatPersetujuID uint, loggedInUserID uint) (*models.LostDocument, error)
+	// FindPage dan SearchGlobal hanya mengembalikan dokumen yang terlihat oleh actorID:
+	// dokumen miliknya, milik anggota regu yang sama, yang diajukan kepadanya, atau semua dokumen
+	// bagi pemegang izin document.view_all. FindPage mengembalikan satu halaman daftar beserta jumlahnya.
+	FindPage(statusFilter string, list repositories.ListQuery, actorID uint) ([]models.LostDocument, repositories.PageResult, error)
+	SearchGlobal(query string, actorID uint) ([]repositories.DocumentSearchHit, error)
+	// ExportDocuments menulis dokumen yang cocok dengan filter dan terlihat oleh actorID ke w, satu baris
+	// per dokumen dengan kolom pilihan columns (kosong berarti semua kolom), lalu mengembalikan jumlahnya.
+	// Kolom tidak dikenal menghasilkan ErrInvalidExportColumn sebelum apa pun ditulis ke w.
+	ExportDocuments(w SpreadsheetRowWriter, filter repositories.DocumentFilter, columns []string, actorID uint) (int, error)
 	FindByID(id uint, actorID uint) (*models.LostDocument, error)
-	DeleteLostDocument(id uint, loggedInUserID uint) error
+	// FindIdentifierDuplicates mengelompokkan nomor identitas barang (NIK, nomor polisi, IMEI, dst.) yang
+	// tercatat pada lebih dari satu laporan. Izin document.crosscheck diperiksa di rute.
+	FindIdentifierDuplicates() ([]repositories.IdentifierMatchGroup, error)
+	// FindIdentifierMatches mengambil laporan lain yang memuat nomor identitas barang yang sama dengan
+	// dokumen docID. actorID harus dapat melihat dokumen docID.
+	FindIdentifierMatches(docID uint, actorID uint) ([]repositories.IdentifierMatchGroup, error)
+	// DeleteLostDocument memindahkan dokumen ke tong sampah; alasan penghapusan wajib diisi.
+	DeleteLostDocument(id uint, loggedInUserID uint, alasan string) error
+	// RestoreRevision mengembalikan isi dokumen ke revisi tertentu dan mencatatnya sebagai revisi baru.
+	// Hanya untuk pemegang izin document.restore_revision dan hanya pada dokumen berstatus DRAF.
+	RestoreRevision(docID uint, revisionNumber int, actorID uint) (*models.LostDocument, error)
+
+	// SubmitForApproval mengajukan draf ke pejabat persetuju. Hanya pemilik dokumen, anggota regunya,
+	// atau pemegang izin document.edit_all.
+	SubmitForApproval(docID uint, actorID uint) (*models.LostDocument, error)
+	// ApproveDocument menerbitkan dokumen: Nomor Surat diambil dan TanggalPersetujuan dicap.
+	// Hanya pejabat persetuju yang ditunjuk pada dokumen.
+	ApproveDocument(docID uint, actorID uint) (*models.LostDocument, error)
+	// RejectDocument mengembalikan dokumen ke DRAF beserta alasannya. Hanya pejabat persetuju yang ditunjuk.
+	RejectDocument(docID uint, actorID uint, alasan string) (*models.LostDocument, error)
+	// RevokeDocument mencabut surat yang sudah terbit. Hanya pejabat persetuju dokumen atau Super Admin.
+	RevokeDocument(docID uint, actorID uint, alasan string) (*models.LostDocument, error)
+	// FindApprovalQueue mengambil dokumen yang menunggu persetujuan actorID.
+	FindApprovalQueue(actorID uint) ([]models.LostDocument, error)
 }
 
 type lostDocumentService struct {
-	db            *gorm.DB
-	docRepo       repositories.LostDocumentRepository
-	residentRepo  repositories.ResidentRepository
-	userRepo      repositories.UserRepository
-	auditService  AuditLogService
-	configService ConfigService
+	db               *gorm.DB
+	docRepo          repositories.LostDocumentRepository
+	residentRepo     repositories.ResidentRepository
+	userRepo         repositories.UserRepository
+	auditService     AuditLogService
+	configService    ConfigService
+	numberingService DocumentNumberingService
+	revisionService  DocumentRevisionService
+	docTypeService   DocumentTypeService
+	itemTypeService  ItemTypeService
 }
 
-func NewLostDocumentService(db *gorm.DB, docRepo repositories.LostDocumentRepository, residentRepo repositories.ResidentRepository, userRepo repositories.UserRepository, auditService AuditLogService, configService ConfigService) LostDocumentService {
+func NewLostDocumentService(db *gorm.DB, docRepo repositories.LostDocumentRepository, residentRepo repositories.ResidentRepository, userRepo repositories.UserRepository, auditService AuditLogService, configService ConfigService, numberingService DocumentNumberingService, revisionService DocumentRevisionService, docTypeService DocumentTypeService, itemTypeService ItemTypeService) LostDocumentService {
 	return &lostDocumentService{
-		db:            db,
-		docRepo:       docRepo,
-		residentRepo:  residentRepo,
-		userRepo:      userRepo,
-		auditService:  auditService,
-		configService: configService,
+		db:               db,
+		docRepo:          docRepo,
+		residentRepo:     residentRepo,
+		userRepo:         userRepo,
+		auditService:     auditService,
+		configService:    configService,
+		numberingService: numberingService,
+		revisionService:  revisionService,
+		docTypeService:   docTypeService,
+		itemTypeService:  itemTypeService,
 	}
 }
 
@@ -52,113 +90,89 @@ func (s *lostDocumentService) FindByID(id uint, actorID uint) (*models.LostDocum
 		return nil, errors.New("pengguna tidak valid")
 	}
 
-	if actor.Peran != models.RoleSuperAdmin && doc.OperatorID != actorID {
-		return nil, errors.New("akses ditolak")
+	if !canViewDocument(actor, doc) {
+		return nil, ErrAccessDenied
 	}
 
-	appConfig, _ := s.configService.GetConfig()
-	archiveDuration := time.Duration(appConfig.ArchiveDurationDays) * 24 * time.Hour
+	return doc, nil
+}
 
-	if doc.Status == "DITERBITKAN" && time.Now().After(doc.TanggalLaporan.Add(archiveDuration)) {
-		doc.Status = "DIARSIPKAN"
-	}
+func (s *lostDocumentService) FindIdentifierDuplicates() ([]repositories.IdentifierMatchGroup, error) {
+	return s.docRepo.FindIdentifierDuplicates()
+}
 
-	return doc, nil
+func (s *lostDocumentService) FindIdentifierMatches(docID uint, actorID uint) ([]repositories.IdentifierMatchGroup, error) {
+	if _, err := s.FindByID(docID, actorID); err != nil {
+		return nil, err
+	}
+	return s.docRepo.FindIdentifierMatches(docID)
 }
 
-func (s *lostDocumentService) generateDocumentNumber() (string, error) {
-	loc, err := s.configService.GetLocation()
+func (s *lostDocumentService) CreateLostDocument(documentType string, residentData models.Resident, items []models.LostItem, extraData map[string]string, operatorID uint, lokasiHilang string, petugasPelaporID uint, pejabatPersetujuID uint) (*models.LostDocument, error) {
+	if residentData.NIK != nil {
+		if err := ValidateNIK(*residentData.NIK, residentData.TanggalLahir, residentData.JenisKelamin); err != nil {
+			return nil, err
+		}
+	}
+
+	docType, err := s.findDocumentType(documentType)
 	if err != nil {
-		loc = time.UTC
+		return nil, err
 	}
-	now := time.Now().In(loc)
-	year := now.Year()
-	month := int(now.Month())
-	monthRoman := intToRoman(month)
-	lastNumFromDB := 0
-	lastDoc, err := s.docRepo.GetLastDocumentOfYear(year)
-	if err != nil && err != gorm.ErrRecordNotFound {
-		return "", err
-	}
-	if err == nil && lastDoc != nil {
-		parts := strings.Split(lastDoc.NomorSurat, "/")
-		if len(parts) > 1 {
-			if num, err := strconv.Atoi(parts[1]); err == nil {
-				lastNumFromDB = num
-			}
-		}
+	if !docType.Aktif {
+		return nil, fmt.Errorf("%w: jenis dokumen %s sudah dinonaktifkan", ErrInvalidDocumentType, docType.Nama)
 	}
-	lastNumFromConfig := 0
-	appConfig, err := s.configService.GetConfig()
+	items, err = s.normalizeItems(items, false)
 	if err != nil {
-		log.Printf("PERINGATAN: Tidak dapat memuat konfigurasi untuk penomoran surat: %v", err)
-	} else {
-		if num, err := strconv.Atoi(appConfig.NomorSuratTerakhir); err == nil {
-			lastNumFromConfig = num
-		}
+		return nil, err
 	}
-	trueLastNumber := 0
-	if lastNumFromDB > lastNumFromConfig {
-		trueLastNumber = lastNumFromDB
-	} else {
-		trueLastNumber = lastNumFromConfig
+	dataTambahan, err := validateDocumentFields(docType, residentData.NIK, lokasiHilang, items, extraData)
+	if err != nil {
+		return nil, err
+	}
+
+	petugasPelapor, err := s.userRepo.FindByID(petugasPelaporID)
+	if err != nil {
+		return nil, fmt.Errorf("gagal memuat data petugas pelapor: %w", err)
+	}
+	if err := s.validateApprover(pejabatPersetujuID, operatorID); err != nil {
+		return nil, err
 	}
-	runningNumber := trueLastNumber + 1
-	return fmt.Sprintf(appConfig.FormatNomorSurat, runningNumber, monthRoman, year), nil
-}
 
-func (s *lostDocumentService) CreateLostDocument(residentData models.Resident, items []models.LostItem, operatorID uint, lokasiHilang string, petugasPelaporID uint, pejabatPersetujuID uint) (*models.LostDocument, error) {
 	var createdDocID uint
-	var finalDocNumber string
-	err := s.db.Transaction(func(tx *gorm.DB) error {
-		var existingResident models.Resident
-		err := tx.Where("nama_lengkap = ? AND tanggal_lahir = ?", residentData.NamaLengkap, residentData.TanggalLahir).First(&existingResident).Error
-		if err == gorm.ErrRecordNotFound {
-			// Komentar: Ini adalah solusi untuk menangani pemohon tanpa NIK.
-			// Untuk produksi, alur ini mungkin perlu disempurnakan,
-			// misalnya dengan menyimpan sebagai draf atau integrasi dengan data kependudukan.
-			residentData.NIK = fmt.Sprintf("TEMP%d", time.Now().UnixNano())
-			newResident, createErr := s.residentRepo.Create(tx, &residentData)
-			if createErr != nil {
-				return createErr
-			}
-			existingResident = *newResident
-		} else if err != nil {
-			return err
-		}
-		docNumber, err := s.generateDocumentNumber()
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		resident, err := s.resolveResident(tx, residentData)
 		if err != nil {
 			return err
 		}
-		finalDocNumber = docNumber
-		loc, err := s.configService.GetLocation()
-		if err != nil {
-			loc = time.UTC
-		}
-		now := time.Now().In(loc)
 		newDoc := &models.LostDocument{
-			NomorSurat:         docNumber,
-			TanggalLaporan:     now,
-			Status:             "DITERBITKAN",
+			TanggalLaporan:     s.now(),
+			Status:             models.StatusDraf,
 			LokasiHilang:       lokasiHilang,
-			ResidentID:         existingResident.ID,
+			JenisDokumen:       docType.Kode,
+			DataTambahan:       dataTambahan,
+			ResidentID:         resident.ID,
 			PetugasPelaporID:   petugasPelaporID,
 			PejabatPersetujuID: &pejabatPersetujuID,
 			OperatorID:         operatorID,
-			TanggalPersetujuan: &now,
 			LostItems:          items,
 		}
 		created, err := s.docRepo.Create(tx, newDoc)
 		if err != nil {
 			return err
 		}
+		created.Resident = *resident
+		created.PetugasPelapor = *petugasPelapor
+		if err := s.revisionService.RecordRevision(tx, created, models.RevisionCreated, operatorID); err != nil {
+			return err
+		}
 		createdDocID = created.ID
 		return nil
 	})
 	if err != nil {
 		return nil, err
 	}
-	s.auditService.LogActivity(operatorID, models.AuditCreateDocument, fmt.Sprintf("Membuat surat keterangan hilang baru dengan nomor: %s", finalDocNumber))
+	s.auditService.LogActivity(operatorID, models.AuditCreateDocument, fmt.Sprintf("Membuat draf %s baru (ID %d) atas nama %s", strings.ToLower(docType.Nama), createdDocID, residentData.NamaLengkap))
 	finalDoc, err := s.docRepo.FindByID(createdDocID)
 	if err != nil {
 		return nil, err
@@ -166,7 +180,77 @@ func (s *lostDocumentService) CreateLostDocument(residentData models.Resident, i
 	return finalDoc, nil
 }
 
-func (s *lostDocumentService) UpdateLostDocument(docID uint, residentData models.Resident, items []models.LostItem, lokasiHilang string, petugasPelaporID uint, pejabatPersetujuID uint, loggedInUserID uint) (*models.LostDocument, error) {
+func (s *lostDocumentService) UpdateLostDocument(docID uint, residentData models.Resident, items []models.LostItem, extraData map[string]string, lokasiHilang string, petugasPelaporID uint, pejabatPersetujuID uint, loggedInUserID uint) (*models.LostDocument, error) {
+	updatedDoc, err := s.saveDocument(docID, residentData, items, extraData, lokasiHilang, petugasPelaporID, pejabatPersetujuID, loggedInUserID, models.RevisionUpdated)
+	if err != nil {
+		return nil, err
+	}
+	s.auditService.LogActivity(loggedInUserID, models.AuditUpdateDocument, fmt.Sprintf("Memperbarui dokumen %s", documentLabel(updatedDoc)))
+	return updatedDoc, nil
+}
+
+func (s *lostDocumentService) RestoreRevision(docID uint, revisionNumber int, actorID uint) (*models.LostDocument, error) {
+	actor, err := s.userRepo.FindByID(actorID)
+	if err != nil {
+		return nil, errors.New("pengguna tidak valid")
+	}
+	if !actor.HasPermission(models.PermDocumentRestore) {
+		return nil, fmt.Errorf("%w: Anda tidak memiliki izin untuk memulihkan revisi", ErrAccessDenied)
+	}
+	// Surat yang sudah diajukan atau terbit tidak boleh berganti isi tanpa persetujuan ulang. Surat terbit
+	// yang isinya keliru dicabut, lalu dibuat ulang sebagai surat baru dan diajukan kembali.
+	doc, err := s.docRepo.FindByID(docID)
+	if err != nil {
+		return nil, err
+	}
+	if doc.Status != models.StatusDraf {
+		return nil, fmt.Errorf("%w: revisi hanya dapat dipulihkan pada surat berstatus draf; cabut surat yang sudah terbit lalu buat surat baru melalui tombol Buat Ulang", ErrInvalidStatusTransition)
+	}
+
+	revision, err := s.revisionService.GetRevision(docID, revisionNumber)
+	if err != nil {
+		return nil, err
+	}
+	snapshot := revision.Snapshot
+	tanggalLahir, err := time.Parse("2006-01-02", snapshot.Resident.TanggalLahir)
+	if err != nil {
+		return nil, fmt.Errorf("tanggal lahir pada revisi %d tidak valid: %w", revisionNumber, err)
+	}
+	residentData := models.Resident{
+		NIK:          snapshot.Resident.NIK,
+		NamaLengkap:  snapshot.Resident.NamaLengkap,
+		TempatLahir:  snapshot.Resident.TempatLahir,
+		TanggalLahir: tanggalLahir,
+		JenisKelamin: snapshot.Resident.JenisKelamin,
+		Agama:        snapshot.Resident.Agama,
+		Pekerjaan:    snapshot.Resident.Pekerjaan,
+		Alamat:       snapshot.Resident.Alamat,
+	}
+	items := make([]models.LostItem, 0, len(snapshot.LostItems))
+	for _, item := range snapshot.LostItems {
+		items = append(items, models.LostItem{NamaBarang: item.NamaBarang, Deskripsi: item.Deskripsi, JenisBarang: item.JenisBarang, Atribut: item.Atribut})
+	}
+	var pejabatPersetujuID uint
+	if snapshot.PejabatPersetujuID != nil {
+		pejabatPersetujuID = *snapshot.PejabatPersetujuID
+	}
+
+	restoredDoc, err := s.saveDocument(docID, residentData, items, snapshot.DataTambahan, snapshot.LokasiHilang, snapshot.PetugasPelaporID, pejabatPersetujuID, actorID, models.RevisionRestored)
+	if err != nil {
+		return nil, err
+	}
+	s.auditService.LogActivity(actorID, models.AuditRestoreRevision, fmt.Sprintf("Memulihkan dokumen %s ke revisi %d", documentLabel(restoredDoc), revisionNumber))
+	return restoredDoc, nil
+}
+
+// saveDocument menimpa isi dokumen dan mencatat hasilnya sebagai revisi dengan aksi yang diberikan.
+func (s *lostDocumentService) saveDocument(docID uint, residentData models.Resident, items []models.LostItem, extraData map[string]string, lokasiHilang string, petugasPelaporID uint, pejabatPersetujuID uint, loggedInUserID uint, revisionAksi string) (*models.LostDocument, error) {
+	if residentData.NIK != nil {
+		if err := ValidateNIK(*residentData.NIK, residentData.TanggalLahir, residentData.JenisKelamin); err != nil {
+			return nil, err
+		}
+	}
+
 	var updatedDoc *models.LostDocument
 	err := s.db.Transaction(func(tx *gorm.DB) error {
 		existingDoc, err := s.docRepo.FindByID(docID)
@@ -177,17 +261,45 @@ func (s *lostDocumentService) UpdateLostDocument(docID uint, residentData models
 		if err != nil {
 			return errors.New("pengguna tidak valid")
 		}
-		if loggedInUser.Peran != models.RoleSuperAdmin && existingDoc.OperatorID != loggedInUserID {
-			return errors.New("akses ditolak: Anda bukan pemilik dokumen ini")
+		if !canEditDocument(loggedInUser, existingDoc) {
+			return fmt.Errorf("%w: dokumen ini bukan milik Anda atau regu Anda", ErrAccessDenied)
+		}
+		// Dokumen yang sudah diajukan atau diterbitkan telah disetujui dengan isi tertentu; mengubahnya
+		// tanpa persetujuan ulang berarti mengubah surat di luar sepengetahuan pejabat persetuju.
+		if existingDoc.Status != models.StatusDraf {
+			return fmt.Errorf("%w: hanya dokumen berstatus draf yang dapat diubah", ErrInvalidStatusTransition)
+		}
+		if err := s.validateApprover(pejabatPersetujuID, existingDoc.OperatorID); err != nil {
+			return err
+		}
+		docType, err := s.findDocumentType(existingDoc.JenisDokumen)
+		if err != nil {
+			return err
+		}
+		// Jenis barang yang sudah dinonaktifkan tetap diterima agar dokumen lama masih dapat diubah.
+		items, err = s.normalizeItems(items, true)
+		if err != nil {
+			return err
+		}
+		dataTambahan, err := validateDocumentFields(docType, residentData.NIK, lokasiHilang, items, extraData)
+		if err != nil {
+			return err
+		}
+		if residentData.NIK != nil && (existingDoc.Resident.NIK == nil || *existingDoc.Resident.NIK != *residentData.NIK) {
+			// NIK yang sudah terdaftar atas penduduk lain berarti dokumen ini milik penduduk tersebut.
+			owner, err := s.residentRepo.FindByNIK(tx, *residentData.NIK)
+			if err == nil {
+				existingDoc.ResidentID = owner.ID
+				existingDoc.Resident = *owner
+			} else if errors.Is(err, gorm.ErrRecordNotFound) {
+				existingDoc.Resident.NIK = residentData.NIK
+			} else {
+				return err
+			}
 		}
-		existingDoc.Resident.NamaLengkap = residentData.NamaLengkap
-		existingDoc.Resident.TempatLahir = residentData.TempatLahir
-		existingDoc.Resident.TanggalLahir = residentData.TanggalLahir
-		existingDoc.Resident.JenisKelamin = residentData.JenisKelamin
-		existingDoc.Resident.Agama = residentData.Agama
-		existingDoc.Resident.Pekerjaan = residentData.Pekerjaan
-		existingDoc.Resident.Alamat = residentData.Alamat
+		applyResidentData(&existingDoc.Resident, residentData)
 		existingDoc.LokasiHilang = lokasiHilang
+		existingDoc.DataTambahan = dataTambahan
 		existingDoc.PetugasPelaporID = petugasPelaporID
 		existingDoc.PejabatPersetujuID = &pejabatPersetujuID
 		existingDoc.LastUpdatedByID = &loggedInUserID
@@ -199,31 +311,41 @@ func (s *lostDocumentService) UpdateLostDocument(docID uint, residentData models
 		if err != nil {
 			return err
 		}
-		return nil
+		return s.revisionService.RecordRevision(tx, updatedDoc, revisionAksi, loggedInUserID)
 	})
 	if err != nil {
 		return nil, err
 	}
-	s.auditService.LogActivity(loggedInUserID, models.AuditUpdateDocument, fmt.Sprintf("Memperbarui dokumen dengan Nomor Surat: %s", updatedDoc.NomorSurat))
 	return updatedDoc, nil
 }
 
-func (s *lostDocumentService) DeleteLostDocument(id uint, loggedInUserID uint) error {
+func (s *lostDocumentService) DeleteLostDocument(id uint, loggedInUserID uint, alasan string) error {
+	alasan = strings.TrimSpace(alasan)
+	if alasan == "" {
+		return ErrReasonRequired
+	}
 	var docToDelete models.LostDocument
 	if err := s.db.First(&docToDelete, id).Error; err != nil {
-		return errors.New("dokumen tidak ditemukan")
+		return err
 	}
-	originalNomorSurat := docToDelete.NomorSurat
 	err := s.db.Transaction(func(tx *gorm.DB) error {
 		loggedInUser, err := s.userRepo.FindByID(loggedInUserID)
 		if err != nil {
 			return errors.New("pengguna tidak valid")
 		}
-		if loggedInUser.Peran != models.RoleSuperAdmin && docToDelete.OperatorID != loggedInUserID {
-			return errors.New("akses ditolak: Anda bukan pemilik dokumen ini")
+		if docToDelete.OperatorID != loggedInUserID && !loggedInUser.HasPermission(models.PermDocumentEditAll) {
+			return fmt.Errorf("%w: Anda bukan pemilik dokumen ini", ErrAccessDenied)
+		}
+		changes := map[string]interface{}{
+			"alasan_penghapusan": alasan,
+			"dihapus_oleh_id":    loggedInUserID,
+		}
+		// Draf belum bernomor, jadi tidak ada nomor yang perlu dibebaskan. Nomor asli tetap dapat
+		// dibaca dari awalan ini saat dokumen dipulihkan dari tong sampah.
+		if docToDelete.NomorSurat != "" {
+			changes["nomor_surat"] = fmt.Sprintf("DELETED_%d_%s", time.Now().Unix(), docToDelete.NomorSurat)
 		}
-		modifiedNomorSurat := fmt.Sprintf("DELETED_%d_%s", time.Now().Unix(), docToDelete.NomorSurat)
-		if err := tx.Model(&models.LostDocument{}).Where("id = ?", id).Update("nomor_surat", modifiedNomorSurat).Error; err != nil {
+		if err := tx.Model(&models.LostDocument{}).Where("id = ?", id).Updates(changes).Error; err != nil {
 			return err
 		}
 		if err := tx.Delete(&models.LostDocument{}, id).Error; err != nil {
@@ -234,50 +356,342 @@ func (s *lostDocumentService) DeleteLostDocument(id uint, loggedInUserID uint) e
 	if err != nil {
 		return err
 	}
-	s.auditService.LogActivity(loggedInUserID, models.AuditDeleteDocument, fmt.Sprintf("Menghapus dokumen dengan Nomor Surat: %s", originalNomorSurat))
+	s.auditService.LogActivity(loggedInUserID, models.AuditDeleteDocument, fmt.Sprintf("Menghapus dokumen %s dengan alasan: %s", documentLabel(&docToDelete), alasan))
 	return nil
 }
 
-func (s *lostDocumentService) processDocsStatus(docs []models.LostDocument) ([]models.LostDocument, error) {
-	appConfig, err := s.configService.GetConfig()
+func (s *lostDocumentService) SearchGlobal(query string, actorID uint) ([]repositories.DocumentSearchHit, error) {
+	scope, err := s.visibilityScope(actorID)
+	if err != nil {
+		return nil, err
+	}
+	return s.docRepo.SearchGlobal(query, scope)
+}
+
+func (s *lostDocumentService) FindPage(statusFilter string, list repositories.ListQuery, actorID uint) ([]models.LostDocument, repositories.PageResult, error) {
+	scope, err := s.visibilityScope(actorID)
+	if err != nil {
+		return nil, repositories.PageResult{}, err
+	}
+	return s.docRepo.FindPage(statusFilter, scope, list)
+}
+
+// visibilityScope menyusun batasan daftar dokumen untuk actorID, sejalan dengan canViewDocument.
+func (s *lostDocumentService) visibilityScope(actorID uint) (repositories.DocumentScope, error) {
+	actor, err := s.userRepo.FindByID(actorID)
+	if err != nil {
+		return repositories.DocumentScope{}, errors.New("pengguna tidak valid")
+	}
+	if actor.HasPermission(models.PermDocumentViewAll) {
+		return repositories.DocumentScope{All: true}, nil
+	}
+	return repositories.DocumentScope{UserID: actor.ID, Regu: actor.Regu}, nil
+}
+
+func (s *lostDocumentService) SubmitForApproval(docID uint, actorID uint) (*models.LostDocument, error) {
+	doc, err := s.docRepo.FindByID(docID)
+	if err != nil {
+		return nil, err
+	}
+	actor, err := s.userRepo.FindByID(actorID)
+	if err != nil {
+		return nil, errors.New("pengguna tidak valid")
+	}
+	if !canEditDocument(actor, doc) {
+		return nil, fmt.Errorf("%w: dokumen ini bukan milik Anda atau regu Anda", ErrAccessDenied)
+	}
+	var pejabatPersetujuID uint
+	if doc.PejabatPersetujuID != nil {
+		pejabatPersetujuID = *doc.PejabatPersetujuID
+	}
+	if err := s.validateApprover(pejabatPersetujuID, doc.OperatorID); err != nil {
+		return nil, err
+	}
+
+	changes := map[string]interface{}{"status": models.StatusMenungguPersetujuan, "alasan_penolakan": ""}
+	if err := s.transition(nil, doc, []string{models.StatusDraf}, changes); err != nil {
+		return nil, err
+	}
+	s.auditService.LogActivity(actorID, models.AuditSubmitDocument, fmt.Sprintf("Mengajukan dokumen %s kepada %s", documentLabel(doc), doc.PejabatPersetuju.NamaLengkap))
+	return s.docRepo.FindByID(docID)
+}
+
+func (s *lostDocumentService) ApproveDocument(docID uint, actorID uint) (*models.LostDocument, error) {
+	doc, err := s.docRepo.FindByID(docID)
+	if err != nil {
+		return nil, err
+	}
+	if doc.OperatorID == actorID {
+		return nil, fmt.Errorf("%w: pembuat dokumen tidak dapat menyetujui dokumennya sendiri", ErrAccessDenied)
+	}
+	if !isApprover(doc, actorID) {
+		return nil, fmt.Errorf("%w: hanya pejabat persetuju yang ditunjuk yang dapat menyetujui dokumen ini", ErrAccessDenied)
+	}
+	if doc.Status != models.StatusMenungguPersetujuan {
+		return nil, ErrInvalidStatusTransition
+	}
+	docType, err := s.findDocumentType(doc.JenisDokumen)
 	if err != nil {
 		return nil, err
 	}
-	archiveDuration := time.Duration(appConfig.ArchiveDurationDays) * 24 * time.Hour
 
-	for i := range docs {
-		if docs[i].Status == "DITERBITKAN" && time.Now().After(docs[i].TanggalLaporan.Add(archiveDuration)) {
-			docs[i].Status = "DIARSIPKAN"
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		// Nomor diambil sebagai pernyataan pertama agar transaksi langsung memegang write lock SQLite.
+		issued, err := s.numberingService.NextDocumentNumber(tx, docType, doc.PetugasPelapor.Regu)
+		if err != nil {
+			return err
+		}
+		now := s.now()
+		changes := map[string]interface{}{
+			"nomor_surat":         issued.NomorSurat,
+			"nomor_urut":          issued.NomorUrut,
+			"tahun_nomor":         issued.Tahun,
+			"status":              models.StatusDiterbitkan,
+			"tanggal_persetujuan": now,
+			"alasan_penolakan":    "",
 		}
+		// Jika dokumen sudah berpindah status, transaksi dibatalkan sehingga nomor urut tidak terpakai.
+		if err := s.transition(tx, doc, []string{models.StatusMenungguPersetujuan}, changes); err != nil {
+			return err
+		}
+		doc.NomorSurat = issued.NomorSurat
+		doc.NomorUrut = issued.NomorUrut
+		doc.TahunNomor = issued.Tahun
+		doc.Status = models.StatusDiterbitkan
+		doc.TanggalPersetujuan = &now
+		return s.revisionService.RecordRevision(tx, doc, models.RevisionApproved, actorID)
+	})
+	if err != nil {
+		return nil, err
 	}
-	return docs, nil
+	s.auditService.LogActivity(actorID, models.AuditApproveDocument, fmt.Sprintf("Menyetujui dan menerbitkan %s dengan nomor: %s", strings.ToLower(docType.Nama), doc.NomorSurat))
+	return s.docRepo.FindByID(docID)
 }
 
-func (s *lostDocumentService) SearchGlobal(query string) ([]models.LostDocument, error) {
-	docs, err := s.docRepo.SearchGlobal(query)
+func (s *lostDocumentService) RejectDocument(docID uint, actorID uint, alasan string) (*models.LostDocument, error) {
+	alasan = strings.TrimSpace(alasan)
+	if alasan == "" {
+		return nil, ErrReasonRequired
+	}
+	doc, err := s.docRepo.FindByID(docID)
 	if err != nil {
 		return nil, err
 	}
-	return s.processDocsStatus(docs)
+	if doc.OperatorID == actorID {
+		return nil, fmt.Errorf("%w: pembuat dokumen tidak dapat menolak dokumennya sendiri", ErrAccessDenied)
+	}
+	if !isApprover(doc, actorID) {
+		return nil, fmt.Errorf("%w: hanya pejabat persetuju yang ditunjuk yang dapat menolak dokumen ini", ErrAccessDenied)
+	}
+
+	changes := map[string]interface{}{"status": models.StatusDraf, "alasan_penolakan": alasan}
+	if err := s.transition(nil, doc, []string{models.StatusMenungguPersetujuan}, changes); err != nil {
+		return nil, err
+	}
+	s.auditService.LogActivity(actorID, models.AuditRejectDocument, fmt.Sprintf("Menolak dokumen %s dengan alasan: %s", documentLabel(doc), alasan))
+	return s.docRepo.FindByID(docID)
 }
 
-func (s *lostDocumentService) FindAll(query string, statusFilter string) ([]models.LostDocument, error) {
-	appConfig, err := s.configService.GetConfig()
+func (s *lostDocumentService) RevokeDocument(docID uint, actorID uint, alasan string) (*models.LostDocument, error) {
+	alasan = strings.TrimSpace(alasan)
+	if alasan == "" {
+		return nil, ErrReasonRequired
+	}
+	doc, err := s.docRepo.FindByID(docID)
 	if err != nil {
 		return nil, err
 	}
+	actor, err := s.userRepo.FindByID(actorID)
+	if err != nil {
+		return nil, errors.New("pengguna tidak valid")
+	}
+	if !isApprover(doc, actorID) && !actor.HasPermission(models.PermDocumentRevoke) {
+		return nil, fmt.Errorf("%w: hanya pejabat persetuju dokumen atau pengguna berizin pencabutan yang dapat mencabut surat", ErrAccessDenied)
+	}
 
-	docs, err := s.docRepo.FindAll(query, statusFilter, appConfig.ArchiveDurationDays)
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		now := s.now()
+		changes := map[string]interface{}{
+			"status":             models.StatusDicabut,
+			"tanggal_pencabutan": now,
+			"alasan_pencabutan":  alasan,
+			"dicabut_oleh_id":    actorID,
+		}
+		if err := s.transition(tx, doc, []string{models.StatusDiterbitkan, models.StatusDiarsipkan}, changes); err != nil {
+			return err
+		}
+		doc.Status = models.StatusDicabut
+		doc.TanggalPencabutan = &now
+		doc.AlasanPencabutan = alasan
+		doc.DicabutOlehID = &actorID
+		return s.revisionService.RecordRevision(tx, doc, models.RevisionRevoked, actorID)
+	})
 	if err != nil {
 		return nil, err
 	}
-	return s.processDocsStatus(docs)
+	s.auditService.LogActivity(actorID, models.AuditRevokeDocument, fmt.Sprintf("Mencabut dokumen %s dengan alasan: %s", documentLabel(doc), alasan))
+	return s.docRepo.FindByID(docID)
+}
+
+// FindApprovalQueue mengambil dokumen yang diajukan kepada actorID. Dokumen yang diajukan kepada
+// pejabat lain tidak ditampilkan walaupun actorID berizin persetujuan.
+func (s *lostDocumentService) FindApprovalQueue(actorID uint) ([]models.LostDocument, error) {
+	return s.docRepo.FindAwaitingApproval(actorID)
 }
 
-func intToRoman(num int) string {
-	romanNumeralMap := map[int]string{1: "I", 2: "II", 3: "III", 4: "IV", 5: "V", 6: "VI", 7: "VII", 8: "VIII", 9: "IX", 10: "X", 11: "XI", 12: "XII"}
-	if val, ok := romanNumeralMap[num]; ok {
-		return val
+// validateApprover memastikan pejabat persetuju sudah dipilih, berizin menyetujui dokumen, dan bukan
+// pembuat dokumen, agar tidak ada dokumen yang disetujui oleh pembuatnya sendiri. Pengguna nonaktif
+// tetap dimuat oleh UserRepository sehingga perlu ditolak di sini.
+func (s *lostDocumentService) validateApprover(pejabatPersetujuID uint, operatorID uint) error {
+	if pejabatPersetujuID == 0 {
+		return fmt.Errorf("%w: pejabat persetuju belum dipilih", ErrMissingRequiredField)
 	}
-	return ""
-}
\ No newline at end of file
+	if pejabatPersetujuID == operatorID {
+		return fmt.Errorf("%w: pembuat dokumen tidak dapat ditunjuk sebagai pejabat persetujunya sendiri", ErrInvalidApprover)
+	}
+	pejabat, err := s.userRepo.FindByID(pejabatPersetujuID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return fmt.Errorf("%w: pengguna tidak ditemukan", ErrInvalidApprover)
+	}
+	if err != nil {
+		return fmt.Errorf("gagal memuat data pejabat persetuju: %w", err)
+	}
+	if pejabat.DeletedAt.Valid {
+		return fmt.Errorf("%w: %s sudah dinonaktifkan", ErrInvalidApprover, pejabat.NamaLengkap)
+	}
+	if !pejabat.HasPermission(models.PermDocumentApprove) {
+		return fmt.Errorf("%w: %s tidak memiliki izin menyetujui dokumen", ErrInvalidApprover, pejabat.NamaLengkap)
+	}
+	return nil
+}
+
+// transition memindahkan status dokumen secara atomik; ErrInvalidStatusTransition dikembalikan
+// jika status dokumen saat ini tidak termasuk fromStatuses.
+func (s *lostDocumentService) transition(tx *gorm.DB, doc *models.LostDocument, fromStatuses []string, changes map[string]interface{}) error {
+	changed, err := s.docRepo.TransitionStatus(tx, doc.ID, fromStatuses, changes)
+	if err != nil {
+		return err
+	}
+	if !changed {
+		return fmt.Errorf("%w (status: %s)", ErrInvalidStatusTransition, doc.Status)
+	}
+	return nil
+}
+
+func (s *lostDocumentService) now() time.Time {
+	loc, err := s.configService.GetLocation()
+	if err != nil {
+		loc = time.UTC
+	}
+	return time.Now().In(loc)
+}
+
+// isApprover memeriksa apakah userID adalah pejabat persetuju yang ditunjuk pada dokumen.
+func isApprover(doc *models.LostDocument, userID uint) bool {
+	return doc.PejabatPersetujuID != nil && *doc.PejabatPersetujuID == userID
+}
+
+// sameRegu bernilai true jika pembuat dokumen satu regu dengan user, sehingga
+// dokumennya dapat dilanjutkan saat serah terima jaga.
+func sameRegu(user *models.User, doc *models.LostDocument) bool {
+	return user.Regu != "" && doc.Operator.ID == doc.OperatorID && doc.Operator.Regu == user.Regu
+}
+
+// canViewDocument memeriksa apakah user boleh melihat dan mencetak doc.
+// Pejabat persetuju perlu melihat dokumen yang diajukan kepadanya.
+func canViewDocument(user *models.User, doc *models.LostDocument) bool {
+	return doc.OperatorID == user.ID || isApprover(doc, user.ID) || sameRegu(user, doc) || user.HasPermission(models.PermDocumentViewAll)
+}
+
+// canEditDocument memeriksa apakah user boleh mengubah atau mengajukan doc.
+func canEditDocument(user *models.User, doc *models.LostDocument) bool {
+	return doc.OperatorID == user.ID || sameRegu(user, doc) || user.HasPermission(models.PermDocumentEditAll)
+}
+
+// documentLabel menyebut dokumen untuk audit log: Nomor Surat bila sudah terbit, ID bila masih draf.
+func documentLabel(doc *models.LostDocument) string {
+	if doc.NomorSurat == "" {
+		return fmt.Sprintf("draf ID %d", doc.ID)
+	}
+	return doc.NomorSurat
+}
+
+// IsPrintable menandai dokumen yang boleh dicetak: sudah terbit dan belum dicabut.
+func IsPrintable(doc *models.LostDocument) bool {
+	return doc.Status == models.StatusDiterbitkan || doc.Status == models.StatusDiarsipkan
+}
+
+// findDocumentType memuat jenis dokumen dari registri; kode yang tidak terdaftar dianggap input tidak valid.
+func (s *lostDocumentService) findDocumentType(documentType string) (*models.DocumentType, error) {
+	docType, err := s.docTypeService.FindByCode(documentType)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return nil, fmt.Errorf("%w: jenis dokumen %s tidak dikenal", ErrInvalidDocumentType, documentType)
+		}
+		return nil, err
+	}
+	return docType, nil
+}
+
+// normalizeItems memvalidasi barang hilang terhadap katalog jenis barang. Lihat normalizeLostItems.
+func (s *lostDocumentService) normalizeItems(items []models.LostItem, allowInactive bool) ([]models.LostItem, error) {
+	if len(items) == 0 {
+		return items, nil
+	}
+	catalogue, err := s.itemTypeService.FindAll(false)
+	if err != nil {
+		return nil, fmt.Errorf("gagal memuat katalog jenis barang: %w", err)
+	}
+	return normalizeLostItems(catalogue, items, allowInactive)
+}
+
+// resolveResident mencari penduduk yang sesuai dengan data pemohon, atau membuat data baru.
+// NIK menjadi kunci utama; tanpa NIK, pencocokan memakai nama lengkap dan tanggal lahir.
+// Penduduk lama tanpa NIK yang cocok nama dan tanggal lahirnya akan dilengkapi NIK-nya.
+func (s *lostDocumentService) resolveResident(tx *gorm.DB, residentData models.Resident) (*models.Resident, error) {
+	return resolveResident(tx, s.residentRepo, residentData)
+}
+
+// resolveResident mencari data penduduk yang cocok dengan data pemohon (berdasarkan NIK, lalu nama dan
+// tanggal lahir) dan memperbaruinya, atau membuat data penduduk baru jika belum ada.
+func resolveResident(tx *gorm.DB, residentRepo repositories.ResidentRepository, residentData models.Resident) (*models.Resident, error) {
+	if residentData.NIK != nil {
+		existing, err := residentRepo.FindByNIK(tx, *residentData.NIK)
+		if err == nil {
+			applyResidentData(existing, residentData)
+			return residentRepo.Update(tx, existing)
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+	}
+
+	existing, err := residentRepo.FindByNameAndBirthDate(tx, residentData.NamaLengkap, residentData.TanggalLahir)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	if err == nil {
+		if residentData.NIK == nil {
+			return existing, nil
+		}
+		// Nama dan tanggal lahir sama tetapi NIK berbeda berarti orang yang berbeda.
+		if existing.NIK == nil {
+			existing.NIK = residentData.NIK
+			applyResidentData(existing, residentData)
+			return residentRepo.Update(tx, existing)
+		}
+	}
+
+	return residentRepo.Create(tx, &residentData)
+}
+
+// applyResidentData menyalin data pemohon terbaru ke data penduduk, kecuali NIK.
+func applyResidentData(resident *models.Resident, residentData models.Resident) {
+	resident.NamaLengkap = residentData.NamaLengkap
+	resident.TempatLahir = residentData.TempatLahir
+	resident.TanggalLahir = residentData.TanggalLahir
+	resident.JenisKelamin = residentData.JenisKelamin
+	resident.Agama = residentData.Agama
+	resident.Pekerjaan = residentData.Pekerjaan
+	resident.Alamat = residentData.Alamat
+}
@@ -1,19 +1,23 @@
 package services
 
 import (
+	"bytes"
 	"errors"
-	"regexp"
 	"simdokpol/internal/dto"
 	"simdokpol/internal/mocks"
 	"simdokpol/internal/models"
+	"simdokpol/internal/repositories"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
 )
 
 func setupMockDB(t *testing.T) (*gorm.DB, sqlmock.Sqlmock) {
@@ -41,65 +45,75 @@ func TestLostDocumentService_CreateLostDocument(t *testing.T) {
 	operatorID := uint(1)
 	petugasPelaporID := uint(2)
 	pejabatPersetujuID := uint(3)
+	kanit := &models.User{ID: pejabatPersetujuID, Peran: models.RoleKanitSPKT, Role: &models.Role{Kode: models.RoleKanitSPKT, Permissions: []string{models.PermDocumentApprove}}}
 
 	loc, _ := time.LoadLocation("Asia/Jakarta")
-	mockConfig := &dto.AppConfig{
-		FormatNomorSurat:   "SKH/%d/%s/TUK.7.2.1/%d",
-		NomorSuratTerakhir: "0",
+	lostDocType := &models.DocumentType{
+		Kode:          models.DocumentTypeLostDocument,
+		Nama:          "Surat Keterangan Hilang",
+		PrintTemplate: models.PrintTemplateLostDocument,
+		Fields: []models.DocumentField{
+			{Key: models.FieldLostItems, Label: "Barang yang hilang", Wajib: true},
+			{Key: models.FieldLokasiHilang, Label: "Lokasi hilang", Wajib: true},
+		},
+		Aktif: true,
 	}
 
 	testCases := []struct {
 		name          string
-		setupMocks    func(dbMock sqlmock.Sqlmock, docRepo *mocks.LostDocumentRepository, resRepo *mocks.ResidentRepository, userRepo *mocks.UserRepository, auditService *mocks.AuditLogService, configService *mocks.ConfigService)
+		setupMocks    func(dbMock sqlmock.Sqlmock, docRepo *mocks.LostDocumentRepository, resRepo *mocks.ResidentRepository, userRepo *mocks.UserRepository, auditService *mocks.AuditLogService, configService *mocks.ConfigService, numberingService *mocks.DocumentNumberingService, revisionService *mocks.DocumentRevisionService)
 		expectedError bool
 	}{
 		{
 			name: "Sukses - Membuat Dokumen dengan Penduduk Baru",
-			setupMocks: func(dbMock sqlmock.Sqlmock, docRepo *mocks.LostDocumentRepository, resRepo *mocks.ResidentRepository, userRepo *mocks.UserRepository, auditService *mocks.AuditLogService, configService *mocks.ConfigService) {
+			setupMocks: func(dbMock sqlmock.Sqlmock, docRepo *mocks.LostDocumentRepository, resRepo *mocks.ResidentRepository, userRepo *mocks.UserRepository, auditService *mocks.AuditLogService, configService *mocks.ConfigService, numberingService *mocks.DocumentNumberingService, revisionService *mocks.DocumentRevisionService) {
 				// Tidak dibatasi karena bisa dipanggil beberapa kali
 				configService.On("GetLocation").Return(loc, nil)
-				
-				docRepo.On("GetLastDocumentOfYear", mock.AnythingOfType("int")).Return((*models.LostDocument)(nil), gorm.ErrRecordNotFound).Once()
-				configService.On("GetConfig").Return(mockConfig, nil).Once()
+
+				userRepo.On("FindByID", petugasPelaporID).Return(&models.User{ID: petugasPelaporID, Regu: "I"}, nil).Once()
+				userRepo.On("FindByID", pejabatPersetujuID).Return(kanit, nil).Once()
 
 				dbMock.ExpectBegin()
-				
-				expectedSQL := "SELECT * FROM `residents` WHERE (nama_lengkap = ? AND tanggal_lahir = ?) AND `residents`.`deleted_at` IS NULL ORDER BY `residents`.`id` LIMIT 1"
-				dbMock.ExpectQuery(regexp.QuoteMeta(expectedSQL)).
-					WithArgs(residentData.NamaLengkap, residentData.TanggalLahir).
-					WillReturnError(gorm.ErrRecordNotFound)
+
+				resRepo.On("FindByNameAndBirthDate", mock.AnythingOfType("*gorm.DB"), residentData.NamaLengkap, residentData.TanggalLahir).
+					Return((*models.Resident)(nil), gorm.ErrRecordNotFound).Once()
 
 				resRepo.On("Create", mock.AnythingOfType("*gorm.DB"), mock.AnythingOfType("*models.Resident")).
 					Return(&models.Resident{ID: 1}, nil).Once()
 
-				docRepo.On("Create", mock.AnythingOfType("*gorm.DB"), mock.AnythingOfType("*models.LostDocument")).
+				// Dokumen baru disimpan sebagai draf tanpa Nomor Surat; barang bebas "KTP" dikenali dari katalog.
+				isDraft := mock.MatchedBy(func(doc *models.LostDocument) bool {
+					return doc.Status == models.StatusDraf && doc.NomorSurat == "" && doc.TanggalPersetujuan == nil &&
+						len(doc.LostItems) == 1 && doc.LostItems[0].JenisBarang == "KTP"
+				})
+				docRepo.On("Create", mock.AnythingOfType("*gorm.DB"), isDraft).
 					Return(&models.LostDocument{ID: 101}, nil).Once()
 
+				revisionService.On("RecordRevision", mock.AnythingOfType("*gorm.DB"), mock.AnythingOfType("*models.LostDocument"), models.RevisionCreated, operatorID).
+					Return(nil).Once()
+
 				dbMock.ExpectCommit()
 
 				auditService.On("LogActivity", operatorID, models.AuditCreateDocument, mock.AnythingOfType("string")).Once()
 
-				finalDoc := &models.LostDocument{ID: 101, NomorSurat: "SKH/1/X/TUK.7.2.1/2025"}
+				finalDoc := &models.LostDocument{ID: 101, Status: models.StatusDraf}
 				docRepo.On("FindByID", uint(101)).Return(finalDoc, nil).Once()
 			},
 			expectedError: false,
 		},
 		{
 			name: "Gagal - Error saat membuat penduduk",
-			setupMocks: func(dbMock sqlmock.Sqlmock, docRepo *mocks.LostDocumentRepository, resRepo *mocks.ResidentRepository, userRepo *mocks.UserRepository, auditService *mocks.AuditLogService, configService *mocks.ConfigService) {
+			setupMocks: func(dbMock sqlmock.Sqlmock, docRepo *mocks.LostDocumentRepository, resRepo *mocks.ResidentRepository, userRepo *mocks.UserRepository, auditService *mocks.AuditLogService, configService *mocks.ConfigService, numberingService *mocks.DocumentNumberingService, revisionService *mocks.DocumentRevisionService) {
 				// Tidak dibatasi karena bisa dipanggil beberapa kali
 				configService.On("GetLocation").Return(loc, nil).Maybe()
-				
-				// Jika GetLastDocumentOfYear dipanggil di awal
-				docRepo.On("GetLastDocumentOfYear", mock.AnythingOfType("int")).Return((*models.LostDocument)(nil), gorm.ErrRecordNotFound).Maybe()
-				configService.On("GetConfig").Return(mockConfig, nil).Maybe()
-				
+
+				userRepo.On("FindByID", petugasPelaporID).Return(&models.User{ID: petugasPelaporID, Regu: "I"}, nil).Once()
+				userRepo.On("FindByID", pejabatPersetujuID).Return(kanit, nil).Once()
+
 				dbMock.ExpectBegin()
 
-				expectedSQL := "SELECT * FROM `residents` WHERE (nama_lengkap = ? AND tanggal_lahir = ?) AND `residents`.`deleted_at` IS NULL ORDER BY `residents`.`id` LIMIT 1"
-				dbMock.ExpectQuery(regexp.QuoteMeta(expectedSQL)).
-					WithArgs(residentData.NamaLengkap, residentData.TanggalLahir).
-					WillReturnError(gorm.ErrRecordNotFound)
+				resRepo.On("FindByNameAndBirthDate", mock.AnythingOfType("*gorm.DB"), residentData.NamaLengkap, residentData.TanggalLahir).
+					Return((*models.Resident)(nil), gorm.ErrRecordNotFound).Once()
 					
 				resRepo.On("Create", mock.AnythingOfType("*gorm.DB"), mock.AnythingOfType("*models.Resident")).
 					Return((*models.Resident)(nil), errors.New("database error")).Once()
@@ -108,6 +122,15 @@ func TestLostDocumentService_CreateLostDocument(t *testing.T) {
 			},
 			expectedError: true,
 		},
+		{
+			name: "Gagal - Pejabat persetuju tidak berizin menyetujui dokumen",
+			setupMocks: func(dbMock sqlmock.Sqlmock, docRepo *mocks.LostDocumentRepository, resRepo *mocks.ResidentRepository, userRepo *mocks.UserRepository, auditService *mocks.AuditLogService, configService *mocks.ConfigService, numberingService *mocks.DocumentNumberingService, revisionService *mocks.DocumentRevisionService) {
+				userRepo.On("FindByID", petugasPelaporID).Return(&models.User{ID: petugasPelaporID, Regu: "I"}, nil).Once()
+				operator := &models.User{ID: pejabatPersetujuID, Peran: models.RoleOperator, Role: &models.Role{Kode: models.RoleOperator, Permissions: []string{models.PermDocumentCreate}}}
+				userRepo.On("FindByID", pejabatPersetujuID).Return(operator, nil).Once()
+			},
+			expectedError: true,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -118,12 +141,18 @@ func TestLostDocumentService_CreateLostDocument(t *testing.T) {
 			mockUserRepo := new(mocks.UserRepository)
 			mockAuditService := new(mocks.AuditLogService)
 			mockConfigService := new(mocks.ConfigService)
+			mockNumberingService := new(mocks.DocumentNumberingService)
+			mockRevisionService := new(mocks.DocumentRevisionService)
+			mockDocTypeService := new(mocks.DocumentTypeService)
+			mockDocTypeService.On("FindByCode", models.DocumentTypeLostDocument).Return(lostDocType, nil)
+			mockItemTypeService := new(mocks.ItemTypeService)
+			mockItemTypeService.On("FindAll", false).Return([]models.ItemType{{Kode: "KTP", Nama: "KTP", Alias: []string{"E-KTP"}, Aktif: true}}, nil)
 
-			tc.setupMocks(dbMock, mockDocRepo, mockResRepo, mockUserRepo, mockAuditService, mockConfigService)
+			tc.setupMocks(dbMock, mockDocRepo, mockResRepo, mockUserRepo, mockAuditService, mockConfigService, mockNumberingService, mockRevisionService)
 
-			service := NewLostDocumentService(db, mockDocRepo, mockResRepo, mockUserRepo, mockAuditService, mockConfigService)
+			service := NewLostDocumentService(db, mockDocRepo, mockResRepo, mockUserRepo, mockAuditService, mockConfigService, mockNumberingService, mockRevisionService, mockDocTypeService, mockItemTypeService)
 
-			_, err := service.CreateLostDocument(residentData, items, operatorID, "Jalan Sudirman", petugasPelaporID, pejabatPersetujuID)
+			_, err := service.CreateLostDocument(models.DocumentTypeLostDocument, residentData, items, nil, operatorID, "Jalan Sudirman", petugasPelaporID, pejabatPersetujuID)
 
 			if tc.expectedError {
 				assert.Error(t, err)
@@ -135,9 +164,410 @@ func TestLostDocumentService_CreateLostDocument(t *testing.T) {
 			mockResRepo.AssertExpectations(t)
 			mockUserRepo.AssertExpectations(t)
 			mockAuditService.AssertExpectations(t)
+			mockNumberingService.AssertExpectations(t)
+			mockRevisionService.AssertExpectations(t)
 			// Gunakan AssertNumberOfCalls untuk yang pakai Maybe()
 			// mockConfigService.AssertExpectations(t)
 			assert.NoError(t, dbMock.ExpectationsWereMet())
 		})
 	}
-}
\ No newline at end of file
+}
+
+func TestLostDocumentService_ApproveDocument(t *testing.T) {
+	loc, _ := time.LoadLocation("Asia/Jakarta")
+	pejabatID := uint(3)
+	issuedNumber := &dto.IssuedNumber{NomorSurat: "SKH/1/X/TUK.7.2.1/2025", NomorUrut: 1, Tahun: 2025}
+	lostDocType := &models.DocumentType{Kode: models.DocumentTypeLostDocument, Nama: "Surat Keterangan Hilang", Aktif: true}
+	awaitingDoc := func() *models.LostDocument {
+		return &models.LostDocument{
+			ID:                 101,
+			Status:             models.StatusMenungguPersetujuan,
+			JenisDokumen:       models.DocumentTypeLostDocument,
+			PejabatPersetujuID: &pejabatID,
+			PetugasPelapor:     models.User{ID: 2, Regu: "I"},
+			OperatorID:         5,
+		}
+	}
+	issuedChanges := mock.MatchedBy(func(changes map[string]interface{}) bool {
+		return changes["nomor_surat"] == issuedNumber.NomorSurat && changes["status"] == models.StatusDiterbitkan && changes["tanggal_persetujuan"] != nil
+	})
+
+	testCases := []struct {
+		name          string
+		actorID       uint
+		setupMocks    func(dbMock sqlmock.Sqlmock, docRepo *mocks.LostDocumentRepository, userRepo *mocks.UserRepository, numberingService *mocks.DocumentNumberingService, revisionService *mocks.DocumentRevisionService, auditService *mocks.AuditLogService)
+		expectedError error
+	}{
+		{
+			name:    "Sukses - Pejabat persetuju menerbitkan nomor",
+			actorID: pejabatID,
+			setupMocks: func(dbMock sqlmock.Sqlmock, docRepo *mocks.LostDocumentRepository, userRepo *mocks.UserRepository, numberingService *mocks.DocumentNumberingService, revisionService *mocks.DocumentRevisionService, auditService *mocks.AuditLogService) {
+				docRepo.On("FindByID", uint(101)).Return(awaitingDoc(), nil).Once()
+				dbMock.ExpectBegin()
+				numberingService.On("NextDocumentNumber", mock.AnythingOfType("*gorm.DB"), lostDocType, "I").Return(issuedNumber, nil).Once()
+				docRepo.On("TransitionStatus", mock.AnythingOfType("*gorm.DB"), uint(101), []string{models.StatusMenungguPersetujuan}, issuedChanges).Return(true, nil).Once()
+				revisionService.On("RecordRevision", mock.AnythingOfType("*gorm.DB"), mock.AnythingOfType("*models.LostDocument"), models.RevisionApproved, pejabatID).Return(nil).Once()
+				dbMock.ExpectCommit()
+				auditService.On("LogActivity", pejabatID, models.AuditApproveDocument, mock.AnythingOfType("string")).Once()
+				docRepo.On("FindByID", uint(101)).Return(&models.LostDocument{ID: 101, NomorSurat: issuedNumber.NomorSurat, Status: models.StatusDiterbitkan}, nil).Once()
+			},
+		},
+		{
+			name:    "Gagal - Bukan pejabat persetuju yang ditunjuk",
+			actorID: uint(2),
+			setupMocks: func(dbMock sqlmock.Sqlmock, docRepo *mocks.LostDocumentRepository, userRepo *mocks.UserRepository, numberingService *mocks.DocumentNumberingService, revisionService *mocks.DocumentRevisionService, auditService *mocks.AuditLogService) {
+				docRepo.On("FindByID", uint(101)).Return(awaitingDoc(), nil).Once()
+			},
+			expectedError: ErrAccessDenied,
+		},
+		{
+			// Izin document.approve hanya membuat pengguna dapat ditunjuk, bukan menyetujui dokumen pejabat lain.
+			name:    "Gagal - Pejabat berizin persetujuan lain tidak dapat menyetujui",
+			actorID: uint(4),
+			setupMocks: func(dbMock sqlmock.Sqlmock, docRepo *mocks.LostDocumentRepository, userRepo *mocks.UserRepository, numberingService *mocks.DocumentNumberingService, revisionService *mocks.DocumentRevisionService, auditService *mocks.AuditLogService) {
+				docRepo.On("FindByID", uint(101)).Return(awaitingDoc(), nil).Once()
+			},
+			expectedError: ErrAccessDenied,
+		},
+		{
+			name:    "Gagal - Pembuat dokumen tidak dapat menyetujui dokumennya sendiri",
+			actorID: uint(5),
+			setupMocks: func(dbMock sqlmock.Sqlmock, docRepo *mocks.LostDocumentRepository, userRepo *mocks.UserRepository, numberingService *mocks.DocumentNumberingService, revisionService *mocks.DocumentRevisionService, auditService *mocks.AuditLogService) {
+				docRepo.On("FindByID", uint(101)).Return(awaitingDoc(), nil).Once()
+			},
+			expectedError: ErrAccessDenied,
+		},
+		{
+			name:    "Gagal - Sudah disetujui lebih dulu, nomor dibatalkan",
+			actorID: pejabatID,
+			setupMocks: func(dbMock sqlmock.Sqlmock, docRepo *mocks.LostDocumentRepository, userRepo *mocks.UserRepository, numberingService *mocks.DocumentNumberingService, revisionService *mocks.DocumentRevisionService, auditService *mocks.AuditLogService) {
+				docRepo.On("FindByID", uint(101)).Return(awaitingDoc(), nil).Once()
+				dbMock.ExpectBegin()
+				numberingService.On("NextDocumentNumber", mock.AnythingOfType("*gorm.DB"), lostDocType, "I").Return(issuedNumber, nil).Once()
+				docRepo.On("TransitionStatus", mock.AnythingOfType("*gorm.DB"), uint(101), []string{models.StatusMenungguPersetujuan}, issuedChanges).Return(false, nil).Once()
+				dbMock.ExpectRollback()
+			},
+			expectedError: ErrInvalidStatusTransition,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, dbMock := setupMockDB(t)
+			mockDocRepo := new(mocks.LostDocumentRepository)
+			mockAuditService := new(mocks.AuditLogService)
+			mockConfigService := new(mocks.ConfigService)
+			mockNumberingService := new(mocks.DocumentNumberingService)
+			mockRevisionService := new(mocks.DocumentRevisionService)
+			mockDocTypeService := new(mocks.DocumentTypeService)
+			mockConfigService.On("GetLocation").Return(loc, nil).Maybe()
+			mockDocTypeService.On("FindByCode", models.DocumentTypeLostDocument).Return(lostDocType, nil).Maybe()
+
+			mockUserRepo := new(mocks.UserRepository)
+			tc.setupMocks(dbMock, mockDocRepo, mockUserRepo, mockNumberingService, mockRevisionService, mockAuditService)
+
+			service := NewLostDocumentService(db, mockDocRepo, new(mocks.ResidentRepository), mockUserRepo, mockAuditService, mockConfigService, mockNumberingService, mockRevisionService, mockDocTypeService, nil)
+
+			doc, err := service.ApproveDocument(101, tc.actorID)
+
+			if tc.expectedError != nil {
+				assert.ErrorIs(t, err, tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, models.StatusDiterbitkan, doc.Status)
+			}
+
+			mockDocRepo.AssertExpectations(t)
+			mockUserRepo.AssertExpectations(t)
+			mockNumberingService.AssertExpectations(t)
+			mockRevisionService.AssertExpectations(t)
+			mockAuditService.AssertExpectations(t)
+			assert.NoError(t, dbMock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestLostDocumentService_ValidateApprover(t *testing.T) {
+	approveRole := &models.Role{Kode: models.RoleKanitSPKT, Permissions: []string{models.PermDocumentApprove}}
+	testCases := []struct {
+		name          string
+		pejabat       *models.User
+		operatorID    uint
+		expectedError error
+	}{
+		{name: "Sukses - Pejabat berizin persetujuan", pejabat: &models.User{ID: 3, Peran: models.RoleKanitSPKT, Role: approveRole}, operatorID: 1},
+		{name: "Gagal - Pejabat belum dipilih", operatorID: 1, expectedError: ErrMissingRequiredField},
+		{name: "Gagal - Pembuat dokumen menunjuk dirinya sendiri", pejabat: &models.User{ID: 1, Peran: models.RoleKanitSPKT, Role: approveRole}, operatorID: 1, expectedError: ErrInvalidApprover},
+		{name: "Gagal - Pejabat tanpa izin persetujuan", pejabat: &models.User{ID: 3, Peran: models.RoleOperator, Role: &models.Role{Kode: models.RoleOperator}}, operatorID: 1, expectedError: ErrInvalidApprover},
+		{name: "Gagal - Pejabat sudah dinonaktifkan", pejabat: &models.User{ID: 3, Peran: models.RoleKanitSPKT, Role: approveRole, DeletedAt: gorm.DeletedAt{Time: time.Now(), Valid: true}}, operatorID: 1, expectedError: ErrInvalidApprover},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			userRepo := new(mocks.UserRepository)
+			var pejabatID uint
+			if tc.pejabat != nil {
+				pejabatID = tc.pejabat.ID
+				userRepo.On("FindByID", pejabatID).Return(tc.pejabat, nil).Maybe()
+			}
+			service := &lostDocumentService{userRepo: userRepo}
+
+			err := service.validateApprover(pejabatID, tc.operatorID)
+
+			if tc.expectedError != nil {
+				assert.ErrorIs(t, err, tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestLostDocumentService_UpdateLostDocument_OnlyDraft(t *testing.T) {
+	db, dbMock := setupMockDB(t)
+	docRepo := new(mocks.LostDocumentRepository)
+	userRepo := new(mocks.UserRepository)
+	issuedDoc := &models.LostDocument{ID: 101, Status: models.StatusDiterbitkan, NomorSurat: "SKH/1/X/TUK.7.2.1/2025", OperatorID: 2}
+	docRepo.On("FindByID", uint(101)).Return(issuedDoc, nil).Once()
+	userRepo.On("FindByID", uint(2)).Return(&models.User{ID: 2, Regu: "I", Peran: models.RoleOperator}, nil).Once()
+	dbMock.ExpectBegin()
+	dbMock.ExpectRollback()
+	service := NewLostDocumentService(db, docRepo, new(mocks.ResidentRepository), userRepo, nil, nil, nil, nil, nil, nil)
+
+	_, err := service.UpdateLostDocument(101, models.Resident{NamaLengkap: "Budi Santoso"}, nil, nil, "Pasar", 2, 3, 2)
+
+	assert.ErrorIs(t, err, ErrInvalidStatusTransition)
+	docRepo.AssertExpectations(t)
+	userRepo.AssertExpectations(t)
+	assert.NoError(t, dbMock.ExpectationsWereMet())
+}
+
+// Surat terbit tidak dapat dipulihkan ke revisi lama; isinya sudah disetujui pejabat persetuju.
+func TestLostDocumentService_RestoreRevision_OnlyDraft(t *testing.T) {
+	docRepo := new(mocks.LostDocumentRepository)
+	userRepo := new(mocks.UserRepository)
+	issuedDoc := &models.LostDocument{ID: 101, Status: models.StatusDiterbitkan, NomorSurat: "SKH/1/X/TUK.7.2.1/2025", OperatorID: 2}
+	admin := &models.User{ID: 1, Peran: models.RoleSuperAdmin}
+	userRepo.On("FindByID", uint(1)).Return(admin, nil).Once()
+	docRepo.On("FindByID", uint(101)).Return(issuedDoc, nil).Once()
+	service := NewLostDocumentService(nil, docRepo, nil, userRepo, nil, nil, nil, nil, nil, nil)
+
+	_, err := service.RestoreRevision(101, 1, 1)
+
+	assert.ErrorIs(t, err, ErrInvalidStatusTransition)
+	docRepo.AssertExpectations(t)
+	userRepo.AssertExpectations(t)
+}
+
+func TestLostDocumentService_RejectDocument_OnlyDesignatedApprover(t *testing.T) {
+	pejabatID := uint(3)
+	docRepo := new(mocks.LostDocumentRepository)
+	docRepo.On("FindByID", uint(101)).Return(&models.LostDocument{ID: 101, Status: models.StatusMenungguPersetujuan, PejabatPersetujuID: &pejabatID, OperatorID: 5}, nil).Once()
+	service := NewLostDocumentService(nil, docRepo, nil, nil, nil, nil, nil, nil, nil, nil)
+
+	_, err := service.RejectDocument(101, 4, "Data pemohon belum lengkap")
+
+	assert.ErrorIs(t, err, ErrAccessDenied)
+	docRepo.AssertExpectations(t)
+}
+
+// Dokumen milik anggota regu yang sudah dinonaktifkan harus tampil di daftar sekaligus dapat dibuka
+// oleh rekan seregunya, agar pekerjaannya tetap bisa dilanjutkan saat serah terima jaga.
+func TestLostDocumentService_DeactivatedOperatorReguScope(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{Logger: logger.Discard})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.Role{}, &models.User{}, &models.Resident{}, &models.LostDocument{}, &models.LostItem{}))
+	operatorRole := &models.Role{Kode: models.RoleOperator, Nama: "Anggota Jaga", Permissions: []string{models.PermDocumentCreate}}
+	require.NoError(t, db.Create(operatorRole).Error)
+	formerMember := &models.User{ID: 2, NamaLengkap: "BRIPDA LAMA", NRP: "2", KataSandi: "x", Peran: models.RoleOperator, Regu: "I"}
+	colleague := &models.User{ID: 3, NamaLengkap: "BRIPDA REKAN", NRP: "3", KataSandi: "x", Peran: models.RoleOperator, Regu: "I"}
+	otherRegu := &models.User{ID: 4, NamaLengkap: "BRIPDA LAIN", NRP: "4", KataSandi: "x", Peran: models.RoleOperator, Regu: "II"}
+	require.NoError(t, db.Create([]*models.User{formerMember, colleague, otherRegu}).Error)
+	resident := &models.Resident{NamaLengkap: "BUDI SANTOSO", TanggalLahir: time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC)}
+	require.NoError(t, db.Create(resident).Error)
+	doc := &models.LostDocument{Status: models.StatusDraf, JenisDokumen: models.DocumentTypeLostDocument, TanggalLaporan: time.Now(), ResidentID: resident.ID, OperatorID: 2, PetugasPelaporID: 2}
+	require.NoError(t, db.Create(doc).Error)
+	require.NoError(t, db.Delete(formerMember).Error)
+
+	service := NewLostDocumentService(db, repositories.NewLostDocumentRepository(db), nil, repositories.NewUserRepository(db), nil, nil, nil, nil, nil, nil)
+
+	docs, _, err := service.FindPage("draft", repositories.ListQuery{Page: 1, Size: 10}, colleague.ID)
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+	assert.Equal(t, "I", docs[0].Operator.Regu)
+	_, err = service.FindByID(doc.ID, colleague.ID)
+	assert.NoError(t, err)
+
+	docs, _, err = service.FindPage("draft", repositories.ListQuery{Page: 1, Size: 10}, otherRegu.ID)
+	require.NoError(t, err)
+	assert.Empty(t, docs)
+	_, err = service.FindByID(doc.ID, otherRegu.ID)
+	assert.ErrorIs(t, err, ErrAccessDenied)
+}
+
+func TestLostDocumentService_RejectDocument_RequiresReason(t *testing.T) {
+	service := NewLostDocumentService(nil, new(mocks.LostDocumentRepository), nil, nil, nil, nil, nil, nil, nil, nil)
+
+	_, err := service.RejectDocument(101, 3, "   ")
+
+	assert.ErrorIs(t, err, ErrReasonRequired)
+}
+
+func TestLostDocumentService_DeleteLostDocument_RequiresReason(t *testing.T) {
+	service := NewLostDocumentService(nil, new(mocks.LostDocumentRepository), nil, nil, nil, nil, nil, nil, nil, nil)
+
+	err := service.DeleteLostDocument(101, 3, "")
+
+	assert.ErrorIs(t, err, ErrReasonRequired)
+}
+
+func TestLostDocumentService_FindByID_ReguVisibility(t *testing.T) {
+	doc := &models.LostDocument{ID: 101, OperatorID: 2, Operator: models.User{ID: 2, Regu: "I", Peran: models.RoleOperator}}
+
+	testCases := []struct {
+		name        string
+		actor       *models.User
+		expectError error
+	}{
+		{name: "Pemilik dokumen", actor: &models.User{ID: 2, Regu: "I", Peran: models.RoleOperator}},
+		{name: "Anggota regu yang sama", actor: &models.User{ID: 3, Regu: "I", Peran: models.RoleOperator}},
+		{name: "Anggota regu lain ditolak", actor: &models.User{ID: 5, Regu: "II", Peran: models.RoleOperator}, expectError: ErrAccessDenied},
+		{name: "Tanpa regu ditolak", actor: &models.User{ID: 6, Peran: models.RoleOperator}, expectError: ErrAccessDenied},
+		{
+			name:  "Kanit melihat semua regu",
+			actor: &models.User{ID: 4, Regu: "II", Peran: models.RoleKanitSPKT, Role: &models.Role{Kode: models.RoleKanitSPKT, Permissions: []string{models.PermDocumentViewAll}}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			docRepo := new(mocks.LostDocumentRepository)
+			userRepo := new(mocks.UserRepository)
+			docRepo.On("FindByID", uint(101)).Return(doc, nil).Once()
+			userRepo.On("FindByID", tc.actor.ID).Return(tc.actor, nil).Once()
+
+			service := NewLostDocumentService(nil, docRepo, nil, userRepo, nil, nil, nil, nil, nil, nil)
+			result, err := service.FindByID(101, tc.actor.ID)
+
+			if tc.expectError != nil {
+				assert.ErrorIs(t, err, tc.expectError)
+				assert.Nil(t, result)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, doc, result)
+			}
+			docRepo.AssertExpectations(t)
+			userRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestLostDocumentService_FindIdentifierMatches(t *testing.T) {
+	doc := &models.LostDocument{ID: 101, OperatorID: 2, Operator: models.User{ID: 2, Regu: "I", Peran: models.RoleOperator}}
+	groups := []repositories.IdentifierMatchGroup{{
+		Atribut: "nopol", Label: "No. Pol", Nilai: "B 1234 XYZ",
+		Documents: []repositories.IdentifierDocument{{ID: 87, NomorSurat: "SKH/87/X/2026", NamaBarang: "BPKB"}},
+	}}
+
+	t.Run("Pemilik dokumen melihat laporan yang cocok", func(t *testing.T) {
+		docRepo := new(mocks.LostDocumentRepository)
+		userRepo := new(mocks.UserRepository)
+		docRepo.On("FindByID", uint(101)).Return(doc, nil).Once()
+		docRepo.On("FindIdentifierMatches", uint(101)).Return(groups, nil).Once()
+		userRepo.On("FindByID", uint(2)).Return(&models.User{ID: 2, Regu: "I", Peran: models.RoleOperator}, nil).Once()
+
+		service := NewLostDocumentService(nil, docRepo, nil, userRepo, nil, nil, nil, nil, nil, nil)
+		result, err := service.FindIdentifierMatches(101, 2)
+
+		assert.NoError(t, err)
+		assert.Equal(t, groups, result)
+		docRepo.AssertExpectations(t)
+	})
+
+	t.Run("Dokumen di luar jangkauan ditolak sebelum dicocokkan", func(t *testing.T) {
+		docRepo := new(mocks.LostDocumentRepository)
+		userRepo := new(mocks.UserRepository)
+		docRepo.On("FindByID", uint(101)).Return(doc, nil).Once()
+		userRepo.On("FindByID", uint(5)).Return(&models.User{ID: 5, Regu: "II", Peran: models.RoleOperator}, nil).Once()
+
+		service := NewLostDocumentService(nil, docRepo, nil, userRepo, nil, nil, nil, nil, nil, nil)
+		result, err := service.FindIdentifierMatches(101, 5)
+
+		assert.ErrorIs(t, err, ErrAccessDenied)
+		assert.Nil(t, result)
+		docRepo.AssertNotCalled(t, "FindIdentifierMatches", uint(101))
+	})
+}
+
+func TestLostDocumentService_FindPage_ScopesByRegu(t *testing.T) {
+	docRepo := new(mocks.LostDocumentRepository)
+	userRepo := new(mocks.UserRepository)
+	operator := &models.User{ID: 2, Regu: "III", Peran: models.RoleOperator}
+	kanit := &models.User{ID: 4, Regu: "I", Peran: models.RoleKanitSPKT, Role: &models.Role{Kode: models.RoleKanitSPKT, Permissions: []string{models.PermDocumentViewAll}}}
+
+	userRepo.On("FindByID", uint(2)).Return(operator, nil).Once()
+	list := repositories.ListQuery{Page: 2, Size: 10, Sort: "tanggal_laporan"}
+	docRepo.On("FindPage", "active", repositories.DocumentScope{UserID: 2, Regu: "III"}, list).Return([]models.LostDocument{}, repositories.PageResult{Total: 12, Filtered: 12}, nil).Once()
+	userRepo.On("FindByID", uint(4)).Return(kanit, nil).Once()
+	docRepo.On("SearchGlobal", "budi", repositories.DocumentScope{All: true}).Return([]repositories.DocumentSearchHit{}, nil).Once()
+
+	service := NewLostDocumentService(nil, docRepo, nil, userRepo, nil, nil, nil, nil, nil, nil)
+	_, page, err := service.FindPage("active", list, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(12), page.Total)
+	_, err = service.SearchGlobal("budi", 4)
+	assert.NoError(t, err)
+
+	docRepo.AssertExpectations(t)
+	userRepo.AssertExpectations(t)
+}
+
+func TestLostDocumentService_ExportDocuments(t *testing.T) {
+	docRepo := new(mocks.LostDocumentRepository)
+	userRepo := new(mocks.UserRepository)
+	auditService := new(mocks.AuditLogService)
+	configService := new(mocks.ConfigService)
+	operator := &models.User{ID: 2, Regu: "III", Peran: models.RoleOperator}
+	nik := "3171011501900001"
+	approvedAt := time.Date(2026, time.October, 1, 2, 0, 0, 0, time.UTC)
+	filter := repositories.DocumentFilter{Query: "budi", Status: "active"}
+
+	userRepo.On("FindByID", uint(2)).Return(operator, nil).Once()
+	configService.On("GetLocation").Return(time.FixedZone("WIB", 7*3600), nil)
+	docRepo.On("FindInBatches", filter, repositories.DocumentScope{UserID: 2, Regu: "III"}, exportBatchSize, mock.Anything).
+		Run(func(args mock.Arguments) {
+			fn := args.Get(3).(func([]models.LostDocument) error)
+			_ = fn([]models.LostDocument{{
+				NomorSurat:         "SKH/1/X/2026",
+				TanggalLaporan:     approvedAt,
+				TanggalPersetujuan: &approvedAt,
+				Resident:           models.Resident{NamaLengkap: "BUDI", NIK: &nik},
+				LostItems:          []models.LostItem{{NamaBarang: "KTP", Deskripsi: "NIK: 3171"}, {NamaBarang: "SIM"}},
+			}})
+		}).Return(nil).Once()
+	auditService.On("LogActivity", uint(2), models.AuditExportDocuments, mock.AnythingOfType("string")).Once()
+
+	var buf bytes.Buffer
+	service := NewLostDocumentService(nil, docRepo, nil, userRepo, auditService, configService, nil, nil, nil, nil)
+	writer := NewCSVRowWriter(&buf)
+	count, err := service.ExportDocuments(writer, filter, []string{"number", "date", "items", "number"}, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+	assert.NoError(t, writer.Close())
+
+	lines := strings.Split(strings.TrimPrefix(buf.String(), "\ufeff"), "\n")
+	assert.Equal(t, "Nomor Surat,Status,Tanggal Laporan,Tanggal Persetujuan,Barang Hilang,Lokasi Hilang", lines[0])
+	assert.Equal(t, "SKH/1/X/2026,,01-10-2026 09:00,01-10-2026 09:00,KTP (NIK: 3171); SIM,", lines[1])
+	docRepo.AssertExpectations(t)
+	auditService.AssertExpectations(t)
+}
+
+func TestLostDocumentService_ExportDocuments_InvalidColumn(t *testing.T) {
+	var buf bytes.Buffer
+	service := NewLostDocumentService(nil, new(mocks.LostDocumentRepository), nil, new(mocks.UserRepository), nil, nil, nil, nil, nil, nil)
+
+	_, err := service.ExportDocuments(NewCSVRowWriter(&buf), repositories.DocumentFilter{}, []string{"number", "password"}, 2)
+
+	assert.ErrorIs(t, err, ErrInvalidExportColumn)
+	assert.Zero(t, buf.Len(), "Tidak ada yang ditulis sebelum kolom divalidasi")
+}
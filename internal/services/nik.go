@@ -0,0 +1,81 @@
+package services
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// nikProvinceCodes adalah kode provinsi Kemendagri yang dipakai sebagai dua digit pertama NIK.
+// Kode provinsi Papua hasil pemekaran (93-96) ikut dimasukkan, sedangkan NIK lama tetap
+// memakai kode 91/92 dan juga dianggap sah.
+var nikProvinceCodes = map[int]bool{
+	11: true, 12: true, 13: true, 14: true, 15: true, 16: true, 17: true, 18: true, 19: true,
+	21: true,
+	31: true, 32: true, 33: true, 34: true, 35: true, 36: true,
+	51: true, 52: true, 53: true,
+	61: true, 62: true, 63: true, 64: true, 65: true,
+	71: true, 72: true, 73: true, 74: true, 75: true, 76: true,
+	81: true, 82: true,
+	91: true, 92: true, 93: true, 94: true, 95: true, 96: true,
+}
+
+// ValidateNIK memeriksa struktur NIK 16 digit: PP KK CC DDMMYY SSSS, yaitu kode provinsi,
+// kabupaten/kota, kecamatan, tanggal lahir (tanggal +40 untuk perempuan), dan nomor urut.
+// NIK tidak memiliki digit checksum, jadi kecocokan dengan tanggal lahir dan jenis kelamin
+// pemohon dipakai sebagai pemeriksaan silang. tanggalLahir yang kosong (zero) dan jenisKelamin
+// yang kosong dilewati.
+func ValidateNIK(nik string, tanggalLahir time.Time, jenisKelamin string) error {
+	if len(nik) != 16 {
+		return fmt.Errorf("%w: NIK harus 16 digit", ErrInvalidNIK)
+	}
+	for _, r := range nik {
+		if r < '0' || r > '9' {
+			return fmt.Errorf("%w: NIK hanya boleh berisi angka", ErrInvalidNIK)
+		}
+	}
+
+	digits := func(from, to int) int {
+		n, _ := strconv.Atoi(nik[from:to])
+		return n
+	}
+
+	if !nikProvinceCodes[digits(0, 2)] {
+		return fmt.Errorf("%w: kode provinsi %s tidak dikenal", ErrInvalidNIK, nik[0:2])
+	}
+	if digits(2, 4) == 0 {
+		return fmt.Errorf("%w: kode kabupaten/kota tidak boleh 00", ErrInvalidNIK)
+	}
+	if digits(4, 6) == 0 {
+		return fmt.Errorf("%w: kode kecamatan tidak boleh 00", ErrInvalidNIK)
+	}
+
+	day, month, year := digits(6, 8), digits(8, 10), digits(10, 12)
+	isFemale := day > 40
+	if isFemale {
+		day -= 40
+	}
+	if day < 1 || day > 31 || month < 1 || month > 12 {
+		return fmt.Errorf("%w: segmen tanggal lahir %s tidak valid", ErrInvalidNIK, nik[6:12])
+	}
+	if digits(12, 16) == 0 {
+		return fmt.Errorf("%w: nomor urut tidak boleh 0000", ErrInvalidNIK)
+	}
+
+	if !tanggalLahir.IsZero() {
+		if tanggalLahir.Day() != day || int(tanggalLahir.Month()) != month || tanggalLahir.Year()%100 != year {
+			return fmt.Errorf("%w: tanggal lahir di NIK (%02d-%02d-%02d) tidak sama dengan tanggal lahir pemohon", ErrInvalidNIK, day, month, year)
+		}
+	}
+	switch jenisKelamin {
+	case "Laki-laki":
+		if isFemale {
+			return fmt.Errorf("%w: NIK terdaftar untuk perempuan, tetapi jenis kelamin pemohon laki-laki", ErrInvalidNIK)
+		}
+	case "Perempuan":
+		if !isFemale {
+			return fmt.Errorf("%w: NIK terdaftar untuk laki-laki, tetapi jenis kelamin pemohon perempuan", ErrInvalidNIK)
+		}
+	}
+	return nil
+}
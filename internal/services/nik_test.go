@@ -0,0 +1,45 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateNIK(t *testing.T) {
+	lahir := time.Date(1990, 1, 15, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name         string
+		nik          string
+		tanggalLahir time.Time
+		jenisKelamin string
+		valid        bool
+	}{
+		{name: "Sukses - Laki-laki", nik: "3171011501900001", tanggalLahir: lahir, jenisKelamin: "Laki-laki", valid: true},
+		{name: "Sukses - Perempuan (tanggal +40)", nik: "3171015501900001", tanggalLahir: lahir, jenisKelamin: "Perempuan", valid: true},
+		{name: "Sukses - Tanpa tanggal lahir dan jenis kelamin", nik: "3171011501900001", valid: true},
+		{name: "Gagal - Kurang dari 16 digit", nik: "317101150190001", valid: false},
+		{name: "Gagal - Mengandung huruf", nik: "31710115019000A1", valid: false},
+		{name: "Gagal - Kode provinsi tidak dikenal", nik: "9971011501900001", valid: false},
+		{name: "Gagal - Kode kabupaten 00", nik: "3100011501900001", valid: false},
+		{name: "Gagal - Kode kecamatan 00", nik: "3171001501900001", valid: false},
+		{name: "Gagal - Bulan tidak valid", nik: "3171011513900001", valid: false},
+		{name: "Gagal - Nomor urut 0000", nik: "3171011501900000", valid: false},
+		{name: "Gagal - Tanggal lahir berbeda", nik: "3171011601900001", tanggalLahir: lahir, valid: false},
+		{name: "Gagal - Jenis kelamin berbeda", nik: "3171011501900001", tanggalLahir: lahir, jenisKelamin: "Perempuan", valid: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateNIK(tc.nik, tc.tanggalLahir, tc.jenisKelamin)
+			if tc.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.True(t, errors.Is(err, ErrInvalidNIK), "error: %v", err)
+			}
+		})
+	}
+}
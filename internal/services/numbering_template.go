@@ -0,0 +1,153 @@
+package services
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Placeholder yang dikenali di format Nomor Surat.
+const (
+	placeholderSeq        = "SEQ"
+	placeholderRomanMonth = "ROMAN_MONTH"
+	placeholderYear       = "YEAR"
+	placeholderOfficeCode = "OFFICE_CODE"
+	placeholderRegu       = "REGU"
+
+	// Lebar maksimum zero-padding untuk {SEQ:n}.
+	maxSeqWidth = 10
+)
+
+// numberingValues adalah nilai-nilai yang dimasukkan ke placeholder saat menyusun Nomor Surat.
+type numberingValues struct {
+	Seq        int
+	Month      int
+	Year       int
+	OfficeCode string
+	Regu       string
+}
+
+// numberingToken adalah satu potongan format: teks biasa (Name kosong) atau placeholder.
+type numberingToken struct {
+	Literal string
+	Name    string
+	Width   int
+}
+
+// parseNumberingTemplate mengurai format seperti "SKH/{SEQ:4}/{ROMAN_MONTH}/{OFFICE_CODE}/{YEAR}".
+// Kurung kurawal yang tidak berpasangan, placeholder yang tidak dikenal, dan argumen yang salah
+// dianggap error, sehingga salah ketik ditolak saat disimpan dan bukan saat surat dibuat.
+func parseNumberingTemplate(format string) ([]numberingToken, error) {
+	var tokens []numberingToken
+	rest := format
+	for rest != "" {
+		open := strings.IndexAny(rest, "{}")
+		if open < 0 {
+			tokens = append(tokens, numberingToken{Literal: rest})
+			break
+		}
+		if rest[open] == '}' {
+			return nil, fmt.Errorf("%w: tanda '}' tanpa pasangan '{'", ErrInvalidNumberFormat)
+		}
+		if open > 0 {
+			tokens = append(tokens, numberingToken{Literal: rest[:open]})
+		}
+
+		body := rest[open+1:]
+		end := strings.IndexAny(body, "{}")
+		if end < 0 || body[end] == '{' {
+			return nil, fmt.Errorf("%w: placeholder '{' tidak ditutup dengan '}'", ErrInvalidNumberFormat)
+		}
+
+		token, err := parseNumberingPlaceholder(body[:end])
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, token)
+		rest = body[end+1:]
+	}
+	return tokens, nil
+}
+
+func parseNumberingPlaceholder(placeholder string) (numberingToken, error) {
+	name, arg, hasArg := strings.Cut(placeholder, ":")
+	token := numberingToken{Name: name}
+
+	switch name {
+	case placeholderSeq:
+		if hasArg {
+			width, err := strconv.Atoi(arg)
+			if err != nil || width < 1 || width > maxSeqWidth {
+				return token, fmt.Errorf("%w: lebar {SEQ:n} harus angka 1 sampai %d", ErrInvalidNumberFormat, maxSeqWidth)
+			}
+			token.Width = width
+		}
+	case placeholderRomanMonth, placeholderYear, placeholderOfficeCode, placeholderRegu:
+		if hasArg {
+			return token, fmt.Errorf("%w: placeholder {%s} tidak menerima argumen", ErrInvalidNumberFormat, name)
+		}
+	default:
+		return token, fmt.Errorf("%w: placeholder {%s} tidak dikenal", ErrInvalidNumberFormat, placeholder)
+	}
+	return token, nil
+}
+
+// validateNumberingTemplate memeriksa sintaks format sekaligus aturan yang menjaga Nomor Surat tetap unik.
+func validateNumberingTemplate(format string, officeCode string) error {
+	if strings.TrimSpace(format) == "" {
+		return fmt.Errorf("%w: format tidak boleh kosong", ErrInvalidNumberFormat)
+	}
+	if strings.Contains(format, "%") {
+		return fmt.Errorf("%w: format lama (%%d/%%s) tidak lagi didukung, gunakan {SEQ}, {ROMAN_MONTH}, dan {YEAR}", ErrInvalidNumberFormat)
+	}
+
+	tokens, err := parseNumberingTemplate(format)
+	if err != nil {
+		return err
+	}
+
+	used := make(map[string]bool)
+	for _, t := range tokens {
+		if t.Name != "" {
+			used[t.Name] = true
+		}
+	}
+	if !used[placeholderSeq] {
+		return fmt.Errorf("%w: format wajib memuat {SEQ}", ErrInvalidNumberFormat)
+	}
+	// Nomor urut dimulai ulang setiap tahun, jadi tanpa {YEAR} nomor tahun depan akan bentrok.
+	if !used[placeholderYear] {
+		return fmt.Errorf("%w: format wajib memuat {YEAR} karena nomor urut dimulai ulang setiap tahun", ErrInvalidNumberFormat)
+	}
+	if used[placeholderOfficeCode] && strings.TrimSpace(officeCode) == "" {
+		return fmt.Errorf("%w: format memakai {OFFICE_CODE}, tetapi Kode Kantor belum diisi", ErrInvalidNumberFormat)
+	}
+	return nil
+}
+
+// renderNumberingTemplate menyusun Nomor Surat dari format yang sudah tervalidasi.
+func renderNumberingTemplate(format string, values numberingValues) (string, error) {
+	tokens, err := parseNumberingTemplate(format)
+	if err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	for _, t := range tokens {
+		switch t.Name {
+		case "":
+			sb.WriteString(t.Literal)
+		case placeholderSeq:
+			sb.WriteString(fmt.Sprintf("%0*d", t.Width, values.Seq))
+		case placeholderRomanMonth:
+			sb.WriteString(intToRoman(values.Month))
+		case placeholderYear:
+			sb.WriteString(strconv.Itoa(values.Year))
+		case placeholderOfficeCode:
+			sb.WriteString(values.OfficeCode)
+		case placeholderRegu:
+			sb.WriteString(values.Regu)
+		}
+	}
+	return sb.String(), nil
+}
@@ -0,0 +1,68 @@
+package services
+
+import (
+	"errors"
+	"simdokpol/internal/dto"
+	"simdokpol/internal/mocks"
+	"simdokpol/internal/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateNumberingTemplate(t *testing.T) {
+	testCases := []struct {
+		name       string
+		format     string
+		officeCode string
+		valid      bool
+	}{
+		{name: "Sukses - Format lengkap", format: "SKH/{SEQ:4}/{ROMAN_MONTH}/{OFFICE_CODE}/{REGU}/{YEAR}", officeCode: "TUK.7.2.1", valid: true},
+		{name: "Sukses - Tanpa kode kantor", format: "SKH/{SEQ}/{ROMAN_MONTH}/TUK.7.2.1/{YEAR}", valid: true},
+		{name: "Gagal - Format kosong", format: "  ", valid: false},
+		{name: "Gagal - Format Sprintf lama", format: "SKH/%d/%s/TUK.7.2.1/%d", valid: false},
+		{name: "Gagal - Tanpa SEQ", format: "SKH/{ROMAN_MONTH}/{YEAR}", valid: false},
+		{name: "Gagal - Tanpa YEAR", format: "SKH/{SEQ}/{ROMAN_MONTH}", valid: false},
+		{name: "Gagal - Placeholder tidak dikenal", format: "SKH/{SEQ}/{BULAN}/{YEAR}", valid: false},
+		{name: "Gagal - Kurung tidak ditutup", format: "SKH/{SEQ/{YEAR}", valid: false},
+		{name: "Gagal - Kurung tutup tanpa pasangan", format: "SKH/SEQ}/{YEAR}", valid: false},
+		{name: "Gagal - Lebar SEQ tidak valid", format: "SKH/{SEQ:0}/{YEAR}", valid: false},
+		{name: "Gagal - Argumen pada YEAR", format: "SKH/{SEQ}/{YEAR:2}", valid: false},
+		{name: "Gagal - OFFICE_CODE tanpa kode kantor", format: "SKH/{SEQ}/{OFFICE_CODE}/{YEAR}", valid: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateNumberingTemplate(tc.format, tc.officeCode)
+			if tc.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.True(t, errors.Is(err, ErrInvalidNumberFormat), "error: %v", err)
+			}
+		})
+	}
+}
+
+func TestRenderNumberingTemplate(t *testing.T) {
+	values := numberingValues{Seq: 7, Month: 10, Year: 2025, OfficeCode: "TUK.7.2.1", Regu: "II"}
+
+	nomor, err := renderNumberingTemplate("SKH/{SEQ}/{ROMAN_MONTH}/{OFFICE_CODE}/{YEAR}", values)
+	assert.NoError(t, err)
+	assert.Equal(t, "SKH/7/X/TUK.7.2.1/2025", nomor)
+
+	nomor, err = renderNumberingTemplate("SKH/{SEQ:4}/REGU-{REGU}/{YEAR}", values)
+	assert.NoError(t, err)
+	assert.Equal(t, "SKH/0007/REGU-II/2025", nomor)
+}
+
+func TestNextDocumentNumber_RejectsInvalidStoredFormat(t *testing.T) {
+	configService := new(mocks.ConfigService)
+	// Format hasil migrasi lama yang masih memuat verb fmt.Sprintf dan tidak memuat {YEAR}.
+	configService.On("GetConfig").Return(&dto.AppConfig{FormatNomorSurat: "SKH/%03d/{ROMAN_MONTH}/TUK.7.2./{SEQ}"}, nil)
+
+	// seqRepo sengaja nil: nomor urut tidak boleh diambil bila formatnya tidak valid.
+	service := NewDocumentNumberingService(nil, nil, configService)
+	_, err := service.NextDocumentNumber(nil, &models.DocumentType{Kode: models.DocumentTypeLostDocument}, "I")
+
+	assert.ErrorIs(t, err, ErrInvalidNumberFormat)
+}
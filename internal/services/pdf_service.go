@@ -0,0 +1,332 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"simdokpol/internal/dto"
+	"simdokpol/internal/models"
+	"strings"
+
+	"github.com/go-pdf/fpdf"
+)
+
+// Path logo yang sama dengan yang dipakai oleh print_preview.html.
+const letterLogoPath = "web/static/img/logo.png"
+
+// Ukuran dasar tata letak surat (dalam milimeter dan point), disamakan dengan print_preview.html.
+const (
+	pdfMargin     = 15.0
+	pdfBodyFont   = 11.0
+	pdfSmallFont  = 8.0
+	pdfLineHeight = 4.6
+	pdfSmallLine  = 3.6
+	pdfSignSpace  = 12.0
+)
+
+type PDFService interface {
+	GenerateDocumentPDF(doc *models.LostDocument, docType *models.DocumentType, verificationURL string) ([]byte, error)
+	// GenerateHandoverReportPDF merender laporan serah terima jaga menjadi file PDF A4 siap cetak.
+	GenerateHandoverReportPDF(report *dto.HandoverReport) ([]byte, error)
+}
+
+type pdfService struct {
+	configService ConfigService
+}
+
+func NewPDFService(configService ConfigService) PDFService {
+	return &pdfService{configService: configService}
+}
+
+// GenerateDocumentPDF merender dokumen menjadi file PDF A4 sepenuhnya di sisi server, mengikuti
+// template cetak jenis dokumennya (print_preview.html atau print_generic.html).
+// Jika verificationURL tidak kosong, QR code verifikasi dicetak di pojok kanan atas.
+func (s *pdfService) GenerateDocumentPDF(doc *models.LostDocument, docType *models.DocumentType, verificationURL string) ([]byte, error) {
+	appConfig, err := s.configService.GetConfig()
+	if err != nil {
+		return nil, fmt.Errorf("gagal memuat konfigurasi aplikasi: %w", err)
+	}
+
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.SetMargins(pdfMargin, pdfMargin, pdfMargin)
+	pdf.SetAutoPageBreak(true, pdfMargin)
+	pdf.SetTitle(fmt.Sprintf("%s - %s", docType.Nama, doc.NomorSurat), true)
+	pdf.SetCreator("SIMDOKPOL", true)
+	pdf.AddPage()
+
+	// Font inti PDF memakai cp1252, sehingga teks UTF-8 perlu diterjemahkan.
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+	l := &letterWriter{pdf: pdf, tr: tr}
+
+	if verificationURL != "" {
+		if err := l.writeVerificationQR(verificationURL); err != nil {
+			return nil, err
+		}
+	}
+	l.writeHeader(appConfig)
+	l.writeTitle(strings.ToUpper(docType.Nama), doc)
+	if docType.PrintTemplate == models.PrintTemplateLostDocument {
+		l.writeBody(doc, appConfig)
+	} else {
+		l.writeGenericBody(doc, docType, appConfig)
+	}
+	l.writeSignatures(doc, appConfig)
+
+	if err := pdf.Error(); err != nil {
+		return nil, fmt.Errorf("gagal merender PDF: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, fmt.Errorf("gagal menulis PDF: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// letterWriter membungkus fpdf dengan helper kecil untuk menulis bagian-bagian surat.
+type letterWriter struct {
+	pdf *fpdf.Fpdf
+	tr  func(string) string
+}
+
+func (l *letterWriter) contentWidth() float64 {
+	pageWidth, _ := l.pdf.GetPageSize()
+	left, _, right, _ := l.pdf.GetMargins()
+	return pageWidth - left - right
+}
+
+func (l *letterWriter) font(style string, size float64) {
+	l.pdf.SetFont("Courier", style, size)
+}
+
+// paragraph menulis paragraf rata kanan-kiri dengan indentasi baris pertama,
+// meniru kelas "text-justify indent-8" pada template HTML.
+func (l *letterWriter) paragraph(text string) {
+	l.font("", pdfBodyFont)
+	l.pdf.MultiCell(0, pdfLineHeight, l.tr("    "+text), "", "J", false)
+}
+
+func (l *letterWriter) writeHeader(cfg *dto.AppConfig) {
+	kopWidth := l.contentWidth() * 0.4
+	l.font("B", pdfSmallFont)
+	for _, line := range []string{cfg.KopBaris1, cfg.KopBaris2, cfg.KopBaris3} {
+		l.pdf.CellFormat(kopWidth, pdfSmallLine, l.tr(line), "", 1, "C", false, 0, "")
+	}
+	x, y := l.pdf.GetXY()
+	l.pdf.SetLineWidth(0.5)
+	l.pdf.Line(x, y, x+kopWidth, y)
+	l.pdf.SetLineWidth(0.2)
+	l.pdf.Ln(2)
+}
+
+// writeVerificationQR menempatkan QR code di area kosong sebelah kanan KOP surat
+// tanpa menggeser posisi tulis, sehingga tata letak surat tetap sama.
+func (l *letterWriter) writeVerificationQR(verificationURL string) error {
+	png, err := encodeQRCode(verificationURL)
+	if err != nil {
+		return fmt.Errorf("gagal membuat QR code verifikasi: %w", err)
+	}
+	qrSize := 20.0
+	pageWidth, _ := l.pdf.GetPageSize()
+	_, top, right, _ := l.pdf.GetMargins()
+	x := pageWidth - right - qrSize
+
+	l.pdf.RegisterImageOptionsReader("verification-qr", fpdf.ImageOptions{ImageType: "PNG"}, bytes.NewReader(png))
+	l.pdf.ImageOptions("verification-qr", x, top, qrSize, qrSize, false, fpdf.ImageOptions{ImageType: "PNG"}, 0, "")
+
+	l.font("", 6)
+	l.pdf.SetXY(x-5, top+qrSize)
+	l.pdf.CellFormat(qrSize+10, 2.5, "Pindai untuk verifikasi", "", 0, "C", false, 0, "")
+	left, _, _, _ := l.pdf.GetMargins()
+	l.pdf.SetXY(left, top)
+	return nil
+}
+
+func (l *letterWriter) writeTitle(title string, doc *models.LostDocument) {
+	if _, err := os.Stat(letterLogoPath); err == nil {
+		logoWidth := 13.0
+		pageWidth, _ := l.pdf.GetPageSize()
+		l.pdf.ImageOptions(letterLogoPath, (pageWidth-logoWidth)/2, l.pdf.GetY(), logoWidth, 0, true, fpdf.ImageOptions{ReadDpi: true}, 0, "")
+		l.pdf.Ln(1)
+	}
+
+	l.font("BU", 10)
+	l.pdf.CellFormat(0, 5, l.tr(title), "", 1, "C", false, 0, "")
+	l.font("", pdfSmallFont)
+	l.pdf.CellFormat(0, pdfSmallLine, l.tr("Nomor: "+doc.NomorSurat), "", 1, "C", false, 0, "")
+	l.pdf.Ln(2)
+}
+
+// letterRow adalah satu baris "label : nilai" pada badan surat.
+type letterRow struct {
+	label string
+	value string
+	bold  bool
+}
+
+func (l *letterWriter) writeRows(rows []letterRow) {
+	indent := 10.0
+	labelWidth := 40.0
+	left, _, _, _ := l.pdf.GetMargins()
+	for _, row := range rows {
+		l.pdf.SetX(left + indent)
+		l.font("", pdfBodyFont)
+		l.pdf.CellFormat(labelWidth, pdfLineHeight, l.tr(row.label), "", 0, "L", false, 0, "")
+		l.pdf.CellFormat(5, pdfLineHeight, ":", "", 0, "L", false, 0, "")
+		if row.bold {
+			l.font("B", pdfBodyFont)
+		}
+		l.pdf.MultiCell(l.contentWidth()-indent-labelWidth-5, pdfLineHeight, l.tr(row.value), "", "L", false)
+	}
+	l.pdf.Ln(1)
+}
+
+func residentRows(resident models.Resident) []letterRow {
+	return []letterRow{
+		{"Nama", strings.ToUpper(resident.NamaLengkap), true},
+		{"TTL", fmt.Sprintf("%s, %s", resident.TempatLahir, resident.TanggalLahir.Format("02-01-2006")), false},
+		{"Agama", resident.Agama, false},
+		{"Jenis kelamin", resident.JenisKelamin, false},
+		{"Pekerjaan", resident.Pekerjaan, false},
+		{"Alamat", resident.Alamat, false},
+	}
+}
+
+func (l *letterWriter) writeBody(doc *models.LostDocument, cfg *dto.AppConfig) {
+	l.paragraph(fmt.Sprintf("---- Yang bertanda tangan dibawah ini A.n. KEPALA KEPOLISIAN %s, Menerangkan dengan benar bahwa :", strings.ToUpper(cfg.KopBaris3)))
+	l.pdf.Ln(1)
+
+	resident := doc.Resident
+	l.writeRows(residentRows(resident))
+	indent := 10.0
+	left, _, _, _ := l.pdf.GetMargins()
+
+	l.paragraph(fmt.Sprintf("Yang bersangkutan tersebut di atas benar telah datang di Kantor %s dan melaporkan bahwa telah kehilangan surat berharga berupa :", cfg.NamaKantor))
+	l.pdf.Ln(1)
+	for _, item := range doc.LostItems {
+		l.pdf.SetX(left + indent)
+		l.pdf.MultiCell(l.contentWidth()-indent, pdfLineHeight, l.tr(fmt.Sprintf("- 1 (Satu) Buah %s Dengan Keterangan : %s A.n Pelapor", item.NamaBarang, item.Deskripsi)), "", "L", false)
+	}
+	l.pdf.Ln(1)
+
+	l.paragraph(fmt.Sprintf("---- Surat/kartu tersebut hilang di sekitar %s, dan sudah dilakukan pencarian namun sampai dikeluarkan Surat Keterangan ini belum ditemukan.", doc.LokasiHilang))
+	l.pdf.Ln(3)
+
+	l.writeApplicantSignature(resident)
+
+	l.paragraph("----- Demikian Surat Keterangan ini dibuat dengan sebenar-benarnya dan dapat dipergunakan sebagaimana perlunya.")
+	l.pdf.Ln(1)
+
+	l.font("BU", pdfBodyFont)
+	l.pdf.CellFormat(0, pdfLineHeight, "Tindakan Yang Diambil :", "", 1, "L", false, 0, "")
+	l.font("", pdfBodyFont)
+	actions := []string{
+		"Menerima laporan dan membuat Surat Keterangan Kehilangan barang guna seperlunya;",
+		"Surat keterangan kehilangan ini berlaku selama 15 (lima belas) hari, berlaku mulai tanggal dikeluarkan;",
+		"Surat Keterangan ini bukan sebagai pengganti surat yang hilang tetapi berguna untuk mengurus kembali surat yang hilang.",
+	}
+	for i, action := range actions {
+		l.pdf.SetX(left)
+		l.pdf.CellFormat(6, pdfLineHeight, fmt.Sprintf("%d.", i+1), "", 0, "L", false, 0, "")
+		l.pdf.MultiCell(l.contentWidth()-6, pdfLineHeight, l.tr(action), "", "L", false)
+	}
+	l.pdf.Ln(2)
+}
+
+// writeApplicantSignature menulis kolom tanda tangan pemohon di separuh kanan halaman.
+func (l *letterWriter) writeApplicantSignature(resident models.Resident) {
+	left, _, _, _ := l.pdf.GetMargins()
+	half := l.contentWidth() / 2
+	l.font("", pdfBodyFont)
+	l.pdf.SetX(left + half)
+	l.pdf.CellFormat(half, pdfLineHeight, "Yang Bermohon", "", 1, "C", false, 0, "")
+	l.pdf.Ln(pdfSignSpace - 2)
+	l.pdf.SetX(left + half)
+	l.font("BU", pdfBodyFont)
+	l.pdf.CellFormat(half, pdfLineHeight, l.tr(strings.ToUpper(resident.NamaLengkap)), "", 1, "C", false, 0, "")
+	l.pdf.Ln(1)
+}
+
+// writeGenericBody menulis badan surat untuk template print_generic.html: data pemohon,
+// lalu setiap isian jenis dokumen sesuai urutan di registri.
+func (l *letterWriter) writeGenericBody(doc *models.LostDocument, docType *models.DocumentType, cfg *dto.AppConfig) {
+	l.paragraph(fmt.Sprintf("---- Yang bertanda tangan dibawah ini A.n. KEPALA KEPOLISIAN %s, Menerangkan dengan benar bahwa :", strings.ToUpper(cfg.KopBaris3)))
+	l.pdf.Ln(1)
+
+	rows := residentRows(doc.Resident)
+	if doc.Resident.NIK != nil {
+		rows = append([]letterRow{{"NIK", *doc.Resident.NIK, false}}, rows...)
+	}
+	l.writeRows(rows)
+
+	l.paragraph(fmt.Sprintf("Yang bersangkutan tersebut di atas benar telah datang di Kantor %s dengan keterangan sebagai berikut :", cfg.NamaKantor))
+	l.pdf.Ln(1)
+	l.writeRows(documentFieldRows(doc, docType))
+	l.pdf.Ln(2)
+	l.writeApplicantSignature(doc.Resident)
+
+	l.paragraph(fmt.Sprintf("----- Demikian %s ini dibuat dengan sebenar-benarnya dan dapat dipergunakan sebagaimana perlunya.", docType.Nama))
+	l.pdf.Ln(3)
+}
+
+// documentFieldRows menyusun baris isian jenis dokumen. NIK sudah tercetak di data pemohon.
+func documentFieldRows(doc *models.LostDocument, docType *models.DocumentType) []letterRow {
+	var rows []letterRow
+	for _, field := range docType.Fields {
+		switch field.Key {
+		case models.FieldNIK:
+			continue
+		case models.FieldLokasiHilang:
+			rows = append(rows, letterRow{label: field.Label, value: doc.LokasiHilang})
+		case models.FieldLostItems:
+			var items []string
+			for _, item := range doc.LostItems {
+				items = append(items, fmt.Sprintf("%s (%s)", item.NamaBarang, item.Deskripsi))
+			}
+			rows = append(rows, letterRow{label: field.Label, value: strings.Join(items, "; ")})
+		default:
+			value := doc.DataTambahan[field.Key]
+			if value == "" {
+				value = "-"
+			}
+			rows = append(rows, letterRow{label: field.Label, value: value})
+		}
+	}
+	return rows
+}
+
+func (l *letterWriter) writeSignatures(doc *models.LostDocument, cfg *dto.AppConfig) {
+	left, _, _, _ := l.pdf.GetMargins()
+	half := l.contentWidth() / 2
+
+	l.font("", pdfSmallFont)
+	l.pdf.SetX(left + half)
+	l.pdf.CellFormat(half, pdfSmallLine, l.tr(fmt.Sprintf("%s, %s", cfg.TempatSurat, doc.TanggalLaporan.Format("02 January 2006"))), "", 1, "C", false, 0, "")
+	l.pdf.Ln(1)
+
+	pejabat := doc.PejabatPersetuju
+	petugas := doc.PetugasPelapor
+	columns := [2][]string{
+		{fmt.Sprintf("a.n. KEPALA %s", strings.ToUpper(cfg.NamaKantor)), strings.TrimSpace(pejabat.Jabatan + " " + pejabat.Regu)},
+		{"Penerima Laporan", strings.TrimSpace(petugas.Jabatan + " " + petugas.Regu)},
+	}
+	signers := [2]models.User{pejabat, petugas}
+
+	top := l.pdf.GetY()
+	for i, lines := range columns {
+		x := left + float64(i)*half
+		l.pdf.SetXY(x, top)
+		l.font("", 9)
+		for _, line := range lines {
+			l.pdf.SetX(x)
+			l.pdf.CellFormat(half, pdfSmallLine+0.4, l.tr(line), "", 1, "C", false, 0, "")
+		}
+		l.pdf.Ln(pdfSignSpace)
+		l.pdf.SetX(x)
+		l.font("BU", 9)
+		l.pdf.CellFormat(half, pdfSmallLine+0.4, l.tr(strings.ToUpper(signers[i].NamaLengkap)), "", 1, "C", false, 0, "")
+		l.pdf.SetX(x)
+		l.font("", 9)
+		l.pdf.CellFormat(half, pdfSmallLine+0.4, l.tr(fmt.Sprintf("%s / NRP %s", signers[i].Pangkat, signers[i].NRP)), "", 1, "C", false, 0, "")
+	}
+}
@@ -0,0 +1,205 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"simdokpol/internal/dto"
+	"simdokpol/internal/models"
+	"simdokpol/internal/repositories"
+	"sync"
+	"time"
+)
+
+// DefaultRecycleBinPurgeInterval adalah jeda antar eksekusi pemusnahan otomatis tong sampah.
+const DefaultRecycleBinPurgeInterval = 24 * time.Hour
+
+// DefaultRecycleBinRetentionDays dipakai bila masa simpan tong sampah belum diatur atau tidak valid.
+const DefaultRecycleBinRetentionDays = 30
+
+// RecycleBinService menampilkan dokumen terhapus, memulihkannya, dan memusnahkannya secara permanen,
+// baik manual maupun otomatis setelah masa simpan di Pengaturan terlewati.
+type RecycleBinService interface {
+	FindDeletedDocuments() ([]dto.DeletedDocument, error)
+	// RestoreDocument mengembalikan dokumen terhapus. Nomor Surat asli ikut dipulihkan bila belum
+	// dipakai dokumen lain; jika sudah, dokumen dipulihkan sebagai draf tanpa nomor.
+	RestoreDocument(id uint, actorID uint) (*dto.RestoredDocument, error)
+	PurgeDocument(id uint, actorID uint) error
+	// PurgeExpired memusnahkan dokumen yang berada di tong sampah lebih lama dari masa simpan.
+	PurgeExpired(actorID uint) (int, error)
+	// Start menjalankan PurgeExpired sekali saat dipanggil lalu berulang setiap interval
+	// sampai ctx dibatalkan. Dipanggil sebagai goroutine.
+	Start(ctx context.Context, interval time.Duration)
+}
+
+type recycleBinService struct {
+	binRepo       repositories.RecycleBinRepository
+	configService ConfigService
+	auditService  AuditLogService
+	now           func() time.Time
+
+	purgeMu sync.Mutex // Mencegah dua pemusnahan kedaluwarsa berjalan bersamaan
+}
+
+func NewRecycleBinService(binRepo repositories.RecycleBinRepository, configService ConfigService, auditService AuditLogService) RecycleBinService {
+	return &recycleBinService{
+		binRepo:       binRepo,
+		configService: configService,
+		auditService:  auditService,
+		now:           time.Now,
+	}
+}
+
+func (s *recycleBinService) FindDeletedDocuments() ([]dto.DeletedDocument, error) {
+	docs, err := s.binRepo.FindDeletedDocuments()
+	if err != nil {
+		return nil, err
+	}
+	retention := s.retention()
+
+	result := make([]dto.DeletedDocument, 0, len(docs))
+	for _, doc := range docs {
+		nomorAsli := originalNomorSurat(doc.NomorSurat)
+		tersedia := true
+		if nomorAsli != "" {
+			taken, err := s.binRepo.IsNomorSuratTaken(nomorAsli, doc.ID)
+			if err != nil {
+				return nil, err
+			}
+			tersedia = !taken
+		}
+		result = append(result, dto.DeletedDocument{
+			ID:              doc.ID,
+			NomorSurat:      nomorAsli,
+			NomorTersedia:   tersedia,
+			JenisDokumen:    doc.JenisDokumen,
+			Status:          doc.Status,
+			NamaPemohon:     doc.Resident.NamaLengkap,
+			TanggalLaporan:  doc.TanggalLaporan,
+			DihapusPada:     doc.DeletedAt.Time,
+			DihapusOleh:     doc.DihapusOleh.NamaLengkap,
+			Alasan:          doc.AlasanPenghapusan,
+			DimusnahkanPada: doc.DeletedAt.Time.Add(retention),
+		})
+	}
+	return result, nil
+}
+
+func (s *recycleBinService) RestoreDocument(id uint, actorID uint) (*dto.RestoredDocument, error) {
+	doc, err := s.binRepo.FindDeletedDocument(id)
+	if err != nil {
+		return nil, err
+	}
+
+	nomorAsli := originalNomorSurat(doc.NomorSurat)
+	result := &dto.RestoredDocument{ID: doc.ID, NomorSurat: nomorAsli, NomorAsli: nomorAsli, Status: doc.Status}
+	changes := map[string]interface{}{
+		"nomor_surat":        nomorAsli,
+		"alasan_penghapusan": "",
+		"dihapus_oleh_id":    nil,
+	}
+	// Nomor yang sama tidak boleh beredar di dua surat; bila nomor asli sudah dipakai, dokumen
+	// dipulihkan sebagai draf dan diajukan ulang untuk mendapat nomor baru.
+	clashChanges := map[string]interface{}{
+		"nomor_surat":         "",
+		"nomor_urut":          0,
+		"tahun_nomor":         0,
+		"status":              models.StatusDraf,
+		"tanggal_persetujuan": nil,
+		"tanggal_pencabutan":  nil,
+		"alasan_pencabutan":   "",
+		"dicabut_oleh_id":     nil,
+	}
+	nomorBentrok, err := s.binRepo.Restore(doc.ID, changes, clashChanges)
+	if err != nil {
+		return nil, err
+	}
+	if nomorBentrok {
+		result.NomorSurat = ""
+		result.Status = models.StatusDraf
+		result.NomorBentrok = true
+	}
+
+	detail := fmt.Sprintf("Memulihkan dokumen %s dari tong sampah", deletedDocumentLabel(doc))
+	if result.NomorBentrok {
+		detail += fmt.Sprintf(" sebagai draf karena Nomor Surat %s sudah dipakai dokumen lain", nomorAsli)
+	}
+	s.auditService.LogActivity(actorID, models.AuditRestoreDeleted, detail)
+	return result, nil
+}
+
+func (s *recycleBinService) PurgeDocument(id uint, actorID uint) error {
+	doc, err := s.binRepo.FindDeletedDocument(id)
+	if err != nil {
+		return err
+	}
+	if err := s.binRepo.Purge(doc.ID); err != nil {
+		return err
+	}
+	s.auditService.LogActivity(actorID, models.AuditPurgeDocument,
+		fmt.Sprintf("Memusnahkan permanen dokumen %s yang dihapus dengan alasan: %s", deletedDocumentLabel(doc), doc.AlasanPenghapusan))
+	return nil
+}
+
+func (s *recycleBinService) PurgeExpired(actorID uint) (int, error) {
+	s.purgeMu.Lock()
+	defer s.purgeMu.Unlock()
+
+	cutoff := s.now().Add(-s.retention())
+	docs, err := s.binRepo.FindDeletedBefore(cutoff)
+	if err != nil {
+		return 0, fmt.Errorf("gagal memuat dokumen kedaluwarsa di tong sampah: %w", err)
+	}
+
+	purged := 0
+	var purgeErr error
+	for i := range docs {
+		if err := s.binRepo.Purge(docs[i].ID); err != nil {
+			purgeErr = fmt.Errorf("gagal memusnahkan dokumen %s: %w", deletedDocumentLabel(&docs[i]), err)
+			break
+		}
+		purged++
+	}
+	// Dokumen yang sudah terlanjur dimusnahkan tetap dicatat meskipun eksekusi berhenti di tengah jalan.
+	if purged > 0 {
+		s.auditService.LogActivity(actorID, models.AuditPurgeDocument,
+			fmt.Sprintf("Memusnahkan permanen %d dokumen yang dihapus sebelum %s", purged, cutoff.Format("02-01-2006 15:04")))
+	}
+	return purged, purgeErr
+}
+
+func (s *recycleBinService) Start(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultRecycleBinPurgeInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.PurgeExpired(models.SystemUserID); err != nil {
+			log.Printf("PERINGATAN: Pemusnahan otomatis tong sampah gagal: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// retention mengembalikan masa simpan tong sampah dari Pengaturan.
+func (s *recycleBinService) retention() time.Duration {
+	days := DefaultRecycleBinRetentionDays
+	if appConfig, err := s.configService.GetConfig(); err == nil && appConfig.RecycleBinRetentionDays > 0 {
+		days = appConfig.RecycleBinRetentionDays
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// deletedDocumentLabel seperti documentLabel, tetapi memakai nomor asli dokumen terhapus.
+func deletedDocumentLabel(doc *models.LostDocument) string {
+	label := *doc
+	label.NomorSurat = originalNomorSurat(doc.NomorSurat)
+	return documentLabel(&label)
+}
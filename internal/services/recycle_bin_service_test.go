@@ -0,0 +1,95 @@
+package services
+
+import (
+	"simdokpol/internal/dto"
+	"simdokpol/internal/mocks"
+	"simdokpol/internal/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func TestRecycleBinService_RestoreDocument(t *testing.T) {
+	actorID := uint(1)
+
+	testCases := []struct {
+		name           string
+		nomorSurat     string
+		nomorTaken     bool
+		expectedNomor  string
+		expectedStatus string
+		expectBentrok  bool
+	}{
+		{name: "Sukses - Nomor Asli Dipulihkan", nomorSurat: "DELETED_1700000000_SKH/12/X/2026", expectedNomor: "SKH/12/X/2026", expectedStatus: models.StatusDiterbitkan},
+		{name: "Sukses - Nomor Sudah Dipakai, Dipulihkan Sebagai Draf", nomorSurat: "DELETED_1700000000_SKH/12/X/2026", nomorTaken: true, expectedStatus: models.StatusDraf, expectBentrok: true},
+		{name: "Sukses - Draf Tanpa Nomor", nomorSurat: "", expectedStatus: models.StatusDraf},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			binRepo := new(mocks.RecycleBinRepository)
+			auditService := new(mocks.AuditLogService)
+
+			status := models.StatusDiterbitkan
+			if tc.nomorSurat == "" {
+				status = models.StatusDraf
+			}
+			binRepo.On("FindDeletedDocument", uint(7)).Return(&models.LostDocument{ID: 7, NomorSurat: tc.nomorSurat, Status: status}, nil)
+			// Pemeriksaan nomor dan pemulihan dijalankan repository dalam satu transaksi.
+			isClashReset := mock.MatchedBy(func(clashChanges map[string]interface{}) bool {
+				return clashChanges["nomor_surat"] == "" && clashChanges["status"] == models.StatusDraf
+			})
+			binRepo.On("Restore", uint(7), mock.MatchedBy(func(changes map[string]interface{}) bool {
+				return changes["nomor_surat"] == originalNomorSurat(tc.nomorSurat) && changes["alasan_penghapusan"] == ""
+			}), isClashReset).Return(tc.nomorTaken, nil).Once()
+			auditService.On("LogActivity", actorID, models.AuditRestoreDeleted, mock.AnythingOfType("string")).Once()
+
+			service := NewRecycleBinService(binRepo, nil, auditService)
+			restored, err := service.RestoreDocument(7, actorID)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedNomor, restored.NomorSurat)
+			assert.Equal(t, tc.expectedStatus, restored.Status)
+			assert.Equal(t, tc.expectBentrok, restored.NomorBentrok)
+			binRepo.AssertExpectations(t)
+			auditService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRecycleBinService_RestoreDocument_NotInBin(t *testing.T) {
+	binRepo := new(mocks.RecycleBinRepository)
+	binRepo.On("FindDeletedDocument", uint(7)).Return((*models.LostDocument)(nil), gorm.ErrRecordNotFound)
+
+	service := NewRecycleBinService(binRepo, nil, new(mocks.AuditLogService))
+	_, err := service.RestoreDocument(7, 1)
+
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	binRepo.AssertNotCalled(t, "Restore", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRecycleBinService_PurgeExpired(t *testing.T) {
+	binRepo := new(mocks.RecycleBinRepository)
+	configService := new(mocks.ConfigService)
+	auditService := new(mocks.AuditLogService)
+
+	configService.On("GetConfig").Return(&dto.AppConfig{RecycleBinRetentionDays: 10}, nil)
+	now := time.Date(2026, 10, 17, 8, 0, 0, 0, time.UTC)
+	binRepo.On("FindDeletedBefore", now.Add(-10*24*time.Hour)).
+		Return([]models.LostDocument{{ID: 3, NomorSurat: "DELETED_1700000000_SKH/3"}, {ID: 4}}, nil).Once()
+	binRepo.On("Purge", uint(3)).Return(nil).Once()
+	binRepo.On("Purge", uint(4)).Return(nil).Once()
+	auditService.On("LogActivity", models.SystemUserID, models.AuditPurgeDocument, mock.AnythingOfType("string")).Once()
+
+	service := NewRecycleBinService(binRepo, configService, auditService).(*recycleBinService)
+	service.now = func() time.Time { return now }
+	purged, err := service.PurgeExpired(models.SystemUserID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, purged)
+	binRepo.AssertExpectations(t)
+	auditService.AssertExpectations(t)
+}
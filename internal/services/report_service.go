@@ -0,0 +1,203 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"simdokpol/internal/dto"
+	"simdokpol/internal/models"
+	"simdokpol/internal/repositories"
+	"time"
+)
+
+const (
+	// MaxHandoverPeriod membatasi rentang laporan serah terima agar tetap sebatas beberapa giliran jaga.
+	MaxHandoverPeriod = 7 * 24 * time.Hour
+	// staleApprovalAge adalah lama pengajuan menunggu persetujuan sebelum dilaporkan sebagai anomali.
+	staleApprovalAge = 24 * time.Hour
+)
+
+// handoverEditActions dan handoverDeleteActions adalah aksi log audit yang dicantumkan
+// pada bagian dokumen diubah dan dokumen dihapus.
+var (
+	handoverEditActions   = []string{models.AuditUpdateDocument, models.AuditRestoreRevision}
+	handoverDeleteActions = []string{models.AuditDeleteDocument}
+)
+
+type ReportService interface {
+	// GenerateHandoverReport menyusun laporan serah terima jaga regu untuk rentang from sampai to.
+	// Anggota regu boleh membuat laporan regunya sendiri; regu lain memerlukan izin document.view_all.
+	GenerateHandoverReport(regu string, from time.Time, to time.Time, actorID uint) (*dto.HandoverReport, error)
+	// GenerateStatisticsReport menyusun rekap statistik untuk periode dto.PeriodMonthly (number = bulan 1-12),
+	// dto.PeriodQuarterly (number = triwulan 1-4), atau dto.PeriodYearly (number diabaikan).
+	// locationKeywords kosong berarti kata kunci lokasi diambil dari kata yang paling sering muncul.
+	GenerateStatisticsReport(period string, year int, number int, locationKeywords []string) (*dto.StatisticsReport, error)
+}
+
+type reportService struct {
+	reportRepo    repositories.ReportRepository
+	userRepo      repositories.UserRepository
+	configService ConfigService
+}
+
+func NewReportService(reportRepo repositories.ReportRepository, userRepo repositories.UserRepository, configService ConfigService) ReportService {
+	return &reportService{reportRepo: reportRepo, userRepo: userRepo, configService: configService}
+}
+
+func (s *reportService) GenerateHandoverReport(regu string, from time.Time, to time.Time, actorID uint) (*dto.HandoverReport, error) {
+	if regu == "" {
+		return nil, fmt.Errorf("%w: regu belum dipilih", ErrMissingRequiredField)
+	}
+	if !to.After(from) || to.Sub(from) > MaxHandoverPeriod {
+		return nil, ErrInvalidReportPeriod
+	}
+	actor, err := s.userRepo.FindByID(actorID)
+	if err != nil {
+		return nil, errors.New("pengguna tidak valid")
+	}
+	if actor.Regu != regu && !actor.HasPermission(models.PermDocumentViewAll) {
+		return nil, fmt.Errorf("%w: Anda hanya dapat membuat laporan serah terima regu Anda sendiri", ErrAccessDenied)
+	}
+
+	report := &dto.HandoverReport{
+		Regu:        regu,
+		From:        from,
+		To:          to,
+		GeneratedAt: s.now(),
+		GeneratedBy: actor.NamaLengkap,
+		Issued:      []dto.HandoverDocument{},
+		Edited:      []dto.HandoverActivity{},
+		Deleted:     []dto.HandoverActivity{},
+		Pending:     []dto.HandoverDocument{},
+		Anomalies:   []dto.HandoverAnomaly{},
+	}
+
+	issued, err := s.reportRepo.FindIssuedByRegu(regu, from, to)
+	if err != nil {
+		return nil, err
+	}
+	for _, doc := range issued {
+		report.Issued = append(report.Issued, handoverDocument(doc, *doc.TanggalPersetujuan))
+	}
+
+	editLogs, err := s.reportRepo.FindAuditLogsByRegu(regu, from, to, handoverEditActions)
+	if err != nil {
+		return nil, err
+	}
+	report.Edited = handoverActivities(editLogs)
+
+	deleteLogs, err := s.reportRepo.FindAuditLogsByRegu(regu, from, to, handoverDeleteActions)
+	if err != nil {
+		return nil, err
+	}
+	report.Deleted = handoverActivities(deleteLogs)
+
+	pending, err := s.reportRepo.FindPendingByRegu(regu)
+	if err != nil {
+		return nil, err
+	}
+	for _, doc := range pending {
+		report.Pending = append(report.Pending, handoverDocument(doc, doc.UpdatedAt))
+		if doc.Status == models.StatusMenungguPersetujuan && report.GeneratedAt.Sub(doc.UpdatedAt) > staleApprovalAge {
+			report.Anomalies = append(report.Anomalies, dto.HandoverAnomaly{
+				Jenis:      dto.AnomalyStaleApproval,
+				Keterangan: fmt.Sprintf("Dokumen atas nama %s menunggu persetujuan %s lebih dari 24 jam", doc.Resident.NamaLengkap, doc.PejabatPersetuju.NamaLengkap),
+				DocumentID: doc.ID,
+				Waktu:      doc.UpdatedAt,
+			})
+		}
+	}
+
+	if err := s.collectAnomalies(report); err != nil {
+		return nil, err
+	}
+
+	report.Summary = dto.HandoverSummary{
+		Issued:    len(report.Issued),
+		Edited:    len(report.Edited),
+		Deleted:   len(report.Deleted),
+		Pending:   len(report.Pending),
+		Anomalies: len(report.Anomalies),
+	}
+	return report, nil
+}
+
+// collectAnomalies menambahkan kejadian dalam rentang laporan yang perlu diperiksa regu berikutnya:
+// dokumen bernomor yang dihapus, dokumen yang dicabut, dan perubahan isi setelah dokumen terbit.
+func (s *reportService) collectAnomalies(report *dto.HandoverReport) error {
+	deleted, err := s.reportRepo.FindNumberedDeletedByRegu(report.Regu, report.From, report.To)
+	if err != nil {
+		return err
+	}
+	for _, doc := range deleted {
+		report.Anomalies = append(report.Anomalies, dto.HandoverAnomaly{
+			Jenis:      dto.AnomalyNumberedDeleted,
+			Keterangan: fmt.Sprintf("Dokumen bernomor atas nama %s dihapus (%s)", doc.Resident.NamaLengkap, doc.NomorSurat),
+			DocumentID: doc.ID,
+			Waktu:      doc.DeletedAt.Time,
+		})
+	}
+
+	revoked, err := s.reportRepo.FindRevokedByRegu(report.Regu, report.From, report.To)
+	if err != nil {
+		return err
+	}
+	for _, doc := range revoked {
+		report.Anomalies = append(report.Anomalies, dto.HandoverAnomaly{
+			Jenis:      dto.AnomalyRevoked,
+			Keterangan: fmt.Sprintf("Surat %s dicabut oleh %s: %s", doc.NomorSurat, doc.DicabutOleh.NamaLengkap, doc.AlasanPencabutan),
+			DocumentID: doc.ID,
+			Waktu:      *doc.TanggalPencabutan,
+		})
+	}
+
+	edited, err := s.reportRepo.FindEditedAfterIssue(report.Regu, report.From, report.To)
+	if err != nil {
+		return err
+	}
+	for _, rev := range edited {
+		report.Anomalies = append(report.Anomalies, dto.HandoverAnomaly{
+			Jenis:      dto.AnomalyEditedAfterIssued,
+			Keterangan: fmt.Sprintf("Surat %s diubah oleh %s setelah terbit (revisi #%d)", rev.NomorSurat, rev.ChangedBy, rev.RevisionNumber),
+			DocumentID: rev.LostDocumentID,
+			Waktu:      rev.CreatedAt,
+		})
+	}
+	return nil
+}
+
+func handoverDocument(doc models.LostDocument, waktu time.Time) dto.HandoverDocument {
+	return dto.HandoverDocument{
+		ID:           doc.ID,
+		NomorSurat:   doc.NomorSurat,
+		JenisDokumen: doc.JenisDokumen,
+		NamaPemohon:  doc.Resident.NamaLengkap,
+		Status:       doc.Status,
+		Operator:     doc.Operator.NamaLengkap,
+		Waktu:        waktu,
+	}
+}
+
+func handoverActivities(logs []models.AuditLog) []dto.HandoverActivity {
+	activities := make([]dto.HandoverActivity, 0, len(logs))
+	for _, entry := range logs {
+		pengguna := ""
+		if entry.User != nil {
+			pengguna = entry.User.NamaLengkap
+		}
+		activities = append(activities, dto.HandoverActivity{
+			Waktu:    entry.Timestamp,
+			Pengguna: pengguna,
+			Aksi:     entry.Aksi,
+			Detail:   entry.Detail,
+		})
+	}
+	return activities
+}
+
+func (s *reportService) now() time.Time {
+	loc, err := s.configService.GetLocation()
+	if err != nil {
+		loc = time.UTC
+	}
+	return time.Now().In(loc)
+}
@@ -0,0 +1,176 @@
+package services
+
+import (
+	"simdokpol/internal/dto"
+	"simdokpol/internal/mocks"
+	"simdokpol/internal/models"
+	"simdokpol/internal/repositories"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReportService_GenerateHandoverReport(t *testing.T) {
+	to := time.Now().UTC()
+	from := to.Add(-24 * time.Hour)
+	approvedAt := to.Add(-2 * time.Hour)
+	revokedAt := to.Add(-1 * time.Hour)
+	operator := &models.User{ID: 2, NamaLengkap: "Operator Regu I", Regu: "I", Peran: models.RoleOperator}
+
+	reportRepo := new(mocks.ReportRepository)
+	userRepo := new(mocks.UserRepository)
+	configService := new(mocks.ConfigService)
+	configService.On("GetLocation").Return(time.UTC, nil)
+	userRepo.On("FindByID", uint(2)).Return(operator, nil).Once()
+
+	reportRepo.On("FindIssuedByRegu", "I", from, to).Return([]models.LostDocument{
+		{ID: 10, NomorSurat: "SKH/1/X/2026", Status: models.StatusDiterbitkan, TanggalPersetujuan: &approvedAt, Operator: *operator},
+	}, nil).Once()
+	reportRepo.On("FindAuditLogsByRegu", "I", from, to, handoverEditActions).Return([]models.AuditLog{
+		{Aksi: models.AuditUpdateDocument, Detail: "Memperbarui dokumen SKH/1/X/2026", Timestamp: to.Add(-time.Hour), User: operator},
+	}, nil).Once()
+	reportRepo.On("FindAuditLogsByRegu", "I", from, to, handoverDeleteActions).Return([]models.AuditLog{}, nil).Once()
+	reportRepo.On("FindPendingByRegu", "I").Return([]models.LostDocument{
+		{ID: 11, Status: models.StatusDraf, UpdatedAt: to.Add(-3 * time.Hour)},
+		{ID: 12, Status: models.StatusMenungguPersetujuan, UpdatedAt: to.Add(-30 * time.Hour)},
+	}, nil).Once()
+	reportRepo.On("FindNumberedDeletedByRegu", "I", from, to).Return([]models.LostDocument{}, nil).Once()
+	reportRepo.On("FindRevokedByRegu", "I", from, to).Return([]models.LostDocument{
+		{ID: 9, NomorSurat: "SKH/0/X/2026", Status: models.StatusDicabut, TanggalPencabutan: &revokedAt, AlasanPencabutan: "Data keliru"},
+	}, nil).Once()
+	reportRepo.On("FindEditedAfterIssue", "I", from, to).Return([]repositories.EditedAfterIssue{
+		{LostDocumentID: 10, NomorSurat: "SKH/1/X/2026", RevisionNumber: 3, ChangedBy: "Operator Regu I", CreatedAt: to.Add(-time.Hour)},
+	}, nil).Once()
+
+	service := NewReportService(reportRepo, userRepo, configService)
+	report, err := service.GenerateHandoverReport("I", from, to, 2)
+
+	assert.NoError(t, err)
+	assert.Equal(t, dto.HandoverSummary{Issued: 1, Edited: 1, Deleted: 0, Pending: 2, Anomalies: 3}, report.Summary)
+	assert.Equal(t, approvedAt, report.Issued[0].Waktu)
+	assert.Equal(t, "Operator Regu I", report.Edited[0].Pengguna)
+	assert.Equal(t, "Operator Regu I", report.GeneratedBy)
+
+	var jenis []string
+	for _, anomaly := range report.Anomalies {
+		jenis = append(jenis, anomaly.Jenis)
+	}
+	assert.Equal(t, []string{dto.AnomalyStaleApproval, dto.AnomalyRevoked, dto.AnomalyEditedAfterIssued}, jenis)
+	reportRepo.AssertExpectations(t)
+}
+
+func TestReportService_GenerateHandoverReport_Rejected(t *testing.T) {
+	to := time.Now()
+	operator := &models.User{ID: 2, Regu: "I", Peran: models.RoleOperator}
+
+	testCases := []struct {
+		name        string
+		regu        string
+		from        time.Time
+		actor       *models.User
+		expectError error
+	}{
+		{name: "Regu kosong", regu: "", from: to.Add(-time.Hour), actor: operator, expectError: ErrMissingRequiredField},
+		{name: "Periode terbalik", regu: "I", from: to.Add(time.Hour), actor: operator, expectError: ErrInvalidReportPeriod},
+		{name: "Periode terlalu panjang", regu: "I", from: to.Add(-MaxHandoverPeriod - time.Hour), actor: operator, expectError: ErrInvalidReportPeriod},
+		{name: "Regu lain tanpa izin", regu: "II", from: to.Add(-time.Hour), actor: operator, expectError: ErrAccessDenied},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			userRepo := new(mocks.UserRepository)
+			userRepo.On("FindByID", tc.actor.ID).Return(tc.actor, nil).Maybe()
+
+			service := NewReportService(new(mocks.ReportRepository), userRepo, nil)
+			report, err := service.GenerateHandoverReport(tc.regu, tc.from, to, tc.actor.ID)
+
+			assert.ErrorIs(t, err, tc.expectError)
+			assert.Nil(t, report)
+		})
+	}
+}
+
+func TestReportService_GenerateStatisticsReport(t *testing.T) {
+	loc := time.FixedZone("WITA", 8*3600)
+	start := time.Date(2026, time.January, 1, 0, 0, 0, 0, loc)
+	end := time.Date(2026, time.April, 1, 0, 0, 0, 0, loc)
+	prevStart, prevEnd := start.AddDate(-1, 0, 0), end.AddDate(-1, 0, 0)
+
+	reportRepo := new(mocks.ReportRepository)
+	configService := new(mocks.ConfigService)
+	configService.On("GetLocation").Return(loc, nil)
+
+	// 4 Januari 2026 23:30 WITA adalah hari Minggu, walaupun di UTC masih hari yang sama.
+	reportRepo.On("FindIssuedSummaries", start, end).Return([]repositories.IssuedSummary{
+		{TanggalLaporan: time.Date(2026, time.January, 4, 23, 30, 0, 0, loc), LokasiHilang: "Pasar Sentral Makassar"},
+		{TanggalLaporan: time.Date(2026, time.January, 5, 9, 0, 0, 0, loc), LokasiHilang: "Terminal Daya"},
+		{TanggalLaporan: time.Date(2026, time.March, 2, 10, 0, 0, 0, loc), LokasiHilang: "Sekitar pasar Terong"},
+	}, nil).Once()
+	reportRepo.On("FindIssuedSummaries", prevStart, prevEnd).Return([]repositories.IssuedSummary{
+		{TanggalLaporan: time.Date(2025, time.January, 6, 8, 0, 0, 0, loc), LokasiHilang: "Pasar Terong"},
+		{TanggalLaporan: time.Date(2025, time.February, 3, 8, 0, 0, 0, loc), LokasiHilang: "Pelabuhan"},
+	}, nil).Once()
+	reportRepo.On("CountIssuedByItem", start, end).Return([]repositories.GroupCount{{Label: "KTP", Count: 2}, {Label: "SIM", Count: 1}}, nil).Once()
+	reportRepo.On("CountIssuedByItem", prevStart, prevEnd).Return([]repositories.GroupCount{{Label: "KTP", Count: 2}}, nil).Once()
+	reportRepo.On("CountIssuedByOperator", start, end).Return([]repositories.GroupCount{{Label: "Operator Regu I", Count: 3}}, nil).Once()
+	reportRepo.On("CountIssuedByPetugasPelapor", start, end).Return([]repositories.GroupCount{{Label: "Bripka Andi", Count: 3}}, nil).Once()
+
+	service := NewReportService(reportRepo, new(mocks.UserRepository), configService)
+	report, err := service.GenerateStatisticsReport(dto.PeriodQuarterly, 2026, 1, []string{"pasar", " terminal "})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Triwulan I 2026", report.Label)
+	assert.Equal(t, 3, report.Total)
+	assert.Equal(t, 2, report.PreviousTotal)
+	assert.Equal(t, 50.0, *report.ChangePercent)
+
+	assert.Len(t, report.ByMonth, 3)
+	assert.Equal(t, dto.StatComparison{Label: "Januari", Current: 2, Previous: 1, ChangePercent: report.ByMonth[0].ChangePercent}, report.ByMonth[0])
+	assert.Equal(t, 100.0, *report.ByMonth[0].ChangePercent)
+	assert.Nil(t, report.ByMonth[2].ChangePercent, "Perubahan tidak dihitung bila tahun lalu nol")
+
+	assert.Equal(t, []dto.StatCount{{Label: "PASAR", Count: 2}, {Label: "TERMINAL", Count: 1}}, report.ByLocation)
+	assert.Equal(t, "Senin", report.ByWeekday[0].Label)
+	assert.Equal(t, 2, report.ByWeekday[0].Count)
+	assert.Equal(t, "Minggu", report.ByWeekday[6].Label)
+	assert.Equal(t, 1, report.ByWeekday[6].Count)
+	reportRepo.AssertExpectations(t)
+}
+
+func TestStatisticsPeriod_Invalid(t *testing.T) {
+	testCases := []struct {
+		name   string
+		period string
+		year   int
+		number int
+	}{
+		{name: "Bulan di luar rentang", period: dto.PeriodMonthly, year: 2026, number: 13},
+		{name: "Triwulan di luar rentang", period: dto.PeriodQuarterly, year: 2026, number: 5},
+		{name: "Tahun tidak wajar", period: dto.PeriodYearly, year: 99, number: 0},
+		{name: "Periode tidak dikenal", period: "weekly", year: 2026, number: 1},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, _, _, err := statisticsPeriod(tc.period, tc.year, tc.number, time.UTC)
+			assert.ErrorIs(t, err, ErrInvalidReportPeriod)
+		})
+	}
+}
+
+func TestLocationCounts_DerivedKeywords(t *testing.T) {
+	docs := []repositories.IssuedSummary{
+		{LokasiHilang: "Jl. Pasar Sentral"},
+		{LokasiHilang: "Sekitar Pasar Terong, Kecamatan Wajo"},
+		{LokasiHilang: "tidak diketahui"},
+	}
+
+	counts := locationCounts(docs, nil)
+
+	assert.Equal(t, dto.StatCount{Label: "PASAR", Count: 2}, counts[0])
+	for _, count := range counts {
+		assert.NotEqual(t, "KECAMATAN", count.Label)
+		assert.NotEqual(t, "TIDAK", count.Label)
+	}
+}
@@ -0,0 +1,123 @@
+package services
+
+import (
+	"fmt"
+	"simdokpol/internal/models"
+	"simdokpol/internal/repositories"
+
+	"gorm.io/gorm"
+)
+
+// Batas jumlah hasil pencarian penduduk untuk autocomplete formulir.
+const (
+	defaultResidentSearchLimit = 10
+	maxResidentSearchLimit     = 50
+)
+
+type ResidentService interface {
+	Search(query string, limit int) ([]models.Resident, error)
+	FindByID(id uint) (*models.Resident, error)
+	FindDuplicateGroups() ([]repositories.DuplicateResidentGroup, error)
+	Merge(primaryID uint, duplicateIDs []uint, actorID uint) (*models.Resident, error)
+}
+
+type residentService struct {
+	db           *gorm.DB
+	residentRepo repositories.ResidentRepository
+	auditService AuditLogService
+}
+
+func NewResidentService(db *gorm.DB, residentRepo repositories.ResidentRepository, auditService AuditLogService) ResidentService {
+	return &residentService{
+		db:           db,
+		residentRepo: residentRepo,
+		auditService: auditService,
+	}
+}
+
+func (s *residentService) Search(query string, limit int) ([]models.Resident, error) {
+	if limit <= 0 {
+		limit = defaultResidentSearchLimit
+	}
+	if limit > maxResidentSearchLimit {
+		limit = maxResidentSearchLimit
+	}
+	return s.residentRepo.Search(query, limit)
+}
+
+func (s *residentService) FindByID(id uint) (*models.Resident, error) {
+	resident, err := s.residentRepo.FindByID(id)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return resident, nil
+}
+
+func (s *residentService) FindDuplicateGroups() ([]repositories.DuplicateResidentGroup, error) {
+	return s.residentRepo.FindDuplicateGroups()
+}
+
+// Merge menggabungkan data penduduk duplikat ke primaryID. Semua dokumen milik duplikat
+// dipindahkan ke data utama, lalu duplikat dihapus. Jika data utama belum memiliki NIK,
+// NIK dari duplikat dipakai; dua NIK yang berbeda berarti orang yang berbeda sehingga ditolak.
+func (s *residentService) Merge(primaryID uint, duplicateIDs []uint, actorID uint) (*models.Resident, error) {
+	ids := []uint{primaryID}
+	seen := map[uint]bool{primaryID: true}
+	for _, id := range duplicateIDs {
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) < 2 {
+		return nil, ErrInvalidResidentMerge
+	}
+	duplicateIDs = ids[1:]
+
+	residents, err := s.residentRepo.FindByIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+	if len(residents) != len(ids) {
+		return nil, ErrNotFound
+	}
+
+	var primary *models.Resident
+	var nik *string
+	for i := range residents {
+		if residents[i].ID == primaryID {
+			primary = &residents[i]
+		}
+		if residents[i].NIK == nil {
+			continue
+		}
+		if nik != nil && *nik != *residents[i].NIK {
+			return nil, ErrResidentMergeConflict
+		}
+		nik = residents[i].NIK
+	}
+
+	var movedDocs int64
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		movedDocs, err = s.residentRepo.Merge(tx, primaryID, duplicateIDs)
+		if err != nil {
+			return err
+		}
+		if primary.NIK == nil && nik != nil {
+			primary.NIK = nik
+			if _, err := s.residentRepo.Update(tx, primary); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.auditService.LogActivity(actorID, models.AuditMergeResidents, fmt.Sprintf("Menggabungkan %d data penduduk ke %s (ID %d), %d dokumen dipindahkan", len(duplicateIDs), primary.NamaLengkap, primary.ID, movedDocs))
+	return primary, nil
+}
@@ -0,0 +1,178 @@
+package services
+
+import (
+	"fmt"
+	"regexp"
+	"simdokpol/internal/models"
+	"simdokpol/internal/repositories"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+var roleCodePattern = regexp.MustCompile(`^[A-Z][A-Z0-9_]{1,49}$`)
+
+// PermissionInfo menjelaskan satu izin untuk ditampilkan di halaman pengelolaan peran.
+type PermissionInfo struct {
+	Kode      string `json:"kode"`
+	Grup      string `json:"grup"`
+	Deskripsi string `json:"deskripsi"`
+}
+
+// AvailablePermissions adalah katalog seluruh izin yang dikenal aplikasi, dalam urutan tampilan.
+var AvailablePermissions = []PermissionInfo{
+	{models.PermDocumentCreate, "Dokumen", "Membuat surat dan mengelola surat milik sendiri"},
+	{models.PermDocumentViewAll, "Dokumen", "Melihat dan mencetak surat milik operator lain"},
+	{models.PermDocumentEditAll, "Dokumen", "Mengubah, mengajukan, dan menghapus surat milik operator lain"},
+	{models.PermDocumentApprove, "Dokumen", "Dapat ditunjuk sebagai pejabat persetuju untuk menyetujui atau menolak surat"},
+	{models.PermDocumentRevoke, "Dokumen", "Mencabut surat yang sudah terbit"},
+	{models.PermDocumentRestore, "Dokumen", "Memulihkan surat ke revisi sebelumnya"},
+	{models.PermDocumentTypeManage, "Dokumen", "Mengelola registri jenis dokumen"},
+	{models.PermDocumentImport, "Dokumen", "Mengimpor surat lama dari file Excel atau CSV"},
+	{models.PermItemTypeManage, "Dokumen", "Mengelola katalog jenis barang hilang beserta atributnya"},
+	{models.PermDocumentCrossCheck, "Dokumen", "Melihat laporan lain yang memuat nomor identitas barang yang sama"},
+	{models.PermRecycleBinManage, "Dokumen", "Memulihkan dokumen dari tong sampah atau memusnahkannya secara permanen"},
+	{models.PermResidentMerge, "Data Penduduk", "Menggabungkan data penduduk ganda"},
+	{models.PermUserManage, "Administrasi", "Mengelola pengguna, peran, dan izin"},
+	{models.PermAuditView, "Administrasi", "Melihat log audit dan laporan celah penomoran"},
+	{models.PermSettingsEdit, "Administrasi", "Mengubah pengaturan sistem"},
+	{models.PermArchiveRun, "Administrasi", "Menjalankan pengarsipan dokumen"},
+	{models.PermReportView, "Laporan", "Melihat dan mengunduh laporan statistik periodik"},
+	{models.PermReportHandover, "Laporan", "Menyusun dan mengunduh laporan serah terima jaga regu sendiri"},
+	{models.PermBackupRun, "Basis Data", "Membuat cadangan basis data"},
+	{models.PermBackupRestore, "Basis Data", "Memulihkan basis data, menjelajahi file cadangan, dan memulihkan dokumen terpilih darinya"},
+}
+
+type RoleService interface {
+	FindAll() ([]models.Role, error)
+	FindByCode(kode string) (*models.Role, error)
+	Create(role *models.Role, actorID uint) (*models.Role, error)
+	Update(kode string, role *models.Role, actorID uint) (*models.Role, error)
+	Delete(kode string, actorID uint) error
+}
+
+type roleService struct {
+	roleRepo     repositories.RoleRepository
+	auditService AuditLogService
+}
+
+func NewRoleService(roleRepo repositories.RoleRepository, auditService AuditLogService) RoleService {
+	return &roleService{
+		roleRepo:     roleRepo,
+		auditService: auditService,
+	}
+}
+
+func (s *roleService) FindAll() ([]models.Role, error) {
+	return s.roleRepo.FindAll()
+}
+
+func (s *roleService) FindByCode(kode string) (*models.Role, error) {
+	role, err := s.roleRepo.FindByCode(kode)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return role, nil
+}
+
+func (s *roleService) Create(role *models.Role, actorID uint) (*models.Role, error) {
+	role.Kode = strings.ToUpper(strings.TrimSpace(role.Kode))
+	if _, err := s.roleRepo.FindByCode(role.Kode); err == nil {
+		return nil, fmt.Errorf("%w: kode %s sudah dipakai", ErrInvalidRole, role.Kode)
+	} else if err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+	role.Bawaan = false
+	if err := validateRole(role); err != nil {
+		return nil, err
+	}
+
+	created, err := s.roleRepo.Create(role)
+	if err != nil {
+		return nil, err
+	}
+	s.auditService.LogActivity(actorID, models.AuditCreateRole, fmt.Sprintf("Menambahkan peran %s (%s) dengan izin: %s", created.Nama, created.Kode, strings.Join(created.Permissions, ", ")))
+	return created, nil
+}
+
+// Update menimpa nama, deskripsi, dan izin sebuah peran. Kode tidak dapat diubah karena
+// dipakai oleh data pengguna, dan peran Super Admin tidak dapat diubah sama sekali.
+func (s *roleService) Update(kode string, role *models.Role, actorID uint) (*models.Role, error) {
+	existing, err := s.FindByCode(kode)
+	if err != nil {
+		return nil, err
+	}
+	if existing.Kode == models.RoleSuperAdmin {
+		return nil, fmt.Errorf("%w: peran Super Admin selalu memiliki semua izin dan tidak dapat diubah", ErrInvalidRole)
+	}
+	role.Kode = existing.Kode
+	role.Bawaan = existing.Bawaan
+	role.CreatedAt = existing.CreatedAt
+	if err := validateRole(role); err != nil {
+		return nil, err
+	}
+
+	updated, err := s.roleRepo.Update(role)
+	if err != nil {
+		return nil, err
+	}
+	s.auditService.LogActivity(actorID, models.AuditUpdateRole, fmt.Sprintf("Memperbarui peran %s (%s) dengan izin: %s", updated.Nama, updated.Kode, strings.Join(updated.Permissions, ", ")))
+	return updated, nil
+}
+
+func (s *roleService) Delete(kode string, actorID uint) error {
+	existing, err := s.FindByCode(kode)
+	if err != nil {
+		return err
+	}
+	if existing.Bawaan {
+		return fmt.Errorf("%w: peran bawaan tidak dapat dihapus", ErrInvalidRole)
+	}
+	count, err := s.roleRepo.CountUsers(existing.Kode)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return fmt.Errorf("%w: %d pengguna masih memakai peran %s", ErrRoleInUse, count, existing.Nama)
+	}
+
+	if err := s.roleRepo.Delete(existing.Kode); err != nil {
+		return err
+	}
+	s.auditService.LogActivity(actorID, models.AuditDeleteRole, fmt.Sprintf("Menghapus peran %s (%s)", existing.Nama, existing.Kode))
+	return nil
+}
+
+// validateRole merapikan definisi peran sebelum disimpan. Izin diurutkan mengikuti katalog
+// AvailablePermissions sehingga izin ganda dan urutan masukan tidak berpengaruh.
+func validateRole(role *models.Role) error {
+	role.Nama = strings.TrimSpace(role.Nama)
+	role.Deskripsi = strings.TrimSpace(role.Deskripsi)
+
+	if !roleCodePattern.MatchString(role.Kode) {
+		return fmt.Errorf("%w: kode hanya boleh berisi huruf kapital, angka, dan garis bawah", ErrInvalidRole)
+	}
+	if role.Nama == "" {
+		return fmt.Errorf("%w: nama peran wajib diisi", ErrInvalidRole)
+	}
+
+	requested := make(map[string]bool)
+	for _, permission := range role.Permissions {
+		requested[permission] = true
+	}
+	permissions := []string{}
+	for _, info := range AvailablePermissions {
+		if requested[info.Kode] {
+			permissions = append(permissions, info.Kode)
+			delete(requested, info.Kode)
+		}
+	}
+	for unknown := range requested {
+		return fmt.Errorf("%w: izin %q tidak dikenal", ErrInvalidRole, unknown)
+	}
+	role.Permissions = permissions
+	return nil
+}
@@ -0,0 +1,58 @@
+package services
+
+import (
+	"errors"
+	"simdokpol/internal/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateRole(t *testing.T) {
+	testCases := []struct {
+		name     string
+		role     models.Role
+		expected []string
+		valid    bool
+	}{
+		{
+			name:     "Sukses - Izin ganda dibuang dan diurutkan sesuai katalog",
+			role:     models.Role{Kode: "PIKET", Nama: " Piket ", Permissions: []string{models.PermAuditView, models.PermDocumentCreate, models.PermAuditView}},
+			expected: []string{models.PermDocumentCreate, models.PermAuditView},
+			valid:    true,
+		},
+		{
+			name:     "Sukses - Peran tanpa izin",
+			role:     models.Role{Kode: "TAMU", Nama: "Tamu"},
+			expected: []string{},
+			valid:    true,
+		},
+		{name: "Gagal - Izin tidak dikenal", role: models.Role{Kode: "PIKET", Nama: "Piket", Permissions: []string{"database.drop"}}},
+		{name: "Gagal - Kode huruf kecil", role: models.Role{Kode: "piket", Nama: "Piket"}},
+		{name: "Gagal - Nama kosong", role: models.Role{Kode: "PIKET", Nama: "  "}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			role := tc.role
+			err := validateRole(&role)
+			if tc.valid {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expected, role.Permissions)
+			} else {
+				assert.True(t, errors.Is(err, ErrInvalidRole))
+			}
+		})
+	}
+}
+
+func TestUserHasPermission(t *testing.T) {
+	kanit := &models.User{Peran: models.RoleKanitSPKT, Role: &models.Role{Kode: models.RoleKanitSPKT, Permissions: []string{models.PermDocumentViewAll, models.PermDocumentApprove}}}
+	superAdmin := &models.User{Peran: models.RoleSuperAdmin}
+	unloaded := &models.User{Peran: models.RoleOperator}
+
+	assert.True(t, kanit.HasPermission(models.PermDocumentViewAll))
+	assert.False(t, kanit.HasPermission(models.PermBackupRestore), "Kanit tidak boleh memulihkan basis data")
+	assert.True(t, superAdmin.HasPermission(models.PermBackupRestore), "Super Admin selalu memiliki semua izin")
+	assert.False(t, unloaded.HasPermission(models.PermDocumentCreate), "peran yang tidak dimuat tidak memberi izin")
+}
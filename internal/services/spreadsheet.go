@@ -0,0 +1,327 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// SpreadsheetSheet adalah satu lembar kerja pada file ekspor. Baris pertama ditulis tebal sebagai judul kolom.
+// Sel bertipe int, int64, float64, dan *float64 ditulis sebagai angka; nilai lain sebagai teks.
+type SpreadsheetSheet struct {
+	Name string
+	Rows [][]interface{}
+}
+
+// WriteXLSX menulis lembar kerja sebagai file Office Open XML (.xlsx) minimal tanpa pustaka luar,
+// sehingga bisa dibuka di Excel maupun LibreOffice.
+func WriteXLSX(w io.Writer, sheets []SpreadsheetSheet) error {
+	zw := zip.NewWriter(w)
+	names := make([]string, len(sheets))
+	for i, sheet := range sheets {
+		names[i] = sheet.Name
+		if err := writeZipFile(zw, fmt.Sprintf("xl/worksheets/sheet%d.xml", i+1), sheetXML(sheet.Rows)); err != nil {
+			return err
+		}
+	}
+	if err := writeXLSXPackage(zw, names); err != nil {
+		return err
+	}
+	return zw.Close()
+}
+
+// WriteCSV menulis lembar kerja sebagai satu file CSV UTF-8 (dengan BOM agar Excel mengenali encoding-nya).
+// Jika ada lebih dari satu lembar kerja, setiap lembar diawali baris nama lembar dan dipisahkan baris kosong.
+func WriteCSV(w io.Writer, sheets []SpreadsheetSheet) error {
+	if _, err := w.Write([]byte("\xEF\xBB\xBF")); err != nil {
+		return err
+	}
+	cw := csv.NewWriter(w)
+	for i, sheet := range sheets {
+		if len(sheets) > 1 {
+			if i > 0 {
+				if err := cw.Write([]string{}); err != nil {
+					return err
+				}
+			}
+			if err := cw.Write([]string{sheet.Name}); err != nil {
+				return err
+			}
+		}
+		for _, row := range sheet.Rows {
+			record := make([]string, len(row))
+			for j, cell := range row {
+				record[j] = cellText(cell)
+			}
+			if err := cw.Write(record); err != nil {
+				return err
+			}
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// SpreadsheetRowWriter menulis satu lembar kerja baris demi baris sehingga data besar bisa dialirkan
+// langsung ke response tanpa ditampung di memori. Tidak ada yang ditulis ke writer tujuan sebelum
+// baris pertama, sehingga pemanggil masih bisa mengirim respons error jika gagal sebelum itu.
+type SpreadsheetRowWriter interface {
+	WriteRow(row []interface{}) error
+	// Close menyelesaikan file. Wajib dipanggil setelah baris terakhir.
+	Close() error
+}
+
+type xlsxRowWriter struct {
+	w         io.Writer
+	sheetName string
+	zw        *zip.Writer
+	sheet     io.Writer
+	rows      int
+}
+
+// NewXLSXRowWriter membuat SpreadsheetRowWriter untuk file .xlsx berisi satu lembar kerja.
+// Baris pertama ditulis tebal sebagai judul kolom, sama seperti WriteXLSX.
+func NewXLSXRowWriter(w io.Writer, sheetName string) SpreadsheetRowWriter {
+	return &xlsxRowWriter{w: w, sheetName: sheetName}
+}
+
+func (x *xlsxRowWriter) start() error {
+	x.zw = zip.NewWriter(x.w)
+	sheet, err := x.zw.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return err
+	}
+	x.sheet = sheet
+	_, err = io.WriteString(x.sheet, sheetXMLHeader)
+	return err
+}
+
+func (x *xlsxRowWriter) WriteRow(row []interface{}) error {
+	if x.zw == nil {
+		if err := x.start(); err != nil {
+			return err
+		}
+	}
+	x.rows++
+	_, err := io.WriteString(x.sheet, rowXML(x.rows, row))
+	return err
+}
+
+func (x *xlsxRowWriter) Close() error {
+	if x.zw == nil {
+		if err := x.start(); err != nil {
+			return err
+		}
+	}
+	if _, err := io.WriteString(x.sheet, sheetXMLFooter); err != nil {
+		return err
+	}
+	if err := writeXLSXPackage(x.zw, []string{x.sheetName}); err != nil {
+		return err
+	}
+	return x.zw.Close()
+}
+
+type csvRowWriter struct {
+	w       io.Writer
+	cw      *csv.Writer
+	written int
+}
+
+// NewCSVRowWriter membuat SpreadsheetRowWriter untuk file CSV UTF-8 dengan BOM, sama seperti WriteCSV.
+func NewCSVRowWriter(w io.Writer) SpreadsheetRowWriter {
+	return &csvRowWriter{w: w}
+}
+
+func (c *csvRowWriter) WriteRow(row []interface{}) error {
+	if c.cw == nil {
+		if _, err := c.w.Write([]byte("\xEF\xBB\xBF")); err != nil {
+			return err
+		}
+		c.cw = csv.NewWriter(c.w)
+	}
+	record := make([]string, len(row))
+	for i, cell := range row {
+		record[i] = cellText(cell)
+	}
+	if err := c.cw.Write(record); err != nil {
+		return err
+	}
+	// Flush berkala agar baris segera terkirim ke klien tanpa menunggu seluruh data.
+	c.written++
+	if c.written%500 == 0 {
+		c.cw.Flush()
+		return c.cw.Error()
+	}
+	return nil
+}
+
+func (c *csvRowWriter) Close() error {
+	if c.cw == nil {
+		_, err := c.w.Write([]byte("\xEF\xBB\xBF"))
+		return err
+	}
+	c.cw.Flush()
+	return c.cw.Error()
+}
+
+// writeXLSXPackage menulis bagian paket .xlsx selain isi lembar kerja. Lembar kerja ke-n
+// harus sudah atau akan ditulis sebagai xl/worksheets/sheet<n>.xml.
+func writeXLSXPackage(zw *zip.Writer, sheetNames []string) error {
+	var contentTypes, workbook, workbookRels strings.Builder
+	contentTypes.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?><Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types"><Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/><Default Extension="xml" ContentType="application/xml"/><Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/><Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>`)
+	workbook.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?><workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets>`)
+	workbookRels.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?><Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">`)
+
+	usedNames := map[string]bool{}
+	for i, sheetName := range sheetNames {
+		n := i + 1
+		name := uniqueSheetName(sheetName, n, usedNames)
+		fmt.Fprintf(&contentTypes, `<Override PartName="/xl/worksheets/sheet%d.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`, n)
+		fmt.Fprintf(&workbook, `<sheet name="%s" sheetId="%d" r:id="rId%d"/>`, xmlEscape(name), n, n)
+		fmt.Fprintf(&workbookRels, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet%d.xml"/>`, n, n)
+	}
+	fmt.Fprintf(&workbookRels, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>`, len(sheetNames)+1)
+	contentTypes.WriteString(`</Types>`)
+	workbook.WriteString(`</sheets></workbook>`)
+	workbookRels.WriteString(`</Relationships>`)
+
+	files := []struct{ name, content string }{
+		{"[Content_Types].xml", contentTypes.String()},
+		{"_rels/.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?><Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/></Relationships>`},
+		{"xl/workbook.xml", workbook.String()},
+		{"xl/_rels/workbook.xml.rels", workbookRels.String()},
+		// Gaya 0 untuk sel biasa, gaya 1 (huruf tebal) untuk judul kolom.
+		{"xl/styles.xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?><styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts><fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills><borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders><cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs><cellXfs count="2"><xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/><xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/></cellXfs></styleSheet>`},
+	}
+	for _, file := range files {
+		if err := writeZipFile(zw, file.name, file.content); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+const (
+	sheetXMLHeader = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?><worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`
+	sheetXMLFooter = `</sheetData></worksheet>`
+)
+
+func sheetXML(rows [][]interface{}) string {
+	var sb strings.Builder
+	sb.WriteString(sheetXMLHeader)
+	for r, row := range rows {
+		sb.WriteString(rowXML(r+1, row))
+	}
+	sb.WriteString(sheetXMLFooter)
+	return sb.String()
+}
+
+// rowXML menyusun satu baris lembar kerja. Baris nomor 1 memakai gaya judul kolom.
+func rowXML(rowNumber int, row []interface{}) string {
+	var sb strings.Builder
+	style := ""
+	if rowNumber == 1 {
+		style = ` s="1"`
+	}
+	fmt.Fprintf(&sb, `<row r="%d">`, rowNumber)
+	for c, cell := range row {
+		ref := columnName(c) + strconv.Itoa(rowNumber)
+		if number, ok := cellNumber(cell); ok {
+			fmt.Fprintf(&sb, `<c r="%s"%s><v>%s</v></c>`, ref, style, number)
+		} else if text := cellText(cell); text != "" {
+			fmt.Fprintf(&sb, `<c r="%s"%s t="inlineStr"><is><t xml:space="preserve">%s</t></is></c>`, ref, style, xmlEscape(text))
+		}
+	}
+	sb.WriteString(`</row>`)
+	return sb.String()
+}
+
+func cellNumber(cell interface{}) (string, bool) {
+	switch v := cell.(type) {
+	case int:
+		return strconv.Itoa(v), true
+	case int64:
+		return strconv.FormatInt(v, 10), true
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	case *float64:
+		if v != nil {
+			return strconv.FormatFloat(*v, 'f', -1, 64), true
+		}
+	}
+	return "", false
+}
+
+func cellText(cell interface{}) string {
+	if number, ok := cellNumber(cell); ok {
+		return number
+	}
+	switch v := cell.(type) {
+	case nil, *float64:
+		return ""
+	case string:
+		return v
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// columnName mengubah indeks kolom (mulai 0) menjadi nama kolom Excel: A, B, ..., Z, AA, AB, ...
+func columnName(index int) string {
+	name := ""
+	for index >= 0 {
+		name = string(rune('A'+index%26)) + name
+		index = index/26 - 1
+	}
+	return name
+}
+
+// uniqueSheetName menyesuaikan nama lembar dengan aturan Excel: maksimal 31 karakter, tanpa karakter
+// []:*?/\ dan tidak boleh sama dengan lembar lain.
+func uniqueSheetName(name string, n int, used map[string]bool) string {
+	name = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`[]:*?/\`, r) {
+			return '-'
+		}
+		return r
+	}, strings.TrimSpace(name))
+	if name == "" {
+		name = fmt.Sprintf("Sheet%d", n)
+	}
+	if runes := []rune(name); len(runes) > 31 {
+		name = string(runes[:31])
+	}
+	for candidate, i := name, 2; ; i++ {
+		if !used[strings.ToLower(candidate)] {
+			used[strings.ToLower(candidate)] = true
+			return candidate
+		}
+		suffix := fmt.Sprintf(" (%d)", i)
+		runes := []rune(name)
+		if len(runes)+len(suffix) > 31 {
+			runes = runes[:31-len(suffix)]
+		}
+		candidate = string(runes) + suffix
+	}
+}
+
+func xmlEscape(s string) string {
+	var buf bytes.Buffer
+	_ = xml.EscapeText(&buf, []byte(s))
+	return buf.String()
+}
+
+func writeZipFile(zw *zip.Writer, name string, content string) error {
+	f, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(f, content)
+	return err
+}
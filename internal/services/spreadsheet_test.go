@@ -0,0 +1,140 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteXLSX(t *testing.T) {
+	sheets := []SpreadsheetSheet{
+		{Name: "Ringkasan", Rows: [][]interface{}{{"Uraian", "Jumlah"}, {"Total <terbit>", 12}}},
+		{Name: "Ringkasan", Rows: [][]interface{}{{"Duplikat"}}},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteXLSX(&buf, sheets))
+
+	reader, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+	files := map[string]string{}
+	for _, f := range reader.File {
+		rc, err := f.Open()
+		require.NoError(t, err)
+		content, _ := io.ReadAll(rc)
+		rc.Close()
+		files[f.Name] = string(content)
+	}
+
+	assert.Contains(t, files, "[Content_Types].xml")
+	assert.Contains(t, files["xl/worksheets/sheet1.xml"], "Total &lt;terbit&gt;")
+	assert.Contains(t, files["xl/worksheets/sheet1.xml"], `<c r="B2"><v>12</v></c>`)
+	assert.Equal(t, 2, strings.Count(files["xl/workbook.xml"], "<sheet "), "Nama sheet ganda tetap menghasilkan dua sheet")
+}
+
+func TestWriteCSV(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteCSV(&buf, []SpreadsheetSheet{{Name: "Ringkasan", Rows: [][]interface{}{{"Uraian", "Jumlah"}, {"Total", 3}}}}))
+
+	assert.Equal(t, "\ufeffUraian,Jumlah\nTotal,3\n", buf.String())
+}
+
+func TestColumnName(t *testing.T) {
+	assert.Equal(t, "A", columnName(0))
+	assert.Equal(t, "Z", columnName(25))
+	assert.Equal(t, "AA", columnName(26))
+	assert.Equal(t, "AB", columnName(27))
+}
+
+func TestXLSXRowWriter(t *testing.T) {
+	var buf bytes.Buffer
+	writer := NewXLSXRowWriter(&buf, "Daftar Dokumen")
+	assert.Zero(t, buf.Len(), "Belum ada yang ditulis sebelum baris pertama")
+
+	require.NoError(t, writer.WriteRow([]interface{}{"Nomor Surat", "Jumlah"}))
+	require.NoError(t, writer.WriteRow([]interface{}{"SKH/1/X/2026", 2}))
+	require.NoError(t, writer.Close())
+
+	reader, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+	var sheet, workbook string
+	for _, f := range reader.File {
+		rc, err := f.Open()
+		require.NoError(t, err)
+		content, _ := io.ReadAll(rc)
+		rc.Close()
+		switch f.Name {
+		case "xl/worksheets/sheet1.xml":
+			sheet = string(content)
+		case "xl/workbook.xml":
+			workbook = string(content)
+		}
+	}
+	assert.Equal(t, sheetXML([][]interface{}{{"Nomor Surat", "Jumlah"}, {"SKH/1/X/2026", 2}}), sheet)
+	assert.Contains(t, workbook, `name="Daftar Dokumen"`)
+}
+
+func TestReadSpreadsheet_XLSXRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteXLSX(&buf, []SpreadsheetSheet{
+		{Name: "Data", Rows: [][]interface{}{{"Nomor Surat", "Nama", "Jumlah"}, {"SKH/1/X/2023", "", 3}}},
+		{Name: "Lain", Rows: [][]interface{}{{"Tidak dibaca"}}},
+	}))
+
+	rows, err := ReadSpreadsheet("arsip.XLSX", buf.Bytes())
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{{"Nomor Surat", "Nama", "Jumlah"}, {"SKH/1/X/2023", "", "3"}}, rows)
+}
+
+func TestReadSpreadsheet_XLSXSharedStrings(t *testing.T) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	parts := map[string]string{
+		"xl/workbook.xml":            `<workbook xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets><sheet name="Arsip" sheetId="1" r:id="rId7"/></sheets></workbook>`,
+		"xl/_rels/workbook.xml.rels": `<Relationships><Relationship Id="rId7" Target="worksheets/arsip.xml"/></Relationships>`,
+		"xl/sharedStrings.xml":       `<sst><si><t>Nama</t></si><si><r><t>Bu</t></r><r><t>di</t></r></si></sst>`,
+		"xl/worksheets/arsip.xml":    `<worksheet><sheetData><row r="1"><c r="A1" t="s"><v>0</v></c></row><row r="3"><c r="C3" t="s"><v>1</v></c></row></sheetData></worksheet>`,
+	}
+	for name, content := range parts {
+		w, err := zw.Create(name)
+		require.NoError(t, err)
+		_, err = w.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, zw.Close())
+
+	rows, err := ReadSpreadsheet("arsip.xlsx", buf.Bytes())
+	require.NoError(t, err)
+	require.NotEmpty(t, rows)
+	assert.Equal(t, []string{"Nama"}, rows[0])
+	assert.Equal(t, []string{"", "", "Budi"}, rows[len(rows)-1])
+}
+
+func TestReadSpreadsheet_CSV(t *testing.T) {
+	rows, err := ReadSpreadsheet("arsip.csv", []byte("\ufeffNomor Surat;Nama\nSKH/1;\"Budi, S.E.\"\n"))
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{{"Nomor Surat", "Nama"}, {"SKH/1", "Budi, S.E."}}, rows)
+
+	rows, err = ReadSpreadsheet("arsip.csv", []byte("Nomor Surat,Nama\nSKH/1,Budi\n"))
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{{"Nomor Surat", "Nama"}, {"SKH/1", "Budi"}}, rows)
+
+	_, err = ReadSpreadsheet("arsip.xlsx", []byte("bukan zip"))
+	assert.ErrorIs(t, err, ErrInvalidImportFile)
+	_, err = ReadSpreadsheet("arsip.ods", nil)
+	assert.ErrorIs(t, err, ErrInvalidImportFile)
+}
+
+func TestColumnIndex(t *testing.T) {
+	assert.Equal(t, 0, columnIndex("A1"))
+	assert.Equal(t, 25, columnIndex("Z9"))
+	assert.Equal(t, 27, columnIndex("AB12"))
+	for i := 0; i < 800; i += 37 {
+		assert.Equal(t, i, columnIndex(columnName(i)+"1"))
+	}
+}
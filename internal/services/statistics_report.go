@@ -0,0 +1,286 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"simdokpol/internal/dto"
+	"simdokpol/internal/repositories"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// maxLocationKeywords membatasi jumlah kata kunci lokasi yang diambil otomatis.
+const maxLocationKeywords = 10
+
+var indonesianMonths = []string{"Januari", "Februari", "Maret", "April", "Mei", "Juni", "Juli", "Agustus", "September", "Oktober", "November", "Desember"}
+
+// indonesianWeekdays diurutkan dari Senin, sesuai kebiasaan rekap mingguan.
+var indonesianWeekdays = []struct {
+	day   time.Weekday
+	label string
+}{
+	{time.Monday, "Senin"}, {time.Tuesday, "Selasa"}, {time.Wednesday, "Rabu"}, {time.Thursday, "Kamis"},
+	{time.Friday, "Jumat"}, {time.Saturday, "Sabtu"}, {time.Sunday, "Minggu"},
+}
+
+// locationStopWords adalah kata umum pada isian lokasi hilang yang tidak berguna sebagai kata kunci.
+var locationStopWords = map[string]bool{
+	"jalan": true, "jln": true, "sekitar": true, "dekat": true, "depan": true, "belakang": true, "samping": true,
+	"daerah": true, "area": true, "kawasan": true, "wilayah": true, "kelurahan": true, "kecamatan": true,
+	"desa": true, "kota": true, "kabupaten": true, "dalam": true, "perjalanan": true, "dari": true,
+	"menuju": true, "antara": true, "tidak": true, "tahu": true, "diketahui": true, "lupa": true,
+}
+
+func (s *reportService) GenerateStatisticsReport(period string, year int, number int, locationKeywords []string) (*dto.StatisticsReport, error) {
+	loc, err := s.configService.GetLocation()
+	if err != nil {
+		loc = time.UTC
+	}
+	start, end, label, err := statisticsPeriod(period, year, number, loc)
+	if err != nil {
+		return nil, err
+	}
+	prevStart, prevEnd := start.AddDate(-1, 0, 0), end.AddDate(-1, 0, 0)
+
+	current, err := s.reportRepo.FindIssuedSummaries(start, end)
+	if err != nil {
+		return nil, err
+	}
+	previous, err := s.reportRepo.FindIssuedSummaries(prevStart, prevEnd)
+	if err != nil {
+		return nil, err
+	}
+	items, err := s.reportRepo.CountIssuedByItem(start, end)
+	if err != nil {
+		return nil, err
+	}
+	prevItems, err := s.reportRepo.CountIssuedByItem(prevStart, prevEnd)
+	if err != nil {
+		return nil, err
+	}
+	operators, err := s.reportRepo.CountIssuedByOperator(start, end)
+	if err != nil {
+		return nil, err
+	}
+	petugas, err := s.reportRepo.CountIssuedByPetugasPelapor(start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &dto.StatisticsReport{
+		Period:        period,
+		Label:         label,
+		Start:         start,
+		End:           end,
+		GeneratedAt:   time.Now().In(loc),
+		Total:         len(current),
+		PreviousTotal: len(previous),
+		ChangePercent: changePercent(len(current), len(previous)),
+		ByMonth:       monthComparisons(start, end, current, previous, loc),
+		ByItem:        groupComparisons(items, prevItems),
+		ByOperator:    statCounts(operators),
+		ByPetugas:     statCounts(petugas),
+		ByLocation:    locationCounts(current, locationKeywords),
+		ByWeekday:     weekdayCounts(current, loc),
+	}
+	return report, nil
+}
+
+// statisticsPeriod menghitung awal (inklusif), akhir (eksklusif), dan judul periode laporan.
+func statisticsPeriod(period string, year int, number int, loc *time.Location) (time.Time, time.Time, string, error) {
+	if year < 2000 || year > 9999 {
+		return time.Time{}, time.Time{}, "", ErrInvalidReportPeriod
+	}
+	switch period {
+	case dto.PeriodMonthly:
+		if number < 1 || number > 12 {
+			return time.Time{}, time.Time{}, "", ErrInvalidReportPeriod
+		}
+		start := time.Date(year, time.Month(number), 1, 0, 0, 0, 0, loc)
+		return start, start.AddDate(0, 1, 0), fmt.Sprintf("%s %d", indonesianMonths[number-1], year), nil
+	case dto.PeriodQuarterly:
+		if number < 1 || number > 4 {
+			return time.Time{}, time.Time{}, "", ErrInvalidReportPeriod
+		}
+		start := time.Date(year, time.Month((number-1)*3+1), 1, 0, 0, 0, 0, loc)
+		return start, start.AddDate(0, 3, 0), fmt.Sprintf("Triwulan %s %d", intToRoman(number), year), nil
+	case dto.PeriodYearly:
+		start := time.Date(year, time.January, 1, 0, 0, 0, 0, loc)
+		return start, start.AddDate(1, 0, 0), fmt.Sprintf("Tahun %d", year), nil
+	}
+	return time.Time{}, time.Time{}, "", ErrInvalidReportPeriod
+}
+
+// changePercent menghitung persentase perubahan terhadap tahun sebelumnya, dibulatkan satu desimal.
+func changePercent(current int, previous int) *float64 {
+	if previous == 0 {
+		return nil
+	}
+	change := math.Round(float64(current-previous)/float64(previous)*1000) / 10
+	return &change
+}
+
+func monthComparisons(start time.Time, end time.Time, current []repositories.IssuedSummary, previous []repositories.IssuedSummary, loc *time.Location) []dto.StatComparison {
+	currentByMonth := map[time.Month]int{}
+	for _, doc := range current {
+		currentByMonth[doc.TanggalLaporan.In(loc).Month()]++
+	}
+	previousByMonth := map[time.Month]int{}
+	for _, doc := range previous {
+		previousByMonth[doc.TanggalLaporan.In(loc).Month()]++
+	}
+	var results []dto.StatComparison
+	for month := start; month.Before(end); month = month.AddDate(0, 1, 0) {
+		cur, prev := currentByMonth[month.Month()], previousByMonth[month.Month()]
+		results = append(results, dto.StatComparison{
+			Label:         indonesianMonths[month.Month()-1],
+			Current:       cur,
+			Previous:      prev,
+			ChangePercent: changePercent(cur, prev),
+		})
+	}
+	return results
+}
+
+// groupComparisons memasangkan jumlah periode ini dengan tahun sebelumnya per label. Kelompok yang hanya
+// muncul tahun lalu tetap dicantumkan dengan jumlah nol agar penurunannya terlihat.
+func groupComparisons(current []repositories.GroupCount, previous []repositories.GroupCount) []dto.StatComparison {
+	previousByLabel := map[string]int{}
+	for _, group := range previous {
+		previousByLabel[group.Label] = group.Count
+	}
+	results := make([]dto.StatComparison, 0, len(current))
+	seen := map[string]bool{}
+	for _, group := range current {
+		seen[group.Label] = true
+		results = append(results, dto.StatComparison{
+			Label:         group.Label,
+			Current:       group.Count,
+			Previous:      previousByLabel[group.Label],
+			ChangePercent: changePercent(group.Count, previousByLabel[group.Label]),
+		})
+	}
+	for _, group := range previous {
+		if !seen[group.Label] {
+			results = append(results, dto.StatComparison{Label: group.Label, Previous: group.Count, ChangePercent: changePercent(0, group.Count)})
+		}
+	}
+	return results
+}
+
+func statCounts(groups []repositories.GroupCount) []dto.StatCount {
+	results := make([]dto.StatCount, 0, len(groups))
+	for _, group := range groups {
+		results = append(results, dto.StatCount{Label: group.Label, Count: group.Count})
+	}
+	return results
+}
+
+func weekdayCounts(docs []repositories.IssuedSummary, loc *time.Location) []dto.StatCount {
+	counts := map[time.Weekday]int{}
+	for _, doc := range docs {
+		counts[doc.TanggalLaporan.In(loc).Weekday()]++
+	}
+	results := make([]dto.StatCount, 0, len(indonesianWeekdays))
+	for _, weekday := range indonesianWeekdays {
+		results = append(results, dto.StatCount{Label: weekday.label, Count: counts[weekday.day]})
+	}
+	return results
+}
+
+// locationCounts menghitung jumlah dokumen yang lokasi hilangnya memuat setiap kata kunci.
+// Tanpa kata kunci, kata yang paling sering muncul (selain locationStopWords) dipakai sebagai kata kunci.
+func locationCounts(docs []repositories.IssuedSummary, keywords []string) []dto.StatCount {
+	counts := map[string]int{}
+	if len(keywords) > 0 {
+		for _, keyword := range keywords {
+			keyword = strings.ToUpper(strings.TrimSpace(keyword))
+			if keyword == "" {
+				continue
+			}
+			counts[keyword] = 0
+			for _, doc := range docs {
+				if strings.Contains(strings.ToUpper(doc.LokasiHilang), keyword) {
+					counts[keyword]++
+				}
+			}
+		}
+	} else {
+		for _, doc := range docs {
+			for word := range locationWords(doc.LokasiHilang) {
+				counts[word]++
+			}
+		}
+	}
+
+	results := make([]dto.StatCount, 0, len(counts))
+	for label, count := range counts {
+		results = append(results, dto.StatCount{Label: label, Count: count})
+	}
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Count != results[j].Count {
+			return results[i].Count > results[j].Count
+		}
+		return results[i].Label < results[j].Label
+	})
+	if len(keywords) == 0 && len(results) > maxLocationKeywords {
+		results = results[:maxLocationKeywords]
+	}
+	return results
+}
+
+// locationWords memecah isian lokasi menjadi kata unik (huruf kapital) yang layak menjadi kata kunci.
+func locationWords(lokasi string) map[string]bool {
+	words := map[string]bool{}
+	for _, word := range strings.FieldsFunc(strings.ToLower(lokasi), func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) }) {
+		if len([]rune(word)) < 4 || locationStopWords[word] || strings.IndexFunc(word, unicode.IsLetter) < 0 {
+			continue
+		}
+		words[strings.ToUpper(word)] = true
+	}
+	return words
+}
+
+// StatisticsSheets menyusun laporan statistik menjadi lembar kerja untuk ekspor XLSX dan CSV.
+// Perubahan (%) dikosongkan jika jumlah tahun sebelumnya nol.
+func StatisticsSheets(report *dto.StatisticsReport) []SpreadsheetSheet {
+	previousYear := report.Start.AddDate(-1, 0, 0).Year()
+	comparisonHeader := []interface{}{"", fmt.Sprintf("Jumlah %d", report.Start.Year()), fmt.Sprintf("Jumlah %d", previousYear), "Perubahan (%)"}
+
+	summary := SpreadsheetSheet{Name: "Ringkasan", Rows: [][]interface{}{
+		{"Laporan Statistik Dokumen", report.Label},
+		{"Periode", fmt.Sprintf("%s s.d. %s", report.Start.Format("02-01-2006"), report.End.AddDate(0, 0, -1).Format("02-01-2006"))},
+		{"Dibuat", report.GeneratedAt.Format("02-01-2006 15:04")},
+		{"Jumlah dokumen terbit", report.Total},
+		{fmt.Sprintf("Jumlah periode yang sama tahun %d", previousYear), report.PreviousTotal},
+		{"Perubahan (%)", report.ChangePercent},
+	}}
+
+	comparisonSheet := func(name string, firstColumn string, rows []dto.StatComparison) SpreadsheetSheet {
+		header := append([]interface{}{firstColumn}, comparisonHeader[1:]...)
+		sheet := SpreadsheetSheet{Name: name, Rows: [][]interface{}{header}}
+		for _, row := range rows {
+			sheet.Rows = append(sheet.Rows, []interface{}{row.Label, row.Current, row.Previous, row.ChangePercent})
+		}
+		return sheet
+	}
+	countSheet := func(name string, firstColumn string, rows []dto.StatCount) SpreadsheetSheet {
+		sheet := SpreadsheetSheet{Name: name, Rows: [][]interface{}{{firstColumn, "Jumlah"}}}
+		for _, row := range rows {
+			sheet.Rows = append(sheet.Rows, []interface{}{row.Label, row.Count})
+		}
+		return sheet
+	}
+
+	return []SpreadsheetSheet{
+		summary,
+		comparisonSheet("Per Bulan", "Bulan", report.ByMonth),
+		comparisonSheet("Jenis Barang", "Jenis Barang", report.ByItem),
+		countSheet("Operator", "Operator", report.ByOperator),
+		countSheet("Petugas Pelapor", "Petugas Pelapor", report.ByPetugas),
+		countSheet("Lokasi", "Kata Kunci Lokasi", report.ByLocation),
+		countSheet("Hari", "Hari", report.ByWeekday),
+	}
+}
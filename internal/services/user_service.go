@@ -9,11 +9,12 @@ import (
 	"strings"
 
 	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
 )
 
 type UserService interface {
 	Create(user *models.User, actorID uint) error
-	FindAll(statusFilter string) ([]models.User, error)
+	FindPage(statusFilter string, list repositories.ListQuery) ([]models.User, repositories.PageResult, error)
 	FindByID(id uint) (*models.User, error)
 	FindOperators() ([]models.User, error)
 	Update(user *models.User, newPassword string, actorID uint) error
@@ -25,13 +26,15 @@ type UserService interface {
 
 type userService struct {
 	userRepo     repositories.UserRepository
+	roleRepo     repositories.RoleRepository
 	auditService AuditLogService
 	cfg          *config.Config
 }
 
-func NewUserService(userRepo repositories.UserRepository, auditService AuditLogService, cfg *config.Config) UserService {
+func NewUserService(userRepo repositories.UserRepository, roleRepo repositories.RoleRepository, auditService AuditLogService, cfg *config.Config) UserService {
 	return &userService{
 		userRepo:     userRepo,
+		roleRepo:     roleRepo,
 		auditService: auditService,
 		cfg:          cfg,
 	}
@@ -91,6 +94,9 @@ func (s *userService) ChangePassword(userID uint, oldPassword, newPassword strin
 
 // ... (sisa fungsi Create, Update (admin), Deactivate, dll. tidak berubah) ...
 func (s *userService) Create(user *models.User, actorID uint) error {
+	if err := s.authorizeRoleChange(actorID, "", user.Peran); err != nil {
+		return err
+	}
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(user.KataSandi), s.cfg.BcryptCost)
 	if err != nil {
 		return err
@@ -118,6 +124,9 @@ func (s *userService) Update(user *models.User, newPassword string, actorID uint
 	if err != nil {
 		return errors.New("pengguna tidak ditemukan untuk pembaruan")
 	}
+	if err := s.authorizeRoleChange(actorID, oldUser.Peran, user.Peran); err != nil {
+		return err
+	}
 
 	if strings.TrimSpace(newPassword) != "" {
 		hashedPassword, err := bcrypt.GenerateFromPassword([]byte(newPassword), s.cfg.BcryptCost)
@@ -147,6 +156,9 @@ func (s *userService) Deactivate(id uint, actorID uint) error {
 	if err != nil {
 		return errors.New("pengguna tidak ditemukan")
 	}
+	if err := s.authorizeRoleChange(actorID, user.Peran, user.Peran); err != nil {
+		return err
+	}
 
 	if err := s.userRepo.Delete(id); err != nil {
 		return err
@@ -174,8 +186,8 @@ func (s *userService) Activate(id uint, actorID uint) error {
 	return nil
 }
 
-func (s *userService) FindAll(statusFilter string) ([]models.User, error) {
-	return s.userRepo.FindAll(statusFilter)
+func (s *userService) FindPage(statusFilter string, list repositories.ListQuery) ([]models.User, repositories.PageResult, error) {
+	return s.userRepo.FindPage(statusFilter, list)
 }
 
 func (s *userService) FindByID(id uint) (*models.User, error) {
@@ -184,4 +196,27 @@ func (s *userService) FindByID(id uint) (*models.User, error) {
 
 func (s *userService) FindOperators() ([]models.User, error) {
 	return s.userRepo.FindOperators()
-}
\ No newline at end of file
+}
+
+// authorizeRoleChange memastikan peran baru terdaftar dan hanya Super Admin yang dapat
+// memberikan, mencabut, atau mengubah akun berperan Super Admin. Pengelola pengguna lain
+// dengan demikian tidak dapat menaikkan hak aksesnya sendiri. actorID 0 berarti setup awal.
+func (s *userService) authorizeRoleChange(actorID uint, currentPeran string, newPeran string) error {
+	if _, err := s.roleRepo.FindByCode(newPeran); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("%w: peran %s tidak terdaftar", ErrInvalidRole, newPeran)
+		}
+		return err
+	}
+	if actorID == 0 || (currentPeran != models.RoleSuperAdmin && newPeran != models.RoleSuperAdmin) {
+		return nil
+	}
+	actor, err := s.userRepo.FindByID(actorID)
+	if err != nil {
+		return errors.New("pengguna tidak valid")
+	}
+	if actor.Peran != models.RoleSuperAdmin {
+		return fmt.Errorf("%w: hanya Super Admin yang dapat mengelola akun Super Admin", ErrAccessDenied)
+	}
+	return nil
+}
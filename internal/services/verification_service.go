@@ -0,0 +1,152 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"simdokpol/internal/models"
+	"simdokpol/internal/repositories"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/skip2/go-qrcode"
+	"gorm.io/gorm"
+)
+
+// Panjang tanda tangan HMAC (dalam byte) yang disertakan di token. 128 bit sudah
+// cukup untuk mencegah pemalsuan sekaligus menjaga QR code tetap kecil.
+const verificationSignatureLength = 16
+
+// DocumentVerificationDTO hanya berisi fakta publik sebuah surat.
+// Data pribadi pemohon (nama, NIK, alamat) sengaja tidak disertakan.
+type DocumentVerificationDTO struct {
+	NomorSurat     string    `json:"nomor_surat"`
+	TanggalLaporan time.Time `json:"tanggal_laporan"`
+	NamaKantor     string    `json:"nama_kantor"`
+	Status         string    `json:"status"`
+	// TanggalPencabutan hanya terisi untuk dokumen berstatus DICABUT.
+	TanggalPencabutan *time.Time `json:"tanggal_pencabutan,omitempty"`
+}
+
+type VerificationService interface {
+	GenerateToken(doc *models.LostDocument) string
+	VerificationURL(doc *models.LostDocument) (string, error)
+	QRCodePNG(content string) ([]byte, error)
+	Verify(token string) (*DocumentVerificationDTO, error)
+}
+
+type verificationService struct {
+	docRepo       repositories.LostDocumentRepository
+	configService ConfigService
+}
+
+func NewVerificationService(docRepo repositories.LostDocumentRepository, configService ConfigService) VerificationService {
+	return &verificationService{
+		docRepo:       docRepo,
+		configService: configService,
+	}
+}
+
+// GenerateToken membuat token "<id>.<tanda tangan>" yang ditandatangani dengan HMAC-SHA256
+// atas ID, Nomor Surat, dan Tanggal Laporan dokumen. Kuncinya diturunkan dari JWTSecretKey,
+// sehingga mengganti JWT_SECRET_KEY akan membatalkan semua QR code yang sudah tercetak.
+func (s *verificationService) GenerateToken(doc *models.LostDocument) string {
+	signature := signDocument(doc.ID, originalNomorSurat(doc.NomorSurat), doc.TanggalLaporan)
+	return fmt.Sprintf("%d.%s", doc.ID, base64.RawURLEncoding.EncodeToString(signature))
+}
+
+// VerificationURL menyusun URL publik yang akan dikodekan ke QR code dari "verification_base_url"
+// di Pengaturan. Host dari request sengaja tidak dipakai sebagai cadangan karena header Host dan
+// X-Forwarded-* dikendalikan klien, sehingga QR code bisa diarahkan ke domain lain.
+func (s *verificationService) VerificationURL(doc *models.LostDocument) (string, error) {
+	appConfig, err := s.configService.GetConfig()
+	if err != nil {
+		return "", err
+	}
+	baseURL := strings.TrimRight(strings.TrimSpace(appConfig.VerificationBaseURL), "/")
+	if baseURL == "" {
+		return "", ErrVerificationBaseURLNotSet
+	}
+	return fmt.Sprintf("%s/verify/%s", baseURL, s.GenerateToken(doc)), nil
+}
+
+func (s *verificationService) QRCodePNG(content string) ([]byte, error) {
+	return encodeQRCode(content)
+}
+
+func (s *verificationService) Verify(token string) (*DocumentVerificationDTO, error) {
+	idPart, signaturePart, found := strings.Cut(token, ".")
+	if !found {
+		return nil, ErrInvalidVerificationToken
+	}
+	id, err := strconv.ParseUint(idPart, 10, 32)
+	if err != nil {
+		return nil, ErrInvalidVerificationToken
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(signaturePart)
+	if err != nil {
+		return nil, ErrInvalidVerificationToken
+	}
+
+	doc, err := s.docRepo.FindByIDUnscoped(uint(id))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidVerificationToken
+		}
+		return nil, err
+	}
+
+	// Draf belum memiliki Nomor Surat dan belum pernah tercetak, sehingga tidak bisa diverifikasi.
+	nomorSurat := originalNomorSurat(doc.NomorSurat)
+	if nomorSurat == "" {
+		return nil, ErrInvalidVerificationToken
+	}
+	expected := signDocument(doc.ID, nomorSurat, doc.TanggalLaporan)
+	if !hmac.Equal(signature, expected) {
+		return nil, ErrInvalidVerificationToken
+	}
+
+	appConfig, err := s.configService.GetConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	status := doc.Status
+	if doc.DeletedAt.Valid {
+		status = models.StatusDihapus
+	}
+
+	return &DocumentVerificationDTO{
+		NomorSurat:        nomorSurat,
+		TanggalLaporan:    doc.TanggalLaporan,
+		NamaKantor:        appConfig.NamaKantor,
+		Status:            status,
+		TanggalPencabutan: doc.TanggalPencabutan,
+	}, nil
+}
+
+func signDocument(id uint, nomorSurat string, tanggalLaporan time.Time) []byte {
+	mac := hmac.New(sha256.New, JWTSecretKey)
+	fmt.Fprintf(mac, "document-verification|%d|%s|%d", id, nomorSurat, tanggalLaporan.Unix())
+	return mac.Sum(nil)[:verificationSignatureLength]
+}
+
+// originalNomorSurat mengembalikan nomor asli dari dokumen yang sudah dihapus,
+// yang nomornya diubah menjadi "DELETED_<timestamp>_<nomor asli>" oleh DeleteLostDocument.
+func originalNomorSurat(nomorSurat string) string {
+	if !strings.HasPrefix(nomorSurat, "DELETED_") {
+		return nomorSurat
+	}
+	parts := strings.SplitN(nomorSurat, "_", 3)
+	if len(parts) < 3 {
+		return nomorSurat
+	}
+	return parts[2]
+}
+
+func encodeQRCode(content string) ([]byte, error) {
+	return qrcode.Encode(content, qrcode.Medium, 256)
+}
@@ -0,0 +1,111 @@
+package services
+
+import (
+	"simdokpol/internal/dto"
+	"simdokpol/internal/mocks"
+	"simdokpol/internal/models"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestVerificationService_Verify(t *testing.T) {
+	JWTSecretKey = []byte("test-secret")
+
+	issuedAt := time.Now().Add(-24 * time.Hour)
+	activeDoc := &models.LostDocument{ID: 7, NomorSurat: "SKH/7/X/2025", TanggalLaporan: issuedAt, Status: models.StatusDiterbitkan}
+	deletedDoc := &models.LostDocument{
+		ID:             8,
+		NomorSurat:     "DELETED_1700000000_SKH/8/X/2025",
+		TanggalLaporan: issuedAt,
+		Status:         models.StatusDiterbitkan,
+		DeletedAt:      gorm.DeletedAt{Time: time.Now(), Valid: true},
+	}
+	mockConfig := &dto.AppConfig{NamaKantor: "Polsek Contoh", ArchiveDurationDays: 15}
+
+	testCases := []struct {
+		name           string
+		doc            *models.LostDocument
+		tamper         func(token string) string
+		expectedStatus string
+		expectedError  error
+	}{
+		{name: "Sukses - Dokumen Aktif", doc: activeDoc, expectedStatus: models.StatusDiterbitkan},
+		{name: "Sukses - Dokumen Dihapus Tetap Dikenali", doc: deletedDoc, expectedStatus: models.StatusDihapus},
+		{
+			name: "Gagal - Tanda Tangan Diubah",
+			doc:  activeDoc,
+			tamper: func(token string) string {
+				sep := strings.Index(token, ".")
+				replacement := "A"
+				if token[sep+1] == 'A' {
+					replacement = "B"
+				}
+				return token[:sep+1] + replacement + token[sep+2:]
+			},
+			expectedError: ErrInvalidVerificationToken,
+		},
+		{
+			name:          "Gagal - ID Ditukar",
+			doc:           activeDoc,
+			tamper:        func(token string) string { return "8" + token[strings.Index(token, "."):] },
+			expectedError: ErrInvalidVerificationToken,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockDocRepo := new(mocks.LostDocumentRepository)
+			mockConfigService := new(mocks.ConfigService)
+			mockDocRepo.On("FindByIDUnscoped", activeDoc.ID).Return(activeDoc, nil).Maybe()
+			mockDocRepo.On("FindByIDUnscoped", deletedDoc.ID).Return(deletedDoc, nil).Maybe()
+			mockConfigService.On("GetConfig").Return(mockConfig, nil).Maybe()
+
+			service := NewVerificationService(mockDocRepo, mockConfigService)
+			token := service.GenerateToken(tc.doc)
+			if tc.tamper != nil {
+				token = tc.tamper(token)
+			}
+
+			result, err := service.Verify(token)
+			if tc.expectedError != nil {
+				assert.ErrorIs(t, err, tc.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedStatus, result.Status)
+			assert.Equal(t, originalNomorSurat(tc.doc.NomorSurat), result.NomorSurat)
+			assert.Equal(t, "Polsek Contoh", result.NamaKantor)
+		})
+	}
+}
+
+func TestVerificationService_VerificationURL(t *testing.T) {
+	JWTSecretKey = []byte("test-secret")
+	doc := &models.LostDocument{ID: 7, NomorSurat: "SKH/7/X/2025", TanggalLaporan: time.Now()}
+
+	t.Run("Sukses - Memakai URL dari Pengaturan", func(t *testing.T) {
+		mockConfigService := new(mocks.ConfigService)
+		mockConfigService.On("GetConfig").Return(&dto.AppConfig{VerificationBaseURL: "https://simdokpol.polres-contoh.go.id/"}, nil)
+		service := NewVerificationService(nil, mockConfigService)
+
+		url, err := service.VerificationURL(doc)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "https://simdokpol.polres-contoh.go.id/verify/"+service.GenerateToken(doc), url)
+	})
+
+	// Host dari request tidak dipakai sebagai cadangan, sehingga tanpa pengaturan QR code tidak dicetak.
+	t.Run("Gagal - URL Belum Diatur", func(t *testing.T) {
+		mockConfigService := new(mocks.ConfigService)
+		mockConfigService.On("GetConfig").Return(&dto.AppConfig{}, nil)
+		service := NewVerificationService(nil, mockConfigService)
+
+		_, err := service.VerificationURL(doc)
+
+		assert.ErrorIs(t, err, ErrVerificationBaseURLNotSet)
+	})
+}
@@ -0,0 +1,6 @@
+-- Menghapus tabel penomoran surat (Migrasi TURUN / Rollback)
+
+DROP INDEX `idx_lost_documents_tahun_nomor`;
+ALTER TABLE `lost_documents` DROP COLUMN `tahun_nomor`;
+ALTER TABLE `lost_documents` DROP COLUMN `nomor_urut`;
+DROP TABLE `document_sequences`;
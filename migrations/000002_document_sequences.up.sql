@@ -0,0 +1,38 @@
+-- Tabel penomoran surat per jenis dokumen dan tahun (Migrasi NAIK)
+
+CREATE TABLE `document_sequences` (
+    `document_type` text NOT NULL,
+    `year` integer NOT NULL,
+    `last_number` integer NOT NULL DEFAULT 0,
+    `updated_at` datetime,
+    PRIMARY KEY (`document_type`, `year`)
+);
+
+ALTER TABLE `lost_documents` ADD COLUMN `nomor_urut` integer NOT NULL DEFAULT 0;
+ALTER TABLE `lost_documents` ADD COLUMN `tahun_nomor` integer NOT NULL DEFAULT 0;
+CREATE INDEX `idx_lost_documents_tahun_nomor` ON `lost_documents`(`tahun_nomor`, `nomor_urut`);
+
+-- Ambil nomor urut dari segmen kedua Nomor Surat lama (contoh: SKH/<urut>/X/.../2025).
+-- Dokumen terhapus berformat DELETED_<ts>_<nomor asli> tetap terurai karena '/' pertama ada di nomor asli.
+UPDATE `lost_documents`
+SET `nomor_urut` = CAST(
+        substr(
+            substr(`nomor_surat`, instr(`nomor_surat`, '/') + 1),
+            1,
+            instr(substr(`nomor_surat`, instr(`nomor_surat`, '/') + 1), '/') - 1
+        ) AS INTEGER),
+    `tahun_nomor` = CAST(strftime('%Y', `tanggal_laporan`) AS INTEGER)
+WHERE instr(`nomor_surat`, '/') > 0;
+
+INSERT INTO `document_sequences` (`document_type`, `year`, `last_number`, `updated_at`)
+SELECT 'LOST_DOCUMENT', `tahun_nomor`, MAX(`nomor_urut`), CURRENT_TIMESTAMP
+FROM `lost_documents`
+WHERE `nomor_urut` > 0 AND `tahun_nomor` > 0
+GROUP BY `tahun_nomor`;
+
+-- Nomor register awal dari halaman setup berlaku untuk tahun berjalan.
+INSERT INTO `document_sequences` (`document_type`, `year`, `last_number`, `updated_at`)
+SELECT 'LOST_DOCUMENT', CAST(strftime('%Y', 'now') AS INTEGER), CAST(`value` AS INTEGER), CURRENT_TIMESTAMP
+FROM `configurations`
+WHERE `key` = 'nomor_surat_terakhir' AND CAST(`value` AS INTEGER) > 0
+ON CONFLICT (`document_type`, `year`) DO UPDATE SET `last_number` = MAX(`last_number`, excluded.`last_number`);
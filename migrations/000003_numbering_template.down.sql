@@ -0,0 +1,19 @@
+-- Mengembalikan format Nomor Surat ke format fmt.Sprintf lama (Migrasi TURUN / Rollback)
+-- Lebar {SEQ:n} tidak dapat direpresentasikan di format lama dan ikut menjadi %d,
+-- sedangkan {OFFICE_CODE} dan {REGU} dihapus.
+
+UPDATE `configurations` SET `value` = replace(`value`, '{SEQ}', '%d') WHERE `key` = 'format_nomor_surat';
+UPDATE `configurations` SET `value` = replace(`value`, '{SEQ:1}', '%d') WHERE `key` = 'format_nomor_surat';
+UPDATE `configurations` SET `value` = replace(`value`, '{SEQ:2}', '%d') WHERE `key` = 'format_nomor_surat';
+UPDATE `configurations` SET `value` = replace(`value`, '{SEQ:3}', '%d') WHERE `key` = 'format_nomor_surat';
+UPDATE `configurations` SET `value` = replace(`value`, '{SEQ:4}', '%d') WHERE `key` = 'format_nomor_surat';
+UPDATE `configurations` SET `value` = replace(`value`, '{SEQ:5}', '%d') WHERE `key` = 'format_nomor_surat';
+UPDATE `configurations` SET `value` = replace(`value`, '{SEQ:6}', '%d') WHERE `key` = 'format_nomor_surat';
+UPDATE `configurations` SET `value` = replace(`value`, '{SEQ:7}', '%d') WHERE `key` = 'format_nomor_surat';
+UPDATE `configurations` SET `value` = replace(`value`, '{SEQ:8}', '%d') WHERE `key` = 'format_nomor_surat';
+UPDATE `configurations` SET `value` = replace(`value`, '{SEQ:9}', '%d') WHERE `key` = 'format_nomor_surat';
+UPDATE `configurations` SET `value` = replace(`value`, '{SEQ:10}', '%d') WHERE `key` = 'format_nomor_surat';
+UPDATE `configurations` SET `value` = replace(`value`, '{ROMAN_MONTH}', '%s') WHERE `key` = 'format_nomor_surat';
+UPDATE `configurations` SET `value` = replace(`value`, '{YEAR}', '%d') WHERE `key` = 'format_nomor_surat';
+UPDATE `configurations` SET `value` = replace(`value`, '{OFFICE_CODE}', '') WHERE `key` = 'format_nomor_surat';
+UPDATE `configurations` SET `value` = replace(`value`, '{REGU}', '') WHERE `key` = 'format_nomor_surat';
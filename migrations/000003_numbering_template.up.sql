@@ -0,0 +1,33 @@
+-- Mengubah format Nomor Surat lama berbasis fmt.Sprintf ke placeholder bernama (Migrasi NAIK)
+-- Contoh: SKH/%d/%s/TUK.7.2.1/%d menjadi SKH/{SEQ}/{ROMAN_MONTH}/TUK.7.2.1/{YEAR}
+-- dan SKH/%03d/%s/TUK.7.2.1/%d menjadi SKH/{SEQ:3}/{ROMAN_MONTH}/TUK.7.2.1/{YEAR}
+
+-- %s hanya pernah dipakai untuk bulan romawi.
+UPDATE `configurations`
+SET `value` = replace(`value`, '%s', '{ROMAN_MONTH}')
+WHERE `key` = 'format_nomor_surat';
+
+-- %0Nd (nomor urut dengan nol di depan) hanya pernah dipakai untuk nomor urut.
+UPDATE `configurations`
+SET `value` = replace(replace(replace(replace(replace(replace(replace(replace(replace(replace(`value`,
+    '%01d', '{SEQ:1}'), '%02d', '{SEQ:2}'), '%03d', '{SEQ:3}'), '%04d', '{SEQ:4}'), '%05d', '{SEQ:5}'),
+    '%06d', '{SEQ:6}'), '%07d', '{SEQ:7}'), '%08d', '{SEQ:8}'), '%09d', '{SEQ:9}'), '%010d', '{SEQ:10}')
+WHERE `key` = 'format_nomor_surat';
+
+-- Bila nomor urut belum ditemukan, %d pertama adalah nomor urut.
+UPDATE `configurations`
+SET `value` = substr(`value`, 1, instr(`value`, '%d') - 1) || '{SEQ}' || substr(`value`, instr(`value`, '%d') + 2)
+WHERE `key` = 'format_nomor_surat' AND instr(`value`, '%d') > 0 AND instr(`value`, '{SEQ') = 0;
+
+-- %d sisanya adalah tahun.
+UPDATE `configurations`
+SET `value` = replace(`value`, '%d', '{YEAR}')
+WHERE `key` = 'format_nomor_surat';
+
+-- Verb lain (misalnya %5d, %x, atau %%) tidak dapat diterjemahkan dengan aman. Migrasi dibatalkan
+-- lewat CHECK agar format diperbaiki manual, alih-alih menerbitkan nomor yang rusak.
+CREATE TEMP TABLE `format_nomor_surat_check` (
+    `value` TEXT CONSTRAINT `format_nomor_surat_memuat_verb_sprintf_yang_tidak_dikenal` CHECK (instr(`value`, '%') = 0)
+);
+INSERT INTO `format_nomor_surat_check` SELECT `value` FROM `configurations` WHERE `key` = 'format_nomor_surat';
+DROP TABLE `format_nomor_surat_check`;
@@ -0,0 +1,31 @@
+-- Mengembalikan NIK penduduk menjadi wajib (Migrasi TURUN / Rollback)
+-- Penduduk tanpa NIK kembali diberi NIK sementara "TEMP<id>".
+
+PRAGMA defer_foreign_keys = ON;
+
+CREATE TEMP TABLE `residents_backup` AS SELECT * FROM `residents`;
+
+DROP TABLE `residents`;
+
+CREATE TABLE `residents` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `nik` text NOT NULL UNIQUE,
+    `nama_lengkap` text NOT NULL,
+    `tempat_lahir` text NOT NULL,
+    `tanggal_lahir` datetime NOT NULL,
+    `jenis_kelamin` text NOT NULL,
+    `agama` text NOT NULL,
+    `pekerjaan` text NOT NULL,
+    `alamat` text NOT NULL,
+    `created_at` datetime,
+    `updated_at` datetime,
+    `deleted_at` datetime
+);
+
+INSERT INTO `residents` (`id`, `nik`, `nama_lengkap`, `tempat_lahir`, `tanggal_lahir`, `jenis_kelamin`, `agama`, `pekerjaan`, `alamat`, `created_at`, `updated_at`, `deleted_at`)
+SELECT `id`, COALESCE(`nik`, 'TEMP' || `id`), `nama_lengkap`, `tempat_lahir`, `tanggal_lahir`, `jenis_kelamin`, `agama`, `pekerjaan`, `alamat`, `created_at`, `updated_at`, `deleted_at`
+FROM `residents_backup`;
+
+DROP TABLE `residents_backup`;
+
+CREATE INDEX `idx_residents_deleted_at` ON `residents`(`deleted_at`);
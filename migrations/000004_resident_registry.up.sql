@@ -0,0 +1,34 @@
+-- Menjadikan NIK penduduk opsional dan menghapus NIK sementara "TEMP..." (Migrasi NAIK)
+-- SQLite tidak dapat menghapus NOT NULL dengan ALTER TABLE, jadi tabel disusun ulang.
+-- Pemeriksaan foreign key ditunda sampai COMMIT: lost_documents sempat tidak punya induk saat
+-- tabel lama dihapus, lalu terpenuhi kembali setelah baris yang sama dimasukkan ke tabel baru.
+
+PRAGMA defer_foreign_keys = ON;
+
+CREATE TEMP TABLE `residents_backup` AS SELECT * FROM `residents`;
+
+DROP TABLE `residents`;
+
+CREATE TABLE `residents` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `nik` text UNIQUE,
+    `nama_lengkap` text NOT NULL,
+    `tempat_lahir` text NOT NULL,
+    `tanggal_lahir` datetime NOT NULL,
+    `jenis_kelamin` text NOT NULL,
+    `agama` text NOT NULL,
+    `pekerjaan` text NOT NULL,
+    `alamat` text NOT NULL,
+    `created_at` datetime,
+    `updated_at` datetime,
+    `deleted_at` datetime
+);
+
+INSERT INTO `residents` (`id`, `nik`, `nama_lengkap`, `tempat_lahir`, `tanggal_lahir`, `jenis_kelamin`, `agama`, `pekerjaan`, `alamat`, `created_at`, `updated_at`, `deleted_at`)
+SELECT `id`, CASE WHEN `nik` LIKE 'TEMP%' THEN NULL ELSE `nik` END, `nama_lengkap`, `tempat_lahir`, `tanggal_lahir`, `jenis_kelamin`, `agama`, `pekerjaan`, `alamat`, `created_at`, `updated_at`, `deleted_at`
+FROM `residents_backup`;
+
+DROP TABLE `residents_backup`;
+
+CREATE INDEX `idx_residents_deleted_at` ON `residents`(`deleted_at`);
+CREATE INDEX `idx_residents_nama_lengkap` ON `residents`(`nama_lengkap`);
@@ -0,0 +1,4 @@
+-- Menghapus riwayat revisi dokumen (Migrasi TURUN / Rollback)
+
+DROP INDEX `idx_document_revisions_document_revision`;
+DROP TABLE `document_revisions`;
@@ -0,0 +1,56 @@
+-- Riwayat revisi dokumen berupa snapshot JSON per penyimpanan (Migrasi NAIK)
+
+CREATE TABLE `document_revisions` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `lost_document_id` integer NOT NULL,
+    `revision_number` integer NOT NULL,
+    `aksi` text NOT NULL,
+    `snapshot` text NOT NULL,
+    `changed_by_id` integer NOT NULL,
+    `created_at` datetime,
+    FOREIGN KEY (`lost_document_id`) REFERENCES `lost_documents`(`id`),
+    FOREIGN KEY (`changed_by_id`) REFERENCES `users`(`id`)
+);
+
+CREATE UNIQUE INDEX `idx_document_revisions_document_revision` ON `document_revisions`(`lost_document_id`, `revision_number`);
+
+-- Dokumen yang sudah ada mendapat revisi 1 "DATA AWAL" berisi keadaannya saat ini,
+-- sehingga perubahan berikutnya tetap bisa dibandingkan dengan isi sebelumnya.
+-- Tanggal lahir diambil 10 karakter pertama agar tidak bergeser oleh konversi zona waktu.
+INSERT INTO `document_revisions` (`lost_document_id`, `revision_number`, `aksi`, `snapshot`, `changed_by_id`, `created_at`)
+SELECT
+    d.`id`,
+    1,
+    'DATA AWAL',
+    json_object(
+        'nomor_surat', d.`nomor_surat`,
+        'status', d.`status`,
+        'lokasi_hilang', COALESCE(d.`lokasi_hilang`, ''),
+        'resident', json_object(
+            'id', r.`id`,
+            'nik', r.`nik`,
+            'nama_lengkap', r.`nama_lengkap`,
+            'tempat_lahir', r.`tempat_lahir`,
+            'tanggal_lahir', substr(r.`tanggal_lahir`, 1, 10),
+            'jenis_kelamin', r.`jenis_kelamin`,
+            'agama', r.`agama`,
+            'pekerjaan', r.`pekerjaan`,
+            'alamat', r.`alamat`
+        ),
+        'petugas_pelapor_id', d.`petugas_pelapor_id`,
+        'petugas_pelapor', TRIM(COALESCE(pp.`pangkat`, '') || ' ' || COALESCE(pp.`nama_lengkap`, '')),
+        'pejabat_persetuju_id', d.`pejabat_persetuju_id`,
+        'pejabat_persetuju', TRIM(COALESCE(pj.`pangkat`, '') || ' ' || COALESCE(pj.`nama_lengkap`, '')),
+        'lost_items', (
+            SELECT json_group_array(json_object('nama_barang', i.`nama_barang`, 'deskripsi', COALESCE(i.`deskripsi`, '')))
+            FROM `lost_items` i
+            WHERE i.`lost_document_id` = d.`id`
+        )
+    ),
+    COALESCE(d.`last_updated_by_id`, d.`operator_id`),
+    COALESCE(d.`updated_at`, d.`created_at`)
+FROM `lost_documents` d
+JOIN `residents` r ON r.`id` = d.`resident_id`
+LEFT JOIN `users` pp ON pp.`id` = d.`petugas_pelapor_id`
+LEFT JOIN `users` pj ON pj.`id` = d.`pejabat_persetuju_id`
+WHERE d.`deleted_at` IS NULL;
@@ -0,0 +1,25 @@
+-- Mengembalikan audit_logs.user_id menjadi wajib (Migrasi TURUN / Rollback)
+-- Catatan aksi sistem (tanpa pengguna) dihapus. Status DIARSIPKAN yang sudah tersimpan dibiarkan,
+-- karena versi lama tetap menampilkannya sebagai arsip.
+
+PRAGMA defer_foreign_keys = ON;
+
+DROP INDEX IF EXISTS `idx_lost_documents_status`;
+
+CREATE TEMP TABLE `audit_logs_backup` AS SELECT * FROM `audit_logs` WHERE `user_id` IS NOT NULL;
+
+DROP TABLE `audit_logs`;
+
+CREATE TABLE `audit_logs` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `user_id` integer NOT NULL,
+    `aksi` text NOT NULL,
+    `detail` text,
+    `timestamp` datetime NOT NULL,
+    FOREIGN KEY (`user_id`) REFERENCES `users`(`id`)
+);
+
+INSERT INTO `audit_logs` (`id`, `user_id`, `aksi`, `detail`, `timestamp`)
+SELECT `id`, `user_id`, `aksi`, `detail`, `timestamp` FROM `audit_logs_backup`;
+
+DROP TABLE `audit_logs_backup`;
@@ -0,0 +1,26 @@
+-- Status arsip disimpan di kolom status oleh pengarsip terjadwal (Migrasi NAIK)
+-- audit_logs.user_id dijadikan opsional agar aksi otomatis sistem dapat dicatat tanpa pengguna.
+-- SQLite tidak dapat menghapus NOT NULL dengan ALTER TABLE, jadi tabel disusun ulang.
+
+PRAGMA defer_foreign_keys = ON;
+
+CREATE TEMP TABLE `audit_logs_backup` AS SELECT * FROM `audit_logs`;
+
+DROP TABLE `audit_logs`;
+
+CREATE TABLE `audit_logs` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `user_id` integer,
+    `aksi` text NOT NULL,
+    `detail` text,
+    `timestamp` datetime NOT NULL,
+    FOREIGN KEY (`user_id`) REFERENCES `users`(`id`)
+);
+
+INSERT INTO `audit_logs` (`id`, `user_id`, `aksi`, `detail`, `timestamp`)
+SELECT `id`, `user_id`, `aksi`, `detail`, `timestamp` FROM `audit_logs_backup`;
+
+DROP TABLE `audit_logs_backup`;
+
+-- Daftar dokumen aktif/arsip kini difilter berdasarkan kolom status.
+CREATE INDEX `idx_lost_documents_status` ON `lost_documents`(`status`);
@@ -0,0 +1,14 @@
+-- Menghapus registri jenis dokumen (Migrasi TURUN / Rollback)
+-- Versi lama hanya mengenal Surat Keterangan Hilang, sehingga dokumen jenis lain beserta
+-- barang, revisi, dan nomor urutnya ikut dihapus.
+
+DELETE FROM `lost_items` WHERE `lost_document_id` IN (SELECT `id` FROM `lost_documents` WHERE `jenis_dokumen` <> 'LOST_DOCUMENT');
+DELETE FROM `document_revisions` WHERE `lost_document_id` IN (SELECT `id` FROM `lost_documents` WHERE `jenis_dokumen` <> 'LOST_DOCUMENT');
+DELETE FROM `lost_documents` WHERE `jenis_dokumen` <> 'LOST_DOCUMENT';
+DELETE FROM `document_sequences` WHERE `document_type` <> 'LOST_DOCUMENT';
+
+DROP INDEX `idx_lost_documents_jenis_dokumen`;
+ALTER TABLE `lost_documents` DROP COLUMN `data_tambahan`;
+ALTER TABLE `lost_documents` DROP COLUMN `jenis_dokumen`;
+
+DROP TABLE `document_types`;
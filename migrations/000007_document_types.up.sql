@@ -0,0 +1,33 @@
+-- Registri jenis dokumen (Migrasi NAIK)
+-- Setiap jenis dokumen punya format nomor, template cetak, dan daftar isian sendiri.
+-- Surat Keterangan Hilang didaftarkan sebagai jenis pertama; format nomornya tetap diambil dari Pengaturan.
+
+CREATE TABLE `document_types` (
+    `kode` text PRIMARY KEY,
+    `nama` text NOT NULL,
+    `format_nomor` text,
+    `print_template` text NOT NULL,
+    `fields` text NOT NULL DEFAULT '[]',
+    `aktif` numeric NOT NULL DEFAULT true,
+    `created_at` datetime,
+    `updated_at` datetime
+);
+
+INSERT INTO `document_types` (`kode`, `nama`, `format_nomor`, `print_template`, `fields`, `aktif`, `created_at`, `updated_at`) VALUES
+('LOST_DOCUMENT', 'Surat Keterangan Hilang', '', 'print_preview.html',
+ '[{"key":"lost_items","label":"Barang Hilang","tipe":"text","wajib":true},{"key":"lokasi_hilang","label":"Perkiraan Lokasi Hilang","tipe":"text","wajib":true}]',
+ true, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP),
+('STPL', 'Surat Tanda Penerimaan Laporan', 'STPL/{SEQ}/{ROMAN_MONTH}/{YEAR}/{OFFICE_CODE}', 'print_generic.html',
+ '[{"key":"perihal","label":"Perihal Laporan","tipe":"text","wajib":true},{"key":"waktu_kejadian","label":"Waktu Kejadian","tipe":"text","wajib":true},{"key":"tempat_kejadian","label":"Tempat Kejadian","tipe":"text","wajib":true},{"key":"uraian","label":"Uraian Singkat","tipe":"textarea","wajib":true}]',
+ true, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP),
+('PENGANTAR_SKCK', 'Surat Pengantar SKCK', 'B/SKCK/{SEQ}/{ROMAN_MONTH}/{YEAR}/{OFFICE_CODE}', 'print_generic.html',
+ '[{"key":"nik","label":"NIK","tipe":"text","wajib":true},{"key":"keperluan","label":"Keperluan","tipe":"textarea","wajib":true},{"key":"ditujukan_kepada","label":"Ditujukan Kepada","tipe":"text","wajib":true}]',
+ true, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP),
+('LAPORAN_POLISI', 'Laporan Polisi', 'LP/B/{SEQ}/{ROMAN_MONTH}/{YEAR}/{OFFICE_CODE}', 'print_generic.html',
+ '[{"key":"jenis_perkara","label":"Jenis Perkara","tipe":"text","wajib":true},{"key":"waktu_kejadian","label":"Waktu Kejadian","tipe":"text","wajib":true},{"key":"tempat_kejadian","label":"Tempat Kejadian","tipe":"text","wajib":true},{"key":"terlapor","label":"Terlapor","tipe":"text","wajib":false},{"key":"saksi","label":"Saksi-saksi","tipe":"textarea","wajib":false},{"key":"barang_bukti","label":"Barang Bukti","tipe":"textarea","wajib":false},{"key":"uraian","label":"Uraian Singkat Kejadian","tipe":"textarea","wajib":true}]',
+ true, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP);
+
+ALTER TABLE `lost_documents` ADD COLUMN `jenis_dokumen` text NOT NULL DEFAULT 'LOST_DOCUMENT';
+ALTER TABLE `lost_documents` ADD COLUMN `data_tambahan` text;
+
+CREATE INDEX `idx_lost_documents_jenis_dokumen` ON `lost_documents`(`jenis_dokumen`);
@@ -0,0 +1,57 @@
+-- Menghapus alur persetujuan dokumen (Migrasi TURUN / Rollback)
+-- Draf dan dokumen yang menunggu persetujuan belum bernomor, sehingga dihapus permanen beserta
+-- barang dan revisinya. Dokumen DICABUT di-soft delete agar verifikasi publik tetap menolaknya
+-- (versi lama tidak mengenal status DICABUT).
+
+PRAGMA defer_foreign_keys = ON;
+
+DELETE FROM `lost_items` WHERE `lost_document_id` IN (SELECT `id` FROM `lost_documents` WHERE `status` IN ('DRAF', 'MENUNGGU_PERSETUJUAN'));
+DELETE FROM `document_revisions` WHERE `lost_document_id` IN (SELECT `id` FROM `lost_documents` WHERE `status` IN ('DRAF', 'MENUNGGU_PERSETUJUAN'));
+DELETE FROM `lost_documents` WHERE `status` IN ('DRAF', 'MENUNGGU_PERSETUJUAN');
+
+UPDATE `lost_documents`
+SET `nomor_surat` = 'DELETED_' || CAST(strftime('%s', 'now') AS TEXT) || '_' || `nomor_surat`,
+    `deleted_at` = COALESCE(`deleted_at`, CURRENT_TIMESTAMP),
+    `status` = 'DITERBITKAN'
+WHERE `status` = 'DICABUT';
+
+CREATE TEMP TABLE `lost_documents_backup` AS SELECT * FROM `lost_documents`;
+
+DROP TABLE `lost_documents`;
+
+CREATE TABLE `lost_documents` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `nomor_surat` text NOT NULL UNIQUE,
+    `tanggal_laporan` datetime NOT NULL,
+    `status` text NOT NULL DEFAULT 'DITERBITKAN',
+    `lokasi_hilang` text,
+    `resident_id` integer NOT NULL,
+    `petugas_pelapor_id` integer NOT NULL,
+    `pejabat_persetuju_id` integer,
+    `operator_id` integer NOT NULL,
+    `last_updated_by_id` integer,
+    `tanggal_persetujuan` datetime,
+    `created_at` datetime,
+    `updated_at` datetime,
+    `deleted_at` datetime,
+    `nomor_urut` integer NOT NULL DEFAULT 0,
+    `tahun_nomor` integer NOT NULL DEFAULT 0,
+    `jenis_dokumen` text NOT NULL DEFAULT 'LOST_DOCUMENT',
+    `data_tambahan` text,
+    FOREIGN KEY (`pejabat_persetuju_id`) REFERENCES `users`(`id`),
+    FOREIGN KEY (`operator_id`) REFERENCES `users`(`id`),
+    FOREIGN KEY (`last_updated_by_id`) REFERENCES `users`(`id`),
+    FOREIGN KEY (`resident_id`) REFERENCES `residents`(`id`),
+    FOREIGN KEY (`petugas_pelapor_id`) REFERENCES `users`(`id`)
+);
+
+INSERT INTO `lost_documents` (`id`, `nomor_surat`, `tanggal_laporan`, `status`, `lokasi_hilang`, `resident_id`, `petugas_pelapor_id`, `pejabat_persetuju_id`, `operator_id`, `last_updated_by_id`, `tanggal_persetujuan`, `created_at`, `updated_at`, `deleted_at`, `nomor_urut`, `tahun_nomor`, `jenis_dokumen`, `data_tambahan`)
+SELECT `id`, `nomor_surat`, `tanggal_laporan`, `status`, `lokasi_hilang`, `resident_id`, `petugas_pelapor_id`, `pejabat_persetuju_id`, `operator_id`, `last_updated_by_id`, `tanggal_persetujuan`, `created_at`, `updated_at`, `deleted_at`, `nomor_urut`, `tahun_nomor`, `jenis_dokumen`, `data_tambahan`
+FROM `lost_documents_backup`;
+
+DROP TABLE `lost_documents_backup`;
+
+CREATE INDEX `idx_lost_documents_deleted_at` ON `lost_documents`(`deleted_at`);
+CREATE INDEX `idx_lost_documents_tahun_nomor` ON `lost_documents`(`tahun_nomor`, `nomor_urut`);
+CREATE INDEX `idx_lost_documents_status` ON `lost_documents`(`status`);
+CREATE INDEX `idx_lost_documents_jenis_dokumen` ON `lost_documents`(`jenis_dokumen`);
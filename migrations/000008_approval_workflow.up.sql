@@ -0,0 +1,54 @@
+-- Alur persetujuan dokumen: DRAF -> MENUNGGU_PERSETUJUAN -> DITERBITKAN -> DICABUT (Migrasi NAIK)
+-- Draf belum memiliki Nomor Surat, sehingga UNIQUE pada nomor_surat diganti indeks unik parsial
+-- yang mengabaikan nomor kosong. SQLite tidak dapat menghapus UNIQUE dengan ALTER TABLE,
+-- jadi tabel disusun ulang; pemeriksaan foreign key lost_items dan document_revisions ditunda sampai COMMIT.
+
+PRAGMA defer_foreign_keys = ON;
+
+CREATE TEMP TABLE `lost_documents_backup` AS SELECT * FROM `lost_documents`;
+
+DROP TABLE `lost_documents`;
+
+CREATE TABLE `lost_documents` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `nomor_surat` text NOT NULL DEFAULT '',
+    `nomor_urut` integer NOT NULL DEFAULT 0,
+    `tahun_nomor` integer NOT NULL DEFAULT 0,
+    `tanggal_laporan` datetime NOT NULL,
+    `status` text NOT NULL DEFAULT 'DRAF',
+    `lokasi_hilang` text,
+    `jenis_dokumen` text NOT NULL DEFAULT 'LOST_DOCUMENT',
+    `data_tambahan` text,
+    `resident_id` integer NOT NULL,
+    `petugas_pelapor_id` integer NOT NULL,
+    `pejabat_persetuju_id` integer,
+    `operator_id` integer NOT NULL,
+    `last_updated_by_id` integer,
+    `tanggal_persetujuan` datetime,
+    `alasan_penolakan` text,
+    `tanggal_pencabutan` datetime,
+    `alasan_pencabutan` text,
+    `dicabut_oleh_id` integer,
+    `created_at` datetime,
+    `updated_at` datetime,
+    `deleted_at` datetime,
+    FOREIGN KEY (`pejabat_persetuju_id`) REFERENCES `users`(`id`),
+    FOREIGN KEY (`operator_id`) REFERENCES `users`(`id`),
+    FOREIGN KEY (`last_updated_by_id`) REFERENCES `users`(`id`),
+    FOREIGN KEY (`dicabut_oleh_id`) REFERENCES `users`(`id`),
+    FOREIGN KEY (`resident_id`) REFERENCES `residents`(`id`),
+    FOREIGN KEY (`petugas_pelapor_id`) REFERENCES `users`(`id`)
+);
+
+INSERT INTO `lost_documents` (`id`, `nomor_surat`, `nomor_urut`, `tahun_nomor`, `tanggal_laporan`, `status`, `lokasi_hilang`, `jenis_dokumen`, `data_tambahan`, `resident_id`, `petugas_pelapor_id`, `pejabat_persetuju_id`, `operator_id`, `last_updated_by_id`, `tanggal_persetujuan`, `created_at`, `updated_at`, `deleted_at`)
+SELECT `id`, `nomor_surat`, `nomor_urut`, `tahun_nomor`, `tanggal_laporan`, `status`, `lokasi_hilang`, `jenis_dokumen`, `data_tambahan`, `resident_id`, `petugas_pelapor_id`, `pejabat_persetuju_id`, `operator_id`, `last_updated_by_id`, `tanggal_persetujuan`, `created_at`, `updated_at`, `deleted_at`
+FROM `lost_documents_backup`;
+
+DROP TABLE `lost_documents_backup`;
+
+CREATE UNIQUE INDEX `idx_lost_documents_nomor_surat` ON `lost_documents`(`nomor_surat`) WHERE `nomor_surat` <> '';
+CREATE INDEX `idx_lost_documents_deleted_at` ON `lost_documents`(`deleted_at`);
+CREATE INDEX `idx_lost_documents_tahun_nomor` ON `lost_documents`(`tahun_nomor`, `nomor_urut`);
+CREATE INDEX `idx_lost_documents_status` ON `lost_documents`(`status`);
+CREATE INDEX `idx_lost_documents_jenis_dokumen` ON `lost_documents`(`jenis_dokumen`);
+CREATE INDEX `idx_lost_documents_pejabat_persetuju_id` ON `lost_documents`(`pejabat_persetuju_id`, `status`);
@@ -0,0 +1,8 @@
+-- Menghapus peran dan izin akses (Migrasi TURUN / Rollback)
+-- Versi lama hanya mengenal SUPER_ADMIN dan OPERATOR, sehingga pengguna dengan peran lain
+-- dikembalikan menjadi OPERATOR.
+
+UPDATE `users` SET `peran` = 'OPERATOR' WHERE `peran` NOT IN ('SUPER_ADMIN', 'OPERATOR');
+
+DROP INDEX `idx_users_peran`;
+DROP TABLE `roles`;
@@ -0,0 +1,30 @@
+-- Peran dan izin akses (Migrasi NAIK)
+-- Peran membundel sejumlah izin bernama (misalnya document.approve, backup.run).
+-- Kolom users.peran tetap dipakai dan kini merujuk ke roles.kode.
+-- Super Admin selalu memiliki semua izin; daftar izinnya di sini hanya untuk tampilan.
+
+CREATE TABLE `roles` (
+    `kode` text PRIMARY KEY,
+    `nama` text NOT NULL,
+    `deskripsi` text,
+    `permissions` text NOT NULL DEFAULT '[]',
+    `bawaan` numeric NOT NULL DEFAULT false,
+    `created_at` datetime,
+    `updated_at` datetime
+);
+
+INSERT INTO `roles` (`kode`, `nama`, `deskripsi`, `permissions`, `bawaan`, `created_at`, `updated_at`) VALUES
+('SUPER_ADMIN', 'Super Admin', 'Akses penuh ke seluruh fitur aplikasi.',
+ '["document.create","document.view_all","document.edit_all","document.approve","document.revoke","document.restore_revision","document_type.manage","resident.merge","user.manage","audit.view","backup.run","backup.restore","settings.edit","archive.run"]',
+ true, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP),
+('OPERATOR', 'Anggota Jaga', 'Membuat dan mengelola surat yang dibuatnya sendiri.',
+ '["document.create"]',
+ true, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP),
+('KANIT_SPKT', 'Kanit SPKT', 'Melihat seluruh surat, menyetujui, dan mencabut surat tanpa akses administrasi sistem.',
+ '["document.create","document.view_all","document.approve","document.revoke"]',
+ true, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP),
+('AUDITOR', 'Auditor', 'Hanya baca: melihat seluruh surat, log audit, dan laporan penomoran.',
+ '["document.view_all","audit.view"]',
+ true, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP);
+
+CREATE INDEX `idx_users_peran` ON `users`(`peran`);
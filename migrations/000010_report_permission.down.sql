@@ -0,0 +1,5 @@
+-- Izin laporan statistik periodik (Migrasi TURUN)
+
+UPDATE `roles`
+SET `permissions` = (SELECT json_group_array(`value`) FROM json_each(`roles`.`permissions`) WHERE `value` <> 'report.view')
+WHERE EXISTS (SELECT 1 FROM json_each(`roles`.`permissions`) WHERE `value` = 'report.view');
@@ -0,0 +1,7 @@
+-- Izin laporan statistik periodik (Migrasi NAIK)
+-- Ditambahkan ke peran bawaan yang menyusun rekap untuk Polres tanpa menimpa izin lain yang sudah diubah.
+
+UPDATE `roles`
+SET `permissions` = json_insert(`permissions`, '$[#]', 'report.view'), `updated_at` = CURRENT_TIMESTAMP
+WHERE `kode` IN ('SUPER_ADMIN', 'KANIT_SPKT', 'AUDITOR')
+  AND NOT EXISTS (SELECT 1 FROM json_each(`roles`.`permissions`) WHERE `value` = 'report.view');
@@ -0,0 +1,11 @@
+-- Indeks pencarian teks penuh dokumen dengan SQLite FTS5 (Migrasi TURUN)
+
+DROP TRIGGER IF EXISTS `document_search_resident_update`;
+DROP TRIGGER IF EXISTS `document_search_item_delete`;
+DROP TRIGGER IF EXISTS `document_search_item_update`;
+DROP TRIGGER IF EXISTS `document_search_item_insert`;
+DROP TRIGGER IF EXISTS `document_search_document_delete`;
+DROP TRIGGER IF EXISTS `document_search_document_update`;
+DROP TRIGGER IF EXISTS `document_search_document_insert`;
+DROP VIEW IF EXISTS `document_search_source`;
+DROP TABLE IF EXISTS `document_search`;
@@ -0,0 +1,85 @@
+-- Indeks pencarian teks penuh dokumen dengan SQLite FTS5 (Migrasi NAIK)
+-- rowid document_search sama dengan id lost_documents. Isi indeks diambil dari view document_search_source
+-- dan dijaga oleh trigger pada lost_documents, lost_items, dan residents, sehingga setiap jalur penulisan
+-- (formulir, impor, pemulihan revisi) ikut memperbarui indeks. Dokumen terhapus tidak diindeks.
+
+CREATE VIRTUAL TABLE `document_search` USING fts5(
+    `nomor_surat`,
+    `nama_lengkap`,
+    `nik`,
+    `tempat_lahir`,
+    `pekerjaan`,
+    `alamat`,
+    `lokasi_hilang`,
+    `barang`,
+    tokenize = 'unicode61 remove_diacritics 2'
+);
+
+CREATE VIEW `document_search_source` AS
+SELECT
+    `d`.`id` AS `id`,
+    `d`.`resident_id` AS `resident_id`,
+    `d`.`nomor_surat` AS `nomor_surat`,
+    `r`.`nama_lengkap` AS `nama_lengkap`,
+    COALESCE(`r`.`nik`, '') AS `nik`,
+    `r`.`tempat_lahir` AS `tempat_lahir`,
+    `r`.`pekerjaan` AS `pekerjaan`,
+    `r`.`alamat` AS `alamat`,
+    COALESCE(`d`.`lokasi_hilang`, '') AS `lokasi_hilang`,
+    COALESCE((
+        SELECT group_concat(`i`.`nama_barang` || COALESCE(' ' || NULLIF(`i`.`deskripsi`, ''), ''), '; ')
+        FROM `lost_items` `i`
+        WHERE `i`.`lost_document_id` = `d`.`id`
+    ), '') AS `barang`
+FROM `lost_documents` `d`
+JOIN `residents` `r` ON `r`.`id` = `d`.`resident_id`
+WHERE `d`.`deleted_at` IS NULL;
+
+CREATE TRIGGER `document_search_document_insert` AFTER INSERT ON `lost_documents` BEGIN
+    INSERT INTO `document_search` (`rowid`, `nomor_surat`, `nama_lengkap`, `nik`, `tempat_lahir`, `pekerjaan`, `alamat`, `lokasi_hilang`, `barang`)
+    SELECT `id`, `nomor_surat`, `nama_lengkap`, `nik`, `tempat_lahir`, `pekerjaan`, `alamat`, `lokasi_hilang`, `barang`
+    FROM `document_search_source` WHERE `id` = NEW.`id`;
+END;
+
+CREATE TRIGGER `document_search_document_update` AFTER UPDATE OF `nomor_surat`, `lokasi_hilang`, `resident_id`, `deleted_at` ON `lost_documents` BEGIN
+    DELETE FROM `document_search` WHERE `rowid` = NEW.`id`;
+    INSERT INTO `document_search` (`rowid`, `nomor_surat`, `nama_lengkap`, `nik`, `tempat_lahir`, `pekerjaan`, `alamat`, `lokasi_hilang`, `barang`)
+    SELECT `id`, `nomor_surat`, `nama_lengkap`, `nik`, `tempat_lahir`, `pekerjaan`, `alamat`, `lokasi_hilang`, `barang`
+    FROM `document_search_source` WHERE `id` = NEW.`id`;
+END;
+
+CREATE TRIGGER `document_search_document_delete` AFTER DELETE ON `lost_documents` BEGIN
+    DELETE FROM `document_search` WHERE `rowid` = OLD.`id`;
+END;
+
+CREATE TRIGGER `document_search_item_insert` AFTER INSERT ON `lost_items` BEGIN
+    DELETE FROM `document_search` WHERE `rowid` = NEW.`lost_document_id`;
+    INSERT INTO `document_search` (`rowid`, `nomor_surat`, `nama_lengkap`, `nik`, `tempat_lahir`, `pekerjaan`, `alamat`, `lokasi_hilang`, `barang`)
+    SELECT `id`, `nomor_surat`, `nama_lengkap`, `nik`, `tempat_lahir`, `pekerjaan`, `alamat`, `lokasi_hilang`, `barang`
+    FROM `document_search_source` WHERE `id` = NEW.`lost_document_id`;
+END;
+
+CREATE TRIGGER `document_search_item_update` AFTER UPDATE ON `lost_items` BEGIN
+    DELETE FROM `document_search` WHERE `rowid` IN (OLD.`lost_document_id`, NEW.`lost_document_id`);
+    INSERT INTO `document_search` (`rowid`, `nomor_surat`, `nama_lengkap`, `nik`, `tempat_lahir`, `pekerjaan`, `alamat`, `lokasi_hilang`, `barang`)
+    SELECT `id`, `nomor_surat`, `nama_lengkap`, `nik`, `tempat_lahir`, `pekerjaan`, `alamat`, `lokasi_hilang`, `barang`
+    FROM `document_search_source` WHERE `id` IN (OLD.`lost_document_id`, NEW.`lost_document_id`);
+END;
+
+CREATE TRIGGER `document_search_item_delete` AFTER DELETE ON `lost_items` BEGIN
+    DELETE FROM `document_search` WHERE `rowid` = OLD.`lost_document_id`;
+    INSERT INTO `document_search` (`rowid`, `nomor_surat`, `nama_lengkap`, `nik`, `tempat_lahir`, `pekerjaan`, `alamat`, `lokasi_hilang`, `barang`)
+    SELECT `id`, `nomor_surat`, `nama_lengkap`, `nik`, `tempat_lahir`, `pekerjaan`, `alamat`, `lokasi_hilang`, `barang`
+    FROM `document_search_source` WHERE `id` = OLD.`lost_document_id`;
+END;
+
+CREATE TRIGGER `document_search_resident_update` AFTER UPDATE OF `nama_lengkap`, `nik`, `tempat_lahir`, `pekerjaan`, `alamat` ON `residents` BEGIN
+    DELETE FROM `document_search` WHERE `rowid` IN (SELECT `id` FROM `lost_documents` WHERE `resident_id` = NEW.`id`);
+    INSERT INTO `document_search` (`rowid`, `nomor_surat`, `nama_lengkap`, `nik`, `tempat_lahir`, `pekerjaan`, `alamat`, `lokasi_hilang`, `barang`)
+    SELECT `id`, `nomor_surat`, `nama_lengkap`, `nik`, `tempat_lahir`, `pekerjaan`, `alamat`, `lokasi_hilang`, `barang`
+    FROM `document_search_source` WHERE `resident_id` = NEW.`id`;
+END;
+
+INSERT INTO `document_search` (`rowid`, `nomor_surat`, `nama_lengkap`, `nik`, `tempat_lahir`, `pekerjaan`, `alamat`, `lokasi_hilang`, `barang`)
+SELECT `id`, `nomor_surat`, `nama_lengkap`, `nik`, `tempat_lahir`, `pekerjaan`, `alamat`, `lokasi_hilang`, `barang`
+FROM `document_search_source`;
@@ -0,0 +1,8 @@
+-- Katalog jenis barang hilang (Migrasi TURUN)
+-- Nama dan deskripsi barang tetap tersimpan, sehingga hanya pengelompokan dan atribut bertipenya yang hilang.
+
+DROP INDEX `idx_lost_items_jenis_barang`;
+ALTER TABLE `lost_items` DROP COLUMN `atribut`;
+ALTER TABLE `lost_items` DROP COLUMN `jenis_barang`;
+
+DROP TABLE `item_types`;
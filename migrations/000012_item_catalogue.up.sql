@@ -0,0 +1,53 @@
+-- Katalog jenis barang hilang (Migrasi NAIK)
+-- Setiap jenis barang memiliki atribut bertipe (NIK, nomor polisi, IMEI, dst.) yang dirender formulir
+-- dan divalidasi server. Barang lama dikelompokkan ke jenisnya berdasarkan kode, nama, atau alias
+-- sehingga "KTP", "E-KTP", dan "ktp" dihitung sebagai satu jenis; deskripsi lamanya tidak diubah.
+
+CREATE TABLE `item_types` (
+    `kode` text PRIMARY KEY,
+    `nama` text NOT NULL,
+    `alias` text NOT NULL DEFAULT '[]',
+    `atribut` text NOT NULL DEFAULT '[]',
+    `aktif` numeric NOT NULL DEFAULT true,
+    `created_at` datetime,
+    `updated_at` datetime
+);
+
+INSERT INTO `item_types` (`kode`, `nama`, `alias`, `atribut`, `aktif`, `created_at`, `updated_at`) VALUES
+('KTP', 'KTP', '["E-KTP","EKTP","KTP-EL","KTP ELEKTRONIK","KARTU TANDA PENDUDUK"]',
+ '[{"key":"nik","label":"NIK","tipe":"nik","wajib":true}]',
+ true, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP),
+('SIM', 'SIM', '["SIM A","SIM B I","SIM B II","SIM C","SIM D","SURAT IZIN MENGEMUDI"]',
+ '[{"key":"golongan","label":"Gol","tipe":"pilihan","wajib":true,"opsi":["A","B I","B II","C","D"]},{"key":"nomor_sim","label":"No. SIM","tipe":"angka","wajib":true}]',
+ true, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP),
+('STNK', 'STNK', '["SURAT TANDA NOMOR KENDARAAN"]',
+ '[{"key":"nopol","label":"No. Pol","tipe":"nopol","wajib":true},{"key":"nomor_rangka","label":"No. Rangka","tipe":"text","wajib":false},{"key":"nomor_mesin","label":"No. Mesin","tipe":"text","wajib":false}]',
+ true, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP),
+('BPKB', 'BPKB', '["BUKU PEMILIK KENDARAAN BERMOTOR"]',
+ '[{"key":"nomor_bpkb","label":"No. BPKB","tipe":"text","wajib":true},{"key":"nopol","label":"No. Pol","tipe":"nopol","wajib":false},{"key":"atas_nama","label":"a.n.","tipe":"text","wajib":false}]',
+ true, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP),
+('IJAZAH', 'Ijazah', '[]',
+ '[{"key":"tingkat","label":"Tingkat","tipe":"pilihan","wajib":true,"opsi":["SD","SMP","SMA/SMK","D3","S1","S2","S3"]},{"key":"nomor_ijazah","label":"No. Ijazah","tipe":"text","wajib":false}]',
+ true, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP),
+('ATM', 'Kartu ATM / Buku Tabungan', '["KARTU ATM","BUKU TABUNGAN","ATM / BUKU TABUNGAN"]',
+ '[{"key":"bank","label":"Bank","tipe":"pilihan","wajib":true,"opsi":["BRI","BCA","Mandiri","BNI","BTN","Lainnya"]},{"key":"nomor_rekening","label":"No. Rek","tipe":"angka","wajib":false}]',
+ true, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP),
+('PASPOR', 'Paspor', '["PASSPORT","PASPORT"]',
+ '[{"key":"nomor_paspor","label":"No. Paspor","tipe":"text","wajib":true}]',
+ true, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP),
+('HP', 'Handphone', '["HANDPHONE","PONSEL","TELEPON GENGGAM","SMARTPHONE"]',
+ '[{"key":"merek","label":"Merek/Tipe","tipe":"text","wajib":true},{"key":"imei","label":"IMEI","tipe":"imei","wajib":false},{"key":"nomor_hp","label":"No. HP","tipe":"angka","wajib":false}]',
+ true, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP);
+
+ALTER TABLE `lost_items` ADD COLUMN `jenis_barang` text;
+ALTER TABLE `lost_items` ADD COLUMN `atribut` text;
+
+UPDATE `lost_items` SET `jenis_barang` = (
+    SELECT `t`.`kode` FROM `item_types` `t`
+    WHERE UPPER(TRIM(`lost_items`.`nama_barang`)) IN (UPPER(`t`.`kode`), UPPER(`t`.`nama`))
+       OR EXISTS (SELECT 1 FROM json_each(`t`.`alias`) WHERE `value` = UPPER(TRIM(`lost_items`.`nama_barang`)))
+    ORDER BY `t`.`kode`
+    LIMIT 1
+);
+
+CREATE INDEX `idx_lost_items_jenis_barang` ON `lost_items`(`jenis_barang`);
@@ -0,0 +1,12 @@
+-- Pencocokan nomor identitas barang antar laporan (Migrasi TURUN)
+
+UPDATE `roles`
+SET `permissions` = (SELECT json_group_array(`value`) FROM json_each(`roles`.`permissions`) WHERE `value` <> 'document.crosscheck')
+WHERE EXISTS (SELECT 1 FROM json_each(`roles`.`permissions`) WHERE `value` = 'document.crosscheck');
+
+UPDATE `item_types`
+SET `atribut` = (
+    SELECT json_group_array(json_remove(`a`.`value`, '$.cocokkan'))
+    FROM json_each(`item_types`.`atribut`) `a`
+)
+WHERE EXISTS (SELECT 1 FROM json_each(`item_types`.`atribut`) `a` WHERE json_extract(`a`.`value`, '$.cocokkan') IS NOT NULL);
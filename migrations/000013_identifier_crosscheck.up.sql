@@ -0,0 +1,22 @@
+-- Pencocokan nomor identitas barang antar laporan (Migrasi NAIK)
+-- Atribut katalog yang ditandai "cocokkan" (NIK, nomor SIM, nomor polisi, IMEI, dst.) dibandingkan antar
+-- laporan untuk menandai kemungkinan laporan berulang atau klaim palsu. Nilai dicocokkan per key atribut,
+-- sehingga nomor polisi pada STNK dan BPKB saling terhubung.
+
+UPDATE `item_types`
+SET `atribut` = (
+    SELECT json_group_array(
+        CASE WHEN json_extract(`a`.`value`, '$.key') IN ('nik', 'nomor_sim', 'nopol', 'nomor_rangka', 'nomor_mesin', 'nomor_bpkb', 'nomor_paspor', 'imei')
+             THEN json_set(`a`.`value`, '$.cocokkan', json('true'))
+             ELSE json(`a`.`value`) END)
+    FROM json_each(`item_types`.`atribut`) `a`
+), `updated_at` = CURRENT_TIMESTAMP
+WHERE EXISTS (
+    SELECT 1 FROM json_each(`item_types`.`atribut`) `a`
+    WHERE json_extract(`a`.`value`, '$.key') IN ('nik', 'nomor_sim', 'nopol', 'nomor_rangka', 'nomor_mesin', 'nomor_bpkb', 'nomor_paspor', 'imei')
+);
+
+UPDATE `roles`
+SET `permissions` = json_insert(`permissions`, '$[#]', 'document.crosscheck'), `updated_at` = CURRENT_TIMESTAMP
+WHERE `kode` IN ('SUPER_ADMIN', 'KANIT_SPKT', 'AUDITOR')
+  AND NOT EXISTS (SELECT 1 FROM json_each(`roles`.`permissions`) WHERE `value` = 'document.crosscheck');
@@ -0,0 +1,10 @@
+-- Tong sampah dokumen (Migrasi TURUN)
+
+UPDATE `roles`
+SET `permissions` = (SELECT json_group_array(`value`) FROM json_each(`roles`.`permissions`) WHERE `value` <> 'recycle_bin.manage')
+WHERE EXISTS (SELECT 1 FROM json_each(`roles`.`permissions`) WHERE `value` = 'recycle_bin.manage');
+
+DELETE FROM `configurations` WHERE `key` = 'recycle_bin_retention_days';
+
+ALTER TABLE `lost_documents` DROP COLUMN `dihapus_oleh_id`;
+ALTER TABLE `lost_documents` DROP COLUMN `alasan_penghapusan`;
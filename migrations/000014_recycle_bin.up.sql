@@ -0,0 +1,16 @@
+-- Tong sampah dokumen (Migrasi NAIK)
+-- Dokumen yang dihapus tetap tersimpan (soft delete) bersama alasan dan penghapusnya, dapat dipulihkan,
+-- dan dimusnahkan permanen setelah masa retensi di Pengaturan terlewati.
+
+ALTER TABLE `lost_documents` ADD COLUMN `alasan_penghapusan` text;
+-- Tanpa REFERENCES agar kolom dapat dihapus lagi oleh migrasi turun (SQLite menolak DROP COLUMN pada foreign key).
+ALTER TABLE `lost_documents` ADD COLUMN `dihapus_oleh_id` integer;
+
+INSERT INTO `configurations` (`key`, `value`)
+SELECT 'recycle_bin_retention_days', '30'
+WHERE NOT EXISTS (SELECT 1 FROM `configurations` WHERE `key` = 'recycle_bin_retention_days');
+
+UPDATE `roles`
+SET `permissions` = json_insert(`permissions`, '$[#]', 'recycle_bin.manage'), `updated_at` = CURRENT_TIMESTAMP
+WHERE `kode` = 'SUPER_ADMIN'
+  AND NOT EXISTS (SELECT 1 FROM json_each(`roles`.`permissions`) WHERE `value` = 'recycle_bin.manage');
@@ -0,0 +1,4 @@
+-- Backup terjadwal (Migrasi TURUN)
+
+DELETE FROM `configurations`
+WHERE `key` IN ('backup_schedule', 'backup_keep_daily', 'backup_keep_weekly', 'backup_keep_monthly');
@@ -0,0 +1,19 @@
+-- Backup terjadwal (Migrasi NAIK)
+-- Jadwal berformat cron lima kolom; nilai kosong menonaktifkan backup otomatis. Backup terjadwal
+-- dirotasi dengan menyimpan sejumlah generasi harian, mingguan, dan bulanan.
+
+INSERT INTO `configurations` (`key`, `value`)
+SELECT 'backup_schedule', '0 2 * * *'
+WHERE NOT EXISTS (SELECT 1 FROM `configurations` WHERE `key` = 'backup_schedule');
+
+INSERT INTO `configurations` (`key`, `value`)
+SELECT 'backup_keep_daily', '7'
+WHERE NOT EXISTS (SELECT 1 FROM `configurations` WHERE `key` = 'backup_keep_daily');
+
+INSERT INTO `configurations` (`key`, `value`)
+SELECT 'backup_keep_weekly', '4'
+WHERE NOT EXISTS (SELECT 1 FROM `configurations` WHERE `key` = 'backup_keep_weekly');
+
+INSERT INTO `configurations` (`key`, `value`)
+SELECT 'backup_keep_monthly', '12'
+WHERE NOT EXISTS (SELECT 1 FROM `configurations` WHERE `key` = 'backup_keep_monthly');
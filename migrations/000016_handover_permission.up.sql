@@ -0,0 +1,10 @@
+-- Izin laporan serah terima jaga (Migrasi NAIK)
+-- Laporan serah terima disusun oleh anggota jaga sendiri, sehingga izinnya dipisah dari report.view
+-- (statistik untuk Polres) dan ikut diberikan ke OPERATOR. Peran lain yang sudah dapat membuka laporan
+-- ini lewat report.view tetap mendapatkannya.
+
+UPDATE `roles`
+SET `permissions` = json_insert(`permissions`, '$[#]', 'report.handover'), `updated_at` = CURRENT_TIMESTAMP
+WHERE (`kode` IN ('SUPER_ADMIN', 'OPERATOR', 'KANIT_SPKT', 'AUDITOR')
+       OR EXISTS (SELECT 1 FROM json_each(`roles`.`permissions`) WHERE `value` = 'report.view'))
+  AND NOT EXISTS (SELECT 1 FROM json_each(`roles`.`permissions`) WHERE `value` = 'report.handover');